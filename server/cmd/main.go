@@ -1,14 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
 	"shopify/config"
 	"shopify/middleware"
 	"shopify/models"
+	"shopify/pkg/scheduler"
 	"shopify/repository"
 	"shopify/service"
 	"shopify/router"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -30,8 +38,68 @@ func main() {
 
 	// 创建服务工厂
 	baseService := service.NewService(repoFactory)
+	store, err := service.NewStorage(config.GlobalConfig.Storage)
+	if err != nil {
+		log.Fatalf("文件存储初始化失败: %v", err)
+	}
+	baseService.SetStorage(store)
+	privateStore, err := service.NewPrivateStorage(config.GlobalConfig.Storage, config.GlobalConfig.Digital)
+	if err != nil {
+		log.Fatalf("数字文件存储初始化失败: %v", err)
+	}
+	baseService.SetPrivateStorage(privateStore)
+	// 商品浏览记录缓冲后批量写入
+	viewRecorder := service.NewViewRecorder(repoFactory, config.GlobalConfig.Tracking)
+	viewRecorder.Start()
+	baseService.SetViewRecorder(viewRecorder)
 	serviceFactory := service.NewServiceFactory(baseService)
 
+	// 将历史的类目文本迁移为类目并关联产品
+	if err := serviceFactory.GetCategoryService().MigrateLegacyCategories(); err != nil {
+		log.Printf("历史类目迁移失败: %v", err)
+	}
+
+	// 确保基准币种存在，其他币种由管理员维护
+	if err := serviceFactory.GetCurrencyService().EnsureBaseCurrency(); err != nil {
+		log.Printf("初始化基准币种失败: %v", err)
+	}
+
+	// 为启用库存流水前已有库存的产品补记期初流水
+	if err := serviceFactory.GetInventoryService().BackfillOpeningStock(); err != nil {
+		log.Printf("补记期初库存流水失败: %v", err)
+	}
+
+	// 为历史产品生成 slug
+	if err := serviceFactory.GetProductService().BackfillSlugs(); err != nil {
+		log.Printf("生成产品 slug 失败: %v", err)
+	}
+
+	// 服务重启会中断进行中的导入任务
+	if err := serviceFactory.GetImportService().FailInterruptedJobs(); err != nil {
+		log.Printf("清理中断的导入任务失败: %v", err)
+	}
+
+	// 构建搜索索引
+	searchService := serviceFactory.GetSearchService()
+	if err := searchService.RebuildIndex(); err != nil {
+		log.Printf("搜索索引构建失败: %v", err)
+	}
+
+	// 启动定时任务
+	sched := scheduler.NewScheduler()
+	// 定期重建搜索索引，同步销量等未实时更新的字段
+	sched.Every("search-reindex", time.Hour, searchService.RebuildIndex)
+	sched.Daily("media-orphan-cleanup", 3, 30,
+		serviceFactory.GetMediaService().RunOrphanCleanup)
+	sched.Daily("low-stock-alert", config.GlobalConfig.Inventory.AlertHour, 0,
+		serviceFactory.GetStockAlertService().RunLowStockAlert)
+	// 执行到期的定时上下架和改价
+	sched.Every("product-schedules", time.Minute, serviceFactory.GetScheduleService().RunDueSchedules)
+	// 定期根据订单重新计算一起购买关系
+	sched.Every("product-associations", time.Duration(config.GlobalConfig.Recommendation.RecomputeHours)*time.Hour,
+		serviceFactory.GetRecommendationService().RecomputeAssociations)
+	sched.Start()
+
 	// 创建 Gin 引擎
 	r := gin.Default()
 
@@ -39,11 +107,42 @@ func main() {
 	r.Use(middleware.Cors())
 	r.Use(middleware.Logger())
 
+	// 本地存储时直接提供上传文件的静态访问
+	if storageConfig := config.GlobalConfig.Storage; storageConfig.Driver == "" || storageConfig.Driver == "local" {
+		r.Static(storageConfig.BaseURL, storageConfig.LocalRoot)
+	}
+
 	// 初始化路由，传入数据库连接
 	router.RegisterRoutes(r, serviceFactory, db)
 
-	// 启动服务器
+	// 启动服务器，收到退出信号后优雅关闭
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	port := config.GlobalConfig.Server.Port
-	log.Printf("服务器启动在 :%d", port)
-	log.Fatal(r.Run(fmt.Sprintf(":%d", port)))
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: r,
+	}
+	go func() {
+		log.Printf("服务器启动在 :%d", port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("服务器运行失败: %v", err)
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("正在关闭服务器...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("服务器关闭失败: %v", err)
+	}
+
+	// 请求处理完毕后再停止定时任务，并写入缓冲中的浏览记录
+	sched.Stop()
+	viewRecorder.Stop()
+	log.Println("服务器已退出")
 }
@@ -11,6 +11,16 @@ type Config struct {
     Redis    RedisConfig    `mapstructure:"redis"`
     Email    EmailConfig    `mapstructure:"email"`
     Payment  PaymentConfig  `mapstructure:"payment"`
+    Inventory InventoryConfig `mapstructure:"inventory"`
+    Search    SearchConfig    `mapstructure:"search"`
+    Storage   StorageConfig   `mapstructure:"storage"`
+    Recommendation RecommendationConfig `mapstructure:"recommendation"`
+    Tracking       TrackingConfig       `mapstructure:"tracking"`
+    SEO            SEOConfig            `mapstructure:"seo"`
+    Feed           FeedConfig           `mapstructure:"feed"`
+    I18n           I18nConfig           `mapstructure:"i18n"`
+    Currency       CurrencyConfig       `mapstructure:"currency"`
+    Digital        DigitalConfig        `mapstructure:"digital"`
 }
 
 type ServerConfig struct {
@@ -74,6 +84,88 @@ type AlipayConfig struct {
     NotifyURL  string `mapstructure:"notify_url"`
 }
 
+type InventoryConfig struct {
+    LowStockThreshold int `mapstructure:"low_stock_threshold"` // 商品未设置阈值时的默认低库存阈值
+    SalesWindowDays   int `mapstructure:"sales_window_days"`   // 计算销售速度的统计天数
+    CoverDays         int `mapstructure:"cover_days"`          // 可售天数低于该值时预警
+    TargetCoverDays   int `mapstructure:"target_cover_days"`   // 补货建议的目标可售天数
+    AlertHour         int `mapstructure:"alert_hour"`          // 每日预警任务执行的小时
+}
+
+type SearchConfig struct {
+    HotWindowHours    int `mapstructure:"hot_window_hours"`    // 热搜词统计的滑动窗口小时数
+    HotLimit          int `mapstructure:"hot_limit"`           // 热搜词返回数量
+    SuggestWindowDays int `mapstructure:"suggest_window_days"` // 联想词中热门搜索的统计天数
+    SuggestLimit      int `mapstructure:"suggest_limit"`       // 联想词返回数量
+}
+
+type StorageConfig struct {
+    Driver           string   `mapstructure:"driver"`             // 存储驱动：local/s3
+    LocalRoot        string   `mapstructure:"local_root"`         // 本地存储目录
+    BaseURL          string   `mapstructure:"base_url"`           // 本地存储的访问地址前缀
+    MaxUploadMB      int      `mapstructure:"max_upload_mb"`      // 单个文件大小上限(MB)
+    ThumbnailSizes   []int    `mapstructure:"thumbnail_sizes"`    // 缩略图尺寸(最长边像素)
+    OrphanGraceHours int      `mapstructure:"orphan_grace_hours"` // 未被引用的文件保留多少小时后清理
+    S3               S3Config `mapstructure:"s3"`
+}
+
+type S3Config struct {
+    Endpoint     string `mapstructure:"endpoint"`
+    Region       string `mapstructure:"region"`
+    Bucket       string `mapstructure:"bucket"`
+    AccessKey    string `mapstructure:"access_key"`
+    SecretKey    string `mapstructure:"secret_key"`
+    PublicURL    string `mapstructure:"public_url"`
+    UsePathStyle bool   `mapstructure:"use_path_style"`
+}
+
+type RecommendationConfig struct {
+    WindowDays     int `mapstructure:"window_days"`     // 统计共同购买的订单天数
+    MinOrders      int `mapstructure:"min_orders"`      // 至少同时出现在多少个订单中才视为关联
+    MaxPerProduct  int `mapstructure:"max_per_product"` // 每个商品保存的关联商品上限
+    Limit          int `mapstructure:"limit"`           // 相关推荐默认返回数量
+    RecomputeHours int `mapstructure:"recompute_hours"` // 重新计算关联的间隔小时数
+}
+
+type TrackingConfig struct {
+    BatchSize    int `mapstructure:"batch_size"`    // 浏览记录缓冲达到该数量时立即写入
+    FlushSeconds int `mapstructure:"flush_seconds"` // 浏览记录缓冲的最长写入间隔秒数
+    MaxBuffer    int `mapstructure:"max_buffer"`    // 缓冲上限，数据库不可用时超出部分丢弃
+    RecentLimit  int `mapstructure:"recent_limit"`  // 最近浏览返回数量
+}
+
+type SEOConfig struct {
+    SiteURL      string `mapstructure:"site_url"`      // 前台站点地址，用于生成站点地图中的绝对地址
+    ProductPath  string `mapstructure:"product_path"`  // 商品页路径模板，{slug} 和 {id} 会被替换
+    CategoryPath string `mapstructure:"category_path"` // 类目页路径模板，{id} 会被替换
+}
+
+type FeedConfig struct {
+    Token string `mapstructure:"token"` // 商品源地址中的访问令牌，为空时不提供商品源
+    Title string `mapstructure:"title"` // 商品源标题
+    Brand string `mapstructure:"brand"` // 默认品牌
+}
+
+type I18nConfig struct {
+    DefaultLocale string   `mapstructure:"default_locale"` // 默认语言，商品、类目和广告本身保存的是该语言的内容
+    Locales       []string `mapstructure:"locales"`        // 支持的语言，包含默认语言
+}
+
+type CurrencyConfig struct {
+    Base    string `mapstructure:"base"`    // 基准币种，商品价格和订单金额均以该币种保存
+    Default string `mapstructure:"default"` // 请求未指定币种时的展示币种
+}
+
+type DigitalConfig struct {
+    SigningSecret  string `mapstructure:"signing_secret"`   // 下载链接签名密钥，为空时每次启动随机生成，重启后已发出的链接失效
+    APIURL         string `mapstructure:"api_url"`          // 接口地址，用于生成下载链接的绝对地址
+    LinkTTLMinutes int    `mapstructure:"link_ttl_minutes"` // 下载链接有效期(分钟)
+    DownloadLimit  int    `mapstructure:"download_limit"`   // 每个订单每个文件的下载次数上限
+    MaxFileMB      int    `mapstructure:"max_file_mb"`      // 数字文件大小上限(MB)
+    LocalRoot      string `mapstructure:"local_root"`       // 本地存储时数字文件的存放目录，不对外公开访问
+    S3Bucket       string `mapstructure:"s3_bucket"`        // S3 存储时数字文件使用的私有存储桶，不能与公开文件的存储桶相同
+}
+
 var GlobalConfig Config
 
 func Init() error {
@@ -83,6 +175,64 @@ func Init() error {
     // 只设置相对于 go.mod 的配置路径
     viper.AddConfigPath("config")
     
+    // 库存预警默认值
+    viper.SetDefault("inventory.low_stock_threshold", 10)
+    viper.SetDefault("inventory.sales_window_days", 30)
+    viper.SetDefault("inventory.cover_days", 7)
+    viper.SetDefault("inventory.target_cover_days", 30)
+    viper.SetDefault("inventory.alert_hour", 8)
+
+    // 搜索默认值
+    viper.SetDefault("search.hot_window_hours", 24)
+    viper.SetDefault("search.hot_limit", 10)
+    viper.SetDefault("search.suggest_window_days", 30)
+    viper.SetDefault("search.suggest_limit", 10)
+
+    // 存储默认值
+    viper.SetDefault("storage.driver", "local")
+    viper.SetDefault("storage.local_root", "uploads")
+    viper.SetDefault("storage.base_url", "/uploads")
+    viper.SetDefault("storage.max_upload_mb", 10)
+    viper.SetDefault("storage.thumbnail_sizes", []int{160, 480, 960})
+    viper.SetDefault("storage.orphan_grace_hours", 24)
+    viper.SetDefault("storage.s3.region", "us-east-1")
+
+    // 推荐默认值
+    viper.SetDefault("recommendation.window_days", 180)
+    viper.SetDefault("recommendation.min_orders", 2)
+    viper.SetDefault("recommendation.max_per_product", 20)
+    viper.SetDefault("recommendation.limit", 8)
+    viper.SetDefault("recommendation.recompute_hours", 6)
+
+    // 浏览记录默认值
+    viper.SetDefault("tracking.batch_size", 200)
+    viper.SetDefault("tracking.flush_seconds", 5)
+    viper.SetDefault("tracking.max_buffer", 10000)
+    viper.SetDefault("tracking.recent_limit", 20)
+
+    // SEO 默认值
+    viper.SetDefault("seo.site_url", "http://localhost:8080")
+    viper.SetDefault("seo.product_path", "/products/{slug}")
+    viper.SetDefault("seo.category_path", "/categories/{id}")
+
+    // 商品源默认值
+    viper.SetDefault("feed.title", "Shopify Products")
+
+    // 多语言默认值
+    viper.SetDefault("i18n.default_locale", "zh-CN")
+    viper.SetDefault("i18n.locales", []string{"zh-CN", "en"})
+
+    // 币种默认值
+    viper.SetDefault("currency.base", "CNY")
+    viper.SetDefault("currency.default", "CNY")
+
+    // 数字商品默认值
+    viper.SetDefault("digital.api_url", "http://localhost:8080")
+    viper.SetDefault("digital.link_ttl_minutes", 60)
+    viper.SetDefault("digital.download_limit", 5)
+    viper.SetDefault("digital.max_file_mb", 500)
+    viper.SetDefault("digital.local_root", "./private")
+
     if err := viper.ReadInConfig(); err != nil {
         return fmt.Errorf("failed to read config file: %v\nSearched paths: %v", err, viper.ConfigFileUsed())
     }
@@ -130,6 +280,77 @@ func Init() error {
     fmt.Printf("App ID: %s\n", GlobalConfig.Payment.Alipay.AppID)
     fmt.Printf("Notify URL: %s\n", GlobalConfig.Payment.Alipay.NotifyURL)
 
+    // 打印库存配置
+    fmt.Printf("\n=== Inventory Configuration ===\n")
+    fmt.Printf("Low Stock Threshold: %d\n", GlobalConfig.Inventory.LowStockThreshold)
+    fmt.Printf("Sales Window Days: %d\n", GlobalConfig.Inventory.SalesWindowDays)
+    fmt.Printf("Cover Days: %d\n", GlobalConfig.Inventory.CoverDays)
+    fmt.Printf("Target Cover Days: %d\n", GlobalConfig.Inventory.TargetCoverDays)
+    fmt.Printf("Alert Hour: %d\n", GlobalConfig.Inventory.AlertHour)
+
+    // 打印搜索配置
+    fmt.Printf("\n=== Search Configuration ===\n")
+    fmt.Printf("Hot Window Hours: %d\n", GlobalConfig.Search.HotWindowHours)
+    fmt.Printf("Hot Limit: %d\n", GlobalConfig.Search.HotLimit)
+    fmt.Printf("Suggest Window Days: %d\n", GlobalConfig.Search.SuggestWindowDays)
+    fmt.Printf("Suggest Limit: %d\n", GlobalConfig.Search.SuggestLimit)
+
+    // 打印存储配置
+    fmt.Printf("\n=== Storage Configuration ===\n")
+    fmt.Printf("Driver: %s\n", GlobalConfig.Storage.Driver)
+    fmt.Printf("Local Root: %s\n", GlobalConfig.Storage.LocalRoot)
+    fmt.Printf("Base URL: %s\n", GlobalConfig.Storage.BaseURL)
+    fmt.Printf("Max Upload MB: %d\n", GlobalConfig.Storage.MaxUploadMB)
+    fmt.Printf("Thumbnail Sizes: %v\n", GlobalConfig.Storage.ThumbnailSizes)
+    fmt.Printf("S3 Endpoint: %s\n", GlobalConfig.Storage.S3.Endpoint)
+    fmt.Printf("S3 Bucket: %s\n", GlobalConfig.Storage.S3.Bucket)
+
+    // 打印推荐配置
+    fmt.Printf("\n=== Recommendation Configuration ===\n")
+    fmt.Printf("Window Days: %d\n", GlobalConfig.Recommendation.WindowDays)
+    fmt.Printf("Min Orders: %d\n", GlobalConfig.Recommendation.MinOrders)
+    fmt.Printf("Max Per Product: %d\n", GlobalConfig.Recommendation.MaxPerProduct)
+    fmt.Printf("Limit: %d\n", GlobalConfig.Recommendation.Limit)
+    fmt.Printf("Recompute Hours: %d\n", GlobalConfig.Recommendation.RecomputeHours)
+
+    // 打印浏览记录配置
+    fmt.Printf("\n=== Tracking Configuration ===\n")
+    fmt.Printf("Batch Size: %d\n", GlobalConfig.Tracking.BatchSize)
+    fmt.Printf("Flush Seconds: %d\n", GlobalConfig.Tracking.FlushSeconds)
+    fmt.Printf("Max Buffer: %d\n", GlobalConfig.Tracking.MaxBuffer)
+    fmt.Printf("Recent Limit: %d\n", GlobalConfig.Tracking.RecentLimit)
+
+    // 打印 SEO 配置
+    fmt.Printf("\n=== SEO Configuration ===\n")
+    fmt.Printf("Site URL: %s\n", GlobalConfig.SEO.SiteURL)
+    fmt.Printf("Product Path: %s\n", GlobalConfig.SEO.ProductPath)
+    fmt.Printf("Category Path: %s\n", GlobalConfig.SEO.CategoryPath)
+
+    // 打印商品源配置
+    fmt.Printf("\n=== Feed Configuration ===\n")
+    fmt.Printf("Enabled: %v\n", GlobalConfig.Feed.Token != "")
+    fmt.Printf("Title: %s\n", GlobalConfig.Feed.Title)
+    fmt.Printf("Brand: %s\n", GlobalConfig.Feed.Brand)
+
+    // 打印多语言配置
+    fmt.Printf("\n=== I18n Configuration ===\n")
+    fmt.Printf("Default Locale: %s\n", GlobalConfig.I18n.DefaultLocale)
+    fmt.Printf("Locales: %v\n", GlobalConfig.I18n.Locales)
+
+    // 打印币种配置
+    fmt.Printf("\n=== Currency Configuration ===\n")
+    fmt.Printf("Base: %s\n", GlobalConfig.Currency.Base)
+    fmt.Printf("Default: %s\n", GlobalConfig.Currency.Default)
+
+    // 打印数字商品配置
+    fmt.Printf("\n=== Digital Configuration ===\n")
+    fmt.Printf("API URL: %s\n", GlobalConfig.Digital.APIURL)
+    fmt.Printf("Link TTL Minutes: %d\n", GlobalConfig.Digital.LinkTTLMinutes)
+    fmt.Printf("Download Limit: %d\n", GlobalConfig.Digital.DownloadLimit)
+    fmt.Printf("Max File MB: %d\n", GlobalConfig.Digital.MaxFileMB)
+    fmt.Printf("Local Root: %s\n", GlobalConfig.Digital.LocalRoot)
+    fmt.Printf("S3 Bucket: %s\n", GlobalConfig.Digital.S3Bucket)
+
     fmt.Printf("\n=== Configuration End ===\n\n")
 
 
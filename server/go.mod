@@ -5,6 +5,7 @@ go 1.24.1
 require (
 	github.com/gin-gonic/gin v1.10.0
 	github.com/golang-jwt/jwt/v4 v4.5.1
+	github.com/mozillazg/go-pinyin v0.21.0
 	github.com/shopspring/decimal v1.4.0
 	github.com/spf13/viper v1.20.0
 	golang.org/x/crypto v0.36.0
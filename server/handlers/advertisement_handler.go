@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
@@ -68,7 +69,11 @@ func GetAdvertisement(c *gin.Context) {
 		c.JSON(http.StatusNotFound, response.Error(404, "Advertisement not found"))
 		return
 	}
-	
+
+	translationSvc := c.MustGet("translationService").(*service.TranslationService)
+	translationSvc.LocalizeAdvertisements(requestLocale(c), ad)
+
+	setETag(c, ad.Version)
 	c.JSON(http.StatusOK, response.Success(ad))
 }
 
@@ -80,9 +85,11 @@ func GetAdvertisement(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path int true "广告ID"
 // @Param advertisement body models.Advertisement true "广告更新信息"
+// @Param If-Match header string false "广告版本号(ETag)"
 // @Success 200 {object} response.SuccessResponse{data=models.Advertisement} "更新成功"
 // @Failure 403 {object} response.ErrorResponse "权限不足"
 // @Failure 400 {object} response.ErrorResponse "无效的广告ID"
+// @Failure 409 {object} response.ErrorResponse "版本冲突"
 // @Failure 500 {object} response.ErrorResponse "服务器错误"
 // @Router /admin/advertisements/{id} [put]
 func UpdateAdvertisement(c *gin.Context) {
@@ -106,13 +113,36 @@ func UpdateAdvertisement(c *gin.Context) {
 	}
 	ad.ID = uint(id)
 
+	// If-Match 请求头优先于请求体中的版本号
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+	if version != 0 {
+		ad.Version = version
+	}
+
 	svc := c.MustGet("advertisementService").(*service.AdvertisementService)
 	if err := svc.UpdateAd(&ad); err != nil {
+		if errors.Is(err, service.ErrVersionConflict) {
+			if current, err := svc.GetAd(uint(id)); err == nil {
+				respondVersionConflict(c, current.Version)
+				return
+			}
+		}
 		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, response.Success(ad))
+	updatedAd, err := svc.GetAd(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	setETag(c, updatedAd.Version)
+	c.JSON(http.StatusOK, response.Success(updatedAd))
 }
 
 // DeleteAdvertisement 删除广告
@@ -171,6 +201,7 @@ func ListAdvertisements(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
 		return
 	}
+	localizeAdvertisements(c, ads)
 
 	c.JSON(http.StatusOK, response.Success(gin.H{
 		"items":     ads,
@@ -206,6 +237,7 @@ func GetActiveAdvertisements(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
 		return
 	}
+	localizeAdvertisements(c, ads)
 
 	c.JSON(http.StatusOK, response.Success(ads))
 }
@@ -218,9 +250,11 @@ func GetActiveAdvertisements(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path int true "广告ID"
 // @Param status body struct{ Status string `json:"status" binding:"required"` } true "广告状态"
+// @Param If-Match header string false "广告版本号(ETag)"
 // @Success 200 {object} response.SuccessResponse{data=nil} "更新成功"
 // @Failure 403 {object} response.ErrorResponse "权限不足"
 // @Failure 400 {object} response.ErrorResponse "无效的广告ID"
+// @Failure 409 {object} response.ErrorResponse "版本冲突"
 // @Failure 500 {object} response.ErrorResponse "服务器错误"
 // @Router /admin/advertisements/{id}/status [put]
 func UpdateAdvertisementStatus(c *gin.Context) {
@@ -245,8 +279,20 @@ func UpdateAdvertisementStatus(c *gin.Context) {
 		return
 	}
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
 	svc := c.MustGet("advertisementService").(*service.AdvertisementService)
-	if err := svc.UpdateAdStatus(uint(id), req.Status); err != nil {
+	if err := svc.UpdateAdStatus(uint(id), req.Status, version); err != nil {
+		if errors.Is(err, service.ErrVersionConflict) {
+			if current, err := svc.GetAd(uint(id)); err == nil {
+				respondVersionConflict(c, current.Version)
+				return
+			}
+		}
 		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
 		return
 	}
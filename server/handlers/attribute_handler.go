@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"shopify/handlers/request"
+	"shopify/models"
+	"shopify/pkg/utils/response"
+	"shopify/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// attributeFilters 解析 attr[code]=value 形式的规格属性筛选参数
+// 同一属性的多个值可以逗号分隔或重复传参
+func attributeFilters(c *gin.Context) []service.AttributeFilter {
+	filters := make([]service.AttributeFilter, 0)
+	for key, values := range c.Request.URL.Query() {
+		if !strings.HasPrefix(key, "attr[") || !strings.HasSuffix(key, "]") {
+			continue
+		}
+		code := strings.ToLower(strings.TrimSpace(key[len("attr[") : len(key)-1]))
+		if code == "" {
+			continue
+		}
+		filter := service.AttributeFilter{Code: code}
+		for _, value := range values {
+			for _, part := range strings.Split(value, ",") {
+				if part = strings.TrimSpace(part); part != "" {
+					filter.Values = append(filter.Values, part)
+				}
+			}
+		}
+		if len(filter.Values) > 0 {
+			filters = append(filters, filter)
+		}
+	}
+	// 保证生成的查询语句稳定
+	sort.Slice(filters, func(i, j int) bool {
+		return filters[i].Code < filters[j].Code
+	})
+	return filters
+}
+
+// ListCategoryAttributes 获取类目规格属性
+// @Summary 获取类目规格属性
+// @Description 获取类目可用的规格属性定义，包含从上级类目继承的属性，可用于构建筛选面板
+// @Tags 类目管理
+// @Produce json
+// @Param id path int true "类目ID"
+// @Success 200 {object} response.SuccessResponse{data=[]models.AttributeDefinition} "获取成功"
+// @Failure 400 {object} response.ErrorResponse "无效的类目ID"
+// @Failure 404 {object} response.ErrorResponse "类目未找到"
+// @Router /categories/{id}/attributes [get]
+func ListCategoryAttributes(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid category ID"))
+		return
+	}
+
+	svc := c.MustGet("attributeService").(*service.AttributeService)
+	definitions, err := svc.ListCategoryAttributes(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.Error(404, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(definitions))
+}
+
+// CreateCategoryAttribute 创建类目规格属性(管理员)
+// @Summary 创建类目规格属性
+// @Description 管理员为类目定义规格属性，子类目自动继承；编码在类目的上下级中必须唯一
+// @Tags 类目管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "类目ID"
+// @Param request body request.AttributeRequest true "属性定义"
+// @Success 200 {object} response.SuccessResponse{data=models.AttributeDefinition} "创建成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Router /admin/categories/{id}/attributes [post]
+func CreateCategoryAttribute(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid category ID"))
+		return
+	}
+
+	var req request.AttributeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	definition := attributeFromRequest(&req)
+	definition.CategoryID = uint(id)
+	definition.Code = req.Code
+
+	svc := c.MustGet("attributeService").(*service.AttributeService)
+	if err := svc.CreateAttribute(definition); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(definition))
+}
+
+// UpdateAttribute 更新规格属性(管理员)
+// @Summary 更新规格属性
+// @Description 管理员更新规格属性定义，编码和所属类目不可修改；已被产品使用时不能修改类型或删除在用的可选值
+// @Tags 类目管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "属性ID"
+// @Param request body request.AttributeRequest true "属性定义"
+// @Success 200 {object} response.SuccessResponse{data=models.AttributeDefinition} "更新成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Router /admin/attributes/{id} [put]
+func UpdateAttribute(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid attribute ID"))
+		return
+	}
+
+	var req request.AttributeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	definition := attributeFromRequest(&req)
+	definition.ID = uint(id)
+
+	svc := c.MustGet("attributeService").(*service.AttributeService)
+	if err := svc.UpdateAttribute(definition); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(definition))
+}
+
+// DeleteAttribute 删除规格属性(管理员)
+// @Summary 删除规格属性
+// @Description 管理员删除规格属性定义，所有产品上的该属性值一并删除
+// @Tags 类目管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "属性ID"
+// @Success 200 {object} response.SuccessResponse "删除成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 404 {object} response.ErrorResponse "属性未找到"
+// @Router /admin/attributes/{id} [delete]
+func DeleteAttribute(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid attribute ID"))
+		return
+	}
+
+	svc := c.MustGet("attributeService").(*service.AttributeService)
+	if err := svc.DeleteAttribute(uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, response.Error(404, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
+
+// SaveProductAttributes 保存产品规格属性值(管理员)
+// @Summary 保存产品规格属性
+// @Description 管理员整体保存产品的规格属性值，属性必须属于产品所在类目或其上级类目，未提交的属性将被清空
+// @Tags 产品管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "产品ID"
+// @Param request body request.SaveProductAttributesRequest true "属性值"
+// @Success 200 {object} response.SuccessResponse{data=[]service.ProductSpec} "保存成功，返回规格参数表"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Router /admin/products/{id}/attributes [put]
+func SaveProductAttributes(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+
+	var req request.SaveProductAttributesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	svc := c.MustGet("attributeService").(*service.AttributeService)
+	specs, err := svc.SaveProductAttributes(uint(id), req.Attributes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(specs))
+}
+
+// attributeFromRequest 将请求转换为属性定义
+func attributeFromRequest(req *request.AttributeRequest) *models.AttributeDefinition {
+	filterable := true
+	if req.Filterable != nil {
+		filterable = *req.Filterable
+	}
+	return &models.AttributeDefinition{
+		Name:          req.Name,
+		Type:          req.Type,
+		Unit:          req.Unit,
+		AllowedValues: req.AllowedValues,
+		Filterable:    filterable,
+		SortOrder:     req.SortOrder,
+	}
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"shopify/handlers/request"
+	"shopify/pkg/utils/response"
+	"shopify/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetBundleComponents 获取套装组件(管理员)
+// @Summary 获取套装组件
+// @Description 返回套装包含的组件商品、SKU和每套数量
+// @Tags 套装
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "套装商品ID"
+// @Success 200 {object} response.SuccessResponse{data=[]models.BundleComponent} "获取成功"
+// @Failure 400 {object} response.ErrorResponse "商品不是套装"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Router /admin/products/{id}/components [get]
+func GetBundleComponents(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+
+	svc := c.MustGet("bundleService").(*service.BundleService)
+	components, err := svc.GetComponents(uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(components))
+}
+
+// SaveBundleComponents 保存套装组件(管理员)
+// @Summary 保存套装组件
+// @Description 整体保存套装的组件，普通商品保存后转为套装。组件必须是普通商品，有规格的组件需指定SKU。
+// @Description 套装库存由组件库存推算，下单时扣减组件库存，转为套装前需先将商品自身库存调整为零
+// @Tags 套装
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "商品ID"
+// @Param request body request.SaveBundleComponentsRequest true "组件列表"
+// @Success 200 {object} response.SuccessResponse{data=service.ProductDetail} "保存成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Router /admin/products/{id}/components [put]
+func SaveBundleComponents(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+
+	var req request.SaveBundleComponentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	inputs := make([]service.BundleComponentInput, 0, len(req.Components))
+	for _, component := range req.Components {
+		inputs = append(inputs, service.BundleComponentInput{
+			ProductID: component.ProductID,
+			SKUID:     component.SKUID,
+			Quantity:  component.Quantity,
+		})
+	}
+
+	svc := c.MustGet("bundleService").(*service.BundleService)
+	detail, err := svc.SaveComponents(uint(id), inputs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(detail))
+}
+
+// RemoveBundleComponents 取消套装(管理员)
+// @Summary 取消套装
+// @Description 删除套装的全部组件，商品恢复为库存为零的普通商品，已下单的订单保留组件明细
+// @Tags 套装
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "套装商品ID"
+// @Success 200 {object} response.SuccessResponse "取消成功"
+// @Failure 400 {object} response.ErrorResponse "商品不是套装"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Router /admin/products/{id}/components [delete]
+func RemoveBundleComponents(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+
+	svc := c.MustGet("bundleService").(*service.BundleService)
+	if err := svc.RemoveComponents(uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
+
+// GetBundleRevenueReport 套装收入分摊报表(管理员)
+// @Summary 套装收入分摊报表
+// @Description 统计天数内未取消、未退款订单中各组件商品通过套装售出的数量和分摊收入，套装收入按组件单独售价占比分摊
+// @Tags 套装
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "统计天数，最多365" default(30)
+// @Success 200 {object} response.SuccessResponse{data=[]service.ComponentRevenue} "获取成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Router /admin/analytics/bundle-revenue [get]
+func GetBundleRevenueReport(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+
+	svc := c.MustGet("bundleService").(*service.BundleService)
+	revenues, err := svc.ComponentRevenue(days)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(revenues))
+}
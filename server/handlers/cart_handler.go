@@ -15,6 +15,7 @@ import (
 // @Produce json
 // @Security BearerAuth
 // @Param product_id body uint true "商品ID"
+// @Param sku_id body uint false "SKU ID，有规格的商品必填"
 // @Param quantity body int true "商品数量"
 // @Success 200 {object} response.SuccessResponse{data=nil} "添加成功"
 // @Failure 401 {object} response.ErrorResponse "未授权"
@@ -29,8 +30,9 @@ func AddCartItem(c *gin.Context) {
 	}
 
 	var req struct {
-		ProductID uint `json:"product_id" binding:"required"`
-		Quantity  int  `json:"quantity" binding:"required,min=1"`
+		ProductID uint  `json:"product_id" binding:"required"`
+		SKUID     *uint `json:"sku_id"`
+		Quantity  int   `json:"quantity" binding:"required,min=1"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -39,7 +41,7 @@ func AddCartItem(c *gin.Context) {
 	}
 
 	svc := c.MustGet("cartService").(*service.CartService)
-	if err := svc.AddItem(userID.(uint), req.ProductID, req.Quantity); err != nil {
+	if err := svc.AddItem(userID.(uint), req.ProductID, req.SKUID, req.Quantity); err != nil {
 		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
 		return
 	}
@@ -49,10 +51,11 @@ func AddCartItem(c *gin.Context) {
 
 // ListCartItems 获取购物车列表
 // @Summary 获取购物车列表
-// @Description 获取当前用户的购物车内所有商品
+// @Description 获取当前用户的购物车内所有商品，display_subtotal 为按展示币种换算的小计
 // @Tags 购物车
 // @Produce json
 // @Security BearerAuth
+// @Param currency query string false "展示币种，如 USD，也可通过 X-Currency 请求头指定"
 // @Success 200 {object} response.SuccessResponse{data=[]models.CartItem} "获取成功"
 // @Failure 401 {object} response.ErrorResponse "未授权"
 // @Failure 500 {object} response.ErrorResponse "服务器错误"
@@ -71,6 +74,8 @@ func ListCartItems(c *gin.Context) {
 		return
 	}
 
+	applyCartPrices(c, items)
+
 	c.JSON(http.StatusOK, response.Success(items))
 }
 
@@ -203,10 +208,11 @@ func SelectAllCartItems(c *gin.Context) {
 
 // GetSelectedCartItems 获取选中的购物车项
 // @Summary 获取选中的购物车项
-// @Description 获取当前用户购物车中已选中的所有商品
+// @Description 获取当前用户购物车中已选中的所有商品，display_subtotal 为按展示币种换算的小计
 // @Tags 购物车
 // @Produce json
 // @Security BearerAuth
+// @Param currency query string false "展示币种，如 USD，也可通过 X-Currency 请求头指定"
 // @Success 200 {object} response.SuccessResponse{data=[]models.CartItem} "获取成功"
 // @Failure 401 {object} response.ErrorResponse "未授权"
 // @Failure 500 {object} response.ErrorResponse "服务器错误"
@@ -225,6 +231,8 @@ func GetSelectedCartItems(c *gin.Context) {
 		return
 	}
 
+	applyCartPrices(c, items)
+
 	c.JSON(http.StatusOK, response.Success(items))
 }
 
@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"shopify/handlers/request"
+	"shopify/models"
+	"shopify/pkg/utils/response"
+	"shopify/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCategoryTree 获取类目树
+// @Summary 获取类目树
+// @Description 获取所有启用的类目，按层级嵌套返回
+// @Tags 类目管理
+// @Produce json
+// @Success 200 {object} response.SuccessResponse{data=[]models.Category} "获取成功"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /categories/tree [get]
+func GetCategoryTree(c *gin.Context) {
+	svc := c.MustGet("categoryService").(*service.CategoryService)
+	tree, err := svc.GetTree(true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	translationSvc := c.MustGet("translationService").(*service.TranslationService)
+	translationSvc.LocalizeCategories(requestLocale(c), tree)
+
+	c.JSON(http.StatusOK, response.Success(tree))
+}
+
+// AdminGetCategoryTree 获取完整类目树(管理员)
+// @Summary 获取完整类目树
+// @Description 管理员获取包含停用类目在内的完整类目树
+// @Tags 类目管理
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.SuccessResponse{data=[]models.Category} "获取成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/categories [get]
+func AdminGetCategoryTree(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	svc := c.MustGet("categoryService").(*service.CategoryService)
+	tree, err := svc.GetTree(false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(tree))
+}
+
+// CreateCategory 创建类目(管理员)
+// @Summary 创建类目
+// @Description 管理员创建类目，可指定父类目
+// @Tags 类目管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.CategoryRequest true "类目信息"
+// @Success 200 {object} response.SuccessResponse{data=models.Category} "创建成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Router /admin/categories [post]
+func CreateCategory(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	var req request.CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	category := &models.Category{
+		ParentID:       req.ParentID,
+		Name:           req.Name,
+		Icon:           req.Icon,
+		SortOrder:      req.SortOrder,
+		Active:         req.Active == nil || *req.Active,
+		GoogleCategory: req.GoogleCategory,
+	}
+
+	svc := c.MustGet("categoryService").(*service.CategoryService)
+	if err := svc.CreateCategory(category); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(category))
+}
+
+// UpdateCategory 更新类目(管理员)
+// @Summary 更新类目
+// @Description 管理员更新类目名称、图标、排序和启用状态
+// @Tags 类目管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "类目ID"
+// @Param request body request.CategoryRequest true "类目信息"
+// @Success 200 {object} response.SuccessResponse{data=models.Category} "更新成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Router /admin/categories/{id} [put]
+func UpdateCategory(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid category ID"))
+		return
+	}
+
+	var req request.CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	category := &models.Category{
+		ID:             uint(id),
+		Name:           req.Name,
+		Icon:           req.Icon,
+		SortOrder:      req.SortOrder,
+		Active:         req.Active == nil || *req.Active,
+		GoogleCategory: req.GoogleCategory,
+	}
+
+	svc := c.MustGet("categoryService").(*service.CategoryService)
+	if err := svc.UpdateCategory(category); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	updated, err := svc.GetCategory(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(updated))
+}
+
+// DeleteCategory 删除类目(管理员)
+// @Summary 删除类目
+// @Description 管理员删除类目，存在子类目或关联产品时不允许删除
+// @Tags 类目管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "类目ID"
+// @Success 200 {object} response.SuccessResponse{data=nil} "删除成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 400 {object} response.ErrorResponse "无法删除"
+// @Router /admin/categories/{id} [delete]
+func DeleteCategory(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid category ID"))
+		return
+	}
+
+	svc := c.MustGet("categoryService").(*service.CategoryService)
+	if err := svc.DeleteCategory(uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
+
+// MoveCategory 移动类目(管理员)
+// @Summary 移动类目
+// @Description 管理员将类目及其子树移动到新的父类目下
+// @Tags 类目管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "类目ID"
+// @Param request body request.MoveCategoryRequest true "目标位置"
+// @Success 200 {object} response.SuccessResponse{data=nil} "移动成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Router /admin/categories/{id}/move [put]
+func MoveCategory(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid category ID"))
+		return
+	}
+
+	var req request.MoveCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	svc := c.MustGet("categoryService").(*service.CategoryService)
+	if err := svc.MoveCategory(uint(id), req.ParentID, req.SortOrder); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
+
+// ReorderCategories 重排同级类目(管理员)
+// @Summary 重排同级类目
+// @Description 管理员按给定顺序重排同一父类目下的类目
+// @Tags 类目管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.ReorderCategoriesRequest true "排序信息"
+// @Success 200 {object} response.SuccessResponse{data=nil} "排序成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Router /admin/categories/reorder [put]
+func ReorderCategories(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	var req request.ReorderCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	svc := c.MustGet("categoryService").(*service.CategoryService)
+	if err := svc.ReorderCategories(req.ParentID, req.IDs); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"shopify/handlers/request"
+	"shopify/models"
+	"shopify/pkg/utils/response"
+	"shopify/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// localizeComparison 将对比结果中的商品替换为请求的语言，并填充展示币种价格
+func localizeComparison(c *gin.Context, comparison *service.ProductComparison) {
+	products := make([]*models.Product, len(comparison.Products))
+	for i := range comparison.Products {
+		products[i] = &comparison.Products[i]
+	}
+	localizeProducts(c, products...)
+}
+
+// CompareProducts 商品对比
+// @Summary 商品对比
+// @Description 对比最多4个商品，返回按行归一化的对比矩阵：价格、评分、销量、库存状态、类目、标签和规格属性。
+// @Description 各商品取值不同的行标记 different，价格、评分和销量标记最优值。未传 ids 时登录用户对比已保存的对比列表
+// @Tags 商品对比
+// @Produce json
+// @Param ids query string false "以逗号分隔的商品ID，最多4个"
+// @Param currency query string false "展示币种"
+// @Success 200 {object} response.SuccessResponse{data=service.ProductComparison} "获取成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 404 {object} response.ErrorResponse "商品不存在"
+// @Router /products/compare [get]
+func CompareProducts(c *gin.Context) {
+	ids, err := service.ParseCompareIDs(c.Query("ids"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	userID, loggedIn := c.Get("userID")
+	if len(ids) == 0 && !loggedIn {
+		c.JSON(http.StatusBadRequest, response.Error(400, "ids is required"))
+		return
+	}
+
+	svc := c.MustGet("compareService").(*service.CompareService)
+	var comparison *service.ProductComparison
+	if len(ids) == 0 {
+		comparison, err = svc.CompareSaved(userID.(uint), requestCurrency(c))
+	} else {
+		comparison, err = svc.Compare(ids, requestCurrency(c))
+	}
+	if err == service.ErrCompareListFull {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.Error(404, err.Error()))
+		return
+	}
+
+	localizeComparison(c, comparison)
+	c.JSON(http.StatusOK, response.Success(comparison))
+}
+
+// GetCompareList 获取对比列表
+// @Summary 获取对比列表
+// @Description 返回当前用户保存的对比列表及对比矩阵，已删除或下架的商品不参与对比
+// @Tags 商品对比
+// @Produce json
+// @Security BearerAuth
+// @Param currency query string false "展示币种"
+// @Success 200 {object} response.SuccessResponse{data=service.ProductComparison} "获取成功"
+// @Failure 401 {object} response.ErrorResponse "未授权"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /users/me/compare [get]
+func GetCompareList(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.Error(401, "Unauthorized"))
+		return
+	}
+
+	svc := c.MustGet("compareService").(*service.CompareService)
+	comparison, err := svc.CompareSaved(userID.(uint), requestCurrency(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	localizeComparison(c, comparison)
+	c.JSON(http.StatusOK, response.Success(comparison))
+}
+
+// AddToCompareList 加入对比列表
+// @Summary 加入对比列表
+// @Description 将商品加入当前用户的对比列表，最多4个，已在列表中时直接返回
+// @Tags 商品对比
+// @Produce json
+// @Security BearerAuth
+// @Param productId path int true "商品ID"
+// @Success 200 {object} response.SuccessResponse{data=gin.H{"product_ids":[]int}} "加入成功"
+// @Failure 400 {object} response.ErrorResponse "对比列表已满或商品不存在"
+// @Failure 401 {object} response.ErrorResponse "未授权"
+// @Router /users/me/compare/{productId} [post]
+func AddToCompareList(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.Error(401, "Unauthorized"))
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("productId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+
+	svc := c.MustGet("compareService").(*service.CompareService)
+	ids, err := svc.AddToCompare(userID.(uint), uint(productID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(gin.H{"product_ids": ids}))
+}
+
+// RemoveFromCompareList 移出对比列表
+// @Summary 移出对比列表
+// @Tags 商品对比
+// @Produce json
+// @Security BearerAuth
+// @Param productId path int true "商品ID"
+// @Success 200 {object} response.SuccessResponse{data=gin.H{"product_ids":[]int}} "移除成功"
+// @Failure 401 {object} response.ErrorResponse "未授权"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /users/me/compare/{productId} [delete]
+func RemoveFromCompareList(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.Error(401, "Unauthorized"))
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("productId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+
+	svc := c.MustGet("compareService").(*service.CompareService)
+	ids, err := svc.RemoveFromCompare(userID.(uint), uint(productID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(gin.H{"product_ids": ids}))
+}
+
+// ReplaceCompareList 同步对比列表
+// @Summary 同步对比列表
+// @Description 用提交的商品列表整体替换当前用户的对比列表，用于合并登录前在本地保存的对比商品
+// @Tags 商品对比
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.ReplaceCompareListRequest true "商品ID列表"
+// @Success 200 {object} response.SuccessResponse{data=gin.H{"product_ids":[]int}} "同步成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 401 {object} response.ErrorResponse "未授权"
+// @Router /users/me/compare [put]
+func ReplaceCompareList(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.Error(401, "Unauthorized"))
+		return
+	}
+
+	var req request.ReplaceCompareListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	svc := c.MustGet("compareService").(*service.CompareService)
+	ids, err := svc.ReplaceCompareList(userID.(uint), req.ProductIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(gin.H{"product_ids": ids}))
+}
+
+// ClearCompareList 清空对比列表
+// @Summary 清空对比列表
+// @Tags 商品对比
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.SuccessResponse "清空成功"
+// @Failure 401 {object} response.ErrorResponse "未授权"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /users/me/compare [delete]
+func ClearCompareList(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.Error(401, "Unauthorized"))
+		return
+	}
+
+	svc := c.MustGet("compareService").(*service.CompareService)
+	if err := svc.ClearCompareList(userID.(uint)); err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
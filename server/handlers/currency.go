@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"shopify/models"
+	"shopify/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+// requestCurrency 获取请求的展示币种，依次使用 currency 参数、X-Currency 请求头和默认币种
+func requestCurrency(c *gin.Context) models.Currency {
+	code := c.Query("currency")
+	if code == "" {
+		code = c.GetHeader("X-Currency")
+	}
+	svc := c.MustGet("currencyService").(*service.CurrencyService)
+	return svc.DisplayCurrency(code)
+}
+
+// requestCurrencyCode 获取显式指定的币种代码，未指定时为空
+func requestCurrencyCode(c *gin.Context) string {
+	if code := c.Query("currency"); code != "" {
+		return code
+	}
+	return c.GetHeader("X-Currency")
+}
+
+// priceRange 解析 min_price 和 max_price 参数，按请求的展示币种换算为基准币种，无效的参数视为不限制
+func priceRange(c *gin.Context) (decimal.Decimal, decimal.Decimal) {
+	currency := requestCurrency(c)
+	svc := c.MustGet("currencyService").(*service.CurrencyService)
+	minPrice, _ := decimal.NewFromString(c.Query("min_price"))
+	maxPrice, _ := decimal.NewFromString(c.Query("max_price"))
+	return svc.ToBaseAmount(minPrice, currency), svc.ToBaseAmount(maxPrice, currency)
+}
+
+// applyProductPrices 为响应中的商品填充展示币种价格
+func applyProductPrices(c *gin.Context, products ...*models.Product) {
+	svc := c.MustGet("currencyService").(*service.CurrencyService)
+	svc.ApplyProductPrices(requestCurrency(c), products...)
+}
+
+// applyProductListPrices 为响应中的商品列表填充展示币种价格
+func applyProductListPrices(c *gin.Context, products []models.Product) {
+	svc := c.MustGet("currencyService").(*service.CurrencyService)
+	svc.ApplyProductListPrices(requestCurrency(c), products)
+}
+
+// applyCartPrices 为响应中的购物车项填充展示币种价格
+func applyCartPrices(c *gin.Context, items []models.CartItem) {
+	svc := c.MustGet("currencyService").(*service.CurrencyService)
+	svc.ApplyCartPrices(requestCurrency(c), items)
+}
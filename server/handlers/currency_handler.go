@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"shopify/handlers/request"
+	"shopify/pkg/utils/response"
+	"shopify/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListCurrencies 获取可选币种
+// @Summary 获取可选币种
+// @Description 返回前台可选择的展示币种及汇率，请求通过 currency 参数或 X-Currency 请求头指定展示币种
+// @Tags 币种
+// @Produce json
+// @Success 200 {object} response.SuccessResponse{data=gin.H{"base":string,"default":string,"items":[]models.Currency}} "获取成功"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /currencies [get]
+func ListCurrencies(c *gin.Context) {
+	svc := c.MustGet("currencyService").(*service.CurrencyService)
+	currencies, err := svc.ListCurrencies(true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(gin.H{
+		"base":    service.BaseCurrency(),
+		"default": service.DefaultCurrency(),
+		"items":   currencies,
+	}))
+}
+
+// AdminListCurrencies 获取全部币种(管理员)
+// @Summary 获取全部币种
+// @Description 返回包括已停用币种在内的全部币种
+// @Tags 币种
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.SuccessResponse{data=[]models.Currency} "获取成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/currencies [get]
+func AdminListCurrencies(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	svc := c.MustGet("currencyService").(*service.CurrencyService)
+	currencies, err := svc.ListCurrencies(false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(currencies))
+}
+
+// CreateCurrency 创建币种(管理员)
+// @Summary 创建币种
+// @Description 汇率为 1 基准币种可兑换的该币种金额，展示价格按小数位数和舍入方式(half_up/half_even/ceil/floor)换算
+// @Tags 币种
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.CurrencyRequest true "币种信息"
+// @Success 200 {object} response.SuccessResponse{data=models.Currency} "创建成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 409 {object} response.ErrorResponse "币种已存在"
+// @Router /admin/currencies [post]
+func CreateCurrency(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	var req request.CurrencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	svc := c.MustGet("currencyService").(*service.CurrencyService)
+	currency, err := svc.CreateCurrency(currencyRequest(req))
+	if err != nil {
+		if errors.Is(err, service.ErrCurrencyExists) {
+			c.JSON(http.StatusConflict, response.Error(409, err.Error()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(currency))
+}
+
+// UpdateCurrency 更新币种(管理员)
+// @Summary 更新币种
+// @Description 更新汇率、小数位数、舍入方式和启用状态，立即用于展示价格，已下单的订单保留下单时的汇率快照。基准币种的汇率固定为 1
+// @Tags 币种
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "币种代码，如 USD"
+// @Param request body request.CurrencyRequest true "币种信息"
+// @Success 200 {object} response.SuccessResponse{data=models.Currency} "更新成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 404 {object} response.ErrorResponse "币种不存在"
+// @Router /admin/currencies/{code} [put]
+func UpdateCurrency(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	var req request.CurrencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	svc := c.MustGet("currencyService").(*service.CurrencyService)
+	currency, err := svc.UpdateCurrency(c.Param("code"), currencyRequest(req))
+	if err != nil {
+		if errors.Is(err, service.ErrCurrencyNotFound) {
+			c.JSON(http.StatusNotFound, response.Error(404, err.Error()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(currency))
+}
+
+// currencyRequest 将请求转换为服务层参数，未提交的小数位数和启用状态使用默认值
+func currencyRequest(req request.CurrencyRequest) service.CurrencyRequest {
+	decimals := 2
+	if req.Decimals != nil {
+		decimals = *req.Decimals
+	}
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+	return service.CurrencyRequest{
+		Code:     req.Code,
+		Name:     req.Name,
+		Symbol:   req.Symbol,
+		Rate:     req.Rate,
+		Decimals: decimals,
+		Rounding: req.Rounding,
+		Active:   active,
+	}
+}
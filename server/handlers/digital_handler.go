@@ -0,0 +1,361 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"shopify/handlers/request"
+	"shopify/pkg/utils/response"
+	"shopify/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetDigitalProduct 获取数字商品的文件和授权码库存(管理员)
+// @Summary 获取数字商品内容
+// @Description 返回数字商品的文件列表、授权码总数和可分配数量
+// @Tags 数字商品
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "商品ID"
+// @Success 200 {object} response.SuccessResponse{data=service.DigitalProduct} "获取成功"
+// @Failure 400 {object} response.ErrorResponse "商品不是数字商品"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Router /admin/products/{id}/digital [get]
+func GetDigitalProduct(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+
+	svc := c.MustGet("digitalService").(*service.DigitalService)
+	product, err := svc.GetDigitalProduct(uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(product))
+}
+
+// UploadDigitalFile 上传数字商品文件(管理员)
+// @Summary 上传数字商品文件
+// @Description 上传电子书、软件安装包等文件，普通商品上传后转为数字商品。文件保存在私有存储中，
+// @Description 用户支付成功后通过有效期和次数受限的签名链接下载。转为数字商品前需先将商品库存调整为零
+// @Tags 数字商品
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "商品ID"
+// @Param file formData file true "文件"
+// @Success 200 {object} response.SuccessResponse{data=models.DigitalAsset} "上传成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 413 {object} response.ErrorResponse "文件过大"
+// @Router /admin/products/{id}/digital/files [post]
+func UploadDigitalFile(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+
+	svc := c.MustGet("digitalService").(*service.DigitalService)
+	maxSize := svc.MaxFileSize()
+
+	// 限制请求体大小，避免超大请求占满内存或磁盘
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+(1<<20))
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, response.Error(413, "file too large"))
+			return
+		}
+		c.JSON(http.StatusBadRequest, response.Error(400, "file is required"))
+		return
+	}
+	src, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(src, maxSize+1))
+	src.Close()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	asset, err := svc.UploadAsset(uint(id), header.Filename, data)
+	if err != nil {
+		if err.Error() == "file too large" {
+			c.JSON(http.StatusRequestEntityTooLarge, response.Error(413, err.Error()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(asset))
+}
+
+// DeleteDigitalFile 删除数字商品文件(管理员)
+// @Summary 删除数字商品文件
+// @Description 删除后新订单不再包含该文件，已购买的订单仍可在下载次数内下载
+// @Tags 数字商品
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "商品ID"
+// @Param fileId path int true "文件ID"
+// @Success 200 {object} response.SuccessResponse "删除成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 404 {object} response.ErrorResponse "文件不存在"
+// @Router /admin/products/{id}/digital/files/{fileId} [delete]
+func DeleteDigitalFile(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+	fileID, err := strconv.ParseUint(c.Param("fileId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid file ID"))
+		return
+	}
+
+	svc := c.MustGet("digitalService").(*service.DigitalService)
+	if err := svc.DeleteAsset(uint(id), uint(fileID)); err != nil {
+		c.JSON(http.StatusNotFound, response.Error(404, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
+
+// AddLicenseKeys 导入授权码(管理员)
+// @Summary 导入授权码
+// @Description 向数字商品的授权码池导入授权码，普通商品导入后转为数字商品。
+// @Description 有授权码池的数字商品库存等于可分配的授权码数量，下单时预留，支付成功后发放给用户
+// @Tags 数字商品
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "商品ID"
+// @Param request body request.AddLicenseKeysRequest true "授权码列表"
+// @Success 200 {object} response.SuccessResponse{data=gin.H{"added":int}} "导入成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Router /admin/products/{id}/digital/keys [post]
+func AddLicenseKeys(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+
+	var req request.AddLicenseKeysRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	svc := c.MustGet("digitalService").(*service.DigitalService)
+	added, err := svc.AddLicenseKeys(uint(id), req.Keys)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(gin.H{"added": added}))
+}
+
+// ListLicenseKeys 获取授权码列表(管理员)
+// @Summary 获取授权码列表
+// @Description 分页获取数字商品的授权码，可按状态筛选
+// @Tags 数字商品
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "商品ID"
+// @Param status query string false "状态：available/reserved/delivered/revoked"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} response.SuccessResponse{data=gin.H{"items":[]models.LicenseKey, "total":int, "page":int, "page_size":int}} "获取成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Router /admin/products/{id}/digital/keys [get]
+func ListLicenseKeys(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	svc := c.MustGet("digitalService").(*service.DigitalService)
+	keys, total, err := svc.ListLicenseKeys(uint(id), c.Query("status"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(gin.H{
+		"items":     keys,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}))
+}
+
+// DeleteLicenseKey 删除授权码(管理员)
+// @Summary 删除授权码
+// @Description 只能删除未分配的授权码，已预留或已发放的授权码不能删除
+// @Tags 数字商品
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "商品ID"
+// @Param keyId path int true "授权码ID"
+// @Success 200 {object} response.SuccessResponse "删除成功"
+// @Failure 400 {object} response.ErrorResponse "授权码已分配"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Router /admin/products/{id}/digital/keys/{keyId} [delete]
+func DeleteLicenseKey(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+	keyID, err := strconv.ParseUint(c.Param("keyId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid license key ID"))
+		return
+	}
+
+	svc := c.MustGet("digitalService").(*service.DigitalService)
+	if err := svc.DeleteLicenseKey(uint(id), uint(keyID)); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
+
+// GetOrderDownloads 获取订单的授权码和下载链接
+// @Summary 获取订单的数字商品
+// @Description 返回订单中已发放的授权码，并为每个仍有下载次数的文件生成新的签名下载链接。订单取消或退款后返回空列表
+// @Tags 订单
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "订单ID"
+// @Success 200 {object} response.SuccessResponse{data=service.OrderDigitalContent} "获取成功"
+// @Failure 401 {object} response.ErrorResponse "未授权"
+// @Failure 404 {object} response.ErrorResponse "订单不存在"
+// @Router /orders/{id}/downloads [get]
+func GetOrderDownloads(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.Error(401, "Unauthorized"))
+		return
+	}
+
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid order ID"))
+		return
+	}
+
+	svc := c.MustGet("digitalService").(*service.DigitalService)
+	content, err := svc.GetOrderContent(uint(orderID), userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.Error(404, "Order not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(content))
+}
+
+// DownloadDigitalFile 通过签名链接下载数字文件
+// @Summary 下载数字文件
+// @Description 校验链接签名和有效期，每次下载消耗一次下载次数。链接从订单详情或发放邮件中获取，无需登录
+// @Tags 订单
+// @Produce octet-stream
+// @Param id path int true "下载授权ID"
+// @Param expires query int true "过期时间戳"
+// @Param signature query string true "签名"
+// @Success 200 {file} file "文件内容"
+// @Failure 403 {object} response.ErrorResponse "链接无效或已过期"
+// @Failure 410 {object} response.ErrorResponse "订单已取消或退款"
+// @Failure 429 {object} response.ErrorResponse "下载次数已用完"
+// @Router /downloads/{id} [get]
+func DownloadDigitalFile(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusForbidden, response.Error(403, service.ErrInvalidDownloadLink.Error()))
+		return
+	}
+
+	svc := c.MustGet("digitalService").(*service.DigitalService)
+	file, err := svc.Download(uint(id), c.Query("expires"), c.Query("signature"))
+	switch err {
+	case nil:
+	case service.ErrInvalidDownloadLink:
+		c.JSON(http.StatusForbidden, response.Error(403, err.Error()))
+		return
+	case service.ErrDownloadUnavailable:
+		c.JSON(http.StatusGone, response.Error(410, err.Error()))
+		return
+	case service.ErrDownloadLimitReached:
+		c.JSON(http.StatusTooManyRequests, response.Error(429, err.Error()))
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+	defer file.Body.Close()
+
+	c.Header("Cache-Control", "private, no-store")
+	c.DataFromReader(http.StatusOK, file.Size, file.ContentType, file.Body, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}),
+	})
+}
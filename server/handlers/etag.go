@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"shopify/pkg/utils/response"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setETag 根据版本号设置 ETag 响应头
+func setETag(c *gin.Context, version uint) {
+	c.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// ifMatchVersion 解析 If-Match 请求头中的版本号
+// 未携带或为 * 时返回 0，表示不校验版本
+func ifMatchVersion(c *gin.Context) (uint, error) {
+	value := strings.TrimSpace(c.GetHeader("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+
+	value = strings.TrimPrefix(value, "W/")
+	value = strings.Trim(value, `"`)
+	version, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || version == 0 {
+		return 0, fmt.Errorf("invalid If-Match header")
+	}
+	return uint(version), nil
+}
+
+// respondVersionConflict 返回 409 冲突及当前版本号
+func respondVersionConflict(c *gin.Context, currentVersion uint) {
+	setETag(c, currentVersion)
+	resp := response.Conflict("resource has been modified by another request")
+	resp.Data = gin.H{"current_version": currentVersion}
+	c.JSON(http.StatusConflict, resp)
+}
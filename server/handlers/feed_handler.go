@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"shopify/pkg/utils/response"
+	"shopify/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetGoogleFeed 获取 Google Merchant Center 商品源
+// @Summary 获取 Google 商品源
+// @Description RSS 2.0 格式的商品源，包含上架商品的价格、库存状态、图片、Google 商品类目和 GTIN。有 SKU 的商品每个 SKU 一个条目，商品变化后增量更新
+// @Tags 商品源
+// @Produce xml
+// @Param token path string true "商品源访问令牌"
+// @Success 200 {string} string "google.xml"
+// @Failure 404 {object} response.ErrorResponse "商品源未启用或令牌错误"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /feeds/{token}/google.xml [get]
+func GetGoogleFeed(c *gin.Context) {
+	respondFeed(c, service.FeedFormatGoogle, "application/xml; charset=utf-8")
+}
+
+// GetCSVFeed 获取通用 CSV 商品源
+// @Summary 获取 CSV 商品源
+// @Description 与 Google 商品源内容相同的 CSV，列名使用 Google 商品属性名，附加图片以逗号分隔
+// @Tags 商品源
+// @Produce text/csv
+// @Param token path string true "商品源访问令牌"
+// @Success 200 {string} string "products.csv"
+// @Failure 404 {object} response.ErrorResponse "商品源未启用或令牌错误"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /feeds/{token}/products.csv [get]
+func GetCSVFeed(c *gin.Context) {
+	respondFeed(c, service.FeedFormatCSV, "text/csv; charset=utf-8")
+}
+
+// respondFeed 输出指定格式的商品源
+func respondFeed(c *gin.Context, format, contentType string) {
+	svc := c.MustGet("feedService").(*service.FeedService)
+	data, err := svc.Feed(c.Param("token"), format)
+	if err != nil {
+		if errors.Is(err, service.ErrFeedNotFound) {
+			c.JSON(http.StatusNotFound, response.Error(404, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, data)
+}
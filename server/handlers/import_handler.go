@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"shopify/models"
+	"shopify/pkg/spreadsheet"
+	"shopify/pkg/utils/response"
+	"shopify/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportFileSize 导入文件大小上限
+const maxImportFileSize = 20 << 20
+
+// ImportProducts 批量导入商品(管理员)
+// @Summary 批量导入商品
+// @Description 上传 CSV 或 XLSX 文件创建后台导入任务，按 id、external_id 或 SKU 编码匹配已有商品进行更新，否则新建。
+// @Description 试运行只校验并输出行列错误报告，不写入数据。返回的任务可通过任务详情接口轮询进度
+// @Tags 商品导入导出
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CSV 或 XLSX 文件"
+// @Param source formData string false "文件格式来源：standard 为本系统格式，shopify 为 Shopify 商品导出 CSV" default(standard)
+// @Param dry_run formData bool false "仅校验不写入"
+// @Param create_categories formData bool false "类目不存在时自动创建"
+// @Param fetch_images formData bool false "下载远程图片到文件存储，默认按原地址保存"
+// @Success 200 {object} response.SuccessResponse{data=models.ImportJob} "任务已创建"
+// @Failure 400 {object} response.ErrorResponse "文件无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 413 {object} response.ErrorResponse "文件过大"
+// @Router /admin/products/import [post]
+func ImportProducts(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+	userID, _ := c.Get("userID")
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize+(1<<20))
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, response.Error(413, "file too large"))
+			return
+		}
+		c.JSON(http.StatusBadRequest, response.Error(400, "file is required"))
+		return
+	}
+	if header.Size > maxImportFileSize {
+		c.JSON(http.StatusRequestEntityTooLarge, response.Error(413, "file too large"))
+		return
+	}
+
+	source := c.DefaultPostForm("source", models.ImportSourceStandard)
+	if !service.ValidImportSource(source) {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid source value"))
+		return
+	}
+	dryRun, _ := strconv.ParseBool(c.PostForm("dry_run"))
+	createCategories, _ := strconv.ParseBool(c.PostForm("create_categories"))
+	fetchImages, _ := strconv.ParseBool(c.PostForm("fetch_images"))
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	svc := c.MustGet("importService").(*service.ImportService)
+	job, err := svc.StartImport(source, header.Filename, data, service.ImportOptions{
+		DryRun:           dryRun,
+		CreateCategories: createCategories,
+		FetchImages:      fetchImages,
+	}, userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(job))
+}
+
+// GetImportJob 获取导入任务(管理员)
+// @Summary 获取导入任务
+// @Description 获取导入任务的进度、新建/更新/失败数量以及行列错误报告
+// @Tags 商品导入导出
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "任务ID"
+// @Success 200 {object} response.SuccessResponse{data=models.ImportJob} "获取成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 404 {object} response.ErrorResponse "任务未找到"
+// @Router /admin/products/imports/{id} [get]
+func GetImportJob(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid job ID"))
+		return
+	}
+
+	svc := c.MustGet("importService").(*service.ImportService)
+	job, err := svc.GetImportJob(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.Error(404, "Import job not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(job))
+}
+
+// ListImportJobs 获取导入任务列表(管理员)
+// @Summary 获取导入任务列表
+// @Description 分页获取导入任务，列表不包含错误明细
+// @Tags 商品导入导出
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Success 200 {object} response.SuccessResponse{data=gin.H{"items":[]models.ImportJob, "total":int, "page":int, "page_size":int}} "获取成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/products/imports [get]
+func ListImportJobs(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	svc := c.MustGet("importService").(*service.ImportService)
+	jobs, total, err := svc.ListImportJobs(page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(gin.H{
+		"items":     jobs,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}))
+}
+
+// ExportProducts 导出全部商品(管理员)
+// @Summary 导出全部商品
+// @Description 以导入使用的标准格式导出全部商品及SKU，修改后可直接重新导入
+// @Tags 商品导入导出
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param format query string false "文件格式：csv/xlsx" default(csv)
+// @Success 200 {file} file "导出文件"
+// @Failure 400 {object} response.ErrorResponse "格式无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/products/export [get]
+func ExportProducts(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	format := c.DefaultQuery("format", spreadsheet.FormatCSV)
+	if format != spreadsheet.FormatCSV && format != spreadsheet.FormatXLSX {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid format value"))
+		return
+	}
+
+	svc := c.MustGet("importService").(*service.ImportService)
+	data, err := svc.ExportCatalogue(format)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	filename := "products-" + time.Now().Format("20060102150405") + "." + format
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, spreadsheet.ContentType(format), data)
+}
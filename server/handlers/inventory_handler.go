@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"net/http"
+	"shopify/handlers/request"
+	"shopify/pkg/utils/response"
+	"shopify/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdjustProductStock 人工调整库存(管理员)
+// @Summary 调整商品库存
+// @Description 管理员调整商品库存，必须填写调整原因，调整记录写入库存流水
+// @Tags 库存管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "产品ID"
+// @Param request body request.StockAdjustRequest true "调整信息"
+// @Success 200 {object} response.SuccessResponse{data=models.InventoryMovement} "调整成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/products/{id}/stock/adjust [post]
+func AdjustProductStock(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+
+	var req request.StockAdjustRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	operatorID, _ := c.Get("userID")
+
+	svc := c.MustGet("inventoryService").(*service.InventoryService)
+	movement, err := svc.AdjustStock(uint(id), req.WarehouseID, req.SKUID, req.Delta, req.Reason, operatorID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(movement))
+}
+
+// ListStockMovements 获取商品库存流水(管理员)
+// @Summary 获取商品库存流水
+// @Description 管理员分页查看商品的库存变动记录
+// @Tags 库存管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "产品ID"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Success 200 {object} response.SuccessResponse{data=gin.H{"items":[]models.InventoryMovement, "total":int, "page":int, "page_size":int}} "获取成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 400 {object} response.ErrorResponse "无效的产品ID"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/products/{id}/stock/movements [get]
+func ListStockMovements(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	svc := c.MustGet("inventoryService").(*service.InventoryService)
+	movements, total, err := svc.ListMovements(uint(id), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(gin.H{
+		"items":     movements,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}))
+}
+
+// StockAudit 库存对账报表(管理员)
+// @Summary 库存对账报表
+// @Description 根据库存流水重建各商品库存，并与当前库存对比标记差异
+// @Tags 库存管理
+// @Produce json
+// @Security BearerAuth
+// @Param drift_only query bool false "只返回存在差异的商品"
+// @Success 200 {object} response.SuccessResponse{data=[]repository.StockDrift} "获取成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/inventory/audit [get]
+func StockAudit(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	onlyDrift, _ := strconv.ParseBool(c.DefaultQuery("drift_only", "false"))
+
+	svc := c.MustGet("inventoryService").(*service.InventoryService)
+	drifts, err := svc.StockAudit(onlyDrift)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(drifts))
+}
+
+// ListAtRiskProducts 获取缺货风险商品(管理员)
+// @Summary 获取缺货风险商品
+// @Description 根据低库存阈值和近期销售速度列出存在缺货风险的商品及补货建议
+// @Tags 库存管理
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.SuccessResponse{data=[]service.StockRisk} "获取成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/inventory/at-risk [get]
+func ListAtRiskProducts(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	svc := c.MustGet("stockAlertService").(*service.StockAlertService)
+	risks, err := svc.ListAtRiskProducts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(risks))
+}
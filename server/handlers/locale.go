@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"shopify/models"
+	"shopify/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestLocale 获取请求的内容语言，依次使用 lang 参数、Accept-Language 请求头和默认语言
+// 管理员未指定 lang 参数时使用默认语言，避免编辑时读到翻译后的内容
+func requestLocale(c *gin.Context) string {
+	lang := c.Query("lang")
+	if role, exists := c.Get("userRole"); exists && role.(string) == "admin" && lang == "" {
+		return service.DefaultLocale()
+	}
+
+	loc := service.ResolveLocale(lang, c.GetHeader("Accept-Language"))
+	c.Header("Content-Language", loc)
+	return loc
+}
+
+// localizeProducts 将响应中的商品内容替换为请求的语言，并填充展示币种价格
+func localizeProducts(c *gin.Context, products ...*models.Product) {
+	svc := c.MustGet("translationService").(*service.TranslationService)
+	svc.LocalizeProducts(requestLocale(c), products...)
+	applyProductPrices(c, products...)
+}
+
+// localizeProductList 将响应中的商品列表替换为请求的语言，并填充展示币种价格
+func localizeProductList(c *gin.Context, products []models.Product) {
+	svc := c.MustGet("translationService").(*service.TranslationService)
+	svc.LocalizeProductList(requestLocale(c), products)
+	applyProductListPrices(c, products)
+}
+
+// localizeAdvertisements 将响应中的广告标题替换为请求的语言
+func localizeAdvertisements(c *gin.Context, ads []models.Advertisement) {
+	ptrs := make([]*models.Advertisement, len(ads))
+	for i := range ads {
+		ptrs[i] = &ads[i]
+	}
+	svc := c.MustGet("translationService").(*service.TranslationService)
+	svc.LocalizeAdvertisements(requestLocale(c), ptrs...)
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"shopify/models"
+	"shopify/pkg/utils/response"
+	"shopify/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 单次请求最多上传的文件数
+const maxUploadFiles = 9
+
+// UploadMedia 上传图片
+// @Summary 上传图片
+// @Description 以 multipart/form-data 上传一张或多张图片，校验文件类型和大小，按内容去重并生成多种尺寸的缩略图。
+// @Description 商品、广告、类目图片仅管理员可上传，评价图片和头像登录用户均可上传
+// @Tags 文件管理
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param purpose formData string true "用途：product/review/ad/avatar/category"
+// @Param file formData file true "图片文件，可重复多个"
+// @Success 200 {object} response.SuccessResponse{data=[]models.MediaFile} "上传成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 401 {object} response.ErrorResponse "未授权"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 413 {object} response.ErrorResponse "文件过大"
+// @Failure 415 {object} response.ErrorResponse "不支持的文件类型"
+// @Router /uploads [post]
+func UploadMedia(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.Error(401, "Unauthorized"))
+		return
+	}
+
+	svc := c.MustGet("mediaService").(*service.MediaService)
+	maxSize := svc.MaxUploadSize()
+
+	// 限制整个请求体大小，避免超大请求占满内存或磁盘
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize*maxUploadFiles+(1<<20))
+	form, err := c.MultipartForm()
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, response.Error(413, "request too large"))
+			return
+		}
+		c.JSON(http.StatusBadRequest, response.Error(400, "invalid multipart form"))
+		return
+	}
+
+	purpose := c.PostForm("purpose")
+	valid, adminOnly := service.ValidMediaPurpose(purpose)
+	if !valid {
+		c.JSON(http.StatusBadRequest, response.Error(400, "invalid purpose"))
+		return
+	}
+	if adminOnly {
+		role, exists := c.Get("userRole")
+		if !exists || role.(string) != "admin" {
+			c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+			return
+		}
+	}
+
+	headers := form.File["file"]
+	if len(headers) == 0 {
+		c.JSON(http.StatusBadRequest, response.Error(400, "file is required"))
+		return
+	}
+	if len(headers) > maxUploadFiles {
+		c.JSON(http.StatusBadRequest, response.Error(400, "too many files"))
+		return
+	}
+
+	files := make([]*models.MediaFile, 0, len(headers))
+	for _, header := range headers {
+		if header.Size > maxSize {
+			c.JSON(http.StatusRequestEntityTooLarge, response.Error(413, "file too large: "+header.Filename))
+			return
+		}
+		src, err := header.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(src, maxSize+1))
+		src.Close()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+			return
+		}
+
+		file, err := svc.Upload(data, purpose, userID.(uint))
+		if err != nil {
+			switch err.Error() {
+			case "file too large":
+				c.JSON(http.StatusRequestEntityTooLarge, response.Error(413, "file too large: "+header.Filename))
+			case "unsupported file type":
+				c.JSON(http.StatusUnsupportedMediaType, response.Error(415, "unsupported file type: "+header.Filename))
+			case "empty file", "invalid purpose":
+				c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+			default:
+				c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+			}
+			return
+		}
+		files = append(files, file)
+	}
+
+	c.JSON(http.StatusOK, response.Success(files))
+}
+
+// CleanupOrphanMedia 清理孤儿文件(管理员)
+// @Summary 清理孤儿文件
+// @Description 立即清理超过保留期且未被商品、SKU、评价、广告、头像或类目引用的文件，定时任务每日也会执行
+// @Tags 文件管理
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.SuccessResponse{data=map[string]int} "清理成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/media/cleanup [post]
+func CleanupOrphanMedia(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	svc := c.MustGet("mediaService").(*service.MediaService)
+	removed, err := svc.CleanupOrphans()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(gin.H{"removed": removed}))
+}
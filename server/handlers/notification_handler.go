@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"net/http"
+	"shopify/pkg/utils/response"
+	"shopify/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListNotifications 获取管理员通知列表
+// @Summary 获取管理员通知列表
+// @Description 分页获取管理员站内通知，可只查看未读通知
+// @Tags 通知管理
+// @Produce json
+// @Security BearerAuth
+// @Param unread query bool false "只看未读"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Success 200 {object} response.SuccessResponse{data=gin.H{"items":[]models.AdminNotification, "total":int, "page":int, "page_size":int}} "获取成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/notifications [get]
+func ListNotifications(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	unreadOnly, _ := strconv.ParseBool(c.DefaultQuery("unread", "false"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	svc := c.MustGet("notificationService").(*service.NotificationService)
+	notifications, total, err := svc.ListNotifications(unreadOnly, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(gin.H{
+		"items":     notifications,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}))
+}
+
+// MarkNotificationRead 标记通知为已读
+// @Summary 标记通知为已读
+// @Description 将指定的管理员通知标记为已读
+// @Tags 通知管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "通知ID"
+// @Success 200 {object} response.SuccessResponse{data=nil} "更新成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 400 {object} response.ErrorResponse "无效的通知ID"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/notifications/{id}/read [put]
+func MarkNotificationRead(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid notification ID"))
+		return
+	}
+
+	svc := c.MustGet("notificationService").(*service.NotificationService)
+	if err := svc.MarkRead(uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
+
+// MarkAllNotificationsRead 标记所有通知为已读
+// @Summary 标记所有通知为已读
+// @Description 将所有未读的管理员通知标记为已读
+// @Tags 通知管理
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.SuccessResponse{data=nil} "更新成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/notifications/read-all [put]
+func MarkAllNotificationsRead(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	svc := c.MustGet("notificationService").(*service.NotificationService)
+	if err := svc.MarkAllRead(); err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -14,12 +15,13 @@ import (
 
 // CreateOrder 创建订单
 // @Summary 创建订单
-// @Description 创建一个新的订单，用户选择商品并提供送货地址。
+// @Description 创建一个新的订单，用户选择商品并提供送货地址。订单保存结算币种和下单时的汇率快照，支付仍按基准币种金额
 // @Tags 订单
 // @Produce json
 // @Security BearerAuth
 // @Param order_items body []request.OrderItemRequest true "订单商品列表"
 // @Param address_id body uint true "送货地址ID"
+// @Param currency body string false "结算币种，为空时使用 currency 参数或 X-Currency 请求头"
 // @Success 200 {object} response.SuccessResponse{data=models.Order} "创建成功"
 // @Failure 401 {object} response.ErrorResponse "未授权"
 // @Failure 400 {object} response.ErrorResponse "无效的请求参数"
@@ -40,8 +42,16 @@ func CreateOrder(c *gin.Context) {
 	}
 
 	svc := c.MustGet("orderService").(*service.OrderService)
-	order, err := svc.CreateOrder(userID.(uint), req.OrderItems, req.AddressID)
+	currency := req.Currency
+	if currency == "" {
+		currency = requestCurrencyCode(c)
+	}
+	order, err := svc.CreateOrder(userID.(uint), req.OrderItems, req.AddressID, currency)
 	if err != nil {
+		if errors.Is(err, service.ErrUnsupportedCurrency) {
+			c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
 		return
 	}
@@ -49,6 +59,44 @@ func CreateOrder(c *gin.Context) {
 	c.JSON(http.StatusOK, response.Success(order))
 }
 
+// PreviewOrder 订单预览
+// @Summary 订单预览
+// @Description 按当前价格和汇率计算订单金额，不扣减库存。单价按结算币种的小数位数和舍入规则换算后乘以数量，与实际下单金额一致
+// @Tags 订单
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.PreviewOrderRequest true "订单商品和结算币种"
+// @Success 200 {object} response.SuccessResponse{data=service.OrderPreview} "预览成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效、币种不支持或库存不足"
+// @Failure 401 {object} response.ErrorResponse "未授权"
+// @Router /orders/preview [post]
+func PreviewOrder(c *gin.Context) {
+	if _, exists := c.Get("userID"); !exists {
+		c.JSON(http.StatusUnauthorized, response.Error(401, "Unauthorized"))
+		return
+	}
+
+	var req request.PreviewOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	currency := req.Currency
+	if currency == "" {
+		currency = requestCurrencyCode(c)
+	}
+	svc := c.MustGet("orderService").(*service.OrderService)
+	preview, err := svc.PreviewOrder(req.OrderItems, currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(preview))
+}
+
 // GetOrder 获取订单详情
 // @Summary 获取订单详情
 // @Description 获取指定订单的详细信息。
@@ -82,6 +130,7 @@ func GetOrder(c *gin.Context) {
 		return
 	}
 
+	setETag(c, order.Version)
 	c.JSON(http.StatusOK, response.Success(order))
 }
 
@@ -93,6 +142,8 @@ func GetOrder(c *gin.Context) {
 // @Security BearerAuth
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(10)
+// @Param cursor query string false "游标，携带该参数(首页为空值)时使用游标分页"
+// @Param with_total query bool false "游标分页时是否统计总数"
 // @Success 200 {object} response.SuccessResponse{data=object} "获取成功"
 // @Failure 401 {object} response.ErrorResponse "未授权"
 // @Failure 500 {object} response.ErrorResponse "服务器错误"
@@ -108,6 +159,18 @@ func ListOrders(c *gin.Context) {
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
 
 	svc := c.MustGet("orderService").(*service.OrderService)
+
+	// 游标分页
+	if req, ok := cursorRequest(c); ok {
+		orders, cursorPage, err := svc.ListUserOrdersByCursor(userID.(uint), req)
+		if err != nil {
+			respondListError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, cursorResponse(gin.H{"orders": orders}, req, cursorPage))
+		return
+	}
+
 	orders, total, err := svc.ListUserOrders(userID.(uint), page, pageSize)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
@@ -131,6 +194,8 @@ func ListOrders(c *gin.Context) {
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(10)
 // @Param status query string false "订单状态"
+// @Param cursor query string false "游标，携带该参数(首页为空值)时使用游标分页"
+// @Param with_total query bool false "游标分页时是否统计总数"
 // @Success 200 {object} response.SuccessResponse{data=object} "获取成功"
 // @Failure 403 {object} response.ErrorResponse "权限不足"
 // @Failure 500 {object} response.ErrorResponse "服务器错误"
@@ -147,6 +212,18 @@ func AdminListOrders(c *gin.Context) {
 	status := c.Query("status")
 
 	svc := c.MustGet("orderService").(*service.OrderService)
+
+	// 游标分页
+	if req, ok := cursorRequest(c); ok {
+		orders, cursorPage, err := svc.ListOrdersByStatusCursor(status, req)
+		if err != nil {
+			respondListError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, cursorResponse(gin.H{"orders": orders}, req, cursorPage))
+		return
+	}
+
 	orders, total, err := svc.ListOrdersByStatus(status, page, pageSize)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
@@ -191,6 +268,7 @@ func AdminGetOrder(c *gin.Context) {
 		return
 	}
 
+	setETag(c, order.Version)
 	c.JSON(http.StatusOK, response.Success(order))
 }
 
@@ -202,10 +280,12 @@ func AdminGetOrder(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path int true "订单ID"
 // @Param status body string true "订单状态"
+// @Param If-Match header string false "订单版本号(ETag)"
 // @Success 200 {object} response.SuccessResponse{data=nil} "更新成功"
 // @Failure 403 {object} response.ErrorResponse "权限不足"
 // @Failure 400 {object} response.ErrorResponse "无效的请求参数"
 // @Failure 404 {object} response.ErrorResponse "订单未找到"
+// @Failure 409 {object} response.ErrorResponse "版本冲突"
 // @Failure 500 {object} response.ErrorResponse "服务器错误"
 // @Router /orders/{id}/status [put]
 func UpdateOrderStatus(c *gin.Context) {
@@ -230,8 +310,20 @@ func UpdateOrderStatus(c *gin.Context) {
 		return
 	}
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
 	svc := c.MustGet("orderService").(*service.OrderService)
-	if err := svc.UpdateOrderStatus(uint(orderID), req.Status); err != nil {
+	if err := svc.UpdateOrderStatus(uint(orderID), req.Status, version); err != nil {
+		if errors.Is(err, service.ErrVersionConflict) {
+			if current, err := svc.GetOrderByID(uint(orderID)); err == nil {
+				respondVersionConflict(c, current.Version)
+				return
+			}
+		}
 		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
 		return
 	}
@@ -383,4 +475,50 @@ func AddLogisticsTrace(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, response.Success(trace))
-}
\ No newline at end of file
+}
+// ListShipments 获取订单的全部物流信息
+// @Summary 获取订单的全部物流信息
+// @Description 订单从多个仓库发货时，按发货仓返回每一条物流信息。仅订单所属用户和管理员可查看
+// @Tags 订单
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "订单ID"
+// @Success 200 {object} response.SuccessResponse{data=[]models.Logistics} "获取成功"
+// @Failure 400 {object} response.ErrorResponse "无效的请求参数"
+// @Failure 401 {object} response.ErrorResponse "未授权"
+// @Failure 404 {object} response.ErrorResponse "订单不存在"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /orders/{id}/shipments [get]
+func ListShipments(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.Error(401, "Unauthorized"))
+		return
+	}
+
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid order ID"))
+		return
+	}
+
+	// 管理员可查看任意订单，普通用户只能查看自己的订单
+	ownerID := userID.(uint)
+	if role, exists := c.Get("userRole"); exists && role.(string) == "admin" {
+		ownerID = 0
+	}
+
+	svc := c.MustGet("orderService").(*service.OrderService)
+	if _, err := svc.GetOrder(uint(orderID), ownerID); err != nil {
+		c.JSON(http.StatusNotFound, response.Error(404, "Order not found"))
+		return
+	}
+
+	logistics, err := svc.ListLogistics(uint(orderID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(logistics))
+}
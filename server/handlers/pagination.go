@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"shopify/pkg/utils/response"
+	"shopify/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxCursorPageSize 游标分页每页最大数量
+const maxCursorPageSize = 100
+
+// cursorRequest 解析游标分页参数
+// 请求携带 cursor 参数(首页可为空值)时使用游标分页，返回 false 表示使用页码分页
+func cursorRequest(c *gin.Context) (service.CursorRequest, bool) {
+	cursor, ok := c.GetQuery("cursor")
+	if !ok {
+		return service.CursorRequest{}, false
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > maxCursorPageSize {
+		limit = maxCursorPageSize
+	}
+	withTotal, _ := strconv.ParseBool(c.Query("with_total"))
+
+	return service.CursorRequest{
+		Cursor:    cursor,
+		Limit:     limit,
+		WithTotal: withTotal,
+	}, true
+}
+
+// cursorResponse 构造游标分页响应
+func cursorResponse(data interface{}, req service.CursorRequest, page *service.CursorPage) *response.Response {
+	return response.SuccessWithCursor(data, req.Limit, page.NextCursor, page.HasMore, page.Total)
+}
+
+// respondListError 返回列表查询错误，游标或筛选条件无效时返回 400
+func respondListError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrInvalidCursor) || errors.Is(err, service.ErrInvalidAttributeFilter) {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+	c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+}
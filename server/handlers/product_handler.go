@@ -1,7 +1,8 @@
 package handlers
 
 import (
-	"fmt"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"shopify/pkg/utils/response"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 // CreateProduct 创建产品(管理员)
@@ -46,14 +48,21 @@ func CreateProduct(c *gin.Context) {
         Description: req.Description,
         Price:       req.Price,
         Stock:       req.Stock,
+        LowStockThreshold: req.LowStockThreshold,
+        CategoryID:  req.CategoryID,
         Category:    req.Category,
         Images:      req.Images,
         Tags:        req.Tags,
         Status:      req.Status,
+        Slug:        req.Slug,
     }
 
     svc := c.MustGet("productService").(*service.ProductService)
     if err := svc.CreateProduct(product); err != nil {
+        if errors.Is(err, service.ErrSlugTaken) {
+            c.JSON(http.StatusConflict, response.Conflict(err.Error()))
+            return
+        }
         c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
         return
     }
@@ -63,15 +72,17 @@ func CreateProduct(c *gin.Context) {
 
 // UpdateProduct 更新产品(管理员)
 // @Summary 更新产品信息
-// @Description 由管理员更新现有产品信息
+// @Description 由管理员更新现有产品信息，只修改请求中出现的字段，显式传入空值时清空该字段。库存需通过库存调整接口修改
 // @Tags 产品管理
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "产品ID"
 // @Param product body request.ProductRequest true "产品更新信息"
+// @Param If-Match header string false "产品版本号(ETag)"
 // @Success 200 {object} response.SuccessResponse{data=models.Product} "更新成功"
 // @Failure 403 {object} response.ErrorResponse "权限不足"
 // @Failure 400 {object} response.ErrorResponse "无效的产品ID"
+// @Failure 409 {object} response.ErrorResponse "版本冲突"
 // @Failure 500 {object} response.ErrorResponse "服务器错误"
 // @Router /admin/products/{id} [put]
 func UpdateProduct(c *gin.Context) {
@@ -91,10 +102,31 @@ func UpdateProduct(c *gin.Context) {
     // 使用单独的请求结构体，只包含可更新的字段
     var req request.ProductRequest
 
-    if err := c.ShouldBindJSON(&req); err != nil {
+    if err := c.ShouldBindBodyWith(&req, binding.JSON); err != nil {
+        c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+        return
+    }
+
+    // 记录请求中出现的字段，未出现的字段保持不变
+    var raw map[string]json.RawMessage
+    if err := c.ShouldBindBodyWith(&raw, binding.JSON); err != nil {
         c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
         return
     }
+    fields := make([]string, 0, len(raw))
+    for field := range raw {
+        fields = append(fields, field)
+    }
+
+    // If-Match 请求头优先于请求体中的版本号
+    version, err := ifMatchVersion(c)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+        return
+    }
+    if version == 0 {
+        version = req.Version
+    }
 
     // 创建产品对象，只设置需要更新的字段
     product := &models.Product{
@@ -102,12 +134,15 @@ func UpdateProduct(c *gin.Context) {
         Name:        req.Name,
         Description: req.Description,
         Price:       req.Price,
-        Stock:       req.Stock,
+        LowStockThreshold: req.LowStockThreshold,
         Rating:      req.Rating,
+        CategoryID:  req.CategoryID,
         Category:    req.Category,
         Images:      req.Images,
         Tags:        req.Tags,
         Status:      req.Status,
+        Slug:        req.Slug,
+        Version:     version,
     }
 
     // 确保数组字段不为 nil
@@ -118,8 +153,19 @@ func UpdateProduct(c *gin.Context) {
         product.Tags = make([]string, 0)
     }
 
+    operatorID, _ := c.Get("userID")
     svc := c.MustGet("productService").(*service.ProductService)
-    if err := svc.UpdateProduct(product); err != nil {
+    if err := svc.UpdateProduct(product, fields, operatorID.(uint)); err != nil {
+        if errors.Is(err, service.ErrVersionConflict) {
+            if current, err := svc.GetProduct(uint(id)); err == nil {
+                respondVersionConflict(c, current.Version)
+                return
+            }
+        }
+        if errors.Is(err, service.ErrSlugTaken) {
+            c.JSON(http.StatusConflict, response.Conflict(err.Error()))
+            return
+        }
         c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
         return
     }
@@ -131,6 +177,7 @@ func UpdateProduct(c *gin.Context) {
         return
     }
 
+    setETag(c, updatedProduct.Version)
     c.JSON(http.StatusOK, response.Success(updatedProduct))
 }
 
@@ -171,56 +218,117 @@ func DeleteProduct(c *gin.Context) {
 
 // ListProducts 获取产品列表
 // @Summary 获取产品列表
-// @Description 获取产品列表并支持分页、筛选
+// @Description 获取产品列表并支持分页、筛选和排序，所有筛选条件同时生效
 // @Tags 产品管理
 // @Produce json
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(10)
+// @Param category_id query int false "类目ID，包含所有子类目"
 // @Param category query string false "类别"
-// @Param min_price query float false "最小价格"
-// @Param max_price query float false "最大价格"
-// @Param tags query string false "标签"
+// @Param min_price query number false "最小价格，以展示币种表示"
+// @Param max_price query number false "最大价格，以展示币种表示"
+// @Param currency query string false "展示币种，如 USD，也可通过 X-Currency 请求头指定"
+// @Param tags query string false "标签，多个以逗号分隔，命中任一即可"
 // @Param keyword query string false "搜索关键字"
-// @Success 200 {object} response.SuccessResponse{data=gin.H{"items":[]models.Product, "total":int, "page":int, "page_size":int}} "获取成功"
+// @Param status query string false "产品状态，仅管理员可用，非管理员只返回上架产品"
+// @Param in_stock query bool false "仅返回有库存的产品"
+// @Param attr[code] query string false "规格属性筛选，如 attr[ram]=16GB，多个值以逗号分隔命中任一；数值属性支持 attr[screen]=13..15 区间"
+// @Param sort query string false "排序方式：newest/price_asc/price_desc/sales/rating" default(newest)
+// @Param cursor query string false "游标，携带该参数(首页为空值)时使用游标分页"
+// @Param with_total query bool false "游标分页时是否统计总数"
+// @Success 200 {object} response.SuccessResponse{data=gin.H{"items":[]models.Product, "facets":[]service.AttributeFacet, "total":int, "page":int, "page_size":int}} "获取成功，facets 为规格属性分面统计，游标分页仅首页返回"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
 // @Failure 500 {object} response.ErrorResponse "服务器错误"
 // @Router /products [get]
 func ListProducts(c *gin.Context) {
     page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
     pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
-    fmt.Println(page, pageSize)
-    
+
+    categoryID, _ := strconv.ParseUint(c.Query("category_id"), 10, 32)
     category := c.Query("category")
-    minPrice, _ := strconv.ParseFloat(c.Query("min_price"), 64)
-    maxPrice, _ := strconv.ParseFloat(c.Query("max_price"), 64)
-    tags := c.Query("tags")
-    keyword := c.Query("keyword")
+    // 兼容 /products/category/:category 路径参数，数字视为类目ID
+    if param := c.Param("category"); param != "" {
+        if id, err := strconv.ParseUint(param, 10, 32); err == nil {
+            categoryID = id
+        } else {
+            category = param
+        }
+    }
+    minPrice, maxPrice := priceRange(c)
+    inStock, _ := strconv.ParseBool(c.Query("in_stock"))
+
+    var tags []string
+    for _, tag := range strings.Split(c.Query("tags"), ",") {
+        if tag = strings.TrimSpace(tag); tag != "" {
+            tags = append(tags, tag)
+        }
+    }
+
+    sort := c.DefaultQuery("sort", service.ProductSortNewest)
+    if !service.ValidProductSort(sort) {
+        c.JSON(http.StatusBadRequest, response.Error(400, "Invalid sort value"))
+        return
+    }
+
+    // 非管理员只能查看上架产品
+    status := "active"
+    if role, exists := c.Get("userRole"); exists && role.(string) == "admin" {
+        status = c.Query("status")
+    }
+
+    query := service.ProductQuery{
+        Category:   category,
+        MinPrice:   minPrice,
+        MaxPrice:   maxPrice,
+        Tags:       tags,
+        Keyword:    c.Query("keyword"),
+        Status:     status,
+        InStock:    inStock,
+        Attributes: attributeFilters(c),
+        Sort:       sort,
+        Page:       page,
+        PageSize:   pageSize,
+    }
 
     svc := c.MustGet("productService").(*service.ProductService)
-    var products []models.Product
-    var total int64
-    var err error
-
-    // 根据不同的查询参数调用不同的服务方法
-    if category != "" {
-        products, total, err = svc.ListProductsByCategory(category, page, pageSize)
-    } else if minPrice > 0 || maxPrice > 0 {
-        products, total, err = svc.ListProductsByPriceRange(minPrice, maxPrice, page, pageSize)
-    } else if tags != "" {
-        tagList := strings.Split(tags, ",")
-        products, total, err = svc.ListProductsByTags(tagList, page, pageSize)
-    } else if keyword != "" {
-        products, total, err = svc.SearchProducts(keyword, page, pageSize)
-    } else {
-        products, total, err = svc.ListProducts(page, pageSize)
+
+    // 游标分页
+    if req, ok := cursorRequest(c); ok {
+        products, cursorPage, err := svc.QueryProductsByCursor(uint(categoryID), query, req)
+        if err != nil {
+            respondListError(c, err)
+            return
+        }
+        localizeProductList(c, products)
+        data := gin.H{"items": products}
+        // 分面统计与翻页无关，只在首页返回
+        if req.Cursor == "" {
+            facets, err := svc.AttributeFacets(uint(categoryID), query)
+            if err != nil {
+                respondListError(c, err)
+                return
+            }
+            data["facets"] = facets
+        }
+        c.JSON(http.StatusOK, cursorResponse(data, req, cursorPage))
+        return
     }
 
+    products, total, err := svc.QueryProducts(uint(categoryID), query)
     if err != nil {
-        c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+        respondListError(c, err)
+        return
+    }
+    localizeProductList(c, products)
+    facets, err := svc.AttributeFacets(uint(categoryID), query)
+    if err != nil {
+        respondListError(c, err)
         return
     }
 
     c.JSON(http.StatusOK, response.Success(gin.H{
         "items": products,
+        "facets": facets,
         "total": total,
         "page": page,
         "page_size": pageSize,
@@ -229,11 +337,14 @@ func ListProducts(c *gin.Context) {
 
 // GetProduct 获取产品详情
 // @Summary 获取产品详情
-// @Description 获取单个产品的详细信息
+// @Description 获取单个产品的详细信息，包含规格项、SKU及各规格组合的可售状态。
+// @Description 前台访问会记录一次浏览，匿名访客通过 X-Session-ID 请求头或 sid Cookie 区分
 // @Tags 产品管理
 // @Produce json
 // @Param id path int true "产品ID"
-// @Success 200 {object} response.SuccessResponse{data=models.Product} "获取成功"
+// @Param ref query string false "浏览来源，如 search、related，为空时取 Referer 请求头"
+// @Param X-Session-ID header string false "匿名会话标识"
+// @Success 200 {object} response.SuccessResponse{data=service.ProductDetail} "获取成功"
 // @Failure 400 {object} response.ErrorResponse "无效的产品ID"
 // @Failure 404 {object} response.ErrorResponse "产品未找到"
 // @Failure 500 {object} response.ErrorResponse "服务器错误"
@@ -246,11 +357,86 @@ func GetProduct(c *gin.Context) {
     }
 
     svc := c.MustGet("productService").(*service.ProductService)
-    product, err := svc.GetProduct(uint(id))
+    product, err := svc.GetProductDetail(uint(id))
+    if err != nil {
+        c.JSON(http.StatusNotFound, response.Error(404, "Product not found"))
+        return
+    }
+
+    recordProductView(c, product.ID)
+    localizeProducts(c, product.Product)
+
+    setETag(c, product.Version)
+    c.JSON(http.StatusOK, response.Success(product))
+}
+
+// GetProductBySlug 根据 slug 获取产品详情
+// @Summary 根据 slug 获取产品详情
+// @Description 返回内容与产品详情一致。使用已被替换的旧 slug 访问时返回 301，Location 指向当前 slug
+// @Tags 产品管理
+// @Produce json
+// @Param slug path string true "产品 slug"
+// @Success 200 {object} response.SuccessResponse{data=service.ProductDetail} "获取成功"
+// @Success 301 {object} response.SuccessResponse{data=gin.H{"slug":string,"location":string}} "slug 已变更"
+// @Failure 404 {object} response.ErrorResponse "产品未找到"
+// @Router /products/slug/{slug} [get]
+func GetProductBySlug(c *gin.Context) {
+    svc := c.MustGet("productService").(*service.ProductService)
+    product, currentSlug, err := svc.GetProductBySlug(c.Param("slug"))
     if err != nil {
         c.JSON(http.StatusNotFound, response.Error(404, "Product not found"))
         return
     }
 
+    if currentSlug != "" {
+        location := "/api/v1/products/slug/" + currentSlug
+        c.Header("Location", location)
+        c.JSON(http.StatusMovedPermanently, &response.Response{
+            Code:    http.StatusMovedPermanently,
+            Message: "moved permanently",
+            Data:    gin.H{"slug": currentSlug, "location": location},
+        })
+        return
+    }
+
+    recordProductView(c, product.ID)
+    localizeProducts(c, product.Product)
+
+    setETag(c, product.Version)
     c.JSON(http.StatusOK, response.Success(product))
+}
+
+// ListProductSlugs 获取产品用过的全部 slug(管理员)
+// @Summary 获取产品 slug 历史
+// @Description 返回产品当前和历史使用过的 slug，历史 slug 访问时跳转到当前 slug
+// @Tags 产品管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "产品ID"
+// @Success 200 {object} response.SuccessResponse{data=[]models.ProductSlug} "获取成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 400 {object} response.ErrorResponse "无效的产品ID"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/products/{id}/slugs [get]
+func ListProductSlugs(c *gin.Context) {
+    role, exists := c.Get("userRole")
+    if !exists || role.(string) != "admin" {
+        c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+        return
+    }
+
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+        return
+    }
+
+    svc := c.MustGet("productService").(*service.ProductService)
+    slugs, err := svc.ListProductSlugs(uint(id))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+        return
+    }
+
+    c.JSON(http.StatusOK, response.Success(slugs))
 } 
\ No newline at end of file
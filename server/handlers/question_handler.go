@@ -0,0 +1,438 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"shopify/handlers/request"
+	"shopify/pkg/utils/response"
+	"shopify/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListProductQuestions 获取商品问答列表
+// @Summary 获取商品问答列表
+// @Description 分页获取商品已审核的问题及回答，回答多的问题排在前面，官方回答优先，其次按点赞数排序。登录用户返回是否已点赞
+// @Tags 商品问答
+// @Produce json
+// @Param id path int true "商品ID"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Success 200 {object} response.SuccessResponse{data=gin.H{"items":[]models.ProductQuestion, "total":int, "page":int, "page_size":int}} "获取成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Router /products/{id}/questions [get]
+func ListProductQuestions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	var userID uint
+	if value, exists := c.Get("userID"); exists {
+		userID = value.(uint)
+	}
+
+	svc := c.MustGet("questionService").(*service.QuestionService)
+	questions, total, err := svc.ListProductQuestions(uint(id), userID, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(gin.H{
+		"items":     questions,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}))
+}
+
+// AskQuestion 提问
+// @Summary 商品提问
+// @Description 登录用户对商品提问，审核通过后在前台展示
+// @Tags 商品问答
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "商品ID"
+// @Param request body request.AskQuestionRequest true "问题内容"
+// @Success 200 {object} response.SuccessResponse{data=models.ProductQuestion} "提交成功，等待审核"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 401 {object} response.ErrorResponse "未授权"
+// @Router /products/{id}/questions [post]
+func AskQuestion(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.Error(401, "Unauthorized"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+
+	var req request.AskQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	svc := c.MustGet("questionService").(*service.QuestionService)
+	question, err := svc.AskQuestion(uint(id), userID.(uint), req.Content)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(question))
+}
+
+// AnswerQuestion 回答问题
+// @Summary 回答问题
+// @Description 管理员和购买过该商品且订单已完成的用户可以回答已审核的问题。管理员的回答作为官方回答直接展示，用户的回答需审核
+// @Tags 商品问答
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "问题ID"
+// @Param request body request.AnswerQuestionRequest true "回答内容"
+// @Success 200 {object} response.SuccessResponse{data=models.ProductAnswer} "提交成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 401 {object} response.ErrorResponse "未授权"
+// @Failure 403 {object} response.ErrorResponse "未购买该商品"
+// @Router /questions/{id}/answers [post]
+func AnswerQuestion(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.Error(401, "Unauthorized"))
+		return
+	}
+	role, _ := c.Get("userRole")
+	isAdmin := role == "admin"
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid question ID"))
+		return
+	}
+
+	var req request.AnswerQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	svc := c.MustGet("questionService").(*service.QuestionService)
+	answer, err := svc.AnswerQuestion(uint(id), userID.(uint), isAdmin, req.Content)
+	if err == service.ErrNotVerifiedBuyer {
+		c.JSON(http.StatusForbidden, response.Error(403, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(answer))
+}
+
+// UpvoteAnswer 点赞回答
+// @Summary 点赞回答
+// @Description 每个用户对每个回答只能点赞一次，不能给自己的回答点赞
+// @Tags 商品问答
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "回答ID"
+// @Success 200 {object} response.SuccessResponse{data=models.ProductAnswer} "点赞成功"
+// @Failure 400 {object} response.ErrorResponse "回答不存在"
+// @Failure 401 {object} response.ErrorResponse "未授权"
+// @Failure 409 {object} response.ErrorResponse "已点赞"
+// @Router /answers/{id}/upvote [post]
+func UpvoteAnswer(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.Error(401, "Unauthorized"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid answer ID"))
+		return
+	}
+
+	svc := c.MustGet("questionService").(*service.QuestionService)
+	answer, err := svc.UpvoteAnswer(uint(id), userID.(uint))
+	if err == service.ErrAlreadyVoted {
+		c.JSON(http.StatusConflict, response.Error(409, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(answer))
+}
+
+// RemoveAnswerUpvote 取消点赞
+// @Summary 取消点赞
+// @Description 取消对回答的点赞，未点赞时直接返回成功
+// @Tags 商品问答
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "回答ID"
+// @Success 200 {object} response.SuccessResponse "取消成功"
+// @Failure 400 {object} response.ErrorResponse "回答不存在"
+// @Failure 401 {object} response.ErrorResponse "未授权"
+// @Router /answers/{id}/upvote [delete]
+func RemoveAnswerUpvote(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.Error(401, "Unauthorized"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid answer ID"))
+		return
+	}
+
+	svc := c.MustGet("questionService").(*service.QuestionService)
+	if err := svc.RemoveUpvote(uint(id), userID.(uint)); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
+
+// AdminListQuestions 问题审核队列(管理员)
+// @Summary 问题审核队列
+// @Description 按审核状态分页获取问题，默认返回待审核的问题，先提交的排在前面
+// @Tags 商品问答
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "审核状态：pending/approved/rejected" default(pending)
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} response.SuccessResponse{data=gin.H{"items":[]models.ProductQuestion, "total":int, "page":int, "page_size":int}} "获取成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Router /admin/questions [get]
+func AdminListQuestions(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	svc := c.MustGet("questionService").(*service.QuestionService)
+	questions, total, err := svc.ListQuestionQueue(c.Query("status"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(gin.H{
+		"items":     questions,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}))
+}
+
+// AdminListAnswers 回答审核队列(管理员)
+// @Summary 回答审核队列
+// @Description 按审核状态分页获取回答及其问题，默认返回待审核的回答，先提交的排在前面
+// @Tags 商品问答
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "审核状态：pending/approved/rejected" default(pending)
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} response.SuccessResponse{data=gin.H{"items":[]models.ProductAnswer, "total":int, "page":int, "page_size":int}} "获取成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Router /admin/answers [get]
+func AdminListAnswers(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	svc := c.MustGet("questionService").(*service.QuestionService)
+	answers, total, err := svc.ListAnswerQueue(c.Query("status"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(gin.H{
+		"items":     answers,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}))
+}
+
+// AdminModerateQuestion 审核问题(管理员)
+// @Summary 审核问题
+// @Description 通过后问题在前台展示并可被回答，驳回后不再展示
+// @Tags 商品问答
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "问题ID"
+// @Param request body request.ModerateQARequest true "审核状态"
+// @Success 200 {object} response.SuccessResponse "审核成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Router /admin/questions/{id}/status [put]
+func AdminModerateQuestion(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid question ID"))
+		return
+	}
+
+	var req request.ModerateQARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	svc := c.MustGet("questionService").(*service.QuestionService)
+	if err := svc.ModerateQuestion(uint(id), req.Status); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
+
+// AdminModerateAnswer 审核回答(管理员)
+// @Summary 审核回答
+// @Description 通过后回答在前台展示并计入问题的回答数量，驳回后不再展示
+// @Tags 商品问答
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "回答ID"
+// @Param request body request.ModerateQARequest true "审核状态"
+// @Success 200 {object} response.SuccessResponse "审核成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Router /admin/answers/{id}/status [put]
+func AdminModerateAnswer(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid answer ID"))
+		return
+	}
+
+	var req request.ModerateQARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	svc := c.MustGet("questionService").(*service.QuestionService)
+	if err := svc.ModerateAnswer(uint(id), req.Status); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
+
+// AdminDeleteQuestion 删除问题(管理员)
+// @Summary 删除问题
+// @Description 删除问题及其全部回答
+// @Tags 商品问答
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "问题ID"
+// @Success 200 {object} response.SuccessResponse "删除成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 404 {object} response.ErrorResponse "问题不存在"
+// @Router /admin/questions/{id} [delete]
+func AdminDeleteQuestion(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid question ID"))
+		return
+	}
+
+	svc := c.MustGet("questionService").(*service.QuestionService)
+	if err := svc.DeleteQuestion(uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, response.Error(404, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
+
+// AdminDeleteAnswer 删除回答(管理员)
+// @Summary 删除回答
+// @Tags 商品问答
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "回答ID"
+// @Success 200 {object} response.SuccessResponse "删除成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 404 {object} response.ErrorResponse "回答不存在"
+// @Router /admin/answers/{id} [delete]
+func AdminDeleteAnswer(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid answer ID"))
+		return
+	}
+
+	svc := c.MustGet("questionService").(*service.QuestionService)
+	if err := svc.DeleteAnswer(uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, response.Error(404, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"shopify/handlers/request"
+	"shopify/models"
+	"shopify/pkg/utils/response"
+	"shopify/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetRelatedProducts 获取相关推荐商品
+// @Summary 获取相关推荐商品
+// @Description 依次返回运营置顶、经常一起购买和同类目热销的商品，source 字段标明推荐来源
+// @Tags 商品推荐
+// @Produce json
+// @Param id path int true "商品ID"
+// @Param limit query int false "返回数量，最多20" default(8)
+// @Success 200 {object} response.SuccessResponse{data=[]service.RelatedProduct} "获取成功"
+// @Failure 400 {object} response.ErrorResponse "商品ID无效"
+// @Failure 404 {object} response.ErrorResponse "商品不存在"
+// @Router /products/{id}/related [get]
+func GetRelatedProducts(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "0"))
+
+	svc := c.MustGet("recommendationService").(*service.RecommendationService)
+	products, err := svc.RelatedProducts(uint(id), limit)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.Error(404, err.Error()))
+		return
+	}
+
+	items := make([]*models.Product, len(products))
+	for i := range products {
+		items[i] = &products[i].Product
+	}
+	localizeProducts(c, items...)
+
+	c.JSON(http.StatusOK, response.Success(products))
+}
+
+// GetProductRelations 获取商品推荐配置(管理员)
+// @Summary 获取商品推荐配置
+// @Description 管理员查看商品的人工置顶、屏蔽以及根据订单计算出的一起购买关系
+// @Tags 商品推荐
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "商品ID"
+// @Success 200 {object} response.SuccessResponse{data=service.ProductRelations} "获取成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 404 {object} response.ErrorResponse "商品不存在"
+// @Router /admin/products/{id}/related [get]
+func GetProductRelations(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+
+	svc := c.MustGet("recommendationService").(*service.RecommendationService)
+	relations, err := svc.GetProductRelations(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.Error(404, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(relations))
+}
+
+// SaveProductRelation 置顶或屏蔽关联商品(管理员)
+// @Summary 置顶或屏蔽关联商品
+// @Description 置顶的商品优先出现在相关推荐中，屏蔽的商品不会出现在相关推荐中，同一对商品重复提交时更新其状态
+// @Tags 商品推荐
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "商品ID"
+// @Param relatedId path int true "关联商品ID"
+// @Param request body request.ProductRelationRequest true "关联信息"
+// @Success 200 {object} response.SuccessResponse{data=models.ProductRelation} "保存成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Router /admin/products/{id}/related/{relatedId} [put]
+func SaveProductRelation(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+	relatedID, err := strconv.ParseUint(c.Param("relatedId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid related product ID"))
+		return
+	}
+
+	var req request.ProductRelationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	operatorID, _ := c.Get("userID")
+
+	svc := c.MustGet("recommendationService").(*service.RecommendationService)
+	relation, err := svc.SaveRelation(uint(id), uint(relatedID), req.Status, req.SortOrder, operatorID.(uint))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(relation))
+}
+
+// DeleteProductRelation 取消关联商品的置顶或屏蔽(管理员)
+// @Summary 取消关联商品的置顶或屏蔽
+// @Description 取消后该商品是否出现在相关推荐中由订单计算结果决定
+// @Tags 商品推荐
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "商品ID"
+// @Param relatedId path int true "关联商品ID"
+// @Success 200 {object} response.SuccessResponse{data=nil} "删除成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 404 {object} response.ErrorResponse "关联不存在"
+// @Router /admin/products/{id}/related/{relatedId} [delete]
+func DeleteProductRelation(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+	relatedID, err := strconv.ParseUint(c.Param("relatedId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid related product ID"))
+		return
+	}
+
+	svc := c.MustGet("recommendationService").(*service.RecommendationService)
+	if err := svc.DeleteRelation(uint(id), uint(relatedID)); err != nil {
+		c.JSON(http.StatusNotFound, response.Error(404, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
+
+// RecomputeRecommendations 重新计算一起购买关系(管理员)
+// @Summary 重新计算一起购买关系
+// @Description 立即根据已支付订单重新计算商品共现关系，平时由定时任务定期计算
+// @Tags 商品推荐
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.SuccessResponse{data=nil} "计算完成"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/recommendations/recompute [post]
+func RecomputeRecommendations(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	svc := c.MustGet("recommendationService").(*service.RecommendationService)
+	if err := svc.RecomputeAssociations(); err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
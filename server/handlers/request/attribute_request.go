@@ -0,0 +1,17 @@
+package request
+
+// AttributeRequest 创建/更新规格属性定义请求
+type AttributeRequest struct {
+	Code          string   `json:"code"`                                                   // 属性编码，仅创建时生效，如 ram
+	Name          string   `json:"name" binding:"required,max=50"`                         // 属性名称
+	Type          string   `json:"type" binding:"required,oneof=text number enum boolean"` // 属性类型
+	Unit          string   `json:"unit" binding:"max=20"`                                  // 单位
+	AllowedValues []string `json:"allowed_values"`                                         // 可选值，枚举类型必填
+	Filterable    *bool    `json:"filterable"`                                             // 是否可筛选，为空时默认可筛选
+	SortOrder     int      `json:"sort_order"`                                             // 排序
+}
+
+// SaveProductAttributesRequest 保存产品规格属性值请求
+type SaveProductAttributesRequest struct {
+	Attributes map[string]string `json:"attributes" binding:"required"` // 键为属性编码，值为空表示不填写
+}
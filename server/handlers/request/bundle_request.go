@@ -0,0 +1,13 @@
+package request
+
+// BundleComponentRequest 套装组件
+type BundleComponentRequest struct {
+	ProductID uint  `json:"product_id" binding:"required"`     // 组件商品ID
+	SKUID     *uint `json:"sku_id"`                            // 组件SKU ID，组件商品有规格时必填
+	Quantity  int   `json:"quantity" binding:"required,min=1"` // 每套包含的数量
+}
+
+// SaveBundleComponentsRequest 保存套装组件请求
+type SaveBundleComponentsRequest struct {
+	Components []BundleComponentRequest `json:"components" binding:"required,min=1,dive"`
+}
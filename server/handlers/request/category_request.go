@@ -0,0 +1,23 @@
+package request
+
+// CategoryRequest 创建/更新类目请求
+type CategoryRequest struct {
+	ParentID       *uint  `json:"parent_id"` // 父类目ID，仅创建时生效，修改父类目请使用移动接口
+	Name           string `json:"name" binding:"required,max=50"`
+	Icon           string `json:"icon"`
+	SortOrder      int    `json:"sort_order"`
+	Active         *bool  `json:"active"`                            // 为空时默认启用
+	GoogleCategory string `json:"google_category" binding:"max=255"` // 商品源中使用的 Google 商品类目
+}
+
+// MoveCategoryRequest 移动类目请求
+type MoveCategoryRequest struct {
+	ParentID  *uint `json:"parent_id"` // 新的父类目ID，为空表示移为顶级类目
+	SortOrder int   `json:"sort_order"`
+}
+
+// ReorderCategoriesRequest 同级类目重排序请求
+type ReorderCategoriesRequest struct {
+	ParentID *uint  `json:"parent_id"`                    // 父类目ID，为空表示顶级类目
+	IDs      []uint `json:"ids" binding:"required,min=1"` // 按新顺序排列的类目ID
+}
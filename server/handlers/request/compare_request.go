@@ -0,0 +1,6 @@
+package request
+
+// ReplaceCompareListRequest 同步对比列表请求
+type ReplaceCompareListRequest struct {
+	ProductIDs []uint `json:"product_ids" binding:"max=4"` // 对比的商品ID，最多4个，为空时清空列表
+}
@@ -0,0 +1,14 @@
+package request
+
+import "github.com/shopspring/decimal"
+
+// CurrencyRequest 创建或更新币种请求
+type CurrencyRequest struct {
+	Code     string          `json:"code" binding:"omitempty,len=3"`           // ISO 4217 币种代码，创建时必填，更新时忽略
+	Name     string          `json:"name" binding:"max=50"`                    // 币种名称
+	Symbol   string          `json:"symbol" binding:"max=10"`                  // 货币符号
+	Rate     decimal.Decimal `json:"rate" binding:"required"`                  // 1 基准币种可兑换的该币种金额
+	Decimals *int            `json:"decimals" binding:"omitempty,min=0,max=4"` // 金额保留的小数位数，默认 2
+	Rounding string          `json:"rounding"`                                 // 舍入方式：half_up/half_even/ceil/floor，默认 half_up
+	Active   *bool           `json:"active"`                                   // 是否可供前台选择，默认启用
+}
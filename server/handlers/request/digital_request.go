@@ -0,0 +1,6 @@
+package request
+
+// AddLicenseKeysRequest 导入授权码请求
+type AddLicenseKeysRequest struct {
+	Keys []string `json:"keys" binding:"required,min=1,max=5000"` // 授权码列表，重复或已存在的授权码会被忽略
+}
@@ -0,0 +1,9 @@
+package request
+
+// StockAdjustRequest 人工调整库存请求
+type StockAdjustRequest struct {
+	WarehouseID uint   `json:"warehouse_id"`              // 调整的仓库ID，为空表示不指定仓库
+	SKUID       uint   `json:"sku_id"`                    // 调整的SKU ID，有规格的产品必填
+	Delta       int    `json:"delta" binding:"required"`  // 调整数量，正数为入库，负数为出库
+	Reason      string `json:"reason" binding:"required"` // 调整原因
+}
@@ -8,4 +8,11 @@ import (
 type CreateOrderRequest struct {
 	AddressID     uint               `json:"address_id"`
 	OrderItems    []models.OrderItem `json:"items"`
+	Currency      string             `json:"currency"` // 结算币种，为空时使用请求的展示币种
+}
+
+// PreviewOrderRequest 订单预览请求
+type PreviewOrderRequest struct {
+	OrderItems []models.OrderItem `json:"items" binding:"required"`
+	Currency   string             `json:"currency"` // 结算币种，为空时使用请求的展示币种
 }
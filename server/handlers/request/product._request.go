@@ -9,12 +9,16 @@ type ProductRequest struct {
 	Description string          `json:"description"`
 	Price       decimal.Decimal `json:"price" `
 	Stock       int             `json:"stock" `
+	LowStockThreshold int       `json:"low_stock_threshold"`
 	Sales       int             `json:"sales" `
+	CategoryID  *uint           `json:"category_id"` // 关联的类目ID，更新时未传入保留原类目，传入 null 或 0 时清除类目
 	Category    string          `json:"category" `
 	Rating      float64         `json:"rating" `
 	Images      []string        `json:"images"`
 	Tags        []string        `json:"tags"`
 	Status      string          `json:"status" binding:"omitempty,oneof=active inactive"`
+	Slug        string          `json:"slug" binding:"omitempty,max=100"` // URL 标识，创建时为空则根据名称生成，修改后旧 slug 跳转到新 slug
+	Version     uint            `json:"version"` // 更新时客户端持有的版本号，也可通过 If-Match 请求头传递
 }
 /**
 
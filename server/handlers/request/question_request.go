@@ -0,0 +1,16 @@
+package request
+
+// AskQuestionRequest 商品提问请求
+type AskQuestionRequest struct {
+	Content string `json:"content" binding:"required"` // 问题内容
+}
+
+// AnswerQuestionRequest 回答问题请求
+type AnswerQuestionRequest struct {
+	Content string `json:"content" binding:"required"` // 回答内容
+}
+
+// ModerateQARequest 审核问答请求
+type ModerateQARequest struct {
+	Status string `json:"status" binding:"required,oneof=pending approved rejected"` // 审核状态
+}
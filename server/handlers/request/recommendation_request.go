@@ -0,0 +1,7 @@
+package request
+
+// ProductRelationRequest 置顶或屏蔽关联商品请求
+type ProductRelationRequest struct {
+	Status    string `json:"status" binding:"required,oneof=pinned blocked"`
+	SortOrder int    `json:"sort_order"` // 置顶排序，数值越小越靠前
+}
@@ -0,0 +1,12 @@
+package request
+
+import "time"
+
+// ScheduleRequest 创建商品定时任务请求
+type ScheduleRequest struct {
+	Action   string     `json:"action" binding:"required,oneof=publish unpublish price"` // 动作：publish 上架, unpublish 下架, price 改价
+	Price    *float64   `json:"price"`                                                   // 改价的目标价格，改价时必填
+	SKUID    uint       `json:"sku_id"`                                                  // 改价的SKU ID，有规格的产品必填
+	RunAt    time.Time  `json:"run_at" binding:"required"`                               // 执行时间
+	RevertAt *time.Time `json:"revert_at"`                                               // 恢复时间，为空表示不恢复
+}
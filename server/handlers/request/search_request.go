@@ -0,0 +1,8 @@
+package request
+
+// SearchTermRequest 置顶或屏蔽搜索词请求
+type SearchTermRequest struct {
+	Term      string `json:"term" binding:"required,max=100"`
+	Status    string `json:"status" binding:"required,oneof=pinned blocked"`
+	SortOrder int    `json:"sort_order"` // 置顶排序，数值越小越靠前
+}
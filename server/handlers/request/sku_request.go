@@ -0,0 +1,26 @@
+package request
+
+// ProductOptionRequest 规格项
+type ProductOptionRequest struct {
+	Name      string   `json:"name" binding:"required"`         // 规格名称，如颜色、尺码
+	Values    []string `json:"values" binding:"required,min=1"` // 可选值
+	SortOrder int      `json:"sort_order"`                      // 排序
+}
+
+// SKURequest SKU信息，带ID表示更新已有SKU
+type SKURequest struct {
+	ID      uint              `json:"id"`                            // SKU ID，为空表示新建
+	Code    string            `json:"code" binding:"required"`       // SKU编码
+	Options map[string]string `json:"options" binding:"required"`    // 规格值组合
+	Price   float64           `json:"price" binding:"required,gt=0"` // 价格
+	Stock   int               `json:"stock" binding:"min=0"`         // 初始库存，仅新建SKU时生效
+	Barcode string            `json:"barcode"`                       // 条码
+	Image   string            `json:"image"`                         // 图片
+	Status  string            `json:"status"`                        // 状态：active/inactive
+}
+
+// SaveSKUsRequest 批量保存规格和SKU请求
+type SaveSKUsRequest struct {
+	Options []ProductOptionRequest `json:"options" binding:"dive"`
+	SKUs    []SKURequest           `json:"skus" binding:"dive"`
+}
@@ -0,0 +1,6 @@
+package request
+
+// TranslationRequest 保存某个语言的翻译请求
+type TranslationRequest struct {
+	Fields map[string]string `json:"fields" binding:"required"` // 字段 -> 翻译内容，如 {"name": "T-shirt"}，内容为空时删除该字段的翻译
+}
@@ -0,0 +1,24 @@
+package request
+
+// WarehouseRequest 创建/更新仓库请求
+type WarehouseRequest struct {
+	Code     string   `json:"code" binding:"required"`
+	Name     string   `json:"name" binding:"required"`
+	Province string   `json:"province"`
+	City     string   `json:"city"`
+	Address  string   `json:"address"`
+	Contact  string   `json:"contact"`
+	Phone    string   `json:"phone"`
+	Regions  []string `json:"regions"`
+	Priority int      `json:"priority"`
+	Status   string   `json:"status" binding:"omitempty,oneof=active inactive"`
+}
+
+// StockTransferRequest 仓库间调拨请求
+type StockTransferRequest struct {
+	ProductID       uint   `json:"product_id" binding:"required"`
+	FromWarehouseID uint   `json:"from_warehouse_id"` // 为空表示从未分仓库存调入
+	ToWarehouseID   uint   `json:"to_warehouse_id"`   // 为空表示调回未分仓库存
+	Quantity        int    `json:"quantity" binding:"required,min=1"`
+	Remark          string `json:"remark"`
+}
@@ -63,6 +63,8 @@ func CreateReview(c *gin.Context) {
 // @Param id path int true "商品ID"
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(10)
+// @Param cursor query string false "游标，携带该参数(首页为空值)时使用游标分页"
+// @Param with_total query bool false "游标分页时是否统计总数"
 // @Success 200 {object} response.SuccessResponse{data=gin.H{"reviews":[]models.Review,"total":int,"rating_stats":models.RatingStats}} "获取成功"
 // @Failure 400 {object} response.ErrorResponse "商品ID无效"
 // @Failure 500 {object} response.ErrorResponse "服务器错误"
@@ -78,14 +80,29 @@ func GetProductReviews(c *gin.Context) {
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
 
 	svc := c.MustGet("reviewService").(*service.ReviewService)
-	reviews, total, err := svc.GetProductReviews(uint(productID), page, pageSize)
+
+	// 获取评分统计
+	stats, err := svc.GetProductRatingStats(uint(productID))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
 		return
 	}
 
-	// 获取评分统计
-	stats, err := svc.GetProductRatingStats(uint(productID))
+	// 游标分页
+	if req, ok := cursorRequest(c); ok {
+		reviews, cursorPage, err := svc.GetProductReviewsByCursor(uint(productID), req)
+		if err != nil {
+			respondListError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, cursorResponse(gin.H{
+			"reviews":      reviews,
+			"rating_stats": stats,
+		}, req, cursorPage))
+		return
+	}
+
+	reviews, total, err := svc.GetProductReviews(uint(productID), page, pageSize)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
 		return
@@ -108,6 +125,8 @@ func GetProductReviews(c *gin.Context) {
 // @Security BearerAuth
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(10)
+// @Param cursor query string false "游标，携带该参数(首页为空值)时使用游标分页"
+// @Param with_total query bool false "游标分页时是否统计总数"
 // @Success 200 {object} response.SuccessResponse{data=gin.H{"reviews":[]models.Review,"total":int}} "获取成功"
 // @Failure 401 {object} response.ErrorResponse "未授权"
 // @Failure 500 {object} response.ErrorResponse "服务器错误"
@@ -123,6 +142,18 @@ func GetUserReviews(c *gin.Context) {
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
 
 	svc := c.MustGet("reviewService").(*service.ReviewService)
+
+	// 游标分页
+	if req, ok := cursorRequest(c); ok {
+		reviews, cursorPage, err := svc.GetUserReviewsByCursor(userID.(uint), req)
+		if err != nil {
+			respondListError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, cursorResponse(gin.H{"reviews": reviews}, req, cursorPage))
+		return
+	}
+
 	reviews, total, err := svc.GetUserReviews(userID.(uint), page, pageSize)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"shopify/handlers/request"
+	"shopify/models"
+	"shopify/pkg/utils/response"
+	"shopify/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+// CreateProductSchedule 创建商品定时任务(管理员)
+// @Summary 创建商品定时任务
+// @Description 定时上架、下架或改价，可设置恢复时间，到期后恢复为执行前的值。执行后被人工修改过的不会被恢复
+// @Tags 商品定时任务
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "产品ID"
+// @Param request body request.ScheduleRequest true "定时任务"
+// @Success 200 {object} response.SuccessResponse{data=models.ProductSchedule} "创建成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Router /admin/products/{id}/schedules [post]
+func CreateProductSchedule(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+
+	var req request.ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	operatorID, _ := c.Get("userID")
+
+	schedule := &models.ProductSchedule{
+		ProductID:  uint(id),
+		SKUID:      req.SKUID,
+		Action:     req.Action,
+		RunAt:      req.RunAt,
+		RevertAt:   req.RevertAt,
+		OperatorID: operatorID.(uint),
+	}
+	if req.Price != nil {
+		price := decimal.NewFromFloat(*req.Price)
+		schedule.Price = &price
+	}
+
+	svc := c.MustGet("scheduleService").(*service.ScheduleService)
+	if err := svc.CreateSchedule(schedule); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(schedule))
+}
+
+// ListProductSchedules 获取商品定时任务(管理员)
+// @Summary 获取商品定时任务
+// @Description 按执行时间倒序返回商品的全部定时任务及执行状态
+// @Tags 商品定时任务
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "产品ID"
+// @Success 200 {object} response.SuccessResponse{data=[]models.ProductSchedule} "获取成功"
+// @Failure 400 {object} response.ErrorResponse "无效的产品ID"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/products/{id}/schedules [get]
+func ListProductSchedules(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+
+	svc := c.MustGet("scheduleService").(*service.ScheduleService)
+	schedules, err := svc.ListSchedules(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(schedules))
+}
+
+// CancelProductSchedule 取消商品定时任务(管理员)
+// @Summary 取消商品定时任务
+// @Description 只能取消尚未执行的任务
+// @Tags 商品定时任务
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "产品ID"
+// @Param scheduleId path int true "定时任务ID"
+// @Success 200 {object} response.SuccessResponse "取消成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Router /admin/products/{id}/schedules/{scheduleId} [delete]
+func CancelProductSchedule(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+	scheduleID, err := strconv.ParseUint(c.Param("scheduleId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid schedule ID"))
+		return
+	}
+
+	svc := c.MustGet("scheduleService").(*service.ScheduleService)
+	if err := svc.CancelSchedule(uint(id), uint(scheduleID)); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
+
+// ListPriceHistory 获取商品价格变动历史(管理员)
+// @Summary 获取商品价格变动历史
+// @Description 分页查看商品及其SKU的价格变动记录，包括人工修改、定时改价和到期恢复
+// @Tags 商品定时任务
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "产品ID"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Success 200 {object} response.SuccessResponse{data=gin.H{"items":[]models.PriceHistory, "total":int, "page":int, "page_size":int}} "获取成功"
+// @Failure 400 {object} response.ErrorResponse "无效的产品ID"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/products/{id}/price-history [get]
+func ListPriceHistory(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	svc := c.MustGet("scheduleService").(*service.ScheduleService)
+	history, total, err := svc.ListPriceHistory(uint(id), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(gin.H{
+		"items":     history,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}))
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"shopify/handlers/request"
+	"shopify/pkg/utils/response"
+	"shopify/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchProducts 搜索产品
+// @Summary 搜索产品
+// @Description 全文搜索产品，按相关度、名称权重和销量排序，返回类目、标签、价格区间分面统计及纠错建议
+// @Tags 产品管理
+// @Produce json
+// @Param q query string false "搜索关键字"
+// @Param keyword query string false "搜索关键字，q 为空时使用"
+// @Param category_id query int false "类目ID，包含所有子类目"
+// @Param tags query string false "标签，多个以逗号分隔，命中任一即可"
+// @Param min_price query number false "最小价格，以展示币种表示"
+// @Param max_price query number false "最大价格，以展示币种表示"
+// @Param currency query string false "展示币种，如 USD，也可通过 X-Currency 请求头指定"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Success 200 {object} response.SuccessResponse{data=service.SearchResult} "搜索成功"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /products/search [get]
+func SearchProducts(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	text := c.Query("q")
+	if text == "" {
+		text = c.Query("keyword")
+	}
+	categoryID, _ := strconv.ParseUint(c.Query("category_id"), 10, 32)
+	minPrice, maxPrice := priceRange(c)
+
+	var tags []string
+	for _, tag := range strings.Split(c.Query("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	query := service.SearchQuery{
+		Text:     text,
+		Tags:     tags,
+		MinPrice: minPrice.InexactFloat64(),
+		MaxPrice: maxPrice.InexactFloat64(),
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	// 登录用户记录搜索者
+	var userID *uint
+	if id, exists := c.Get("userID"); exists {
+		uid := id.(uint)
+		userID = &uid
+	}
+
+	svc := c.MustGet("searchService").(*service.SearchService)
+	result, err := svc.Search(uint(categoryID), query, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+	localizeProductList(c, result.Items)
+
+	c.JSON(http.StatusOK, response.Success(result))
+}
+
+// SearchSuggest 搜索联想
+// @Summary 搜索联想
+// @Description 根据输入前缀返回联想词，依次来自运营置顶词、热门搜索词和商品名称
+// @Tags 搜索
+// @Produce json
+// @Param q query string true "输入前缀"
+// @Success 200 {object} response.SuccessResponse{data=[]string} "获取成功"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /search/suggest [get]
+func SearchSuggest(c *gin.Context) {
+	svc := c.MustGet("searchService").(*service.SearchService)
+	suggestions, err := svc.Suggest(c.Query("q"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(suggestions))
+}
+
+// GetHotSearches 获取热搜词
+// @Summary 获取热搜词
+// @Description 返回滑动时间窗口内搜索次数最多的词，运营置顶词排在最前，屏蔽词不返回
+// @Tags 搜索
+// @Produce json
+// @Success 200 {object} response.SuccessResponse{data=[]service.HotTerm} "获取成功"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /search/hot [get]
+func GetHotSearches(c *gin.Context) {
+	svc := c.MustGet("searchService").(*service.SearchService)
+	terms, err := svc.HotTerms()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(terms))
+}
+
+// ListSearchTerms 获取置顶和屏蔽的搜索词(管理员)
+// @Summary 获取运营搜索词
+// @Description 管理员查看置顶和屏蔽的搜索词
+// @Tags 搜索
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "状态：pinned/blocked"
+// @Success 200 {object} response.SuccessResponse{data=[]models.SearchTerm} "获取成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/search/terms [get]
+func ListSearchTerms(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	svc := c.MustGet("searchService").(*service.SearchService)
+	terms, err := svc.ListTerms(c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(terms))
+}
+
+// SaveSearchTerm 置顶或屏蔽搜索词(管理员)
+// @Summary 置顶或屏蔽搜索词
+// @Description 管理员置顶或屏蔽搜索词，同一个词重复提交时更新其状态
+// @Tags 搜索
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.SearchTermRequest true "搜索词信息"
+// @Success 200 {object} response.SuccessResponse{data=models.SearchTerm} "保存成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Router /admin/search/terms [post]
+func SaveSearchTerm(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	var req request.SearchTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	svc := c.MustGet("searchService").(*service.SearchService)
+	term, err := svc.SaveTerm(req.Term, req.Status, req.SortOrder)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(term))
+}
+
+// DeleteSearchTerm 取消置顶或屏蔽(管理员)
+// @Summary 删除运营搜索词
+// @Description 管理员取消搜索词的置顶或屏蔽
+// @Tags 搜索
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "搜索词ID"
+// @Success 200 {object} response.SuccessResponse{data=nil} "删除成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 404 {object} response.ErrorResponse "搜索词不存在"
+// @Router /admin/search/terms/{id} [delete]
+func DeleteSearchTerm(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid term ID"))
+		return
+	}
+
+	svc := c.MustGet("searchService").(*service.SearchService)
+	if err := svc.DeleteTerm(uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, response.Error(404, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
+
+// ListZeroResultSearches 无结果搜索报表(管理员)
+// @Summary 无结果搜索报表
+// @Description 管理员查看最近一段时间内没有结果的搜索词，按搜索次数排序，用于补充商品
+// @Tags 搜索
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "统计天数" default(7)
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Success 200 {object} response.SuccessResponse{data=gin.H{"items":[]repository.QueryCount, "total":int, "page":int, "page_size":int}} "获取成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/search/zero-results [get]
+func ListZeroResultSearches(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "7"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	svc := c.MustGet("searchService").(*service.SearchService)
+	items, total, err := svc.ListZeroResultQueries(days, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(gin.H{
+		"items":     items,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}))
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"shopify/pkg/utils/response"
+	"shopify/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSitemap 获取站点地图
+// @Summary 获取站点地图
+// @Description 包含首页、启用的类目和上架的商品，商品或类目变化后重新生成。地址数超过 50000 时返回站点地图索引
+// @Tags SEO
+// @Produce xml
+// @Success 200 {string} string "sitemap.xml"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /sitemap.xml [get]
+func GetSitemap(c *gin.Context) {
+	respondSitemap(c, 0)
+}
+
+// GetSitemapPage 获取拆分后的站点地图分页
+// @Summary 获取站点地图分页
+// @Description 站点地图拆分后由索引引用的分页，页码从 1 开始
+// @Tags SEO
+// @Produce xml
+// @Param page path string true "分页文件名，如 1.xml"
+// @Success 200 {string} string "sitemap"
+// @Failure 404 {object} response.ErrorResponse "分页不存在"
+// @Router /sitemap/{page} [get]
+func GetSitemapPage(c *gin.Context) {
+	page, err := strconv.Atoi(strings.TrimSuffix(c.Param("page"), ".xml"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusNotFound, response.Error(404, "sitemap not found"))
+		return
+	}
+	respondSitemap(c, page)
+}
+
+// respondSitemap 输出指定页的站点地图
+func respondSitemap(c *gin.Context, page int) {
+	svc := c.MustGet("sitemapService").(*service.SitemapService)
+	data, err := svc.Sitemap(page)
+	if err != nil {
+		if errors.Is(err, service.ErrSitemapNotFound) {
+			c.JSON(http.StatusNotFound, response.Error(404, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", data)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"shopify/handlers/request"
+	"shopify/models"
+	"shopify/pkg/utils/response"
+	"shopify/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+// SaveProductSKUs 批量编辑商品规格和SKU(管理员)
+// @Summary 批量编辑商品SKU
+// @Description 管理员整体保存商品的规格项和SKU列表，未出现在列表中的SKU将被删除，新建SKU的初始库存写入库存流水
+// @Tags 产品管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "产品ID"
+// @Param request body request.SaveSKUsRequest true "规格和SKU信息"
+// @Success 200 {object} response.SuccessResponse{data=service.ProductDetail} "保存成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/products/{id}/skus [put]
+func SaveProductSKUs(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+
+	var req request.SaveSKUsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	options := make([]models.ProductOption, 0, len(req.Options))
+	for _, option := range req.Options {
+		options = append(options, models.ProductOption{
+			Name:      option.Name,
+			Values:    option.Values,
+			SortOrder: option.SortOrder,
+		})
+	}
+	skus := make([]models.SKU, 0, len(req.SKUs))
+	for _, sku := range req.SKUs {
+		skus = append(skus, models.SKU{
+			ID:      sku.ID,
+			Code:    sku.Code,
+			Options: sku.Options,
+			Price:   decimal.NewFromFloat(sku.Price),
+			Stock:   sku.Stock,
+			Barcode: sku.Barcode,
+			Image:   sku.Image,
+			Status:  sku.Status,
+		})
+	}
+
+	operatorID, _ := c.Get("userID")
+
+	svc := c.MustGet("skuService").(*service.SKUService)
+	detail, err := svc.SaveSKUs(uint(id), options, skus, operatorID.(uint))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(detail))
+}
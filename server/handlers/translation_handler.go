@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"shopify/handlers/request"
+	"shopify/models"
+	"shopify/pkg/utils/response"
+	"shopify/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetProductTranslations 获取商品翻译(管理员)
+// @Summary 获取商品翻译
+// @Description 返回商品名称和描述在各语言下的翻译，默认语言的内容保存在商品本身
+// @Tags 多语言
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "商品ID"
+// @Success 200 {object} response.SuccessResponse{data=service.EntityTranslations} "获取成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 404 {object} response.ErrorResponse "商品不存在"
+// @Router /admin/products/{id}/translations [get]
+func GetProductTranslations(c *gin.Context) {
+	getTranslations(c, models.TranslationEntityProduct)
+}
+
+// SaveProductTranslations 保存商品翻译(管理员)
+// @Summary 保存商品翻译
+// @Description 保存商品在某个语言下的名称(name)和描述(description)，内容为空的字段删除翻译，未提交的字段保持不变
+// @Tags 多语言
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "商品ID"
+// @Param locale path string true "语言，如 en"
+// @Param request body request.TranslationRequest true "翻译内容"
+// @Success 200 {object} response.SuccessResponse{data=service.EntityTranslations} "保存成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效或语言不支持"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 404 {object} response.ErrorResponse "商品不存在"
+// @Router /admin/products/{id}/translations/{locale} [put]
+func SaveProductTranslations(c *gin.Context) {
+	saveTranslations(c, models.TranslationEntityProduct)
+}
+
+// DeleteProductTranslations 删除商品翻译(管理员)
+// @Summary 删除商品翻译
+// @Description 删除商品在某个语言下的全部翻译，删除后该语言显示默认语言的内容
+// @Tags 多语言
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "商品ID"
+// @Param locale path string true "语言，如 en"
+// @Success 200 {object} response.SuccessResponse "删除成功"
+// @Failure 400 {object} response.ErrorResponse "语言不支持"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 404 {object} response.ErrorResponse "商品不存在"
+// @Router /admin/products/{id}/translations/{locale} [delete]
+func DeleteProductTranslations(c *gin.Context) {
+	deleteTranslations(c, models.TranslationEntityProduct)
+}
+
+// GetCategoryTranslations 获取类目翻译(管理员)
+// @Summary 获取类目翻译
+// @Description 返回类目名称在各语言下的翻译
+// @Tags 多语言
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "类目ID"
+// @Success 200 {object} response.SuccessResponse{data=service.EntityTranslations} "获取成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 404 {object} response.ErrorResponse "类目不存在"
+// @Router /admin/categories/{id}/translations [get]
+func GetCategoryTranslations(c *gin.Context) {
+	getTranslations(c, models.TranslationEntityCategory)
+}
+
+// SaveCategoryTranslations 保存类目翻译(管理员)
+// @Summary 保存类目翻译
+// @Description 保存类目在某个语言下的名称(name)，内容为空时删除翻译
+// @Tags 多语言
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "类目ID"
+// @Param locale path string true "语言，如 en"
+// @Param request body request.TranslationRequest true "翻译内容"
+// @Success 200 {object} response.SuccessResponse{data=service.EntityTranslations} "保存成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效或语言不支持"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 404 {object} response.ErrorResponse "类目不存在"
+// @Router /admin/categories/{id}/translations/{locale} [put]
+func SaveCategoryTranslations(c *gin.Context) {
+	saveTranslations(c, models.TranslationEntityCategory)
+}
+
+// DeleteCategoryTranslations 删除类目翻译(管理员)
+// @Summary 删除类目翻译
+// @Description 删除类目在某个语言下的翻译
+// @Tags 多语言
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "类目ID"
+// @Param locale path string true "语言，如 en"
+// @Success 200 {object} response.SuccessResponse "删除成功"
+// @Failure 400 {object} response.ErrorResponse "语言不支持"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 404 {object} response.ErrorResponse "类目不存在"
+// @Router /admin/categories/{id}/translations/{locale} [delete]
+func DeleteCategoryTranslations(c *gin.Context) {
+	deleteTranslations(c, models.TranslationEntityCategory)
+}
+
+// GetAdvertisementTranslations 获取广告翻译(管理员)
+// @Summary 获取广告翻译
+// @Description 返回广告标题在各语言下的翻译
+// @Tags 多语言
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "广告ID"
+// @Success 200 {object} response.SuccessResponse{data=service.EntityTranslations} "获取成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 404 {object} response.ErrorResponse "广告不存在"
+// @Router /admin/advertisements/{id}/translations [get]
+func GetAdvertisementTranslations(c *gin.Context) {
+	getTranslations(c, models.TranslationEntityAdvertisement)
+}
+
+// SaveAdvertisementTranslations 保存广告翻译(管理员)
+// @Summary 保存广告翻译
+// @Description 保存广告在某个语言下的标题(title)，内容为空时删除翻译
+// @Tags 多语言
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "广告ID"
+// @Param locale path string true "语言，如 en"
+// @Param request body request.TranslationRequest true "翻译内容"
+// @Success 200 {object} response.SuccessResponse{data=service.EntityTranslations} "保存成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效或语言不支持"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 404 {object} response.ErrorResponse "广告不存在"
+// @Router /admin/advertisements/{id}/translations/{locale} [put]
+func SaveAdvertisementTranslations(c *gin.Context) {
+	saveTranslations(c, models.TranslationEntityAdvertisement)
+}
+
+// DeleteAdvertisementTranslations 删除广告翻译(管理员)
+// @Summary 删除广告翻译
+// @Description 删除广告在某个语言下的翻译
+// @Tags 多语言
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "广告ID"
+// @Param locale path string true "语言，如 en"
+// @Success 200 {object} response.SuccessResponse "删除成功"
+// @Failure 400 {object} response.ErrorResponse "语言不支持"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 404 {object} response.ErrorResponse "广告不存在"
+// @Router /admin/advertisements/{id}/translations/{locale} [delete]
+func DeleteAdvertisementTranslations(c *gin.Context) {
+	deleteTranslations(c, models.TranslationEntityAdvertisement)
+}
+
+// translationTarget 校验管理员权限并解析要翻译的对象ID
+func translationTarget(c *gin.Context) (uint, bool) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return 0, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondTranslationError 翻译对象不存在时返回 404，其他错误为参数错误
+func respondTranslationError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrTranslationTargetNotFound) {
+		c.JSON(http.StatusNotFound, response.Error(404, err.Error()))
+		return
+	}
+	c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+}
+
+func getTranslations(c *gin.Context, entityType string) {
+	id, ok := translationTarget(c)
+	if !ok {
+		return
+	}
+
+	svc := c.MustGet("translationService").(*service.TranslationService)
+	translations, err := svc.GetTranslations(entityType, id)
+	if err != nil {
+		respondTranslationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(translations))
+}
+
+func saveTranslations(c *gin.Context, entityType string) {
+	id, ok := translationTarget(c)
+	if !ok {
+		return
+	}
+
+	var req request.TranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	svc := c.MustGet("translationService").(*service.TranslationService)
+	translations, err := svc.SaveTranslations(entityType, id, c.Param("locale"), req.Fields)
+	if err != nil {
+		respondTranslationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(translations))
+}
+
+func deleteTranslations(c *gin.Context, entityType string) {
+	id, ok := translationTarget(c)
+	if !ok {
+		return
+	}
+
+	svc := c.MustGet("translationService").(*service.TranslationService)
+	if err := svc.DeleteTranslations(entityType, id, c.Param("locale")); err != nil {
+		respondTranslationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+
+	"shopify/models"
+	"shopify/pkg/utils/response"
+	"shopify/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// viewSessionCookie 匿名访客会话 Cookie 名称
+const viewSessionCookie = "sid"
+
+// viewSessionID 获取匿名访客的会话标识，没有时生成并写入 Cookie
+func viewSessionID(c *gin.Context) string {
+	if sessionID := c.GetHeader("X-Session-ID"); sessionID != "" {
+		return sessionID
+	}
+	if sessionID, err := c.Cookie(viewSessionCookie); err == nil && sessionID != "" {
+		return sessionID
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	sessionID := hex.EncodeToString(buf)
+	c.SetCookie(viewSessionCookie, sessionID, 365*24*3600, "/", "", false, true)
+	return sessionID
+}
+
+// recordProductView 记录前台商品浏览，管理员查看不计入
+func recordProductView(c *gin.Context, productID uint) {
+	if role, exists := c.Get("userRole"); exists && role.(string) == "admin" {
+		return
+	}
+
+	view := models.ProductView{
+		ProductID: productID,
+		SessionID: viewSessionID(c),
+		Referrer:  c.Query("ref"),
+	}
+	if view.Referrer == "" {
+		view.Referrer = c.Request.Referer()
+	}
+	if id, exists := c.Get("userID"); exists {
+		uid := id.(uint)
+		view.UserID = &uid
+	}
+
+	svc := c.MustGet("viewService").(*service.ViewService)
+	svc.RecordView(view)
+}
+
+// GetRecentlyViewed 获取最近浏览的商品
+// @Summary 获取最近浏览的商品
+// @Description 按最近浏览时间倒序返回当前用户浏览过的商品，同一商品只返回一次
+// @Tags 用户相关
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "返回数量" default(20)
+// @Success 200 {object} response.SuccessResponse{data=[]service.RecentlyViewedProduct} "获取成功"
+// @Failure 401 {object} response.ErrorResponse "未授权"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /users/me/recently-viewed [get]
+func GetRecentlyViewed(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "0"))
+
+	svc := c.MustGet("viewService").(*service.ViewService)
+	products, err := svc.RecentlyViewed(userID.(uint), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	items := make([]*models.Product, len(products))
+	for i := range products {
+		items[i] = &products[i].Product
+	}
+	localizeProducts(c, items...)
+
+	c.JSON(http.StatusOK, response.Success(products))
+}
+
+// GetProductViewReport 获取商品浏览报表(管理员)
+// @Summary 获取商品浏览报表
+// @Description 返回商品在统计天数内的浏览次数、独立访客数、按天趋势以及登录用户浏览→加购→下单的转化漏斗
+// @Tags 浏览统计
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "商品ID"
+// @Param days query int false "统计天数，最多365" default(30)
+// @Success 200 {object} response.SuccessResponse{data=service.ProductViewReport} "获取成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Router /admin/products/{id}/views [get]
+func GetProductViewReport(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+
+	svc := c.MustGet("viewService").(*service.ViewService)
+	report, err := svc.ProductViewReport(uint(id), days)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(report))
+}
+
+// ListTopViewedProducts 商品浏览排行(管理员)
+// @Summary 商品浏览排行
+// @Description 按浏览次数倒序分页返回统计天数内各商品的浏览次数和独立访客数
+// @Tags 浏览统计
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "统计天数，最多365" default(7)
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Success 200 {object} response.SuccessResponse{data=gin.H{"items":[]service.ProductViewStats, "total":int, "page":int, "page_size":int}} "获取成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Router /admin/analytics/views [get]
+func ListTopViewedProducts(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "7"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	svc := c.MustGet("viewService").(*service.ViewService)
+	stats, total, err := svc.ListTopViewed(days, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(gin.H{
+		"items":     stats,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}))
+}
+
+// GetConversionFunnel 浏览→加购→下单转化漏斗(管理员)
+// @Summary 转化漏斗
+// @Description 统计天数内登录用户浏览商品后加入购物车和下单的人数及转化率。下单后购物车会被清空，已下单的用户同样计入加购
+// @Tags 浏览统计
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "统计天数，最多365" default(7)
+// @Param limit query int false "返回浏览人数最多的商品数量" default(20)
+// @Success 200 {object} response.SuccessResponse{data=service.FunnelReport} "获取成功"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Router /admin/analytics/funnel [get]
+func GetConversionFunnel(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "7"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	svc := c.MustGet("viewService").(*service.ViewService)
+	report, err := svc.FunnelReport(days, limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(report))
+}
@@ -0,0 +1,373 @@
+package handlers
+
+import (
+	"net/http"
+	"shopify/handlers/request"
+	"shopify/models"
+	"shopify/pkg/utils/response"
+	"shopify/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateWarehouse 创建仓库(管理员)
+// @Summary 创建仓库
+// @Description 由管理员创建发货仓库
+// @Tags 仓库管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param warehouse body request.WarehouseRequest true "仓库信息"
+// @Success 200 {object} response.SuccessResponse{data=models.Warehouse} "创建成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/warehouses [post]
+func CreateWarehouse(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	var req request.WarehouseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	warehouse := &models.Warehouse{
+		Code:     req.Code,
+		Name:     req.Name,
+		Province: req.Province,
+		City:     req.City,
+		Address:  req.Address,
+		Contact:  req.Contact,
+		Phone:    req.Phone,
+		Regions:  req.Regions,
+		Priority: req.Priority,
+		Status:   req.Status,
+	}
+
+	svc := c.MustGet("warehouseService").(*service.WarehouseService)
+	if err := svc.CreateWarehouse(warehouse); err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(warehouse))
+}
+
+// ListWarehouses 获取仓库列表(管理员)
+// @Summary 获取仓库列表
+// @Description 按优先级获取所有仓库
+// @Tags 仓库管理
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.SuccessResponse{data=[]models.Warehouse} "获取成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/warehouses [get]
+func ListWarehouses(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	svc := c.MustGet("warehouseService").(*service.WarehouseService)
+	warehouses, err := svc.ListWarehouses()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(warehouses))
+}
+
+// GetWarehouse 获取仓库详情(管理员)
+// @Summary 获取仓库详情
+// @Description 获取单个仓库的详细信息
+// @Tags 仓库管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "仓库ID"
+// @Success 200 {object} response.SuccessResponse{data=models.Warehouse} "获取成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 400 {object} response.ErrorResponse "无效的仓库ID"
+// @Failure 404 {object} response.ErrorResponse "仓库未找到"
+// @Router /admin/warehouses/{id} [get]
+func GetWarehouse(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid warehouse ID"))
+		return
+	}
+
+	svc := c.MustGet("warehouseService").(*service.WarehouseService)
+	warehouse, err := svc.GetWarehouse(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.Error(404, "Warehouse not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(warehouse))
+}
+
+// UpdateWarehouse 更新仓库信息(管理员)
+// @Summary 更新仓库信息
+// @Description 由管理员更新仓库信息、配送省份和优先级
+// @Tags 仓库管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "仓库ID"
+// @Param warehouse body request.WarehouseRequest true "仓库更新信息"
+// @Success 200 {object} response.SuccessResponse{data=models.Warehouse} "更新成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 400 {object} response.ErrorResponse "无效的仓库ID"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/warehouses/{id} [put]
+func UpdateWarehouse(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid warehouse ID"))
+		return
+	}
+
+	var req request.WarehouseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+	if req.Status == "" {
+		req.Status = "active"
+	}
+
+	warehouse := &models.Warehouse{
+		ID:       uint(id),
+		Code:     req.Code,
+		Name:     req.Name,
+		Province: req.Province,
+		City:     req.City,
+		Address:  req.Address,
+		Contact:  req.Contact,
+		Phone:    req.Phone,
+		Regions:  req.Regions,
+		Priority: req.Priority,
+		Status:   req.Status,
+	}
+
+	svc := c.MustGet("warehouseService").(*service.WarehouseService)
+	if err := svc.UpdateWarehouse(warehouse); err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	updatedWarehouse, err := svc.GetWarehouse(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(updatedWarehouse))
+}
+
+// DeleteWarehouse 删除仓库(管理员)
+// @Summary 删除仓库
+// @Description 由管理员删除仓库，仓库中仍有库存时不允许删除
+// @Tags 仓库管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "仓库ID"
+// @Success 200 {object} response.SuccessResponse{data=nil} "删除成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 400 {object} response.ErrorResponse "无效的仓库ID"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/warehouses/{id} [delete]
+func DeleteWarehouse(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid warehouse ID"))
+		return
+	}
+
+	svc := c.MustGet("warehouseService").(*service.WarehouseService)
+	if err := svc.DeleteWarehouse(uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
+
+// ListWarehouseStocks 获取仓库库存(管理员)
+// @Summary 获取仓库库存
+// @Description 分页查看仓库中各商品的库存
+// @Tags 仓库管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "仓库ID"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Success 200 {object} response.SuccessResponse{data=gin.H{"items":[]models.WarehouseStock, "total":int, "page":int, "page_size":int}} "获取成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 400 {object} response.ErrorResponse "无效的仓库ID"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/warehouses/{id}/stocks [get]
+func ListWarehouseStocks(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid warehouse ID"))
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	svc := c.MustGet("warehouseService").(*service.WarehouseService)
+	stocks, total, err := svc.ListWarehouseStocks(uint(id), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(gin.H{
+		"items":     stocks,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}))
+}
+
+// ListProductWarehouseStocks 获取商品在各仓库的库存(管理员)
+// @Summary 获取商品分仓库存
+// @Description 查看商品在各个有效仓库中的库存分布
+// @Tags 仓库管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "产品ID"
+// @Success 200 {object} response.SuccessResponse{data=[]models.WarehouseStock} "获取成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 400 {object} response.ErrorResponse "无效的产品ID"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/products/{id}/stock/warehouses [get]
+func ListProductWarehouseStocks(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid product ID"))
+		return
+	}
+
+	svc := c.MustGet("warehouseService").(*service.WarehouseService)
+	stocks, err := svc.ListProductStocks(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(stocks))
+}
+
+// TransferWarehouseStock 仓库间调拨库存(管理员)
+// @Summary 仓库间调拨库存
+// @Description 在仓库之间调拨商品库存，调拨不改变商品总库存
+// @Tags 仓库管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.StockTransferRequest true "调拨信息"
+// @Success 200 {object} response.SuccessResponse{data=nil} "调拨成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 400 {object} response.ErrorResponse "请求参数无效"
+// @Failure 500 {object} response.ErrorResponse "服务器错误"
+// @Router /admin/warehouses/transfer [post]
+func TransferWarehouseStock(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	var req request.StockTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid request parameters"))
+		return
+	}
+
+	operatorID, _ := c.Get("userID")
+
+	svc := c.MustGet("warehouseService").(*service.WarehouseService)
+	if err := svc.TransferStock(req.ProductID, req.FromWarehouseID, req.ToWarehouseID, req.Quantity, req.Remark, operatorID.(uint)); err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(nil))
+}
+
+// GetPackingSlips 获取订单装箱单(管理员)
+// @Summary 获取订单装箱单
+// @Description 按发货仓生成订单装箱单，包含仓库、物流和商品明细
+// @Tags 仓库管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "订单ID"
+// @Success 200 {object} response.SuccessResponse{data=[]service.PackingSlip} "获取成功"
+// @Failure 403 {object} response.ErrorResponse "权限不足"
+// @Failure 400 {object} response.ErrorResponse "无效的订单ID"
+// @Failure 404 {object} response.ErrorResponse "订单未找到"
+// @Router /admin/orders/{id}/packing-slips [get]
+func GetPackingSlips(c *gin.Context) {
+	role, exists := c.Get("userRole")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, response.Error(403, "Permission denied"))
+		return
+	}
+
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(400, "Invalid order ID"))
+		return
+	}
+
+	svc := c.MustGet("warehouseService").(*service.WarehouseService)
+	slips, err := svc.GetPackingSlips(uint(orderID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.Error(404, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(slips))
+}
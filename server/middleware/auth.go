@@ -37,4 +37,20 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		c.Next()
 	}
-} 
\ No newline at end of file
+} 
+// OptionalAuthMiddleware 携带有效token时设置用户信息，未登录或token无效时按匿名访问继续处理
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			if claims, err := jwt.ParseToken(parts[1]); err == nil {
+				c.Set("userID", claims.UserID)
+				c.Set("username", claims.Username)
+				c.Set("email", claims.Email)
+				c.Set("userRole", claims.Role)
+			}
+		}
+
+		c.Next()
+	}
+}
@@ -8,7 +8,8 @@ func Cors() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag")
 		
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
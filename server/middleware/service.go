@@ -15,6 +15,27 @@ func InjectServices(sf *service.ServiceFactory, db *gorm.DB) gin.HandlerFunc {
 		c.Set("cartService", sf.GetCartService())
 		c.Set("reviewService", sf.GetReviewService())
 		c.Set("advertisementService", sf.GetAdvertisementService())
+		c.Set("inventoryService", sf.GetInventoryService())
+		c.Set("warehouseService", sf.GetWarehouseService())
+		c.Set("notificationService", sf.GetNotificationService())
+		c.Set("stockAlertService", sf.GetStockAlertService())
+		c.Set("skuService", sf.GetSKUService())
+		c.Set("categoryService", sf.GetCategoryService())
+		c.Set("searchService", sf.GetSearchService())
+		c.Set("mediaService", sf.GetMediaService())
+		c.Set("attributeService", sf.GetAttributeService())
+		c.Set("importService", sf.GetImportService())
+		c.Set("scheduleService", sf.GetScheduleService())
+		c.Set("recommendationService", sf.GetRecommendationService())
+		c.Set("viewService", sf.GetViewService())
+		c.Set("sitemapService", sf.GetSitemapService())
+		c.Set("feedService", sf.GetFeedService())
+		c.Set("translationService", sf.GetTranslationService())
+		c.Set("currencyService", sf.GetCurrencyService())
+		c.Set("bundleService", sf.GetBundleService())
+		c.Set("digitalService", sf.GetDigitalService())
+		c.Set("questionService", sf.GetQuestionService())
+		c.Set("compareService", sf.GetCompareService())
 		c.Next()
 	}
 } 
\ No newline at end of file
@@ -15,6 +15,7 @@ type Advertisement struct {
 	StartTime time.Time      `json:"start_time"`                                      // 广告开始时间
 	EndTime   time.Time      `json:"end_time"`                                        // 广告结束时间
 	Status    string         `gorm:"type:varchar(20);default:'active'" json:"status"` // 广告状态，默认为active
+	Version   uint           `gorm:"not null;default:1" json:"version"`               // 乐观锁版本号
 	CreatedAt time.Time      `json:"created_at"`                                      // 创建时间
 	UpdatedAt time.Time      `json:"updated_at"`                                      // 更新时间
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`                                  // 删除时间，软删除
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+)
+
+// 规格属性类型常量
+const (
+	AttributeTypeText    = "text"    // 文本
+	AttributeTypeNumber  = "number"  // 数值，可带单位，支持区间筛选
+	AttributeTypeEnum    = "enum"    // 枚举，只能取可选值之一
+	AttributeTypeBoolean = "boolean" // 是/否
+)
+
+// AttributeDefinition 类目规格属性定义，子类目继承祖先类目的属性
+type AttributeDefinition struct {
+	ID            uint      `gorm:"primarykey;autoIncrement" json:"id"`                                        // 属性的唯一标识符
+	CategoryID    uint      `gorm:"not null;uniqueIndex:idx_category_code" json:"category_id"`                 // 所属类目ID
+	Code          string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_category_code;index" json:"code"` // 属性编码，用于筛选参数，如 ram
+	Name          string    `gorm:"type:varchar(50);not null" json:"name"`                                     // 属性名称，如 内存
+	Type          string    `gorm:"type:varchar(20);not null" json:"type"`                                     // 属性类型：文本/数值/枚举/布尔
+	Unit          string    `gorm:"type:varchar(20)" json:"unit"`                                              // 单位，如 GB、英寸
+	AllowedValues []string  `gorm:"type:json;serializer:json" json:"allowed_values"`                           // 可选值，仅枚举类型使用
+	Filterable    bool      `gorm:"not null" json:"filterable"`                                                // 是否可用于列表筛选和分面统计
+	SortOrder     int       `gorm:"default:0" json:"sort_order"`                                               // 排序，数值越小越靠前
+	CreatedAt     time.Time `json:"created_at"`                                                                // 创建时间
+	UpdatedAt     time.Time `json:"updated_at"`                                                                // 更新时间
+}
+
+// ProductAttributeValue 产品规格属性值
+type ProductAttributeValue struct {
+	ID           uint      `gorm:"primarykey;autoIncrement" json:"id"`                                   // 属性值的唯一标识符
+	ProductID    uint      `gorm:"not null;uniqueIndex:idx_product_attribute" json:"product_id"`         // 关联的产品ID
+	AttributeID  uint      `gorm:"not null;uniqueIndex:idx_product_attribute;index" json:"attribute_id"` // 关联的属性定义ID
+	Code         string    `gorm:"type:varchar(50);not null;index:idx_code_value" json:"code"`           // 属性编码，冗余存储便于筛选
+	Value        string    `gorm:"type:varchar(100);not null;index:idx_code_value" json:"value"`         // 规范化后的属性值
+	NumericValue *float64  `json:"numeric_value"`                                                        // 数值类型的数值，用于区间筛选
+	CreatedAt    time.Time `json:"created_at"`                                                           // 创建时间
+	UpdatedAt    time.Time `json:"updated_at"`                                                           // 更新时间
+}
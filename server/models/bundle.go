@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BundleComponent 套装组件表，记录套装由哪些商品按什么数量组成
+type BundleComponent struct {
+	ID          uint      `gorm:"primarykey;autoIncrement" json:"id"`      // 组件的唯一标识符
+	BundleID    uint      `gorm:"not null;index" json:"bundle_id"`         // 所属套装商品ID
+	ComponentID uint      `gorm:"not null;index" json:"component_id"`      // 组件商品ID
+	Component   Product   `gorm:"foreignKey:ComponentID" json:"component"` // 组件商品对象
+	SKUID       *uint     `json:"sku_id"`                                  // 组件SKU ID，组件商品有规格时必填
+	SKU         *SKU      `gorm:"foreignKey:SKUID" json:"sku,omitempty"`   // 组件SKU对象
+	Quantity    int       `gorm:"not null;default:1" json:"quantity"`      // 每套包含的数量
+	SortOrder   int       `gorm:"default:0" json:"sort_order"`             // 展示顺序
+	CreatedAt   time.Time `json:"created_at"`                              // 创建时间
+}
+
+// OrderItemComponent 套装订单项的组件明细，记录扣减的组件库存和分摊到组件的收入
+type OrderItemComponent struct {
+	ID              uint            `gorm:"primarykey;autoIncrement" json:"id"`                  // 明细的唯一标识符
+	OrderID         uint            `gorm:"not null;index" json:"order_id"`                      // 关联的订单ID
+	OrderItemID     uint            `gorm:"not null;index" json:"order_item_id"`                 // 关联的套装订单项ID
+	ProductID       uint            `gorm:"not null;index" json:"product_id"`                    // 组件商品ID
+	Product         Product         `gorm:"foreignKey:ProductID" json:"product"`                 // 组件商品对象
+	SKUID           *uint           `json:"sku_id"`                                              // 组件SKU ID
+	SKU             *SKU            `gorm:"foreignKey:SKUID" json:"sku,omitempty"`               // 组件SKU对象
+	Quantity        int             `gorm:"not null" json:"quantity"`                            // 合计数量，即每套数量乘以套装数量
+	UnitPrice       decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"unit_price"`       // 下单时组件单独售卖的单价
+	AllocatedAmount decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"allocated_amount"` // 分摊到该组件的套装收入
+	CreatedAt       time.Time       `json:"created_at"`                                          // 创建时间
+}
@@ -1,17 +1,20 @@
 package models
 
 import (
-    "time"
+	"time"
 )
 
 type CartItem struct {
-	ID        uint      `gorm:"primarykey;autoIncrement" json:"id"`  // 购物车项的唯一标识符
-	UserID    uint      `gorm:"not null" json:"user_id"`             // 关联的用户ID
-	User      User      `gorm:"foreignKey:UserID" json:"-"`          // 关联的用户对象，JSON序列化时忽略
-	ProductID uint      `gorm:"not null" json:"product_id"`          // 关联的产品ID
-	Product   Product   `gorm:"foreignKey:ProductID" json:"product"` // 关联的产品对象
-	Quantity  int       `gorm:"not null" json:"quantity"`            // 产品数量
-	Selected  bool      `gorm:"default:true" json:"selected"`        // 是否选中，默认为true
-	CreatedAt time.Time `json:"created_at"`                          // 创建时间
-	UpdatedAt time.Time `json:"updated_at"`                          // 更新时间
-}
\ No newline at end of file
+	ID              uint      `gorm:"primarykey;autoIncrement" json:"id"`    // 购物车项的唯一标识符
+	UserID          uint      `gorm:"not null" json:"user_id"`               // 关联的用户ID
+	User            User      `gorm:"foreignKey:UserID" json:"-"`            // 关联的用户对象，JSON序列化时忽略
+	ProductID       uint      `gorm:"not null" json:"product_id"`            // 关联的产品ID
+	Product         Product   `gorm:"foreignKey:ProductID" json:"product"`   // 关联的产品对象
+	SKUID           *uint     `gorm:"index" json:"sku_id"`                   // 关联的SKU ID，无规格商品为空
+	SKU             *SKU      `gorm:"foreignKey:SKUID" json:"sku,omitempty"` // 关联的SKU对象
+	Quantity        int       `gorm:"not null" json:"quantity"`              // 产品数量
+	Selected        bool      `gorm:"default:true" json:"selected"`          // 是否选中，默认为true
+	CreatedAt       time.Time `json:"created_at"`                            // 创建时间
+	UpdatedAt       time.Time `json:"updated_at"`                            // 更新时间
+	DisplaySubtotal *Money    `gorm:"-" json:"display_subtotal,omitempty"`   // 按请求币种换算的小计
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Category 商品类目表，通过 Path 记录祖先链便于查询子孙类目
+type Category struct {
+	ID             uint           `gorm:"primarykey;autoIncrement" json:"id"`       // 类目的唯一标识符
+	ParentID       *uint          `gorm:"index" json:"parent_id"`                   // 父类目ID，为空表示顶级类目
+	Name           string         `gorm:"type:varchar(50);not null" json:"name"`    // 类目名称
+	Icon           string         `gorm:"type:varchar(255)" json:"icon"`            // 类目图标
+	SortOrder      int            `gorm:"default:0" json:"sort_order"`              // 同级排序，数值越小越靠前
+	Active         bool           `gorm:"default:true" json:"active"`               // 是否启用
+	GoogleCategory string         `gorm:"type:varchar(255)" json:"google_category"` // Google 商品类目，如 Apparel & Accessories > Clothing，为空时沿用上级类目
+	Path           string         `gorm:"type:varchar(255);index" json:"path"`      // 祖先路径，如 /1/5/，包含自身ID
+	Level          int            `gorm:"default:0" json:"level"`                   // 层级，顶级为0
+	Children       []*Category    `gorm:"-" json:"children,omitempty"`              // 子类目，仅用于返回类目树
+	CreatedAt      time.Time      `json:"created_at"`                               // 创建时间
+	UpdatedAt      time.Time      `json:"updated_at"`                               // 更新时间
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`                           // 删除时间（软删除）
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+// CompareItem 用户的商品对比列表，保存在服务端以便跨设备同步
+type CompareItem struct {
+	ID        uint      `gorm:"primarykey;autoIncrement" json:"id"`                              // 记录的唯一标识符
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_compare_user_product" json:"user_id"`    // 用户ID
+	ProductID uint      `gorm:"not null;uniqueIndex:idx_compare_user_product" json:"product_id"` // 对比的商品ID
+	Product   Product   `gorm:"foreignKey:ProductID" json:"product"`                             // 对比的商品
+	CreatedAt time.Time `json:"created_at"`                                                      // 加入时间
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 币种金额的舍入方式
+const (
+	RoundingHalfUp   = "half_up"   // 四舍五入
+	RoundingHalfEven = "half_even" // 银行家舍入
+	RoundingCeil     = "ceil"      // 向上取整
+	RoundingFloor    = "floor"     // 向下取整
+)
+
+// Currency 币种及汇率表，汇率为 1 基准币种可兑换的该币种金额
+type Currency struct {
+	ID        uint            `gorm:"primarykey;autoIncrement" json:"id"`                 // 币种的唯一标识符
+	Code      string          `gorm:"type:varchar(3);uniqueIndex;not null" json:"code"`   // ISO 4217 币种代码，如 USD
+	Name      string          `gorm:"type:varchar(50)" json:"name"`                       // 币种名称
+	Symbol    string          `gorm:"type:varchar(10)" json:"symbol"`                     // 货币符号，如 $
+	Rate      decimal.Decimal `gorm:"type:decimal(18,8);not null" json:"rate"`            // 相对基准币种的汇率
+	Decimals  int             `gorm:"not null;default:2" json:"decimals"`                 // 金额保留的小数位数
+	Rounding  string          `gorm:"type:varchar(20);default:'half_up'" json:"rounding"` // 舍入方式
+	Active    bool            `gorm:"default:true" json:"active"`                         // 是否可供前台选择
+	CreatedAt time.Time       `json:"created_at"`                                         // 创建时间
+	UpdatedAt time.Time       `json:"updated_at"`                                         // 更新时间
+}
+
+// Money 按展示币种换算后的金额，不落库
+type Money struct {
+	Amount   decimal.Decimal `json:"amount"`   // 换算并舍入后的金额
+	Currency string          `json:"currency"` // 币种代码
+	Symbol   string          `json:"symbol"`   // 货币符号
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DigitalUnlimitedStock 仅含文件的数字商品不限库存，库存字段取该值以兼容按库存筛选和下单校验
+const DigitalUnlimitedStock = 999999
+
+// 授权码状态
+const (
+	LicenseKeyAvailable = "available" // 可分配
+	LicenseKeyReserved  = "reserved"  // 已随订单预留，待支付
+	LicenseKeyDelivered = "delivered" // 已发放
+	LicenseKeyRevoked   = "revoked"   // 订单退款或取消后作废
+)
+
+// DigitalAsset 数字商品文件表，文件保存在私有路径，只能通过签名下载链接获取
+type DigitalAsset struct {
+	ID          uint           `gorm:"primarykey;autoIncrement" json:"id"`     // 文件的唯一标识符
+	ProductID   uint           `gorm:"not null;index" json:"product_id"`       // 关联的商品ID
+	Name        string         `gorm:"type:varchar(255);not null" json:"name"` // 下载时的文件名
+	StorageKey  string         `gorm:"type:varchar(255);not null" json:"-"`    // 存储路径，不对外暴露
+	ContentType string         `gorm:"type:varchar(100)" json:"content_type"`  // 文件类型
+	Size        int64          `gorm:"not null;default:0" json:"size"`         // 文件大小(字节)
+	Checksum    string         `gorm:"type:varchar(64)" json:"checksum"`       // SHA-256 校验值
+	CreatedAt   time.Time      `json:"created_at"`                             // 创建时间
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`                         // 删除时间（软删除），已售出的订单仍可下载
+}
+
+// LicenseKey 授权码池，下单时预留，支付后发放
+type LicenseKey struct {
+	ID          uint       `gorm:"primarykey;autoIncrement" json:"id"`                                        // 授权码的唯一标识符
+	ProductID   uint       `gorm:"not null;uniqueIndex:idx_license_product_key" json:"product_id"`            // 关联的商品ID
+	Key         string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_license_product_key" json:"key"` // 授权码
+	Status      string     `gorm:"type:varchar(20);not null;default:'available';index" json:"status"`         // 状态：可分配/已预留/已发放/已作废
+	OrderID     *uint      `gorm:"index" json:"order_id"`                                                     // 预留或发放的订单ID
+	OrderItemID *uint      `gorm:"index" json:"order_item_id"`                                                // 预留或发放的订单项ID
+	DeliveredAt *time.Time `json:"delivered_at"`                                                              // 发放时间
+	CreatedAt   time.Time  `json:"created_at"`                                                                // 创建时间
+	UpdatedAt   time.Time  `json:"updated_at"`                                                                // 更新时间
+}
+
+// DownloadGrant 订单的文件下载授权，每个订单项的每个文件一条，限制下载次数
+type DownloadGrant struct {
+	ID             uint         `gorm:"primarykey;autoIncrement" json:"id"`       // 下载授权的唯一标识符
+	OrderID        uint         `gorm:"not null;index" json:"order_id"`           // 关联的订单ID
+	OrderItemID    uint         `gorm:"not null;index" json:"order_item_id"`      // 关联的订单项ID
+	UserID         uint         `gorm:"not null;index" json:"user_id"`            // 购买用户ID
+	AssetID        uint         `gorm:"not null" json:"asset_id"`                 // 关联的文件ID
+	Asset          DigitalAsset `gorm:"foreignKey:AssetID" json:"asset"`          // 关联的文件对象
+	DownloadLimit  int          `gorm:"not null" json:"download_limit"`           // 下载次数上限
+	DownloadCount  int          `gorm:"not null;default:0" json:"download_count"` // 已下载次数
+	LastDownloadAt *time.Time   `json:"last_download_at"`                         // 最近下载时间
+	CreatedAt      time.Time    `json:"created_at"`                               // 创建时间
+	URL            string       `gorm:"-" json:"url,omitempty"`                   // 签名下载链接，查询订单时生成
+	URLExpiresAt   *time.Time   `gorm:"-" json:"url_expires_at,omitempty"`        // 下载链接过期时间
+}
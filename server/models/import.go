@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+)
+
+// 导入任务状态常量
+const (
+	ImportStatusPending   = "pending"   // 等待执行
+	ImportStatusRunning   = "running"   // 执行中
+	ImportStatusCompleted = "completed" // 已完成，可能包含部分行错误
+	ImportStatusFailed    = "failed"    // 执行失败
+)
+
+// 导入文件来源格式常量
+const (
+	ImportSourceStandard = "standard" // 本系统导出的格式
+	ImportSourceShopify  = "shopify"  // Shopify 商品导出 CSV
+)
+
+// ImportError 导入时某一行某一列的错误
+type ImportError struct {
+	Row     int    `json:"row"`              // 表格中的行号，表头为第1行
+	Column  string `json:"column,omitempty"` // 出错的列名，整行错误时为空
+	Message string `json:"message"`          // 错误描述
+}
+
+// ImportJob 商品批量导入任务表
+type ImportJob struct {
+	ID         uint          `gorm:"primarykey;autoIncrement" json:"id"`            // 任务的唯一标识符
+	Source     string        `gorm:"type:varchar(20);not null" json:"source"`       // 文件来源格式
+	Format     string        `gorm:"type:varchar(10);not null" json:"format"`       // 文件格式：csv/xlsx
+	FileName   string        `gorm:"type:varchar(255)" json:"file_name"`            // 上传的文件名
+	DryRun     bool          `gorm:"default:false" json:"dry_run"`                  // 是否仅校验不写入
+	Status     string        `gorm:"type:varchar(20);not null;index" json:"status"` // 任务状态
+	Total      int           `gorm:"default:0" json:"total"`                        // 待处理的商品数
+	Processed  int           `gorm:"default:0" json:"processed"`                    // 已处理的商品数
+	Created    int           `gorm:"default:0" json:"created"`                      // 新建(或将新建)的商品数
+	Updated    int           `gorm:"default:0" json:"updated"`                      // 更新(或将更新)的商品数
+	Failed     int           `gorm:"default:0" json:"failed"`                       // 失败的商品数
+	Errors     []ImportError `gorm:"type:json;serializer:json" json:"errors"`       // 行列错误明细
+	Warnings   []ImportError `gorm:"type:json;serializer:json" json:"warnings"`     // 不影响导入的提示，如未识别的列
+	Message    string        `gorm:"type:varchar(255)" json:"message"`              // 任务失败原因
+	OperatorID uint          `gorm:"index" json:"operator_id"`                      // 操作人ID
+	StartedAt  *time.Time    `json:"started_at"`                                    // 开始执行时间
+	FinishedAt *time.Time    `json:"finished_at"`                                   // 结束时间
+	CreatedAt  time.Time     `json:"created_at"`                                    // 创建时间
+	UpdatedAt  time.Time     `json:"updated_at"`                                    // 更新时间
+}
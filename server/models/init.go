@@ -72,6 +72,37 @@ func autoMigrate(db *gorm.DB) error {
 		&Advertisement{},
 		&Logistics{},
 		&LogisticsTrace{},
+		&InventoryMovement{},
+		&Warehouse{},
+		&WarehouseStock{},
+		&OrderAllocation{},
+		&AdminNotification{},
+		&ProductOption{},
+		&SKU{},
+		&Category{},
+		&SearchLog{},
+		&SearchTerm{},
+		&MediaFile{},
+		&AttributeDefinition{},
+		&ProductAttributeValue{},
+		&ImportJob{},
+		&ProductSchedule{},
+		&PriceHistory{},
+		&ProductAssociation{},
+		&ProductRelation{},
+		&ProductView{},
+		&ProductSlug{},
+		&Translation{},
+		&Currency{},
+		&BundleComponent{},
+		&OrderItemComponent{},
+		&DigitalAsset{},
+		&LicenseKey{},
+		&DownloadGrant{},
+		&ProductQuestion{},
+		&ProductAnswer{},
+		&AnswerVote{},
+		&CompareItem{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to migrate database: %v", err)
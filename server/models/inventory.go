@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+// 库存变动原因常量
+const (
+	InventoryReasonOrder      = "order"      // 下单扣减
+	InventoryReasonCancel     = "cancel"     // 取消订单回补
+	InventoryReasonRefund     = "refund"     // 退款回补
+	InventoryReasonAdjustment = "adjustment" // 人工调整
+	InventoryReasonImport     = "import"     // 批量导入
+	InventoryReasonTransfer   = "transfer"   // 仓库间调拨
+	InventoryReasonOpening    = "opening"    // 期初库存，启用流水前已有的库存
+)
+
+// InventoryMovement 库存变动流水表
+type InventoryMovement struct {
+	ID          uint      `gorm:"primarykey;autoIncrement" json:"id"`            // 流水的唯一标识符
+	ProductID   uint      `gorm:"not null;index" json:"product_id"`              // 关联的产品ID
+	Product     Product   `gorm:"foreignKey:ProductID" json:"-"`                 // 关联的产品对象，JSON序列化时忽略
+	WarehouseID uint      `gorm:"index" json:"warehouse_id"`                     // 关联的仓库ID，0表示未分配仓库
+	SKUID       uint      `gorm:"index" json:"sku_id"`                           // 关联的SKU ID，0表示产品级库存
+	Delta       int       `gorm:"not null" json:"delta"`                         // 变动数量，正数为入库，负数为出库
+	Quantity    int       `gorm:"not null" json:"quantity"`                      // 变动后的库存数量
+	Reason      string    `gorm:"type:varchar(20);not null;index" json:"reason"` // 变动原因：下单/取消/退款/人工调整/导入/调拨/期初
+	ReferenceID uint      `gorm:"index" json:"reference_id"`                     // 关联单据ID，如订单ID
+	Remark      string    `gorm:"type:varchar(255)" json:"remark"`               // 备注说明
+	OperatorID  uint      `json:"operator_id"`                                   // 操作人ID，系统操作为0
+	CreatedAt   time.Time `json:"created_at"`                                    // 创建时间
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// 媒体文件用途常量
+const (
+	MediaPurposeProduct  = "product"  // 商品图片
+	MediaPurposeReview   = "review"   // 评价图片
+	MediaPurposeAd       = "ad"       // 广告图片
+	MediaPurposeAvatar   = "avatar"   // 用户头像
+	MediaPurposeCategory = "category" // 类目图标
+)
+
+// MediaFile 上传的媒体文件表，按内容哈希去重
+type MediaFile struct {
+	ID         uint              `gorm:"primarykey;autoIncrement" json:"id"`             // 文件的唯一标识符
+	Hash       string            `gorm:"type:char(64);uniqueIndex;not null" json:"hash"` // 文件内容的SHA-256
+	Key        string            `gorm:"type:varchar(255);not null" json:"key"`          // 存储中的对象路径
+	URL        string            `gorm:"type:varchar(255);not null;index" json:"url"`    // 公开访问地址
+	MimeType   string            `gorm:"type:varchar(50);not null" json:"mime_type"`     // 文件类型
+	Size       int64             `gorm:"not null" json:"size"`                           // 文件大小(字节)
+	Width      int               `json:"width"`                                          // 图片宽度
+	Height     int               `json:"height"`                                         // 图片高度
+	Thumbnails map[string]string `gorm:"type:json;serializer:json" json:"thumbnails"`    // 缩略图地址，键为尺寸
+	Purpose    string            `gorm:"type:varchar(20);not null" json:"purpose"`       // 用途：商品/评价/广告/头像/类目
+	UploaderID uint              `gorm:"index;not null" json:"uploader_id"`              // 上传用户ID
+	CreatedAt  time.Time         `gorm:"index" json:"created_at"`                        // 上传时间
+}
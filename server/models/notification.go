@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// 管理员通知类型常量
+const (
+	NotificationTypeLowStock  = "low_stock"  // 低库存预警
+	NotificationTypeQAPending = "qa_pending" // 商品问答待审核
+)
+
+// AdminNotification 管理员站内通知表
+type AdminNotification struct {
+	ID          uint       `gorm:"primarykey;autoIncrement" json:"id"`          // 通知的唯一标识符
+	Type        string     `gorm:"type:varchar(30);not null;index" json:"type"` // 通知类型
+	Title       string     `gorm:"type:varchar(100);not null" json:"title"`     // 通知标题
+	Content     string     `gorm:"type:text" json:"content"`                    // 通知内容
+	ReferenceID uint       `gorm:"index" json:"reference_id"`                   // 关联对象ID，如产品ID
+	Read        bool       `gorm:"default:false;index" json:"read"`             // 是否已读
+	ReadAt      *time.Time `json:"read_at"`                                     // 阅读时间
+	CreatedAt   time.Time  `json:"created_at"`                                  // 创建时间
+}
@@ -16,49 +16,63 @@ const (
 )
 
 type Order struct {
-	ID            uint            `gorm:"primarykey;autoIncrement" json:"id"`                   // 订单的唯一标识符
-	UserID        uint            `gorm:"not null" json:"user_id"`                              // 关联的用户ID
-	User          User            `gorm:"foreignKey:UserID" json:"-"`                           // 关联的用户对象
-	OrderNumber   string          `gorm:"type:varchar(50);unique;not null" json:"order_number"` // 订单编号
-	Status        string          `gorm:"type:varchar(20);not null" json:"status"`              // 订单状态：待处理/已支付/已发货/已完成/已取消
-	TotalAmount   decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"total_amount"`      // 订单总金额
-	AddressID     uint            `gorm:"not null" json:"address_id"`                           // 关联的地址ID
-	Address       Address         `gorm:"foreignKey:AddressID" json:"address"`                  // 关联的地址对象
-	PaymentMethod string          `gorm:"type:varchar(20)" json:"payment_method"`               // 支付方式
-	PaymentStatus string          `gorm:"type:varchar(20)" json:"payment_status"`               // 支付状态：未支付/已支付/已退款
-	PaymentTime   *time.Time      `json:"payment_time"`                                         // 支付时间
-	CreatedAt     time.Time       `json:"created_at"`                                           // 创建时间
-	UpdatedAt     time.Time       `json:"updated_at"`                                           // 更新时间
-	DeletedAt     gorm.DeletedAt  `gorm:"index" json:"-"`                                       // 删除时间（软删除）
-	OrderItems    []OrderItem     `gorm:"foreignKey:OrderID" json:"order_items"`                // 修改这里
+	ID               uint              `gorm:"primarykey;autoIncrement" json:"id"`                   // 订单的唯一标识符
+	UserID           uint              `gorm:"not null" json:"user_id"`                              // 关联的用户ID
+	User             User              `gorm:"foreignKey:UserID" json:"-"`                           // 关联的用户对象
+	OrderNumber      string            `gorm:"type:varchar(50);unique;not null" json:"order_number"` // 订单编号
+	Status           string            `gorm:"type:varchar(20);not null" json:"status"`              // 订单状态：待处理/已支付/已发货/已完成/已取消
+	TotalAmount      decimal.Decimal   `gorm:"type:decimal(10,2);not null" json:"total_amount"`      // 订单总金额
+	Currency         string            `gorm:"type:varchar(3)" json:"currency"`                      // 结算币种
+	ExchangeRate     decimal.Decimal   `gorm:"type:decimal(18,8);default:1" json:"exchange_rate"`    // 下单时结算币种相对基准币种的汇率快照
+	SettlementAmount decimal.Decimal   `gorm:"type:decimal(12,2)" json:"settlement_amount"`          // 按结算币种换算的订单金额
+	AddressID        uint              `gorm:"not null" json:"address_id"`                           // 关联的地址ID
+	Address          Address           `gorm:"foreignKey:AddressID" json:"address"`                  // 关联的地址对象
+	PaymentMethod    string            `gorm:"type:varchar(20)" json:"payment_method"`               // 支付方式
+	PaymentStatus    string            `gorm:"type:varchar(20)" json:"payment_status"`               // 支付状态：未支付/已支付/已退款
+	PaymentTime      *time.Time        `json:"payment_time"`                                         // 支付时间
+	Version          uint              `gorm:"not null;default:1" json:"version"`                    // 乐观锁版本号
+	CreatedAt        time.Time         `json:"created_at"`                                           // 创建时间
+	UpdatedAt        time.Time         `json:"updated_at"`                                           // 更新时间
+	DeletedAt        gorm.DeletedAt    `gorm:"index" json:"-"`                                       // 删除时间（软删除）
+	OrderItems       []OrderItem       `gorm:"foreignKey:OrderID" json:"order_items"`                // 修改这里
+	Allocations      []OrderAllocation `gorm:"foreignKey:OrderID" json:"allocations,omitempty"`      // 发货仓分配记录
 }
 
 type OrderItem struct {
-	ID        uint            `gorm:"primarykey" json:"id"`                     // 订单项的唯一标识符
-	OrderID   uint            `gorm:"not null" json:"order_id"`                 // 关联的订单ID
-	Order     Order           `gorm:"foreignKey:OrderID" json:"-"`              // 关联的订单对象
-	ProductID uint            `gorm:"not null" json:"product_id"`               // 关联的产品ID
-	Product   Product         `gorm:"foreignKey:ProductID" json:"product"`      // 关联的产品对象
-	Quantity  int             `gorm:"not null" json:"quantity"`                 // 产品数量
-	Price     decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"price"` // 产品单价
-	CreatedAt time.Time       `json:"created_at"`                               // 创建时间
-	UpdatedAt time.Time       `json:"updated_at"`                               // 更新时间
+	ID          uint                 `gorm:"primarykey" json:"id"`                                 // 订单项的唯一标识符
+	OrderID     uint                 `gorm:"not null" json:"order_id"`                             // 关联的订单ID
+	Order       Order                `gorm:"foreignKey:OrderID" json:"-"`                          // 关联的订单对象
+	ProductID   uint                 `gorm:"not null" json:"product_id"`                           // 关联的产品ID
+	Product     Product              `gorm:"foreignKey:ProductID" json:"product"`                  // 关联的产品对象
+	ProductType string               `gorm:"type:varchar(20)" json:"product_type"`                 // 下单时的商品类型快照，历史订单为空
+	SKUID       *uint                `gorm:"index" json:"sku_id"`                                  // 关联的SKU ID，无规格商品为空
+	SKU         *SKU                 `gorm:"foreignKey:SKUID" json:"sku,omitempty"`                // 关联的SKU对象
+	Quantity    int                  `gorm:"not null" json:"quantity"`                             // 产品数量
+	Price       decimal.Decimal      `gorm:"type:decimal(10,2);not null" json:"price"`             // 产品单价
+	CreatedAt   time.Time            `json:"created_at"`                                           // 创建时间
+	UpdatedAt   time.Time            `json:"updated_at"`                                           // 更新时间
+	Components  []OrderItemComponent `gorm:"foreignKey:OrderItemID" json:"components,omitempty"`   // 套装订单项的组件明细
+	DeliveredAt *time.Time           `json:"delivered_at,omitempty"`                               // 数字商品的发放时间
+	LicenseKeys []LicenseKey         `gorm:"foreignKey:OrderItemID" json:"license_keys,omitempty"` // 已发放的授权码
+	Downloads   []DownloadGrant      `gorm:"foreignKey:OrderItemID" json:"downloads,omitempty"`    // 文件下载授权
 }
 
 // Logistics 物流信息表
 type Logistics struct {
-	ID            uint             `gorm:"primarykey;autoIncrement" json:"id"`     // 物流信息的唯一标识符
-	OrderID       uint             `gorm:"not null" json:"order_id"`               // 关联的订单ID
-	Order         Order            `gorm:"foreignKey:OrderID" json:"-"`            // 关联的订单对象，JSON序列化时忽略
-	TrackingNo    string           `gorm:"type:varchar(50)" json:"tracking_no"`    // 物流追踪号
-	Carrier       string           `gorm:"type:varchar(50)" json:"carrier"`        // 承运商名称
-	Status        string           `gorm:"type:varchar(20)" json:"status"`         // 物流状态
-	ShippingFee   decimal.Decimal  `gorm:"type:decimal(10,2)" json:"shipping_fee"` // 运费
-	ShippedTime   *time.Time       `json:"shipped_time"`                           // 发货时间
-	DeliveredTime *time.Time       `json:"delivered_time"`                         // 送达时间
-	CreatedAt     time.Time        `json:"created_at"`                             // 创建时间
-	UpdatedAt     time.Time        `json:"updated_at"`                             // 更新时间
-	Traces        []LogisticsTrace `gorm:"foreignKey:LogisticsID" json:"traces"`   // 添加这个字段
+	ID            uint             `gorm:"primarykey;autoIncrement" json:"id"`                // 物流信息的唯一标识符
+	OrderID       uint             `gorm:"not null" json:"order_id"`                          // 关联的订单ID
+	Order         Order            `gorm:"foreignKey:OrderID" json:"-"`                       // 关联的订单对象，JSON序列化时忽略
+	WarehouseID   *uint            `gorm:"index" json:"warehouse_id"`                         // 发货仓库ID，为空表示未分配仓库
+	Warehouse     *Warehouse       `gorm:"foreignKey:WarehouseID" json:"warehouse,omitempty"` // 发货仓库对象
+	TrackingNo    string           `gorm:"type:varchar(50)" json:"tracking_no"`               // 物流追踪号
+	Carrier       string           `gorm:"type:varchar(50)" json:"carrier"`                   // 承运商名称
+	Status        string           `gorm:"type:varchar(20)" json:"status"`                    // 物流状态
+	ShippingFee   decimal.Decimal  `gorm:"type:decimal(10,2)" json:"shipping_fee"`            // 运费
+	ShippedTime   *time.Time       `json:"shipped_time"`                                      // 发货时间
+	DeliveredTime *time.Time       `json:"delivered_time"`                                    // 送达时间
+	CreatedAt     time.Time        `json:"created_at"`                                        // 创建时间
+	UpdatedAt     time.Time        `json:"updated_at"`                                        // 更新时间
+	Traces        []LogisticsTrace `gorm:"foreignKey:LogisticsID" json:"traces"`              // 添加这个字段
 }
 
 // LogisticsTrace 物流跟踪表
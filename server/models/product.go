@@ -7,21 +7,38 @@ import (
 	"gorm.io/gorm"
 )
 
+// 商品类型
+const (
+	ProductTypeStandard = "standard" // 普通商品
+	ProductTypeBundle   = "bundle"   // 套装，由其他商品组合而成，库存由组件库存推算
+	ProductTypeDigital  = "digital"  // 数字商品，支付后自动发放文件下载和授权码，无需物流
+)
+
 type Product struct {
-	ID          uint            `gorm:"primarykey;autoIncrement" json:"id"`              // 产品的唯一标识符
-	Name        string          `gorm:"type:varchar(100);not null" json:"name"`          // 产品名称
-	Description string          `gorm:"type:text" json:"description"`                    // 产品描述
-	Price       decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"price"`        // 产品价格
-	Stock       int             `gorm:"not null" json:"stock"`                           // 库存数量
-	Sales       int             `gorm:"default:0" json:"sales"`                          // 添加销量字段
-	Rating      float64         `gorm:"type:decimal(2,1);default:0" json:"rating"`       // 添加评分字段，保留一位小数
-	Status      string          `gorm:"type:varchar(20);default:'active'" json:"status"` // 产品状态：活跃/不活跃
-	Images      []string        `gorm:"type:json;serializer:json" json:"images"`         // 产品图片列表
-	Category    string          `gorm:"type:varchar(50)" json:"category"`                // 产品类别
-	Tags        []string        `gorm:"type:json;serializer:json" json:"tags"`           // 产品标签
-	CreatedAt   time.Time       `json:"created_at"`                                      // 创建时间
-	UpdatedAt   time.Time       `json:"updated_at"`                                      // 更新时间
-	DeletedAt   gorm.DeletedAt  `gorm:"index" json:"-"`                                  // 删除时间（软删除）
+	ID                uint              `gorm:"primarykey;autoIncrement" json:"id"`              // 产品的唯一标识符
+	Name              string            `gorm:"type:varchar(100);not null" json:"name"`          // 产品名称
+	Description       string            `gorm:"type:text" json:"description"`                    // 产品描述
+	Price             decimal.Decimal   `gorm:"type:decimal(10,2);not null" json:"price"`        // 产品价格
+	Stock             int               `gorm:"not null" json:"stock"`                           // 库存数量
+	LowStockThreshold int               `gorm:"default:0" json:"low_stock_threshold"`            // 低库存预警阈值，0表示使用默认阈值
+	Sales             int               `gorm:"default:0" json:"sales"`                          // 添加销量字段
+	Rating            float64           `gorm:"type:decimal(2,1);default:0" json:"rating"`       // 添加评分字段，保留一位小数
+	Status            string            `gorm:"type:varchar(20);default:'active'" json:"status"` // 产品状态：活跃/不活跃
+	Version           uint              `gorm:"not null;default:1" json:"version"`               // 乐观锁版本号
+	Images            []string          `gorm:"type:json;serializer:json" json:"images"`         // 产品图片列表
+	CategoryID        *uint             `gorm:"index" json:"category_id"`                        // 关联的类目ID
+	Category          string            `gorm:"type:varchar(50)" json:"category"`                // 类目名称，随 CategoryID 同步
+	Tags              []string          `gorm:"type:json;serializer:json" json:"tags"`           // 产品标签
+	ExternalID        *string           `gorm:"type:varchar(64);uniqueIndex" json:"external_id"` // 外部编码，如供应商货号，用于批量导入时匹配
+	Slug              string            `gorm:"type:varchar(100);index" json:"slug"`             // 当前的 URL 标识，唯一性由 ProductSlug 保证
+	Type              string            `gorm:"type:varchar(20);default:'standard'" json:"type"` // 商品类型：普通/套装/数字
+	CreatedAt         time.Time         `json:"created_at"`                                      // 创建时间
+	UpdatedAt         time.Time         `json:"updated_at"`                                      // 更新时间
+	DeletedAt         gorm.DeletedAt    `gorm:"index" json:"-"`                                  // 删除时间（软删除）
+	Options           []ProductOption   `gorm:"foreignKey:ProductID" json:"options,omitempty"`   // 规格项
+	SKUs              []SKU             `gorm:"foreignKey:ProductID" json:"skus,omitempty"`      // SKU列表
+	Components        []BundleComponent `gorm:"foreignKey:BundleID" json:"components,omitempty"` // 套装的组件
+	DisplayPrice      *Money            `gorm:"-" json:"display_price,omitempty"`                // 按请求币种换算的展示价格
 }
 
 // Review 商品评价表
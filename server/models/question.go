@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 问答审核状态
+const (
+	QAStatusPending  = "pending"  // 待审核
+	QAStatusApproved = "approved" // 已通过，前台可见
+	QAStatusRejected = "rejected" // 已驳回
+)
+
+// ProductQuestion 商品问题表，用户提问后需审核通过才会在前台展示
+type ProductQuestion struct {
+	ID          uint            `gorm:"primarykey;autoIncrement" json:"id"`                                                          // 问题的唯一标识符
+	ProductID   uint            `gorm:"not null;index:idx_question_product_status" json:"product_id"`                                // 关联的商品ID
+	Product     *Product        `gorm:"foreignKey:ProductID" json:"product,omitempty"`                                               // 关联的商品，审核列表中使用
+	UserID      uint            `gorm:"not null;index" json:"user_id"`                                                               // 提问用户ID
+	User        User            `gorm:"foreignKey:UserID" json:"user"`                                                               // 提问用户
+	Content     string          `gorm:"type:text;not null" json:"content"`                                                           // 问题内容
+	Status      string          `gorm:"type:varchar(20);not null;default:'pending';index:idx_question_product_status" json:"status"` // 审核状态
+	AnswerCount int             `gorm:"not null;default:0" json:"answer_count"`                                                      // 已审核通过的回答数量
+	Answers     []ProductAnswer `gorm:"foreignKey:QuestionID" json:"answers,omitempty"`                                              // 回答列表
+	CreatedAt   time.Time       `json:"created_at"`                                                                                  // 创建时间
+	UpdatedAt   time.Time       `json:"updated_at"`                                                                                  // 更新时间
+	DeletedAt   gorm.DeletedAt  `gorm:"index" json:"-"`                                                                              // 删除时间（软删除）
+}
+
+// ProductAnswer 商品回答表，只有管理员和已完成订单的购买者可以回答
+type ProductAnswer struct {
+	ID              uint             `gorm:"primarykey;autoIncrement" json:"id"`                              // 回答的唯一标识符
+	QuestionID      uint             `gorm:"not null;index" json:"question_id"`                               // 关联的问题ID
+	Question        *ProductQuestion `gorm:"foreignKey:QuestionID" json:"question,omitempty"`                 // 关联的问题，审核列表中使用
+	UserID          uint             `gorm:"not null;index" json:"user_id"`                                   // 回答用户ID
+	User            User             `gorm:"foreignKey:UserID" json:"user"`                                   // 回答用户
+	Content         string           `gorm:"type:text;not null" json:"content"`                               // 回答内容
+	IsAdmin         bool             `gorm:"not null;default:false" json:"is_admin"`                          // 是否为官方回答
+	IsVerifiedBuyer bool             `gorm:"not null;default:false" json:"is_verified_buyer"`                 // 回答时是否为已购买用户
+	Status          string           `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"` // 审核状态
+	Upvotes         int              `gorm:"not null;default:0" json:"upvotes"`                               // 点赞数
+	Upvoted         bool             `gorm:"-" json:"upvoted"`                                                // 当前用户是否已点赞
+	CreatedAt       time.Time        `json:"created_at"`                                                      // 创建时间
+	UpdatedAt       time.Time        `json:"updated_at"`                                                      // 更新时间
+	DeletedAt       gorm.DeletedAt   `gorm:"index" json:"-"`                                                  // 删除时间（软删除）
+}
+
+// AnswerVote 回答点赞记录，每个用户对每个回答只能点赞一次
+type AnswerVote struct {
+	ID        uint      `gorm:"primarykey;autoIncrement" json:"id"`                         // 点赞的唯一标识符
+	AnswerID  uint      `gorm:"not null;uniqueIndex:idx_answer_vote_user" json:"answer_id"` // 关联的回答ID
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_answer_vote_user" json:"user_id"`   // 点赞用户ID
+	CreatedAt time.Time `json:"created_at"`                                                 // 点赞时间
+}
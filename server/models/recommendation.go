@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+)
+
+// 人工关联状态常量
+const (
+	ProductRelationPinned  = "pinned"  // 置顶，优先出现在相关推荐中
+	ProductRelationBlocked = "blocked" // 屏蔽，不出现在相关推荐中
+)
+
+// ProductAssociation 根据已支付订单计算的商品共现关系表，由定时任务整体重建
+type ProductAssociation struct {
+	ID           uint      `gorm:"primarykey;autoIncrement" json:"id"`                               // 记录的唯一标识符
+	ProductID    uint      `gorm:"not null;uniqueIndex:idx_product_associated" json:"product_id"`    // 商品ID
+	AssociatedID uint      `gorm:"not null;uniqueIndex:idx_product_associated" json:"associated_id"` // 一起购买的商品ID
+	Orders       int       `gorm:"not null" json:"orders"`                                           // 同时出现的订单数
+	Score        float64   `gorm:"not null;index" json:"score"`                                      // 关联度，按两者各自订单数归一化，避免爆款与所有商品都强关联
+	CreatedAt    time.Time `json:"created_at"`                                                       // 计算时间
+}
+
+// ProductRelation 运营人工配置的商品关联表
+type ProductRelation struct {
+	ID         uint      `gorm:"primarykey;autoIncrement" json:"id"`                         // 记录的唯一标识符
+	ProductID  uint      `gorm:"not null;uniqueIndex:idx_product_related" json:"product_id"` // 商品ID
+	RelatedID  uint      `gorm:"not null;uniqueIndex:idx_product_related" json:"related_id"` // 关联的商品ID
+	Status     string    `gorm:"type:varchar(20);not null" json:"status"`                    // 状态：置顶/屏蔽
+	SortOrder  int       `gorm:"default:0" json:"sort_order"`                                // 置顶排序，数值越小越靠前
+	OperatorID uint      `json:"operator_id"`                                                // 操作人ID
+	CreatedAt  time.Time `json:"created_at"`                                                 // 创建时间
+	UpdatedAt  time.Time `json:"updated_at"`                                                 // 更新时间
+}
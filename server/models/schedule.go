@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 定时任务动作常量
+const (
+	ScheduleActionPublish   = "publish"   // 定时上架
+	ScheduleActionUnpublish = "unpublish" // 定时下架
+	ScheduleActionPrice     = "price"     // 定时改价
+)
+
+// 定时任务状态常量
+const (
+	ScheduleStatusPending   = "pending"   // 等待执行
+	ScheduleStatusApplied   = "applied"   // 已执行，如设置了恢复时间则等待恢复
+	ScheduleStatusReverted  = "reverted"  // 已恢复为执行前的值
+	ScheduleStatusSkipped   = "skipped"   // 执行后被人工修改，跳过恢复
+	ScheduleStatusCancelled = "cancelled" // 已取消
+	ScheduleStatusFailed    = "failed"    // 执行失败
+)
+
+// 价格变动来源常量
+const (
+	PriceSourceManual   = "manual"   // 人工修改商品或SKU
+	PriceSourceSchedule = "schedule" // 定时改价
+	PriceSourceRevert   = "revert"   // 定时改价到期恢复
+)
+
+// ProductSchedule 商品定时上下架和改价任务表
+type ProductSchedule struct {
+	ID             uint             `gorm:"primarykey;autoIncrement" json:"id"`            // 任务的唯一标识符
+	ProductID      uint             `gorm:"not null;index" json:"product_id"`              // 关联的产品ID
+	SKUID          uint             `gorm:"default:0" json:"sku_id"`                       // 改价的SKU ID，0表示商品本身
+	Action         string           `gorm:"type:varchar(20);not null" json:"action"`       // 动作：上架/下架/改价
+	Price          *decimal.Decimal `gorm:"type:decimal(10,2)" json:"price"`               // 改价的目标价格
+	RunAt          time.Time        `gorm:"not null;index" json:"run_at"`                  // 执行时间
+	RevertAt       *time.Time       `gorm:"index" json:"revert_at"`                        // 恢复时间，为空表示不恢复
+	Status         string           `gorm:"type:varchar(20);not null;index" json:"status"` // 任务状态
+	PreviousPrice  *decimal.Decimal `gorm:"type:decimal(10,2)" json:"previous_price"`      // 执行前的价格，用于恢复
+	PreviousStatus string           `gorm:"type:varchar(20)" json:"previous_status"`       // 执行前的商品状态，用于恢复
+	Message        string           `gorm:"type:varchar(255)" json:"message"`              // 失败或跳过的原因
+	OperatorID     uint             `json:"operator_id"`                                   // 创建人ID
+	AppliedAt      *time.Time       `json:"applied_at"`                                    // 执行时间
+	RevertedAt     *time.Time       `json:"reverted_at"`                                   // 恢复时间
+	CreatedAt      time.Time        `json:"created_at"`                                    // 创建时间
+	UpdatedAt      time.Time        `json:"updated_at"`                                    // 更新时间
+}
+
+// PriceHistory 商品价格变动历史表，用于价格保护
+type PriceHistory struct {
+	ID         uint            `gorm:"primarykey;autoIncrement" json:"id"`                   // 记录的唯一标识符
+	ProductID  uint            `gorm:"not null;index:idx_product_created" json:"product_id"` // 关联的产品ID
+	SKUID      uint            `gorm:"default:0" json:"sku_id"`                              // SKU ID，0表示商品展示价格
+	OldPrice   decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"old_price"`         // 变动前价格
+	NewPrice   decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"new_price"`         // 变动后价格
+	Source     string          `gorm:"type:varchar(20);not null" json:"source"`              // 变动来源
+	ScheduleID *uint           `json:"schedule_id"`                                          // 关联的定时任务ID
+	OperatorID uint            `json:"operator_id"`                                          // 操作人ID，定时任务为创建人
+	CreatedAt  time.Time       `gorm:"index:idx_product_created" json:"created_at"`          // 变动时间
+}
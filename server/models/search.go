@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// 搜索词运营状态常量
+const (
+	SearchTermPinned  = "pinned"  // 置顶，优先出现在热搜和联想中
+	SearchTermBlocked = "blocked" // 屏蔽，不出现在热搜和联想中
+)
+
+// SearchLog 搜索日志表
+type SearchLog struct {
+	ID          uint      `gorm:"primarykey;autoIncrement" json:"id"`            // 日志的唯一标识符
+	Query       string    `gorm:"type:varchar(100);not null;index" json:"query"` // 规范化后的搜索词
+	ResultCount int       `gorm:"not null" json:"result_count"`                  // 搜索结果数量
+	UserID      *uint     `gorm:"index" json:"user_id"`                          // 搜索用户ID，未登录为空
+	CreatedAt   time.Time `gorm:"index" json:"created_at"`                       // 搜索时间
+}
+
+// SearchTerm 运营配置的搜索词表
+type SearchTerm struct {
+	ID        uint      `gorm:"primarykey;autoIncrement" json:"id"`                 // 搜索词的唯一标识符
+	Term      string    `gorm:"type:varchar(100);uniqueIndex;not null" json:"term"` // 搜索词
+	Status    string    `gorm:"type:varchar(20);not null;index" json:"status"`      // 状态：置顶/屏蔽
+	SortOrder int       `gorm:"default:0" json:"sort_order"`                        // 置顶排序，数值越小越靠前
+	CreatedAt time.Time `json:"created_at"`                                         // 创建时间
+	UpdatedAt time.Time `json:"updated_at"`                                         // 更新时间
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ProductOption 产品规格项，如尺码、颜色
+type ProductOption struct {
+	ID        uint      `gorm:"primarykey;autoIncrement" json:"id"`      // 规格项的唯一标识符
+	ProductID uint      `gorm:"not null;index" json:"product_id"`        // 关联的产品ID
+	Name      string    `gorm:"type:varchar(50);not null" json:"name"`   // 规格名称，如 size、colour
+	Values    []string  `gorm:"type:json;serializer:json" json:"values"` // 可选值列表，如 ["S","M","L"]
+	SortOrder int       `gorm:"default:0" json:"sort_order"`             // 排序，数值越小越靠前
+	CreatedAt time.Time `json:"created_at"`                              // 创建时间
+	UpdatedAt time.Time `json:"updated_at"`                              // 更新时间
+}
+
+// SKU 产品库存单位，对应一组规格值的组合
+type SKU struct {
+	ID           uint              `gorm:"primarykey;autoIncrement" json:"id"`                // SKU的唯一标识符
+	ProductID    uint              `gorm:"not null;index" json:"product_id"`                  // 关联的产品ID
+	Code         string            `gorm:"type:varchar(64);uniqueIndex;not null" json:"code"` // SKU编码
+	Options      map[string]string `gorm:"type:json;serializer:json" json:"options"`          // 规格值组合，如 {"size":"M","colour":"red"}
+	Price        decimal.Decimal   `gorm:"type:decimal(10,2);not null" json:"price"`          // SKU价格
+	Stock        int               `gorm:"not null;default:0" json:"stock"`                   // SKU库存
+	Barcode      string            `gorm:"type:varchar(64)" json:"barcode"`                   // 条形码
+	Image        string            `gorm:"type:varchar(255)" json:"image"`                    // SKU图片URL
+	Status       string            `gorm:"type:varchar(20);default:'active'" json:"status"`   // SKU状态：活跃/不活跃
+	CreatedAt    time.Time         `json:"created_at"`                                        // 创建时间
+	UpdatedAt    time.Time         `json:"updated_at"`                                        // 更新时间
+	DeletedAt    gorm.DeletedAt    `gorm:"index" json:"-"`                                    // 删除时间（软删除）
+	DisplayPrice *Money            `gorm:"-" json:"display_price,omitempty"`                  // 按请求币种换算的展示价格
+}
+
+// TableName 指定 SKU 表名
+func (SKU) TableName() string {
+	return "skus"
+}
@@ -0,0 +1,13 @@
+package models
+
+import (
+	"time"
+)
+
+// ProductSlug 商品 URL 标识表，保留商品用过的全部 slug，旧 slug 跳转到当前 slug
+type ProductSlug struct {
+	ID        uint      `gorm:"primarykey;autoIncrement" json:"id"`                 // 记录的唯一标识符
+	ProductID uint      `gorm:"not null;index" json:"product_id"`                   // 关联的产品ID
+	Slug      string    `gorm:"type:varchar(100);uniqueIndex;not null" json:"slug"` // URL 标识，所有商品之间唯一
+	CreatedAt time.Time `json:"created_at"`                                         // 创建时间
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// 翻译对象类型常量
+const (
+	TranslationEntityProduct       = "product"       // 商品，可翻译名称和描述
+	TranslationEntityCategory      = "category"      // 类目，可翻译名称
+	TranslationEntityAdvertisement = "advertisement" // 广告，可翻译标题
+)
+
+// 可翻译字段常量
+const (
+	TranslationFieldName        = "name"
+	TranslationFieldDescription = "description"
+	TranslationFieldTitle       = "title"
+)
+
+// Translation 多语言内容表，默认语言的内容保存在对象本身，这里只保存其他语言
+type Translation struct {
+	ID         uint      `gorm:"primarykey;autoIncrement" json:"id"`                                              // 记录的唯一标识符
+	EntityType string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_translation_entity" json:"entity_type"` // 对象类型：商品/类目/广告
+	EntityID   uint      `gorm:"not null;uniqueIndex:idx_translation_entity" json:"entity_id"`                    // 对象ID
+	Locale     string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_translation_entity" json:"locale"`      // 语言，如 en
+	Field      string    `gorm:"type:varchar(30);not null;uniqueIndex:idx_translation_entity" json:"field"`       // 翻译的字段，如 name
+	Value      string    `gorm:"type:text;not null" json:"value"`                                                 // 翻译内容
+	CreatedAt  time.Time `json:"created_at"`                                                                      // 创建时间
+	UpdatedAt  time.Time `json:"updated_at"`                                                                      // 更新时间
+}
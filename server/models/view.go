@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+// ProductView 商品浏览记录表
+type ProductView struct {
+	ID        uint      `gorm:"primarykey;autoIncrement" json:"id"`                                 // 记录的唯一标识符
+	ProductID uint      `gorm:"not null;index:idx_product_created" json:"product_id"`               // 浏览的商品ID
+	UserID    *uint     `gorm:"index:idx_user_created" json:"user_id"`                              // 浏览用户ID，未登录为空
+	SessionID string    `gorm:"type:varchar(64);index" json:"session_id"`                           // 匿名会话标识，用于统计未登录访客
+	Referrer  string    `gorm:"type:varchar(255)" json:"referrer"`                                  // 来源，如搜索、相关推荐或外部链接
+	CreatedAt time.Time `gorm:"index:idx_product_created;index:idx_user_created" json:"created_at"` // 浏览时间
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Warehouse 仓库表
+type Warehouse struct {
+	ID        uint           `gorm:"primarykey;autoIncrement" json:"id"`              // 仓库的唯一标识符
+	Code      string         `gorm:"type:varchar(20);unique;not null" json:"code"`    // 仓库编码
+	Name      string         `gorm:"type:varchar(50);not null" json:"name"`           // 仓库名称
+	Province  string         `gorm:"type:varchar(50)" json:"province"`                // 所在省份
+	City      string         `gorm:"type:varchar(50)" json:"city"`                    // 所在城市
+	Address   string         `gorm:"type:varchar(255)" json:"address"`                // 详细地址
+	Contact   string         `gorm:"type:varchar(50)" json:"contact"`                 // 联系人
+	Phone     string         `gorm:"type:varchar(20)" json:"phone"`                   // 联系电话
+	Regions   []string       `gorm:"type:json;serializer:json" json:"regions"`        // 优先配送的省份列表
+	Priority  int            `gorm:"default:0" json:"priority"`                       // 分配优先级，数值越小越优先
+	Status    string         `gorm:"type:varchar(20);default:'active'" json:"status"` // 仓库状态：活跃/不活跃
+	CreatedAt time.Time      `json:"created_at"`                                      // 创建时间
+	UpdatedAt time.Time      `json:"updated_at"`                                      // 更新时间
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`                                  // 删除时间（软删除）
+}
+
+// WarehouseStock 仓库库存表
+type WarehouseStock struct {
+	ID          uint      `gorm:"primarykey;autoIncrement" json:"id"`                             // 仓库库存的唯一标识符
+	WarehouseID uint      `gorm:"not null;uniqueIndex:idx_warehouse_product" json:"warehouse_id"` // 关联的仓库ID
+	Warehouse   Warehouse `gorm:"foreignKey:WarehouseID" json:"warehouse"`                        // 关联的仓库对象
+	ProductID   uint      `gorm:"not null;uniqueIndex:idx_warehouse_product" json:"product_id"`   // 关联的产品ID
+	Product     Product   `gorm:"foreignKey:ProductID" json:"-"`                                  // 关联的产品对象，JSON序列化时忽略
+	Quantity    int       `gorm:"not null;default:0" json:"quantity"`                             // 库存数量
+	CreatedAt   time.Time `json:"created_at"`                                                     // 创建时间
+	UpdatedAt   time.Time `json:"updated_at"`                                                     // 更新时间
+}
+
+// OrderAllocation 订单发货仓分配表
+type OrderAllocation struct {
+	ID          uint      `gorm:"primarykey;autoIncrement" json:"id"`      // 分配记录的唯一标识符
+	OrderID     uint      `gorm:"not null;index" json:"order_id"`          // 关联的订单ID
+	OrderItemID uint      `gorm:"not null" json:"order_item_id"`           // 关联的订单项ID
+	WarehouseID uint      `gorm:"not null;index" json:"warehouse_id"`      // 发货仓库ID
+	Warehouse   Warehouse `gorm:"foreignKey:WarehouseID" json:"warehouse"` // 发货仓库对象
+	ProductID   uint      `gorm:"not null" json:"product_id"`              // 关联的产品ID
+	Quantity    int       `gorm:"not null" json:"quantity"`                // 分配数量
+	CreatedAt   time.Time `json:"created_at"`                              // 创建时间
+}
@@ -0,0 +1,100 @@
+package scheduler
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Job 定时任务
+type Job struct {
+	Name string
+	Run  func() error
+	next func(now time.Time) time.Time // 计算下一次执行时间
+}
+
+// Scheduler 简单的进程内定时任务调度器
+type Scheduler struct {
+	jobs []*Job
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		stop: make(chan struct{}),
+	}
+}
+
+// Every 按固定间隔执行任务
+func (s *Scheduler) Every(name string, interval time.Duration, run func() error) {
+	s.jobs = append(s.jobs, &Job{
+		Name: name,
+		Run:  run,
+		next: func(now time.Time) time.Time {
+			return now.Add(interval)
+		},
+	})
+}
+
+// Daily 每天在指定时间执行任务
+func (s *Scheduler) Daily(name string, hour, minute int, run func() error) {
+	s.jobs = append(s.jobs, &Job{
+		Name: name,
+		Run:  run,
+		next: func(now time.Time) time.Time {
+			next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+			if !next.After(now) {
+				next = next.AddDate(0, 0, 1)
+			}
+			return next
+		},
+	})
+}
+
+// Start 启动所有任务，每个任务在独立的 goroutine 中运行
+func (s *Scheduler) Start() {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(job)
+	}
+}
+
+// Stop 停止所有任务并等待正在执行的任务结束
+func (s *Scheduler) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(job *Job) {
+	defer s.wg.Done()
+
+	for {
+		wait := time.Until(job.next(time.Now()))
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-s.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.runJob(job)
+		}
+	}
+}
+
+// runJob 执行任务并记录结果，任务 panic 不影响调度器
+func (s *Scheduler) runJob(job *Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[scheduler] job %s panic: %v", job.Name, r)
+		}
+	}()
+
+	start := time.Now()
+	if err := job.Run(); err != nil {
+		log.Printf("[scheduler] job %s failed: %v", job.Name, err)
+		return
+	}
+	log.Printf("[scheduler] job %s finished in %v", job.Name, time.Since(start))
+}
@@ -0,0 +1,547 @@
+package search
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// 各字段在相关度中的权重
+const (
+	nameWeight  = 3.0
+	tagWeight   = 2.0
+	bodyWeight  = 1.0
+	salesWeight = 0.1
+)
+
+// DefaultPriceBuckets 默认的价格分面区间边界
+var DefaultPriceBuckets = []float64{50, 100, 200, 500, 1000, 2000}
+
+// posting 词元在单个文档中各字段的出现次数
+type posting struct {
+	name int
+	tags int
+	body int
+}
+
+// MemoryIndex 进程内倒排索引
+type MemoryIndex struct {
+	mu           sync.RWMutex
+	docs         map[uint]*Document
+	postings     map[string]map[uint]*posting // 词元 -> 文档 -> 出现次数
+	terms        map[string]int               // 纠错词典：完整词 -> 文档数
+	docTerms     map[uint][]string            // 文档包含的完整词，删除时使用
+	priceBuckets []float64
+}
+
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{
+		docs:         make(map[uint]*Document),
+		postings:     make(map[string]map[uint]*posting),
+		terms:        make(map[string]int),
+		docTerms:     make(map[uint][]string),
+		priceBuckets: DefaultPriceBuckets,
+	}
+}
+
+// Index 写入或更新文档
+func (m *MemoryIndex) Index(doc Document) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.remove(doc.ID)
+	m.add(&doc)
+	return nil
+}
+
+// Delete 从索引中删除文档
+func (m *MemoryIndex) Delete(id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.remove(id)
+	return nil
+}
+
+// Rebuild 使用全量文档重建索引
+func (m *MemoryIndex) Rebuild(docs []Document) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.docs = make(map[uint]*Document, len(docs))
+	m.postings = make(map[string]map[uint]*posting)
+	m.terms = make(map[string]int)
+	m.docTerms = make(map[uint][]string, len(docs))
+	for i := range docs {
+		m.add(&docs[i])
+	}
+	return nil
+}
+
+// add 写入文档，调用方需持有写锁
+func (m *MemoryIndex) add(doc *Document) {
+	m.docs[doc.ID] = doc
+
+	addTokens := func(text string, field func(p *posting)) {
+		for _, token := range Tokenize(text) {
+			docs, ok := m.postings[token]
+			if !ok {
+				docs = make(map[uint]*posting)
+				m.postings[token] = docs
+			}
+			p, ok := docs[doc.ID]
+			if !ok {
+				p = &posting{}
+				docs[doc.ID] = p
+			}
+			field(p)
+		}
+	}
+	addTokens(doc.Name, func(p *posting) { p.name++ })
+	addTokens(doc.CategoryName+" "+strings.Join(doc.Tags, " "), func(p *posting) { p.tags++ })
+	addTokens(doc.Description, func(p *posting) { p.body++ })
+	for _, t := range doc.Translations {
+		addTokens(t.Name, func(p *posting) { p.name++ })
+		addTokens(t.CategoryName, func(p *posting) { p.tags++ })
+		addTokens(t.Description, func(p *posting) { p.body++ })
+	}
+
+	// 纠错词典只收录各语言名称、类目和标签中的词
+	seen := make(map[string]bool)
+	for _, word := range Words(doc.keywordText()) {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		m.terms[word]++
+		m.docTerms[doc.ID] = append(m.docTerms[doc.ID], word)
+	}
+}
+
+// keywordText 文档各语言的名称、类目和标签
+func (doc *Document) keywordText() string {
+	parts := []string{doc.Name, doc.CategoryName, strings.Join(doc.Tags, " ")}
+	for _, t := range doc.Translations {
+		parts = append(parts, t.Name, t.CategoryName)
+	}
+	return strings.Join(parts, " ")
+}
+
+// remove 删除文档，调用方需持有写锁
+func (m *MemoryIndex) remove(id uint) {
+	doc, ok := m.docs[id]
+	if !ok {
+		return
+	}
+
+	text := doc.keywordText() + " " + doc.Description
+	for _, t := range doc.Translations {
+		text += " " + t.Description
+	}
+	for _, token := range Tokenize(text) {
+		if docs, ok := m.postings[token]; ok {
+			delete(docs, id)
+			if len(docs) == 0 {
+				delete(m.postings, token)
+			}
+		}
+	}
+	for _, word := range m.docTerms[id] {
+		if m.terms[word]--; m.terms[word] <= 0 {
+			delete(m.terms, word)
+		}
+	}
+	delete(m.docTerms, id)
+	delete(m.docs, id)
+}
+
+// expandToken 单个中日韩文字无法命中二元组，展开为以该字开头或结尾的词元
+func (m *MemoryIndex) expandToken(token string) []string {
+	if utf8.RuneCountInString(token) != 1 {
+		return []string{token}
+	}
+	r, _ := utf8.DecodeRuneInString(token)
+	if !isCJK(r) {
+		return []string{token}
+	}
+	expanded := []string{token}
+	for candidate := range m.postings {
+		if candidate != token && strings.ContainsRune(candidate, r) {
+			expanded = append(expanded, candidate)
+		}
+	}
+	return expanded
+}
+
+// matches 判断文档是否满足筛选条件
+func (q *Query) matches(doc *Document) bool {
+	if doc.Status != "active" {
+		return false
+	}
+	if len(q.CategoryIDs) > 0 {
+		found := false
+		for _, id := range q.CategoryIDs {
+			if doc.CategoryID == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(q.Tags) > 0 {
+		found := false
+		for _, want := range q.Tags {
+			for _, tag := range doc.Tags {
+				if tag == want {
+					found = true
+					break
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if q.MinPrice > 0 && doc.Price < q.MinPrice {
+		return false
+	}
+	if q.MaxPrice > 0 && doc.Price > q.MaxPrice {
+		return false
+	}
+	return true
+}
+
+// Search 执行搜索，多个词元之间为 AND 关系
+func (m *MemoryIndex) Search(query Query) (*Result, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	scores := make(map[uint]float64)
+	tokens := uniqueStrings(Tokenize(query.Text))
+
+	if len(tokens) == 0 {
+		// 无搜索文本时返回全部文档，按销量排序
+		for id := range m.docs {
+			scores[id] = 0
+		}
+	} else {
+		total := float64(len(m.docs))
+		for i, token := range tokens {
+			matched := make(map[uint]float64)
+			for _, term := range m.expandToken(token) {
+				docs := m.postings[term]
+				idf := math.Log(1 + total/float64(len(docs)+1))
+				for id, p := range docs {
+					score := idf * (nameWeight*float64(p.name) + tagWeight*float64(p.tags) + bodyWeight*float64(p.body))
+					if score > matched[id] {
+						matched[id] = score
+					}
+				}
+			}
+			if i == 0 {
+				scores = matched
+				continue
+			}
+			for id := range scores {
+				if score, ok := matched[id]; ok {
+					scores[id] += score
+				} else {
+					delete(scores, id)
+				}
+			}
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	matchedDocs := make([]*Document, 0, len(scores))
+	for id, score := range scores {
+		doc := m.docs[id]
+		if !query.matches(doc) {
+			continue
+		}
+		// 销量作为加权因子，避免冷门商品完全沉底
+		score = (score + 1) * (1 + salesWeight*math.Log1p(float64(doc.Sales)))
+		hits = append(hits, Hit{ID: id, Score: score})
+		matchedDocs = append(matchedDocs, doc)
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID > hits[j].ID
+	})
+
+	result := &Result{
+		Total:  len(hits),
+		Facets: m.facets(matchedDocs),
+	}
+
+	start := (query.Page - 1) * query.PageSize
+	if start < 0 {
+		start = 0
+	}
+	end := start + query.PageSize
+	if query.PageSize <= 0 || end > len(hits) {
+		end = len(hits)
+	}
+	if start < end {
+		result.Hits = hits[start:end]
+	} else {
+		result.Hits = []Hit{}
+	}
+
+	result.Suggestions = m.suggest(query.Text)
+	return result, nil
+}
+
+// Complete 返回名称匹配前缀的商品名称，按销量排序
+func (m *MemoryIndex) Complete(prefix string, limit int) ([]string, error) {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" || limit <= 0 {
+		return []string{}, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// 各语言的名称都参与匹配，返回命中的那个名称
+	type completion struct {
+		doc  *Document
+		name string
+	}
+	var matched []completion
+	for _, doc := range m.docs {
+		if doc.Status != "active" {
+			continue
+		}
+		names := []string{doc.Name}
+		for _, t := range doc.Translations {
+			if t.Name != "" {
+				names = append(names, t.Name)
+			}
+		}
+		for _, name := range names {
+			if completes(name, prefix) {
+				matched = append(matched, completion{doc: doc, name: name})
+				break
+			}
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		if matched[i].doc.Sales != matched[j].doc.Sales {
+			return matched[i].doc.Sales > matched[j].doc.Sales
+		}
+		return matched[i].doc.ID < matched[j].doc.ID
+	})
+
+	names := make([]string, 0, limit)
+	seen := make(map[string]bool)
+	for _, c := range matched {
+		if len(names) >= limit {
+			break
+		}
+		if !seen[c.name] {
+			seen[c.name] = true
+			names = append(names, c.name)
+		}
+	}
+	return names, nil
+}
+
+// completes 判断名称或名称中的某个词是否以 prefix 开头
+func completes(name, prefix string) bool {
+	if strings.HasPrefix(strings.ToLower(name), prefix) {
+		return true
+	}
+	for _, word := range Words(name) {
+		if strings.HasPrefix(word, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// facets 统计命中文档的类目、标签和价格区间分布
+func (m *MemoryIndex) facets(docs []*Document) Facets {
+	categories := make(map[uint]*FacetCount)
+	tags := make(map[string]*FacetCount)
+	buckets := make([]FacetCount, len(m.priceBuckets)+1)
+
+	for i := range buckets {
+		var low, high string
+		if i > 0 {
+			low = strconv.FormatFloat(m.priceBuckets[i-1], 'f', -1, 64)
+		}
+		if i < len(m.priceBuckets) {
+			high = strconv.FormatFloat(m.priceBuckets[i], 'f', -1, 64)
+		}
+		buckets[i].Value = low + "-" + high
+		switch {
+		case low == "":
+			buckets[i].Label = fmt.Sprintf("%s以下", high)
+		case high == "":
+			buckets[i].Label = fmt.Sprintf("%s以上", low)
+		default:
+			buckets[i].Label = fmt.Sprintf("%s-%s", low, high)
+		}
+	}
+
+	for _, doc := range docs {
+		if doc.CategoryID != 0 {
+			facet, ok := categories[doc.CategoryID]
+			if !ok {
+				facet = &FacetCount{Value: strconv.FormatUint(uint64(doc.CategoryID), 10), Label: doc.CategoryName}
+				categories[doc.CategoryID] = facet
+			}
+			facet.Count++
+		}
+		for _, tag := range uniqueStrings(doc.Tags) {
+			facet, ok := tags[tag]
+			if !ok {
+				facet = &FacetCount{Value: tag, Label: tag}
+				tags[tag] = facet
+			}
+			facet.Count++
+		}
+		bucket := sort.SearchFloat64s(m.priceBuckets, doc.Price)
+		if bucket < len(m.priceBuckets) && doc.Price == m.priceBuckets[bucket] {
+			bucket++
+		}
+		buckets[bucket].Count++
+	}
+
+	facets := Facets{
+		Categories:   sortFacets(categories),
+		Tags:         sortFacets(tags),
+		PriceBuckets: make([]FacetCount, 0, len(buckets)),
+	}
+	for _, bucket := range buckets {
+		if bucket.Count > 0 {
+			facets.PriceBuckets = append(facets.PriceBuckets, bucket)
+		}
+	}
+	return facets
+}
+
+// sortFacets 按数量降序排列分面
+func sortFacets[K comparable](facets map[K]*FacetCount) []FacetCount {
+	list := make([]FacetCount, 0, len(facets))
+	for _, facet := range facets {
+		list = append(list, *facet)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Value < list[j].Value
+	})
+	return list
+}
+
+// suggest 对词典中不存在的词给出纠错建议，返回替换后的完整查询
+func (m *MemoryIndex) suggest(text string) []string {
+	words := Words(text)
+	if len(words) == 0 {
+		return []string{}
+	}
+
+	const maxSuggestions = 3
+	for i, word := range words {
+		if m.knownWord(word) {
+			continue
+		}
+
+		candidates := m.closestTerms(word, maxSuggestions)
+		suggestions := make([]string, 0, len(candidates))
+		for _, candidate := range candidates {
+			corrected := make([]string, len(words))
+			copy(corrected, words)
+			corrected[i] = candidate
+			suggestions = append(suggestions, strings.Join(corrected, " "))
+		}
+		return suggestions
+	}
+	return []string{}
+}
+
+// knownWord 判断词是否出现在词典中，或其全部词元都能在索引中命中
+func (m *MemoryIndex) knownWord(word string) bool {
+	if _, ok := m.terms[word]; ok {
+		return true
+	}
+	for _, token := range Tokenize(word) {
+		found := false
+		for _, term := range m.expandToken(token) {
+			if _, ok := m.postings[term]; ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// closestTerms 查找编辑距离最近的词，距离相同时优先文档数多的词
+func (m *MemoryIndex) closestTerms(word string, limit int) []string {
+	length := utf8.RuneCountInString(word)
+	maxDistance := 1
+	if length > 4 {
+		maxDistance = 2
+	}
+
+	type candidate struct {
+		term     string
+		distance int
+		freq     int
+	}
+	var candidates []candidate
+	for term, freq := range m.terms {
+		diff := utf8.RuneCountInString(term) - length
+		if diff > maxDistance || -diff > maxDistance {
+			continue
+		}
+		if distance := editDistance(word, term); distance <= maxDistance {
+			candidates = append(candidates, candidate{term, distance, freq})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		if candidates[i].freq != candidates[j].freq {
+			return candidates[i].freq > candidates[j].freq
+		}
+		return candidates[i].term < candidates[j].term
+	})
+
+	terms := make([]string, 0, limit)
+	for i := 0; i < len(candidates) && i < limit; i++ {
+		terms = append(terms, candidates[i].term)
+	}
+	return terms
+}
+
+// uniqueStrings 去重并保持原有顺序
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
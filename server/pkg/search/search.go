@@ -0,0 +1,81 @@
+package search
+
+// Document 索引中的商品文档
+type Document struct {
+	ID           uint     // 产品ID
+	Name         string   // 产品名称
+	Description  string   // 产品描述
+	CategoryID   uint     // 类目ID
+	CategoryName string   // 类目名称
+	Tags         []string // 标签
+	Price        float64  // 价格
+	Sales        int      // 销量
+	Status       string   // 产品状态，仅 active 可被搜索
+
+	Translations []LocalizedText // 其他语言的文本，与默认语言一同建立索引
+}
+
+// LocalizedText 文档在某个语言下的文本，未翻译的字段为空
+type LocalizedText struct {
+	Locale       string // 语言
+	Name         string // 产品名称
+	Description  string // 产品描述
+	CategoryName string // 类目名称
+}
+
+// Query 搜索条件
+type Query struct {
+	Text        string   // 搜索文本
+	CategoryIDs []uint   // 类目筛选，命中任一即可
+	Tags        []string // 标签筛选，命中任一即可
+	MinPrice    float64  // 最低价格
+	MaxPrice    float64  // 最高价格
+	Page        int
+	PageSize    int
+}
+
+// Hit 单条搜索结果
+type Hit struct {
+	ID    uint    `json:"id"`    // 产品ID
+	Score float64 `json:"score"` // 相关度得分
+}
+
+// FacetCount 分面统计项
+type FacetCount struct {
+	Value string `json:"value"` // 分面取值
+	Label string `json:"label"` // 展示名称
+	Count int    `json:"count"` // 命中数量
+}
+
+// Facets 搜索结果的分面统计
+type Facets struct {
+	Categories   []FacetCount `json:"categories"`    // 按类目统计
+	Tags         []FacetCount `json:"tags"`          // 按标签统计
+	PriceBuckets []FacetCount `json:"price_buckets"` // 按价格区间统计
+}
+
+// Result 搜索结果
+type Result struct {
+	Hits        []Hit    `json:"hits"`        // 当前页结果
+	Total       int      `json:"total"`       // 命中总数
+	Facets      Facets   `json:"facets"`      // 分面统计
+	Suggestions []string `json:"suggestions"` // 纠错建议
+}
+
+// Engine 搜索引擎接口
+type Engine interface {
+	// Index 写入或更新文档
+	Index(doc Document) error
+
+	// Delete 从索引中删除文档
+	Delete(id uint) error
+
+	// Rebuild 使用全量文档重建索引
+	Rebuild(docs []Document) error
+
+	// Search 执行搜索
+	Search(query Query) (*Result, error)
+
+	// Complete 返回名称以 prefix 开头或包含以 prefix 开头的词的商品名称，按销量排序
+	Complete(prefix string, limit int) ([]string, error)
+}
@@ -0,0 +1,89 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// isCJK 判断是否为中日韩文字
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// Tokenize 分词：字母和数字按单词切分并转为小写，中日韩文字按二元组切分
+// 单个孤立的中日韩文字作为一个词元
+func Tokenize(text string) []string {
+	var tokens []string
+	for _, word := range Words(text) {
+		runes := []rune(word)
+		if !isCJK(runes[0]) {
+			tokens = append(tokens, word)
+			continue
+		}
+		if len(runes) == 1 {
+			tokens = append(tokens, word)
+			continue
+		}
+		for i := 0; i+1 < len(runes); i++ {
+			tokens = append(tokens, string(runes[i:i+2]))
+		}
+	}
+	return tokens
+}
+
+// Words 按空白和标点切分为词，中日韩连续文字作为一个词，字母数字与中日韩文字之间也会切分
+func Words(text string) []string {
+	var words []string
+	var current []rune
+	currentCJK := false
+
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = current[:0]
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			if !currentCJK {
+				flush()
+			}
+			currentCJK = true
+			current = append(current, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if currentCJK {
+				flush()
+			}
+			currentCJK = false
+			current = append(current, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return words
+}
+
+// editDistance 计算两个词的编辑距离
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
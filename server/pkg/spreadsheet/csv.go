@@ -0,0 +1,35 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"unicode/utf8"
+)
+
+// utf8BOM Excel 保存的 UTF-8 CSV 会带有 BOM
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ReadCSV 读取 UTF-8 编码的 CSV，允许各行列数不一致
+func ReadCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	if !utf8.Valid(data) {
+		return nil, errors.New("csv file must be UTF-8 encoded")
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	return reader.ReadAll()
+}
+
+// WriteCSV 写出带 BOM 的 UTF-8 CSV，便于 Excel 正确识别中文
+func WriteCSV(rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(utf8BOM)
+	writer := csv.NewWriter(&buf)
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,60 @@
+package spreadsheet
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+// 支持的表格格式
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// ErrUnsupportedFormat 不支持的表格格式
+var ErrUnsupportedFormat = errors.New("unsupported spreadsheet format")
+
+// DetectFormat 根据文件名扩展名判断表格格式
+func DetectFormat(filename string) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// Read 读取表格的所有行，XLSX 只读取第一个工作表
+func Read(format string, data []byte) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		return ReadCSV(data)
+	case FormatXLSX:
+		return ReadXLSX(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// Write 将所有行写为指定格式的表格
+func Write(format string, rows [][]string) ([]byte, error) {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(rows)
+	case FormatXLSX:
+		return WriteXLSX(rows)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// ContentType 返回表格格式对应的 MIME 类型
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
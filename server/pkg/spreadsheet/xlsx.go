@@ -0,0 +1,299 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidXLSX 文件不是有效的 XLSX 工作簿
+var ErrInvalidXLSX = errors.New("invalid xlsx file")
+
+// maxXLSXPartSize 单个 XML 部件解压后的大小上限，防止压缩炸弹
+const maxXLSXPartSize = 200 << 20
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText 共享字符串和内联字符串，富文本由多个 r 片段组成
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var builder strings.Builder
+	for _, run := range t.Runs {
+		builder.WriteString(run.T)
+	}
+	return builder.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxCell struct {
+	Ref    string   `xml:"r,attr"`
+	Type   string   `xml:"t,attr"`
+	Value  string   `xml:"v"`
+	Inline xlsxText `xml:"is"`
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Index int        `xml:"r,attr"`
+		Cells []xlsxCell `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ReadXLSX 读取 XLSX 第一个工作表的所有行，空行保留为空切片以保持行号一致
+func ReadXLSX(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, ErrInvalidXLSX
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared xlsxSharedStrings
+	if file, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeXMLPart(file, &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	file, ok := files[sheetPath]
+	if !ok {
+		return nil, ErrInvalidXLSX
+	}
+	var sheet xlsxSheet
+	if err := decodeXMLPart(file, &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(sheet.Rows))
+	for i, row := range sheet.Rows {
+		// 行号缺省时按顺序递增，空行在文件中会被省略
+		index := row.Index
+		if index == 0 {
+			index = len(rows) + 1
+		}
+		for len(rows) < index-1 {
+			rows = append(rows, []string{})
+		}
+
+		values := make([]string, 0, len(row.Cells))
+		for j, cell := range row.Cells {
+			column := j
+			if cell.Ref != "" {
+				if column, err = columnIndex(cell.Ref); err != nil {
+					return nil, fmt.Errorf("row %d: %v", i+1, err)
+				}
+			}
+			for len(values) < column {
+				values = append(values, "")
+			}
+			value, err := cellValue(cell, shared.Items)
+			if err != nil {
+				return nil, err
+			}
+			if column < len(values) {
+				values[column] = value
+			} else {
+				values = append(values, value)
+			}
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+// firstSheetPath 通过工作簿关系找到第一个工作表的路径
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	workbookFile, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", ErrInvalidXLSX
+	}
+	var workbook xlsxWorkbook
+	if err := decodeXMLPart(workbookFile, &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("xlsx file has no worksheet")
+	}
+
+	relsFile, ok := files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return "xl/worksheets/sheet1.xml", nil
+	}
+	var rels xlsxRelationships
+	if err := decodeXMLPart(relsFile, &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].RelID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return "", ErrInvalidXLSX
+}
+
+func decodeXMLPart(file *zip.File, v interface{}) error {
+	reader, err := file.Open()
+	if err != nil {
+		return ErrInvalidXLSX
+	}
+	defer reader.Close()
+	if err := xml.NewDecoder(io.LimitReader(reader, maxXLSXPartSize)).Decode(v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidXLSX, file.Name, err)
+	}
+	return nil
+}
+
+// cellValue 按单元格类型取出文本值
+func cellValue(cell xlsxCell, shared []xlsxText) (string, error) {
+	switch cell.Type {
+	case "s":
+		index, err := strconv.Atoi(cell.Value)
+		if err != nil || index < 0 || index >= len(shared) {
+			return "", fmt.Errorf("%w: bad shared string index in %s", ErrInvalidXLSX, cell.Ref)
+		}
+		return shared[index].String(), nil
+	case "inlineStr":
+		return cell.Inline.String(), nil
+	case "b":
+		if cell.Value == "1" {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case "", "n":
+		// 科学计数法的数字还原为普通写法，避免条码等长数字被改写
+		if strings.ContainsAny(cell.Value, "eE") {
+			if number, err := strconv.ParseFloat(cell.Value, 64); err == nil {
+				return strconv.FormatFloat(number, 'f', -1, 64), nil
+			}
+		}
+		return cell.Value, nil
+	default:
+		return cell.Value, nil
+	}
+}
+
+// columnIndex 将单元格引用(如 AB12)的列部分转换为从0开始的列号
+func columnIndex(ref string) (int, error) {
+	index := 0
+	letters := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A'+1)
+		letters++
+	}
+	if letters == 0 || letters > 3 {
+		return 0, fmt.Errorf("invalid cell reference %q", ref)
+	}
+	return index - 1, nil
+}
+
+// columnName 将从0开始的列号转换为列名，如 0 -> A，27 -> AB
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbookXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+)
+
+// WriteXLSX 写出只包含一个工作表的 XLSX，所有单元格均为内联字符串
+func WriteXLSX(rows [][]string) ([]byte, error) {
+	var sheet bytes.Buffer
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for i, row := range rows {
+		fmt.Fprintf(&sheet, `<row r="%d">`, i+1)
+		for j, value := range row {
+			if value == "" {
+				continue
+			}
+			fmt.Fprintf(&sheet, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, columnName(j), i+1)
+			if err := xml.EscapeText(&sheet, []byte(value)); err != nil {
+				return nil, err
+			}
+			sheet.WriteString(`</t></is></c>`)
+		}
+		sheet.WriteString(`</row>`)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	parts := []struct {
+		name    string
+		content []byte
+	}{
+		{"[Content_Types].xml", []byte(xlsxContentTypes)},
+		{"_rels/.rels", []byte(xlsxRootRels)},
+		{"xl/workbook.xml", []byte(xlsxWorkbookXML)},
+		{"xl/_rels/workbook.xml.rels", []byte(xlsxWorkbookRels)},
+		{"xl/worksheets/sheet1.xml", sheet.Bytes()},
+	}
+	for _, part := range parts {
+		writer, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := writer.Write(part.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalConfig 本地文件系统存储配置
+type LocalConfig struct {
+	Root    string // 文件存放目录
+	BaseURL string // 对外访问的地址前缀，如 /uploads
+}
+
+// LocalStorage 本地文件系统存储
+type LocalStorage struct {
+	config LocalConfig
+}
+
+func NewLocalStorage(config LocalConfig) (*LocalStorage, error) {
+	if config.Root == "" {
+		return nil, errors.New("local storage root is required")
+	}
+	if err := os.MkdirAll(config.Root, 0o755); err != nil {
+		return nil, err
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	return &LocalStorage{config: config}, nil
+}
+
+// path 将 key 转换为本地路径，拒绝跳出根目录的 key
+func (s *LocalStorage) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" {
+		return "", errors.New("invalid storage key")
+	}
+	return filepath.Join(s.config.Root, filepath.FromSlash(clean)), nil
+}
+
+// Put 先写入临时文件再重命名，避免读到写了一半的文件
+func (s *LocalStorage) Put(key string, data []byte, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *LocalStorage) Get(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+func (s *LocalStorage) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStorage) Exists(key string) (bool, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *LocalStorage) URL(key string) string {
+	return s.config.BaseURL + "/" + strings.TrimLeft(key, "/")
+}
+
+// Root 返回文件存放目录，用于挂载静态文件服务
+func (s *LocalStorage) Root() string {
+	return s.config.Root
+}
@@ -0,0 +1,274 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Config S3 兼容存储配置，可对接 AWS S3、MinIO 等
+type S3Config struct {
+	Endpoint     string // 服务地址，如 http://127.0.0.1:9000
+	Region       string // 区域，MinIO 默认为 us-east-1
+	Bucket       string // 存储桶
+	AccessKey    string
+	SecretKey    string
+	PublicURL    string // 对外访问的地址前缀，为空时使用 Endpoint
+	UsePathStyle bool   // 使用路径风格访问(MinIO 需开启)
+	ACL          string // 上传对象的访问权限，如 private，为空时使用存储桶的默认权限
+}
+
+// S3Storage S3 兼容存储，使用 AWS Signature V4 签名
+type S3Storage struct {
+	config S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewS3Storage(config S3Config) (*S3Storage, error) {
+	if config.Endpoint == "" || config.Bucket == "" {
+		return nil, errors.New("s3 endpoint and bucket are required")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	config.PublicURL = strings.TrimRight(config.PublicURL, "/")
+	return &S3Storage{
+		config: config,
+		client: &http.Client{Timeout: 30 * time.Second},
+		now:    time.Now,
+	}, nil
+}
+
+// objectURL 返回对象的请求地址
+func (s *S3Storage) objectURL(key string) (*url.URL, error) {
+	endpoint, err := url.Parse(s.config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	key = strings.TrimLeft(key, "/")
+	if s.config.UsePathStyle {
+		endpoint.Path = "/" + s.config.Bucket + "/" + key
+	} else {
+		endpoint.Host = s.config.Bucket + "." + endpoint.Host
+		endpoint.Path = "/" + key
+	}
+	return endpoint, nil
+}
+
+func (s *S3Storage) Put(key string, data []byte, contentType string) error {
+	headers := map[string]string{"Content-Type": contentType}
+	if s.config.ACL != "" {
+		headers["X-Amz-Acl"] = s.config.ACL
+	}
+	resp, err := s.do(http.MethodPut, key, data, headers)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkS3Response(resp)
+}
+
+func (s *S3Storage) Get(key string) (io.ReadCloser, error) {
+	resp, err := s.do(http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkS3Response(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *S3Storage) Delete(key string) error {
+	resp, err := s.do(http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := checkS3Response(resp); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (s *S3Storage) Exists(key string) (bool, error) {
+	resp, err := s.do(http.MethodHead, key, nil, nil)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if err := checkS3Response(resp); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *S3Storage) URL(key string) string {
+	key = strings.TrimLeft(key, "/")
+	if s.config.PublicURL != "" {
+		return s.config.PublicURL + "/" + key
+	}
+	u, err := s.objectURL(key)
+	if err != nil {
+		return ""
+	}
+	return u.String()
+}
+
+// do 发送签名后的请求
+func (s *S3Storage) do(method, key string, body []byte, headers map[string]string) (*http.Response, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	s.sign(req, body)
+
+	return s.client.Do(req)
+}
+
+// checkS3Response 将非 2xx 响应转换为错误
+func checkS3Response(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("s3 request failed: %s %s", resp.Status, strings.TrimSpace(string(message)))
+}
+
+// sign 按 AWS Signature Version 4 为请求签名
+func (s *S3Storage) sign(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	// 参与签名的请求头，按小写名称排序
+	names := make([]string, 0, len(req.Header))
+	values := make(map[string]string, len(req.Header))
+	for name, value := range req.Header {
+		lower := strings.ToLower(name)
+		names = append(names, lower)
+		values[lower] = strings.Join(value, ",")
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(values[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.config.SecretKey), date)
+	signingKey = hmacSHA256(signingKey, s.config.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKey, scope, signedHeaders, signature))
+}
+
+// canonicalURI 对路径逐段进行 URI 编码，保留分隔符
+func canonicalURI(path string) string {
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// canonicalQuery 按名称排序并编码查询参数
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, uriEncode(key)+"="+uriEncode(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode 按 SigV4 规则编码，仅保留非保留字符
+func uriEncode(value string) string {
+	var builder strings.Builder
+	for _, b := range []byte(value) {
+		if (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') ||
+			b == '-' || b == '_' || b == '.' || b == '~' {
+			builder.WriteByte(b)
+		} else {
+			fmt.Fprintf(&builder, "%%%02X", b)
+		}
+	}
+	return builder.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,27 @@
+package storage
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrNotFound 对象不存在
+var ErrNotFound = errors.New("object not found")
+
+// Storage 文件存储接口，key 为以 / 分隔的相对路径
+type Storage interface {
+	// Put 写入对象，已存在时覆盖
+	Put(key string, data []byte, contentType string) error
+
+	// Get 读取对象，调用方负责关闭
+	Get(key string) (io.ReadCloser, error)
+
+	// Delete 删除对象，对象不存在时不报错
+	Delete(key string) error
+
+	// Exists 判断对象是否存在
+	Exists(key string) (bool, error)
+
+	// URL 返回对象的公开访问地址
+	URL(key string) string
+}
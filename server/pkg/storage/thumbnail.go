@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+
+	_ "image/gif" // 注册 GIF 解码器
+)
+
+// ImageInfo 图片尺寸信息
+type ImageInfo struct {
+	Width  int
+	Height int
+	Format string // jpeg/png/gif
+}
+
+// DecodeImage 解码图片，支持 JPEG、PNG、GIF
+func DecodeImage(data []byte) (image.Image, *ImageInfo, error) {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	bounds := img.Bounds()
+	return img, &ImageInfo{Width: bounds.Dx(), Height: bounds.Dy(), Format: format}, nil
+}
+
+// Thumbnail 按最长边等比缩放到 size，图片不大于 size 时返回 nil
+// 使用区域平均进行缩小，避免最近邻采样产生的锯齿
+func Thumbnail(src image.Image, size int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if size <= 0 || (width <= size && height <= size) {
+		return nil
+	}
+
+	dstWidth, dstHeight := size, size
+	if width >= height {
+		dstHeight = max(1, height*size/width)
+	} else {
+		dstWidth = max(1, width*size/height)
+	}
+
+	// 统一转换为 RGBA 便于直接读取像素
+	rgba, ok := src.(*image.RGBA)
+	if !ok || rgba.Bounds().Min != (image.Point{}) {
+		rgba = image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		y0 := y * height / dstHeight
+		y1 := max(y0+1, (y+1)*height/dstHeight)
+		for x := 0; x < dstWidth; x++ {
+			x0 := x * width / dstWidth
+			x1 := max(x0+1, (x+1)*width/dstWidth)
+
+			var r, g, b, a, count uint64
+			for sy := y0; sy < y1; sy++ {
+				offset := rgba.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(rgba.Pix[offset])
+					g += uint64(rgba.Pix[offset+1])
+					b += uint64(rgba.Pix[offset+2])
+					a += uint64(rgba.Pix[offset+3])
+					offset += 4
+					count++
+				}
+			}
+
+			offset := dst.PixOffset(x, y)
+			dst.Pix[offset] = uint8(r / count)
+			dst.Pix[offset+1] = uint8(g / count)
+			dst.Pix[offset+2] = uint8(b / count)
+			dst.Pix[offset+3] = uint8(a / count)
+		}
+	}
+	return dst
+}
+
+// EncodeImage 编码缩略图，PNG 和 GIF 保留透明通道输出 PNG，其余输出 JPEG
+// 返回编码后的数据、内容类型和扩展名
+func EncodeImage(img image.Image, sourceFormat string) ([]byte, string, string, error) {
+	var buf bytes.Buffer
+	if sourceFormat == "png" || sourceFormat == "gif" {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", "", err
+		}
+		return buf.Bytes(), "image/png", ".png", nil
+	}
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, "", "", err
+	}
+	return buf.Bytes(), "image/jpeg", ".jpg", nil
+}
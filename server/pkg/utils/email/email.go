@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 	"net/smtp"
+	"strings"
 
 	"shopify/config"
 )
@@ -76,14 +77,19 @@ Best regards,
 Your Application Team
 `, code)
 
+	return s.Send([]string{to}, subject, body)
+}
+
+// Send 发送纯文本邮件
+func (s *EmailSender) Send(to []string, subject, body string) error {
 	message := fmt.Sprintf("To: %s\r\n"+
 		"Subject: %s\r\n"+
 		"Content-Type: text/plain; charset=UTF-8\r\n"+
 		"\r\n"+
-		"%s", to, subject, body)
+		"%s", strings.Join(to, ", "), subject, body)
 
 	auth := smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
 	addr := fmt.Sprintf("%s:%d", s.config.Host, s.config.Port)
 
-	return smtp.SendMail(addr, auth, s.config.From, []string{to}, []byte(message))
+	return smtp.SendMail(addr, auth, s.config.From, to, []byte(message))
 }
\ No newline at end of file
@@ -0,0 +1,87 @@
+// Package locale 根据请求参数和 Accept-Language 请求头确定内容语言
+package locale
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Match 在支持的语言中查找与 tag 匹配的语言，先比较完整标签，再比较主语言
+// 如 en-SG 匹配 en，zh-HK 匹配 zh-CN，找不到时返回空字符串
+func Match(tag string, supported []string) string {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	if tag == "" || tag == "*" {
+		return ""
+	}
+	for _, s := range supported {
+		if strings.EqualFold(s, tag) {
+			return s
+		}
+	}
+	primary := primaryLanguage(tag)
+	for _, s := range supported {
+		if strings.EqualFold(primaryLanguage(s), primary) {
+			return s
+		}
+	}
+	return ""
+}
+
+// Resolve 确定请求使用的语言，依次使用 lang 参数、Accept-Language 中权重最高的可用语言和默认语言
+func Resolve(lang, acceptLanguage string, supported []string, fallback string) string {
+	if matched := Match(lang, supported); matched != "" {
+		return matched
+	}
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		if matched := Match(tag, supported); matched != "" {
+			return matched
+		}
+	}
+	return fallback
+}
+
+type weightedTag struct {
+	tag    string
+	weight float64
+}
+
+// parseAcceptLanguage 解析 Accept-Language 请求头，按权重从高到低返回语言标签，忽略权重为 0 的语言
+func parseAcceptLanguage(header string) []string {
+	var weighted []weightedTag
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" {
+			continue
+		}
+		weight := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			if q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+				weight = q
+			}
+		}
+		if weight > 0 {
+			weighted = append(weighted, weightedTag{tag: tag, weight: weight})
+		}
+	}
+
+	sort.SliceStable(weighted, func(i, j int) bool { return weighted[i].weight > weighted[j].weight })
+	tags := make([]string, len(weighted))
+	for i, w := range weighted {
+		tags[i] = w.tag
+	}
+	return tags
+}
+
+// primaryLanguage 获取语言标签的主语言部分，如 zh-CN 的 zh
+func primaryLanguage(tag string) string {
+	if i := strings.IndexByte(tag, '-'); i >= 0 {
+		return tag[:i]
+	}
+	return tag
+}
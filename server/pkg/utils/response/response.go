@@ -11,6 +11,7 @@ const (
 	CodeUnauthorized     = 401   // 未授权
 	CodeForbidden        = 403   // 禁止访问
 	CodeNotFound         = 404   // 资源不存在
+	CodeConflict         = 409   // 资源冲突
 	CodeInternalError    = 500   // 服务器内部错误
 	CodeValidationError  = 422   // 参数验证错误
 	CodeServiceError     = 503   // 服务不可用
@@ -26,11 +27,13 @@ type Response struct {
 	Pagination *Pagination `json:"pagination,omitempty"`             // 分页信息
 }
 
-// Pagination 分页信息
+// Pagination 分页信息，页码分页使用 current，游标分页使用 next_cursor/has_more
 type Pagination struct {
-	Current  int   `json:"current" example:"1"`        // 当前页
-	PageSize int   `json:"pageSize" example:"10"`      // 每页大小
-	Total    int64 `json:"total" example:"100"`        // 总记录数
+	Current    int    `json:"current,omitempty" example:"1"`        // 当前页
+	PageSize   int    `json:"pageSize" example:"10"`                // 每页大小
+	Total      *int64 `json:"total,omitempty" example:"100"`        // 总记录数，游标分页时可选
+	NextCursor string `json:"next_cursor,omitempty"`                // 下一页游标
+	HasMore    bool   `json:"has_more"`                             // 是否还有更多数据
 }
 
 // Success 成功响应
@@ -51,7 +54,23 @@ func SuccessWithPagination(data interface{}, current, pageSize int, total int64)
 		Pagination: &Pagination{
 			Current:  current,
 			PageSize: pageSize,
-			Total:    total,
+			Total:    &total,
+			HasMore:  int64(current*pageSize) < total,
+		},
+	}
+}
+
+// SuccessWithCursor 带游标分页的成功响应，total 为空表示未统计总数
+func SuccessWithCursor(data interface{}, pageSize int, nextCursor string, hasMore bool, total *int64) *Response {
+	return &Response{
+		Code:    CodeSuccess,
+		Message: "success",
+		Data:    data,
+		Pagination: &Pagination{
+			PageSize:   pageSize,
+			Total:      total,
+			NextCursor: nextCursor,
+			HasMore:    hasMore,
 		},
 	}
 }
@@ -102,6 +121,14 @@ func NotFound(message string) *Response {
 	return Error(CodeNotFound, message)
 }
 
+// Conflict 409错误
+func Conflict(message string) *Response {
+	if message == "" {
+		message = "resource conflict"
+	}
+	return Error(CodeConflict, message)
+}
+
 // ValidationError 422错误
 func ValidationError(message string, errors []string) *Response {
 	return ErrorWithDetails(CodeValidationError, message, errors)
@@ -147,6 +174,8 @@ func (r *Response) GetHTTPStatusCode() int {
 		return http.StatusForbidden
 	case CodeNotFound:
 		return http.StatusNotFound
+	case CodeConflict:
+		return http.StatusConflict
 	case CodeValidationError:
 		return http.StatusUnprocessableEntity
 	case CodeServiceError:
@@ -0,0 +1,72 @@
+// Package slug 生成用于 URL 的短标识，中文按拼音转写
+package slug
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+)
+
+// MaxLength slug 最大长度
+const MaxLength = 100
+
+// generatedLength 自动生成的 slug 长度上限，预留去重后缀的空间
+const generatedLength = 80
+
+var pattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+var pinyinArgs = pinyin.NewArgs()
+
+// Valid 判断 slug 是否只包含小写字母、数字和单个连字符
+func Valid(s string) bool {
+	return len(s) <= MaxLength && pattern.MatchString(s)
+}
+
+// Generate 根据名称生成 slug，中文转为不带声调的拼音，每个汉字之间用连字符分隔
+// 无法转写的字符视为分隔符，结果可能为空
+func Generate(name string) string {
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			word.WriteRune(r)
+		case unicode.Is(unicode.Han, r):
+			flush()
+			if readings := pinyin.SinglePinyin(r, pinyinArgs); len(readings) > 0 {
+				words = append(words, readings[0])
+			}
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return truncate(strings.Join(words, "-"), generatedLength)
+}
+
+// WithSuffix 为重复的 slug 追加数字后缀，保证不超过最大长度
+func WithSuffix(s, suffix string) string {
+	return truncate(s, MaxLength-len(suffix)-1) + "-" + suffix
+}
+
+// truncate 在连字符处截断，避免截断半个单词
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	s = s[:limit]
+	if i := strings.LastIndexByte(s, '-'); i > 0 {
+		s = s[:i]
+	}
+	return strings.Trim(s, "-")
+}
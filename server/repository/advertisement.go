@@ -31,10 +31,11 @@ func (r *AdvertisementRepository) GetByID(id uint) (*models.Advertisement, error
     return &ad, nil
 }
 
-// Update 更新广告信息
+// Update 更新广告信息，ad.Version 为更新前的版本号
 func (r *AdvertisementRepository) Update(ad *models.Advertisement) error {
-    return r.db.Model(&models.Advertisement{}).
-        Where("id = ?", ad.ID).
+    // 按版本号更新，版本不一致说明已被其他请求修改
+    result := r.db.Model(&models.Advertisement{}).
+        Where("id = ? AND version = ?", ad.ID, ad.Version).
         Updates(models.Advertisement{
             Title:     ad.Title,
             Image:     ad.Image,
@@ -43,8 +44,16 @@ func (r *AdvertisementRepository) Update(ad *models.Advertisement) error {
             StartTime: ad.StartTime,
             EndTime:   ad.EndTime,
             Status:    ad.Status,
+            Version:   ad.Version + 1,
             UpdatedAt: time.Now(),
-        }).Error
+        })
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrVersionConflict
+    }
+    return nil
 }
 
 // Delete 删除广告(软删除)
@@ -99,9 +108,21 @@ func (r *AdvertisementRepository) ListActive() ([]models.Advertisement, error) {
     return ads, err
 }
 
-// UpdateStatus 更新广告状态
-func (r *AdvertisementRepository) UpdateStatus(id uint, status string) error {
-    return r.db.Model(&models.Advertisement{}).
-        Where("id = ?", id).
-        Update("status", status).Error
+// UpdateStatus 更新广告状态，version 为 0 时不校验版本号
+func (r *AdvertisementRepository) UpdateStatus(id uint, status string, version uint) error {
+    query := r.db.Model(&models.Advertisement{}).Where("id = ?", id)
+    if version != 0 {
+        query = query.Where("version = ?", version)
+    }
+    result := query.Updates(map[string]interface{}{
+        "status":  status,
+        "version": gorm.Expr("version + 1"),
+    })
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 && version != 0 {
+        return ErrVersionConflict
+    }
+    return nil
 } 
\ No newline at end of file
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"shopify/models"
+
+	"gorm.io/gorm"
+)
+
+// AttributeFilter 规格属性筛选条件，Values 不为空时按值匹配任一，否则按数值区间匹配
+type AttributeFilter struct {
+	Code   string   // 属性编码
+	Values []string // 规范化后的属性值
+	Min    *float64 // 数值下限(含)
+	Max    *float64 // 数值上限(含)
+}
+
+// subquery 返回满足筛选条件的产品ID子查询
+func (f AttributeFilter) subquery(db *gorm.DB) *gorm.DB {
+	query := db.Model(&models.ProductAttributeValue{}).
+		Select("product_id").
+		Where("code = ?", f.Code)
+	if len(f.Values) > 0 {
+		return query.Where("value IN ?", f.Values)
+	}
+	if f.Min != nil {
+		query = query.Where("numeric_value >= ?", *f.Min)
+	}
+	if f.Max != nil {
+		query = query.Where("numeric_value <= ?", *f.Max)
+	}
+	return query
+}
+
+// AttributeFacetCount 属性值的产品数量统计
+type AttributeFacetCount struct {
+	Code  string `json:"code"`
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type AttributeRepository struct {
+	*BaseRepository
+}
+
+func NewAttributeRepository(db *gorm.DB) *AttributeRepository {
+	return &AttributeRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// CreateDefinition 创建属性定义
+func (r *AttributeRepository) CreateDefinition(definition *models.AttributeDefinition) error {
+	return r.db.Create(definition).Error
+}
+
+// GetDefinition 根据ID获取属性定义
+func (r *AttributeRepository) GetDefinition(id uint) (*models.AttributeDefinition, error) {
+	var definition models.AttributeDefinition
+	err := r.db.First(&definition, id).Error
+	return &definition, err
+}
+
+// UpdateDefinition 更新属性定义，所属类目和编码不可修改
+func (r *AttributeRepository) UpdateDefinition(definition *models.AttributeDefinition) error {
+	return r.db.Model(definition).
+		Select("name", "type", "unit", "allowed_values", "filterable", "sort_order").
+		Updates(definition).Error
+}
+
+// DeleteDefinition 删除属性定义及所有产品上的对应属性值
+func (r *AttributeRepository) DeleteDefinition(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("attribute_id = ?", id).Delete(&models.ProductAttributeValue{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.AttributeDefinition{}, id).Error
+	})
+}
+
+// DeleteDefinitionsByCategory 删除类目下的所有属性定义及对应属性值
+func (r *AttributeRepository) DeleteDefinitionsByCategory(categoryID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		ids := tx.Model(&models.AttributeDefinition{}).Select("id").Where("category_id = ?", categoryID)
+		if err := tx.Where("attribute_id IN (?)", ids).Delete(&models.ProductAttributeValue{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("category_id = ?", categoryID).Delete(&models.AttributeDefinition{}).Error
+	})
+}
+
+// ListDefinitionsByCategories 获取多个类目下的属性定义
+func (r *AttributeRepository) ListDefinitionsByCategories(categoryIDs []uint) ([]models.AttributeDefinition, error) {
+	var definitions []models.AttributeDefinition
+	if len(categoryIDs) == 0 {
+		return definitions, nil
+	}
+	err := r.db.Where("category_id IN ?", categoryIDs).
+		Order("sort_order ASC, id ASC").
+		Find(&definitions).Error
+	return definitions, err
+}
+
+// ListFilterableByCodes 获取可筛选的属性定义，同一编码可能在多个类目下定义
+func (r *AttributeRepository) ListFilterableByCodes(codes []string) ([]models.AttributeDefinition, error) {
+	var definitions []models.AttributeDefinition
+	if len(codes) == 0 {
+		return definitions, nil
+	}
+	err := r.db.Where("code IN ? AND filterable = ?", codes, true).
+		Order("sort_order ASC, id ASC").
+		Find(&definitions).Error
+	return definitions, err
+}
+
+// CountDefinitionsByCode 统计多个类目中使用指定编码的属性定义数量
+func (r *AttributeRepository) CountDefinitionsByCode(categoryIDs []uint, code string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.AttributeDefinition{}).
+		Where("category_id IN ? AND code = ?", categoryIDs, code).
+		Count(&count).Error
+	return count, err
+}
+
+// CountValues 统计属性已被多少产品使用，values 不为空时只统计这些取值
+func (r *AttributeRepository) CountValues(attributeID uint, values []string) (int64, error) {
+	var count int64
+	query := r.db.Model(&models.ProductAttributeValue{}).Where("attribute_id = ?", attributeID)
+	if len(values) > 0 {
+		query = query.Where("value IN ?", values)
+	}
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// ListValuesByProduct 获取产品的所有属性值
+func (r *AttributeRepository) ListValuesByProduct(productID uint) ([]models.ProductAttributeValue, error) {
+	var values []models.ProductAttributeValue
+	err := r.db.Where("product_id = ?", productID).Find(&values).Error
+	return values, err
+}
+
+// ReplaceProductValues 用新的属性值整体替换产品原有的属性值
+func (r *AttributeRepository) ReplaceProductValues(productID uint, values []models.ProductAttributeValue) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productID).Delete(&models.ProductAttributeValue{}).Error; err != nil {
+			return err
+		}
+		if len(values) == 0 {
+			return nil
+		}
+		return tx.Create(&values).Error
+	})
+}
+
+// DeleteProductValuesExcept 删除产品上不属于指定属性定义的属性值，用于产品更换类目后清理
+func (r *AttributeRepository) DeleteProductValuesExcept(productID uint, attributeIDs []uint) error {
+	query := r.db.Where("product_id = ?", productID)
+	if len(attributeIDs) > 0 {
+		query = query.Where("attribute_id NOT IN ?", attributeIDs)
+	}
+	return query.Delete(&models.ProductAttributeValue{}).Error
+}
+
+// CountFacets 统计满足查询条件的产品中各可筛选属性值的产品数量
+// 已选中的属性在统计自身取值时忽略自身的筛选条件，便于同一属性多选
+func (r *AttributeRepository) CountFacets(q ProductQuery) ([]AttributeFacetCount, error) {
+	filterable := r.db.Model(&models.AttributeDefinition{}).Select("id").Where("filterable = ?", true)
+
+	count := func(q ProductQuery, scope func(*gorm.DB) *gorm.DB) ([]AttributeFacetCount, error) {
+		var counts []AttributeFacetCount
+		products := q.apply(r.db.Session(&gorm.Session{NewDB: true}).Model(&models.Product{})).Select("id")
+		err := scope(r.db.Model(&models.ProductAttributeValue{})).
+			Select("code, value, COUNT(DISTINCT product_id) AS count").
+			Where("attribute_id IN (?)", filterable).
+			Where("product_id IN (?)", products).
+			Group("code, value").
+			Order("count DESC, value ASC").
+			Scan(&counts).Error
+		return counts, err
+	}
+
+	selected := make([]string, 0, len(q.Attributes))
+	for _, filter := range q.Attributes {
+		selected = append(selected, filter.Code)
+	}
+
+	// 未选中的属性共用完整的查询条件
+	counts, err := count(q, func(db *gorm.DB) *gorm.DB {
+		if len(selected) > 0 {
+			return db.Where("code NOT IN ?", selected)
+		}
+		return db
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 已选中的属性逐个去掉自身条件后统计
+	for _, code := range selected {
+		code := code
+		selectedCounts, err := count(q.withoutAttribute(code), func(db *gorm.DB) *gorm.DB {
+			return db.Where("code = ?", code)
+		})
+		if err != nil {
+			return nil, err
+		}
+		counts = append(counts, selectedCounts...)
+	}
+	return counts, nil
+}
@@ -1,6 +1,13 @@
 package repository
 
-import "gorm.io/gorm"
+import (
+    "errors"
+
+    "gorm.io/gorm"
+)
+
+// ErrVersionConflict 乐观锁版本冲突，记录已被其他请求修改
+var ErrVersionConflict = errors.New("version conflict")
 
 type Repository interface {
     Create(interface{}) error
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"time"
+
+	"shopify/models"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type BundleRepository struct {
+	*BaseRepository
+}
+
+func NewBundleRepository(db *gorm.DB) *BundleRepository {
+	return &BundleRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// ComponentRevenue 组件商品在统计周期内通过套装售出的数量和分摊收入
+type ComponentRevenue struct {
+	ProductID uint            `json:"product_id"` // 组件商品ID
+	Name      string          `json:"name"`       // 组件商品名称
+	Quantity  int             `json:"quantity"`   // 售出数量
+	Revenue   decimal.Decimal `json:"revenue"`    // 分摊收入
+}
+
+// ListComponents 获取套装的组件，预加载组件商品和SKU
+func (r *BundleRepository) ListComponents(bundleID uint) ([]models.BundleComponent, error) {
+	var components []models.BundleComponent
+	err := r.db.Where("bundle_id = ?", bundleID).
+		Preload("Component").
+		Preload("SKU").
+		Order("sort_order ASC, id ASC").
+		Find(&components).Error
+	return components, err
+}
+
+// ListBundleIDsByComponents 获取包含任一指定商品作为组件的套装ID
+func (r *BundleRepository) ListBundleIDsByComponents(productIDs []uint) ([]uint, error) {
+	var ids []uint
+	if len(productIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Model(&models.BundleComponent{}).
+		Where("component_id IN ?", productIDs).
+		Distinct().
+		Pluck("bundle_id", &ids).Error
+	return ids, err
+}
+
+// IsComponent 判断商品是否为某个套装的组件
+func (r *BundleRepository) IsComponent(productID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.BundleComponent{}).
+		Where("component_id = ?", productID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ReplaceComponents 替换套装的全部组件
+func (r *BundleRepository) ReplaceComponents(bundleID uint, components []models.BundleComponent) error {
+	if err := r.DeleteComponents(bundleID); err != nil {
+		return err
+	}
+	for i := range components {
+		components[i].ID = 0
+		components[i].BundleID = bundleID
+	}
+	if len(components) == 0 {
+		return nil
+	}
+	return r.db.Omit("Component", "SKU").Create(&components).Error
+}
+
+// DeleteComponents 删除套装的全部组件
+func (r *BundleRepository) DeleteComponents(bundleID uint) error {
+	return r.db.Where("bundle_id = ?", bundleID).Delete(&models.BundleComponent{}).Error
+}
+
+// CreateOrderItemComponents 保存套装订单项的组件明细
+func (r *BundleRepository) CreateOrderItemComponents(components []models.OrderItemComponent) error {
+	if len(components) == 0 {
+		return nil
+	}
+	return r.db.Omit("Product", "SKU").Create(&components).Error
+}
+
+// SumComponentRevenue 统计指定时间以来未取消且未退款订单中各组件的售出数量和分摊收入
+func (r *BundleRepository) SumComponentRevenue(since time.Time) ([]ComponentRevenue, error) {
+	var revenues []ComponentRevenue
+	err := r.db.Table("order_item_components").
+		Select("order_item_components.product_id, products.name, "+
+			"SUM(order_item_components.quantity) AS quantity, "+
+			"SUM(order_item_components.allocated_amount) AS revenue").
+		Joins("JOIN orders ON orders.id = order_item_components.order_id").
+		Joins("LEFT JOIN products ON products.id = order_item_components.product_id").
+		Where("orders.created_at >= ?", since).
+		Where("orders.status <> ? AND (orders.payment_status IS NULL OR orders.payment_status <> ?)",
+			models.OrderStatusCancelled, models.PaymentStatusRefunded).
+		Where("orders.deleted_at IS NULL").
+		Group("order_item_components.product_id, products.name").
+		Order("revenue DESC").
+		Scan(&revenues).Error
+	return revenues, err
+}
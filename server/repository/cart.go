@@ -25,15 +25,21 @@ func (r *CartRepository) GetUserCart(userID uint) ([]models.CartItem, error) {
     var items []models.CartItem
     err := r.db.Where("user_id = ?", userID).
         Preload("Product").
+        Preload("SKU").
         Find(&items).Error
     return items, err
 }
 
-// GetCartItem 获取特定的购物车项
-func (r *CartRepository) GetCartItem(userID, productID uint) (*models.CartItem, error) {
+// GetCartItem 获取特定的购物车项，skuID 为空表示无规格商品
+func (r *CartRepository) GetCartItem(userID, productID uint, skuID *uint) (*models.CartItem, error) {
     var item models.CartItem
-    err := r.db.Where("user_id = ? AND product_id = ?", userID, productID).
-        First(&item).Error
+    query := r.db.Where("user_id = ? AND product_id = ?", userID, productID)
+    if skuID == nil {
+        query = query.Where("sku_id IS NULL")
+    } else {
+        query = query.Where("sku_id = ?", *skuID)
+    }
+    err := query.First(&item).Error
     if err != nil {
         return nil, err
     }
@@ -80,6 +86,7 @@ func (r *CartRepository) GetSelectedItems(userID uint) ([]models.CartItem, error
     var items []models.CartItem
     err := r.db.Where("user_id = ? AND selected = ?", userID, true).
         Preload("Product").
+        Preload("SKU").
         Find(&items).Error
     return items, err
 }
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"fmt"
+	"shopify/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CategoryRepository struct {
+	*BaseRepository
+}
+
+func NewCategoryRepository(db *gorm.DB) *CategoryRepository {
+	return &CategoryRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create 创建类目，创建后根据父类目路径生成自身路径
+func (r *CategoryRepository) Create(category *models.Category, parentPath string) error {
+	if err := r.db.Create(category).Error; err != nil {
+		return err
+	}
+	if parentPath == "" {
+		parentPath = "/"
+	}
+	category.Path = fmt.Sprintf("%s%d/", parentPath, category.ID)
+	// active 字段有默认值，创建时的 false 会被忽略，这里一并写入
+	return r.db.Model(category).UpdateColumns(map[string]interface{}{
+		"path":   category.Path,
+		"active": category.Active,
+	}).Error
+}
+
+// GetByID 获取类目详情
+func (r *CategoryRepository) GetByID(id uint) (*models.Category, error) {
+	var category models.Category
+	err := r.db.First(&category, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// GetByName 获取指定父类目下的同名类目
+func (r *CategoryRepository) GetByName(parentID *uint, name string) (*models.Category, error) {
+	var category models.Category
+	query := r.db.Where("name = ?", name)
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+	if err := query.First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// Update 更新类目基本信息，父类目和路径通过 Move 修改
+func (r *CategoryRepository) Update(category *models.Category) error {
+	return r.db.Model(&models.Category{}).
+		Where("id = ?", category.ID).
+		Select("name", "icon", "sort_order", "active", "google_category", "updated_at").
+		Updates(models.Category{
+			Name:           category.Name,
+			Icon:           category.Icon,
+			SortOrder:      category.SortOrder,
+			Active:         category.Active,
+			GoogleCategory: category.GoogleCategory,
+			UpdatedAt:      time.Now(),
+		}).Error
+}
+
+// Delete 删除类目(软删除)
+func (r *CategoryRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Category{}, id).Error
+}
+
+// ListAll 获取全部类目，按层级和排序返回
+func (r *CategoryRepository) ListAll(activeOnly bool) ([]models.Category, error) {
+	var categories []models.Category
+	query := r.db.Model(&models.Category{})
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+	err := query.Order("level ASC, sort_order ASC, id ASC").Find(&categories).Error
+	return categories, err
+}
+
+// ListChildren 获取直接子类目
+func (r *CategoryRepository) ListChildren(parentID *uint) ([]models.Category, error) {
+	var categories []models.Category
+	query := r.db.Model(&models.Category{})
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+	err := query.Order("sort_order ASC, id ASC").Find(&categories).Error
+	return categories, err
+}
+
+// CountChildren 统计直接子类目数量
+func (r *CategoryRepository) CountChildren(id uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Category{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// ListDescendantIDs 获取类目自身及所有子孙类目的ID
+func (r *CategoryRepository) ListDescendantIDs(path string) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.Category{}).
+		Where("path LIKE ?", path+"%").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Move 将类目及其子树移动到新的父类目下
+// oldPath、newPath 分别为类目移动前后的自身路径
+func (r *CategoryRepository) Move(id uint, parentID *uint, oldPath, newPath string, levelDelta int) error {
+	if err := r.db.Model(&models.Category{}).
+		Where("id = ?", id).
+		UpdateColumn("parent_id", parentID).Error; err != nil {
+		return err
+	}
+	return r.db.Model(&models.Category{}).
+		Where("path LIKE ?", oldPath+"%").
+		UpdateColumns(map[string]interface{}{
+			"path":  gorm.Expr("CONCAT(?, SUBSTRING(path, ?))", newPath, len(oldPath)+1),
+			"level": gorm.Expr("level + ?", levelDelta),
+		}).Error
+}
+
+// UpdateSortOrder 更新类目排序
+func (r *CategoryRepository) UpdateSortOrder(id uint, sortOrder int) error {
+	return r.db.Model(&models.Category{}).
+		Where("id = ?", id).
+		UpdateColumn("sort_order", sortOrder).Error
+}
+
+// CountProducts 统计挂在指定类目下的产品数量
+func (r *CategoryRepository) CountProducts(ids []uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Product{}).Where("category_id IN ?", ids).Count(&count).Error
+	return count, err
+}
+
+// SyncProductCategoryName 同步产品冗余的类目名称
+func (r *CategoryRepository) SyncProductCategoryName(id uint, name string) error {
+	return r.db.Model(&models.Product{}).
+		Where("category_id = ?", id).
+		UpdateColumn("category", name).Error
+}
+
+// ListLegacyCategoryNames 获取尚未关联类目ID的历史类目名称
+func (r *CategoryRepository) ListLegacyCategoryNames() ([]string, error) {
+	var names []string
+	err := r.db.Model(&models.Product{}).
+		Where("category_id IS NULL AND category <> ''").
+		Distinct().
+		Pluck("category", &names).Error
+	return names, err
+}
+
+// LinkLegacyProducts 将历史类目名称的产品关联到类目
+func (r *CategoryRepository) LinkLegacyProducts(name string, id uint) error {
+	return r.db.Model(&models.Product{}).
+		Where("category_id IS NULL AND category = ?", name).
+		UpdateColumn("category_id", id).Error
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"shopify/models"
+
+	"gorm.io/gorm"
+)
+
+type CompareRepository struct {
+	*BaseRepository
+}
+
+func NewCompareRepository(db *gorm.DB) *CompareRepository {
+	return &CompareRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// ListProductIDs 获取用户对比列表中的商品ID，按加入顺序排列
+func (r *CompareRepository) ListProductIDs(userID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.CompareItem{}).
+		Where("user_id = ?", userID).
+		Order("id ASC").
+		Pluck("product_id", &ids).Error
+	return ids, err
+}
+
+// Add 加入对比列表，已存在时不重复添加
+func (r *CompareRepository) Add(userID, productID uint) error {
+	var count int64
+	if err := r.db.Model(&models.CompareItem{}).
+		Where("user_id = ? AND product_id = ?", userID, productID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return r.db.Create(&models.CompareItem{UserID: userID, ProductID: productID}).Error
+}
+
+// Remove 从对比列表中移除商品
+func (r *CompareRepository) Remove(userID, productID uint) error {
+	return r.db.Where("user_id = ? AND product_id = ?", userID, productID).
+		Delete(&models.CompareItem{}).Error
+}
+
+// Clear 清空对比列表
+func (r *CompareRepository) Clear(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.CompareItem{}).Error
+}
+
+// Replace 用新的商品列表替换对比列表
+func (r *CompareRepository) Replace(userID uint, productIDs []uint) error {
+	if err := r.Clear(userID); err != nil {
+		return err
+	}
+	if len(productIDs) == 0 {
+		return nil
+	}
+	items := make([]models.CompareItem, 0, len(productIDs))
+	for _, id := range productIDs {
+		items = append(items, models.CompareItem{UserID: userID, ProductID: id})
+	}
+	return r.db.Create(&items).Error
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"shopify/models"
+
+	"gorm.io/gorm"
+)
+
+type CurrencyRepository struct {
+	*BaseRepository
+}
+
+func NewCurrencyRepository(db *gorm.DB) *CurrencyRepository {
+	return &CurrencyRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// List 获取币种列表，activeOnly 为 true 时只返回启用的币种
+func (r *CurrencyRepository) List(activeOnly bool) ([]models.Currency, error) {
+	var currencies []models.Currency
+	query := r.db.Model(&models.Currency{})
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+	err := query.Order("code ASC").Find(&currencies).Error
+	return currencies, err
+}
+
+// GetByCode 根据币种代码获取币种
+func (r *CurrencyRepository) GetByCode(code string) (*models.Currency, error) {
+	var currency models.Currency
+	err := r.db.Where("code = ?", code).First(&currency).Error
+	if err != nil {
+		return nil, err
+	}
+	return &currency, nil
+}
+
+// Create 创建币种
+func (r *CurrencyRepository) Create(currency *models.Currency) error {
+	return r.db.Create(currency).Error
+}
+
+// Update 更新币种的名称、汇率和舍入规则
+func (r *CurrencyRepository) Update(currency *models.Currency) error {
+	return r.db.Model(currency).
+		Select("name", "symbol", "rate", "decimals", "rounding", "active").
+		Updates(currency).Error
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidCursor 游标无法解析或与排序方式不匹配
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursorTimeLayout 游标中时间排序键的格式，与数据库连接的本地时区一致
+const cursorTimeLayout = "2006-01-02 15:04:05.999999"
+
+// Cursor 游标内容，由排序方式、最后一条记录的排序键和ID组成
+type Cursor struct {
+	Sort  string `json:"s"` // 排序方式
+	Value string `json:"v"` // 排序键的值
+	ID    uint   `json:"i"` // 记录ID
+}
+
+// CursorPage 游标分页结果
+type CursorPage struct {
+	NextCursor string // 下一页游标，没有更多数据时为空
+	HasMore    bool   // 是否还有更多数据
+	Total      *int64 // 总数，未要求统计时为空
+}
+
+// CursorRequest 游标分页请求
+type CursorRequest struct {
+	Cursor    string // 上一页返回的游标，为空表示第一页
+	Limit     int    // 每页数量
+	WithTotal bool   // 是否统计总数
+}
+
+// keysetOrder 游标分页的排序方式，ID 作为第二排序键保证顺序稳定
+type keysetOrder struct {
+	Name   string // 排序方式名称，写入游标用于校验
+	Column string // 排序列
+	Desc   bool   // 是否降序
+}
+
+// EncodeCursor 将游标编码为不透明字符串
+func EncodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor 解析游标字符串
+func DecodeCursor(value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// cursorTime 将时间排序键转换为游标中的字符串
+func cursorTime(t time.Time) string {
+	return t.Local().Format(cursorTimeLayout)
+}
+
+// cursorPaginate 按游标执行分页查询
+// keyOf 返回记录的排序键和ID，用于生成下一页游标
+func cursorPaginate[T any](query *gorm.DB, order keysetOrder, req CursorRequest, keyOf func(*T) (string, uint)) ([]T, *CursorPage, error) {
+	page := &CursorPage{}
+
+	if req.WithTotal {
+		var total int64
+		if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+			return nil, nil, err
+		}
+		page.Total = &total
+	}
+
+	find := query.Session(&gorm.Session{})
+	if req.Cursor != "" {
+		cursor, err := DecodeCursor(req.Cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+		if cursor.Sort != order.Name {
+			return nil, nil, ErrInvalidCursor
+		}
+		op := ">"
+		if order.Desc {
+			op = "<"
+		}
+		find = find.Where("("+order.Column+" "+op+" ? OR ("+order.Column+" = ? AND id "+op+" ?))",
+			cursor.Value, cursor.Value, cursor.ID)
+	}
+
+	direction := " ASC"
+	if order.Desc {
+		direction = " DESC"
+	}
+
+	// 多取一条判断是否还有下一页
+	var items []T
+	err := find.Order(order.Column + direction).
+		Order("id" + direction).
+		Limit(req.Limit + 1).
+		Find(&items).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(items) > req.Limit {
+		items = items[:req.Limit]
+		page.HasMore = true
+		value, id := keyOf(&items[len(items)-1])
+		page.NextCursor = EncodeCursor(Cursor{Sort: order.Name, Value: value, ID: id})
+	}
+
+	return items, page, nil
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"time"
+
+	"shopify/models"
+
+	"gorm.io/gorm"
+)
+
+type DigitalRepository struct {
+	*BaseRepository
+}
+
+func NewDigitalRepository(db *gorm.DB) *DigitalRepository {
+	return &DigitalRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// CreateAsset 创建数字文件记录
+func (r *DigitalRepository) CreateAsset(asset *models.DigitalAsset) error {
+	return r.db.Create(asset).Error
+}
+
+// GetAsset 获取数字文件
+func (r *DigitalRepository) GetAsset(id uint) (*models.DigitalAsset, error) {
+	var asset models.DigitalAsset
+	if err := r.db.First(&asset, id).Error; err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+// ListAssets 获取商品的数字文件
+func (r *DigitalRepository) ListAssets(productID uint) ([]models.DigitalAsset, error) {
+	var assets []models.DigitalAsset
+	err := r.db.Where("product_id = ?", productID).
+		Order("id ASC").
+		Find(&assets).Error
+	return assets, err
+}
+
+// DeleteAsset 软删除数字文件，已发放的下载授权仍可下载
+func (r *DigitalRepository) DeleteAsset(id uint) error {
+	return r.db.Delete(&models.DigitalAsset{}, id).Error
+}
+
+// CreateLicenseKeys 批量导入授权码
+func (r *DigitalRepository) CreateLicenseKeys(keys []models.LicenseKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(keys, 500).Error
+}
+
+// ListExistingKeys 获取商品中已存在的授权码，用于导入时去重
+func (r *DigitalRepository) ListExistingKeys(productID uint, keys []string) ([]string, error) {
+	var existing []string
+	if len(keys) == 0 {
+		return existing, nil
+	}
+	err := r.db.Model(&models.LicenseKey{}).
+		Where("product_id = ? AND `key` IN ?", productID, keys).
+		Pluck("key", &existing).Error
+	return existing, err
+}
+
+// ListLicenseKeys 分页获取商品的授权码，status 为空时返回全部
+func (r *DigitalRepository) ListLicenseKeys(productID uint, status string, page, pageSize int) ([]models.LicenseKey, int64, error) {
+	var keys []models.LicenseKey
+	var total int64
+
+	query := r.db.Model(&models.LicenseKey{}).Where("product_id = ?", productID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Order("id DESC").
+		Find(&keys).Error
+	return keys, total, err
+}
+
+// CountLicenseKeys 统计商品的授权码总数和可分配数
+func (r *DigitalRepository) CountLicenseKeys(productID uint) (total int64, available int64, err error) {
+	if err = r.db.Model(&models.LicenseKey{}).
+		Where("product_id = ?", productID).
+		Count(&total).Error; err != nil {
+		return
+	}
+	err = r.db.Model(&models.LicenseKey{}).
+		Where("product_id = ? AND status = ?", productID, models.LicenseKeyAvailable).
+		Count(&available).Error
+	return
+}
+
+// GetLicenseKey 获取授权码
+func (r *DigitalRepository) GetLicenseKey(id uint) (*models.LicenseKey, error) {
+	var key models.LicenseKey
+	if err := r.db.First(&key, id).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// DeleteAvailableKey 删除未分配的授权码，已预留或发放的授权码不能删除
+func (r *DigitalRepository) DeleteAvailableKey(id uint) (bool, error) {
+	result := r.db.Where("status = ?", models.LicenseKeyAvailable).
+		Delete(&models.LicenseKey{}, id)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ReserveKeys 为订单项预留授权码，可分配数量不足时返回 ErrInsufficientStock
+// 使用带条件的 UPDATE ... LIMIT 抢占，并发下单不会分到同一个授权码
+func (r *DigitalRepository) ReserveKeys(productID, orderID, orderItemID uint, quantity int) error {
+	result := r.db.Model(&models.LicenseKey{}).
+		Where("product_id = ? AND status = ?", productID, models.LicenseKeyAvailable).
+		Order("id ASC").
+		Limit(quantity).
+		Updates(map[string]interface{}{
+			"status":        models.LicenseKeyReserved,
+			"order_id":      orderID,
+			"order_item_id": orderItemID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected < int64(quantity) {
+		return ErrInsufficientStock
+	}
+	return nil
+}
+
+// DeliverKeys 将订单项预留的授权码标记为已发放
+func (r *DigitalRepository) DeliverKeys(orderItemID uint, deliveredAt time.Time) error {
+	return r.db.Model(&models.LicenseKey{}).
+		Where("order_item_id = ? AND status = ?", orderItemID, models.LicenseKeyReserved).
+		Updates(map[string]interface{}{
+			"status":       models.LicenseKeyDelivered,
+			"delivered_at": deliveredAt,
+		}).Error
+}
+
+// ReleaseKeys 释放订单项预留但未发放的授权码
+func (r *DigitalRepository) ReleaseKeys(orderItemID uint) error {
+	return r.db.Model(&models.LicenseKey{}).
+		Where("order_item_id = ? AND status = ?", orderItemID, models.LicenseKeyReserved).
+		Updates(map[string]interface{}{
+			"status":        models.LicenseKeyAvailable,
+			"order_id":      nil,
+			"order_item_id": nil,
+		}).Error
+}
+
+// RevokeKeys 作废订单项已发放的授权码
+func (r *DigitalRepository) RevokeKeys(orderItemID uint) error {
+	return r.db.Model(&models.LicenseKey{}).
+		Where("order_item_id = ? AND status = ?", orderItemID, models.LicenseKeyDelivered).
+		Update("status", models.LicenseKeyRevoked).Error
+}
+
+// CreateGrants 批量创建下载授权
+func (r *DigitalRepository) CreateGrants(grants []models.DownloadGrant) error {
+	if len(grants) == 0 {
+		return nil
+	}
+	return r.db.Create(&grants).Error
+}
+
+// GetGrant 获取下载授权，文件已删除时仍返回
+func (r *DigitalRepository) GetGrant(id uint) (*models.DownloadGrant, error) {
+	var grant models.DownloadGrant
+	err := r.db.Preload("Asset", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).First(&grant, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &grant, nil
+}
+
+// ListGrantsByOrder 获取订单的下载授权
+func (r *DigitalRepository) ListGrantsByOrder(orderID uint) ([]models.DownloadGrant, error) {
+	var grants []models.DownloadGrant
+	err := r.db.Where("order_id = ?", orderID).
+		Preload("Asset", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+		Order("id ASC").
+		Find(&grants).Error
+	return grants, err
+}
+
+// IncrementDownload 下载次数加一，达到上限时返回 false
+func (r *DigitalRepository) IncrementDownload(id uint, downloadedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.DownloadGrant{}).
+		Where("id = ? AND download_count < download_limit", id).
+		Updates(map[string]interface{}{
+			"download_count":   gorm.Expr("download_count + 1"),
+			"last_download_at": downloadedAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
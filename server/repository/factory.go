@@ -44,4 +44,80 @@ func (f *RepositoryFactory) GetPaymentRepository() *PaymentRepository {
         f.paymentRepo = NewPaymentRepository(f.db)
     }
     return f.paymentRepo
-} 
\ No newline at end of file
+}
+
+func (f *RepositoryFactory) GetInventoryRepository() *InventoryRepository {
+    return NewInventoryRepository(f.db)
+}
+
+func (f *RepositoryFactory) GetWarehouseRepository() *WarehouseRepository {
+    return NewWarehouseRepository(f.db)
+}
+
+func (f *RepositoryFactory) GetNotificationRepository() *NotificationRepository {
+    return NewNotificationRepository(f.db)
+}
+
+func (f *RepositoryFactory) GetSKURepository() *SKURepository {
+    return NewSKURepository(f.db)
+}
+
+func (f *RepositoryFactory) GetCategoryRepository() *CategoryRepository {
+    return NewCategoryRepository(f.db)
+}
+
+func (f *RepositoryFactory) GetSearchRepository() *SearchRepository {
+    return NewSearchRepository(f.db)
+}
+
+func (f *RepositoryFactory) GetMediaRepository() *MediaRepository {
+    return NewMediaRepository(f.db)
+}
+
+func (f *RepositoryFactory) GetAttributeRepository() *AttributeRepository {
+    return NewAttributeRepository(f.db)
+}
+
+func (f *RepositoryFactory) GetImportRepository() *ImportRepository {
+    return NewImportRepository(f.db)
+}
+
+func (f *RepositoryFactory) GetScheduleRepository() *ScheduleRepository {
+    return NewScheduleRepository(f.db)
+}
+
+func (f *RepositoryFactory) GetRecommendationRepository() *RecommendationRepository {
+    return NewRecommendationRepository(f.db)
+}
+
+func (f *RepositoryFactory) GetViewRepository() *ViewRepository {
+    return NewViewRepository(f.db)
+}
+
+func (f *RepositoryFactory) GetSlugRepository() *SlugRepository {
+    return NewSlugRepository(f.db)
+}
+
+func (f *RepositoryFactory) GetTranslationRepository() *TranslationRepository {
+    return NewTranslationRepository(f.db)
+}
+
+func (f *RepositoryFactory) GetCurrencyRepository() *CurrencyRepository {
+    return NewCurrencyRepository(f.db)
+}
+
+func (f *RepositoryFactory) GetBundleRepository() *BundleRepository {
+    return NewBundleRepository(f.db)
+}
+
+func (f *RepositoryFactory) GetDigitalRepository() *DigitalRepository {
+    return NewDigitalRepository(f.db)
+}
+
+func (f *RepositoryFactory) GetQuestionRepository() *QuestionRepository {
+    return NewQuestionRepository(f.db)
+}
+
+func (f *RepositoryFactory) GetCompareRepository() *CompareRepository {
+    return NewCompareRepository(f.db)
+}
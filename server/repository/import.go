@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"shopify/models"
+
+	"gorm.io/gorm"
+)
+
+type ImportRepository struct {
+	*BaseRepository
+}
+
+func NewImportRepository(db *gorm.DB) *ImportRepository {
+	return &ImportRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create 创建导入任务
+func (r *ImportRepository) Create(job *models.ImportJob) error {
+	return r.db.Create(job).Error
+}
+
+// GetByID 获取导入任务
+func (r *ImportRepository) GetByID(id uint) (*models.ImportJob, error) {
+	var job models.ImportJob
+	err := r.db.First(&job, id).Error
+	return &job, err
+}
+
+// Save 保存导入任务的进度和结果
+func (r *ImportRepository) Save(job *models.ImportJob) error {
+	return r.db.Save(job).Error
+}
+
+// List 获取导入任务列表(支持分页)，不返回错误明细
+func (r *ImportRepository) List(page, pageSize int) ([]models.ImportJob, int64, error) {
+	var jobs []models.ImportJob
+	var total int64
+
+	offset := (page - 1) * pageSize
+
+	if err := r.db.Model(&models.ImportJob{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.db.Omit("errors", "warnings").
+		Offset(offset).
+		Limit(pageSize).
+		Order("id DESC").
+		Find(&jobs).Error
+
+	return jobs, total, err
+}
+
+// FailUnfinished 将未结束的任务标记为失败，用于服务重启后清理中断的任务
+func (r *ImportRepository) FailUnfinished(message string) (int64, error) {
+	result := r.db.Model(&models.ImportJob{}).
+		Where("status IN ?", []string{models.ImportStatusPending, models.ImportStatusRunning}).
+		Updates(map[string]interface{}{
+			"status":  models.ImportStatusFailed,
+			"message": message,
+		})
+	return result.RowsAffected, result.Error
+}
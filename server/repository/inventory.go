@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"shopify/models"
+
+	"gorm.io/gorm"
+)
+
+type InventoryRepository struct {
+	*BaseRepository
+}
+
+func NewInventoryRepository(db *gorm.DB) *InventoryRepository {
+	return &InventoryRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// StockDrift 库存对账结果
+type StockDrift struct {
+	ProductID   uint   `json:"product_id"`   // 产品ID
+	Name        string `json:"name"`         // 产品名称
+	Stock       int    `json:"stock"`        // 产品表中的当前库存
+	LedgerStock int    `json:"ledger_stock"` // 根据流水重建的库存
+	Drift       int    `json:"drift"`        // 差异 = 当前库存 - 流水库存
+}
+
+// CreateMovement 创建库存流水
+func (r *InventoryRepository) CreateMovement(movement *models.InventoryMovement) error {
+	return r.db.Create(movement).Error
+}
+
+// ListByProduct 获取产品的库存流水(支持分页)
+func (r *InventoryRepository) ListByProduct(productID uint, page, pageSize int) ([]models.InventoryMovement, int64, error) {
+	var movements []models.InventoryMovement
+	var total int64
+
+	offset := (page - 1) * pageSize
+
+	// 获取总数
+	if err := r.db.Model(&models.InventoryMovement{}).Where("product_id = ?", productID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 获取分页数据
+	err := r.db.Where("product_id = ?", productID).
+		Offset(offset).
+		Limit(pageSize).
+		Order("id DESC").
+		Find(&movements).Error
+
+	return movements, total, err
+}
+
+// LatestMovementID 获取最新的库存流水ID，没有流水时返回0
+func (r *InventoryRepository) LatestMovementID() (uint, error) {
+	var id uint
+	err := r.db.Model(&models.InventoryMovement{}).
+		Select("COALESCE(MAX(id), 0)").
+		Scan(&id).Error
+	return id, err
+}
+
+// ListProductIDsBetween 获取流水ID在 (afterID, toID] 区间内有库存变动的产品
+func (r *InventoryRepository) ListProductIDsBetween(afterID, toID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.InventoryMovement{}).
+		Where("id > ? AND id <= ?", afterID, toID).
+		Distinct().
+		Pluck("product_id", &ids).Error
+	return ids, err
+}
+
+// ListByReference 获取某个单据关联的库存流水
+func (r *InventoryRepository) ListByReference(reason string, referenceID uint) ([]models.InventoryMovement, error) {
+	var movements []models.InventoryMovement
+	err := r.db.Where("reason = ? AND reference_id = ?", reason, referenceID).
+		Order("id ASC").
+		Find(&movements).Error
+	return movements, err
+}
+
+// ListProductsWithoutMovements 获取有库存但没有任何流水的产品，即启用流水前已有库存的产品
+func (r *InventoryRepository) ListProductsWithoutMovements(limit int) ([]models.Product, error) {
+	var products []models.Product
+	err := r.db.Where("stock <> 0").
+		Where("type NOT IN ?", []string{models.ProductTypeBundle, models.ProductTypeDigital}).
+		Where("NOT EXISTS (?)", r.db.Model(&models.InventoryMovement{}).
+			Select("1").
+			Where("inventory_movements.product_id = products.id")).
+		Order("id ASC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
+// ListStockDrift 根据流水重建库存并与产品表对比
+// onlyDrift 为 true 时只返回存在差异的产品
+func (r *InventoryRepository) ListStockDrift(onlyDrift bool) ([]StockDrift, error) {
+	var drifts []StockDrift
+
+	ledger := r.db.Model(&models.InventoryMovement{}).
+		Select("product_id, SUM(delta) AS ledger_stock").
+		Group("product_id")
+
+	query := r.db.Table("products").
+		Select("products.id AS product_id, products.name, products.stock, "+
+			"COALESCE(l.ledger_stock, 0) AS ledger_stock, "+
+			"products.stock - COALESCE(l.ledger_stock, 0) AS drift").
+		Joins("LEFT JOIN (?) AS l ON l.product_id = products.id", ledger).
+		Where("products.deleted_at IS NULL").
+		Where("products.type NOT IN ?", []string{models.ProductTypeBundle, models.ProductTypeDigital}) // 套装和数字商品的库存由组件、授权码推算，没有流水
+
+	if onlyDrift {
+		query = query.Where("products.stock <> COALESCE(l.ledger_stock, 0)")
+	}
+
+	err := query.Order("products.id ASC").Scan(&drifts).Error
+	return drifts, err
+}
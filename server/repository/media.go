@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"shopify/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MediaRepository struct {
+	*BaseRepository
+}
+
+func NewMediaRepository(db *gorm.DB) *MediaRepository {
+	return &MediaRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create 创建媒体文件记录
+func (r *MediaRepository) Create(file *models.MediaFile) error {
+	return r.db.Create(file).Error
+}
+
+// GetByHash 根据内容哈希获取媒体文件
+func (r *MediaRepository) GetByHash(hash string) (*models.MediaFile, error) {
+	var file models.MediaFile
+	err := r.db.Where("hash = ?", hash).First(&file).Error
+	return &file, err
+}
+
+// ListCreatedBefore 获取指定时间之前上传的媒体文件
+func (r *MediaRepository) ListCreatedBefore(before time.Time) ([]models.MediaFile, error) {
+	var files []models.MediaFile
+	err := r.db.Where("created_at < ?", before).Order("id").Find(&files).Error
+	return files, err
+}
+
+// Delete 删除媒体文件记录
+func (r *MediaRepository) Delete(id uint) error {
+	return r.db.Delete(&models.MediaFile{}, id).Error
+}
+
+// ListReferencedURLs 收集业务数据中引用的所有图片地址，包括已软删除的记录
+// 软删除的数据可能被恢复，其图片不应被当作孤儿文件清理
+func (r *MediaRepository) ListReferencedURLs() (map[string]bool, error) {
+	referenced := make(map[string]bool)
+
+	// JSON 数组字段
+	var productImages []models.Product
+	if err := r.db.Unscoped().Select("id", "images").Find(&productImages).Error; err != nil {
+		return nil, err
+	}
+	for _, product := range productImages {
+		for _, url := range product.Images {
+			referenced[url] = true
+		}
+	}
+	var reviewImages []models.Review
+	if err := r.db.Unscoped().Select("id", "images").Find(&reviewImages).Error; err != nil {
+		return nil, err
+	}
+	for _, review := range reviewImages {
+		for _, url := range review.Images {
+			referenced[url] = true
+		}
+	}
+
+	// 单值字段
+	singles := []struct {
+		model  interface{}
+		column string
+	}{
+		{&models.SKU{}, "image"},
+		{&models.Advertisement{}, "image"},
+		{&models.User{}, "avatar"},
+		{&models.Category{}, "icon"},
+	}
+	for _, single := range singles {
+		var urls []string
+		if err := r.db.Unscoped().Model(single.model).
+			Where(single.column+" <> ''").
+			Pluck(single.column, &urls).Error; err != nil {
+			return nil, err
+		}
+		for _, url := range urls {
+			referenced[url] = true
+		}
+	}
+	return referenced, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"shopify/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type NotificationRepository struct {
+	*BaseRepository
+}
+
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create 创建通知
+func (r *NotificationRepository) Create(notification *models.AdminNotification) error {
+	return r.db.Create(notification).Error
+}
+
+// HasUnread 检查是否存在同类型、同关联对象的未读通知
+func (r *NotificationRepository) HasUnread(notificationType string, referenceID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.AdminNotification{}).
+		Where("type = ? AND reference_id = ? AND `read` = ?", notificationType, referenceID, false).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// List 获取通知列表(支持分页)
+func (r *NotificationRepository) List(unreadOnly bool, page, pageSize int) ([]models.AdminNotification, int64, error) {
+	var notifications []models.AdminNotification
+	var total int64
+
+	offset := (page - 1) * pageSize
+
+	query := r.db.Model(&models.AdminNotification{})
+	if unreadOnly {
+		query = query.Where("`read` = ?", false)
+	}
+
+	// 获取总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 获取分页数据
+	err := query.Offset(offset).
+		Limit(pageSize).
+		Order("id DESC").
+		Find(&notifications).Error
+
+	return notifications, total, err
+}
+
+// MarkRead 标记通知为已读
+func (r *NotificationRepository) MarkRead(id uint) error {
+	now := time.Now()
+	return r.db.Model(&models.AdminNotification{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"read":    true,
+			"read_at": &now,
+		}).Error
+}
+
+// MarkAllRead 标记所有通知为已读
+func (r *NotificationRepository) MarkAllRead() error {
+	now := time.Now()
+	return r.db.Model(&models.AdminNotification{}).
+		Where("`read` = ?", false).
+		Updates(map[string]interface{}{
+			"read":    true,
+			"read_at": &now,
+		}).Error
+}
@@ -24,8 +24,19 @@ func (r *OrderRepository) Create(order *models.Order) error {
 // GetByID 获取订单详情
 func (r *OrderRepository) GetByID(id uint) (*models.Order, error) {
 	var order models.Order
+	// 商品和SKU删除后历史订单仍需展示和回补库存
+	unscoped := func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}
 	err := r.db.Preload("OrderItems").
-		Preload("OrderItems.Product").
+		Preload("OrderItems.Product", unscoped).
+		Preload("OrderItems.SKU", unscoped).
+		Preload("OrderItems.Components.Product", unscoped).
+		Preload("OrderItems.LicenseKeys", "status = ?", models.LicenseKeyDelivered).
+		Preload("OrderItems.Downloads.Asset", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped() // 文件删除后已购买的订单仍可下载
+		}).
+		Preload("Allocations.Warehouse").
 		Preload("Address").
 		Preload("User", func(db *gorm.DB) *gorm.DB {
 			return db.Select("id, nickname, email") // 只选择需要的用户字段
@@ -71,6 +82,8 @@ func (r *OrderRepository) ListByUserID(userID uint, page, pageSize int) ([]model
 	err := r.db.Where("user_id = ?", userID).
 		Preload("OrderItems").  // 修改这里
 		Preload("OrderItems.Product").  // 修改这里
+		Preload("OrderItems.SKU").
+		Preload("OrderItems.Components.Product").
 		Preload("Address").
 		Offset(offset).
 		Limit(pageSize).
@@ -84,17 +97,47 @@ func (r *OrderRepository) ListByUserID(userID uint, page, pageSize int) ([]model
 	return orders, total, err
 }
 
+// ListByUserIDCursor 游标分页获取用户订单列表，按创建时间倒序
+func (r *OrderRepository) ListByUserIDCursor(userID uint, req CursorRequest) ([]models.Order, *CursorPage, error) {
+	query := r.db.Model(&models.Order{}).
+		Where("user_id = ?", userID).
+		Preload("OrderItems").
+		Preload("OrderItems.Product").
+		Preload("OrderItems.SKU").
+		Preload("OrderItems.Components.Product").
+		Preload("Address")
+
+	return cursorPaginate(query, orderKeysetOrder, req, orderSortKey)
+}
+
+// orderKeysetOrder 订单游标分页的排序键
+var orderKeysetOrder = keysetOrder{Name: "newest", Column: "created_at", Desc: true}
+
+// orderSortKey 获取订单的排序键
+func orderSortKey(order *models.Order) (string, uint) {
+	return cursorTime(order.CreatedAt), order.ID
+}
+
 // Update 更新订单信息
 func (r *OrderRepository) Update(order *models.Order) error {
     // 使用 Model 和 Where 来指定更新的记录
-    return r.db.Model(&models.Order{}).
-        Where("id = ?", order.ID).
+    // 按版本号更新，版本不一致说明已被其他请求修改
+    result := r.db.Model(&models.Order{}).
+        Where("id = ? AND version = ?", order.ID, order.Version).
         Updates(map[string]interface{}{
             "status":         order.Status,
             "payment_status": order.PaymentStatus,
             "payment_time":   order.PaymentTime,
+            "version":        gorm.Expr("version + 1"),
             "updated_at":     time.Now(),
-        }).Error
+        })
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrVersionConflict
+    }
+    return nil
 }
 
 // UpdateStatus 更新订单状态
@@ -103,15 +146,35 @@ func (r *OrderRepository) UpdateStatus(orderID uint, status string) error {
         Where("id = ?", orderID).
         Updates(map[string]interface{}{
             "status":     status,
+            "version":    gorm.Expr("version + 1"),
             "updated_at": time.Now(),
         }).Error
 }
 
+// UpdateStatusWithVersion 按版本号更新订单状态
+func (r *OrderRepository) UpdateStatusWithVersion(orderID uint, status string, version uint) error {
+    result := r.db.Model(&models.Order{}).
+        Where("id = ? AND version = ?", orderID, version).
+        Updates(map[string]interface{}{
+            "status":     status,
+            "version":    gorm.Expr("version + 1"),
+            "updated_at": time.Now(),
+        })
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrVersionConflict
+    }
+    return nil
+}
+
 // UpdatePaymentStatus 更新支付状态
 func (r *OrderRepository) UpdatePaymentStatus(orderID uint, status string, paymentTime *time.Time) error {
     updates := map[string]interface{}{
         "payment_status": status,
         "payment_time":   paymentTime,
+        "version":        gorm.Expr("version + 1"),
         "updated_at":     time.Now(),
     }
     return r.db.Model(&models.Order{}).
@@ -143,6 +206,7 @@ func (r *OrderRepository) UpdateLogistics(logistics *models.Logistics) error {
 func (r *OrderRepository) GetLogistics(orderID uint) (*models.Logistics, error) {
 	var logistics models.Logistics
 	err := r.db.Where("order_id = ?", orderID).
+		Preload("Warehouse").
 		Preload("Traces", func(db *gorm.DB) *gorm.DB {
 			return db.Order("trace_time DESC")
 		}).
@@ -153,6 +217,39 @@ func (r *OrderRepository) GetLogistics(orderID uint) (*models.Logistics, error)
 	return &logistics, nil
 }
 
+// GetLogisticsByWarehouse 获取订单在指定发货仓的物流信息
+func (r *OrderRepository) GetLogisticsByWarehouse(orderID uint, warehouseID *uint) (*models.Logistics, error) {
+	var logistics models.Logistics
+	query := r.db.Where("order_id = ?", orderID)
+	if warehouseID == nil {
+		query = query.Where("warehouse_id IS NULL")
+	} else {
+		query = query.Where("warehouse_id = ?", *warehouseID)
+	}
+	err := query.Preload("Warehouse").
+		Preload("Traces", func(db *gorm.DB) *gorm.DB {
+			return db.Order("trace_time DESC")
+		}).
+		First(&logistics).Error
+	if err != nil {
+		return nil, err
+	}
+	return &logistics, nil
+}
+
+// ListLogistics 获取订单的所有物流信息(按发货仓拆分)
+func (r *OrderRepository) ListLogistics(orderID uint) ([]models.Logistics, error) {
+	var logistics []models.Logistics
+	err := r.db.Where("order_id = ?", orderID).
+		Preload("Warehouse").
+		Preload("Traces", func(db *gorm.DB) *gorm.DB {
+			return db.Order("trace_time DESC")
+		}).
+		Order("id ASC").
+		Find(&logistics).Error
+	return logistics, err
+}
+
 // AddLogisticsTrace 添加物流跟踪记录
 func (r *OrderRepository) AddLogisticsTrace(trace *models.LogisticsTrace) error {
 	return r.db.Create(trace).Error
@@ -177,6 +274,8 @@ func (r *OrderRepository) ListOrdersByStatus(status string, page, pageSize int)
 
 	// 获取分页数据
 	err := query.Preload("OrderItems.Product").  // 修改这里，使用 OrderItems 而不是 Items
+		Preload("OrderItems.SKU").
+		Preload("OrderItems.Components.Product").
 		Preload("Address").
 		Preload("User", func(db *gorm.DB) *gorm.DB {
 			return db.Select("id, nickname, email")
@@ -189,6 +288,23 @@ func (r *OrderRepository) ListOrdersByStatus(status string, page, pageSize int)
 	return orders, total, err
 }
 
+// ListOrdersByStatusCursor 管理员按状态游标分页查询订单
+func (r *OrderRepository) ListOrdersByStatusCursor(status string, req CursorRequest) ([]models.Order, *CursorPage, error) {
+	query := r.db.Model(&models.Order{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	query = query.Preload("OrderItems.Product").
+		Preload("OrderItems.SKU").
+		Preload("OrderItems.Components.Product").
+		Preload("Address").
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, nickname, email")
+		})
+
+	return cursorPaginate(query, orderKeysetOrder, req, orderSortKey)
+}
+
 // CreateOrderItem 创建订单项
 func (r *OrderRepository) CreateOrderItem(item *models.OrderItem) error {
 	return r.db.Create(item).Error
@@ -211,4 +327,29 @@ func (r *OrderRepository) ListOrderItems(orderID uint) ([]models.OrderItem, erro
 		Preload("Product").
 		Find(&items).Error
 	return items, err
-} 
\ No newline at end of file
+} 
+
+// MarkItemDelivered 记录数字商品订单项的发放时间
+func (r *OrderRepository) MarkItemDelivered(itemID uint, deliveredAt time.Time) error {
+	return r.db.Model(&models.OrderItem{}).
+		Where("id = ?", itemID).
+		Update("delivered_at", deliveredAt).Error
+}
+
+// ProductSales 产品在统计周期内的销量
+type ProductSales struct {
+	ProductID uint `json:"product_id"`
+	Quantity  int  `json:"quantity"`
+}
+
+// SumSalesSince 统计指定时间以来未取消订单中各产品的销量
+func (r *OrderRepository) SumSalesSince(since time.Time) ([]ProductSales, error) {
+	var sales []ProductSales
+	err := r.db.Model(&models.OrderItem{}).
+		Select("order_items.product_id, SUM(order_items.quantity) AS quantity").
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("orders.created_at >= ? AND orders.status <> ? AND orders.deleted_at IS NULL", since, models.OrderStatusCancelled).
+		Group("order_items.product_id").
+		Scan(&sales).Error
+	return sales, err
+}
@@ -1,13 +1,19 @@
 package repository
 
 import (
-	"database/sql"
+	"errors"
 	"shopify/models"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
+// ErrInsufficientStock 库存不足
+var ErrInsufficientStock = errors.New("insufficient stock")
+
 type ProductRepository struct {
 	*BaseRepository
 }
@@ -41,6 +47,61 @@ func (r *ProductRepository) GetProductViewByID(id uint) (*models.Product, error)
 	return &product, nil
 }
 
+// GetWithSKUs 获取产品及其规格项和SKU
+func (r *ProductRepository) GetWithSKUs(id uint) (*models.Product, error) {
+	var product models.Product
+	err := r.db.Preload("Options", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort_order ASC, id ASC")
+	}).Preload("SKUs", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).First(&product, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// UpdatePrice 更新产品展示价格，同时递增版本号使进行中的结算感知价格变化
+func (r *ProductRepository) UpdatePrice(id uint, price decimal.Decimal) error {
+	return r.db.Model(&models.Product{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"price":   price,
+			"version": gorm.Expr("version + 1"),
+		}).Error
+}
+
+// UpdateStatus 更新产品上下架状态并递增版本号
+func (r *ProductRepository) UpdateStatus(id uint, status string) error {
+	return r.db.Model(&models.Product{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"status":     status,
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// UpdateSlug 更新产品当前的 slug
+func (r *ProductRepository) UpdateSlug(id uint, slug string) error {
+	return r.db.Model(&models.Product{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"slug":       slug,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// ListWithoutSlug 获取尚未生成 slug 的产品，用于补全历史数据
+func (r *ProductRepository) ListWithoutSlug(limit int) ([]models.Product, error) {
+	var products []models.Product
+	err := r.db.Where("slug = '' OR slug IS NULL").
+		Order("id ASC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
 func (r *ProductRepository) GetByID(id uint) (*models.Product, error) {
 	var product models.Product
 	err := r.db.First(&product, id).Error
@@ -50,8 +111,9 @@ func (r *ProductRepository) GetByID(id uint) (*models.Product, error) {
 	return &product, nil
 }
 
-// Update 更新产品信息
-func (r *ProductRepository) Update(product *models.Product) error {
+// Update 更新产品信息，只写入 columns 中列出的列，零值同样写入
+// 库存不在此处更新，需通过库存调整写入流水；product.Version 为更新前的版本号
+func (r *ProductRepository) Update(product *models.Product, columns ...string) error {
 	// 确保 Images 和 Tags 字段不为 nil
 	if product.Images == nil {
 		product.Images = make([]string, 0)
@@ -60,20 +122,31 @@ func (r *ProductRepository) Update(product *models.Product) error {
 		product.Tags = make([]string, 0)
 	}
 
-	// 使用 Model 和 Where 来指定更新的记录
-	return r.db.Model(&models.Product{}).
-		Where("id = ?", product.ID).
+	// 按版本号更新，版本不一致说明已被其他请求修改
+	result := r.db.Model(&models.Product{}).
+		Where("id = ? AND version = ?", product.ID, product.Version).
+		Select(append(columns, "version", "updated_at")).
 		Updates(models.Product{
-			Name:        product.Name,
-			Description: product.Description,
-			Price:      product.Price,
-			Stock:      product.Stock,
-			Status:     product.Status,
-			Category:   product.Category,
-			Images:     product.Images,
-			Tags:       product.Tags,
-			UpdatedAt:  time.Now(),
-		}).Error
+			Name:              product.Name,
+			Description:       product.Description,
+			Price:             product.Price,
+			LowStockThreshold: product.LowStockThreshold,
+			Status:            product.Status,
+			CategoryID:        product.CategoryID,
+			Category:          product.Category,
+			Images:            product.Images,
+			Tags:              product.Tags,
+			ExternalID:        product.ExternalID,
+			Version:           product.Version + 1,
+			UpdatedAt:         time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
 }
 
 // Delete 删除产品(软删除)
@@ -81,132 +154,282 @@ func (r *ProductRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Product{}, id).Error
 }
 
-// List 获取产品列表(支持分页)
-func (r *ProductRepository) List(page, pageSize int) ([]models.Product, int64, error) {
-	var products []models.Product
-	var total int64
-
-	offset := (page - 1) * pageSize
+// 产品列表支持的排序方式
+const (
+	ProductSortNewest    = "newest"
+	ProductSortPriceAsc  = "price_asc"
+	ProductSortPriceDesc = "price_desc"
+	ProductSortSales     = "sales"
+	ProductSortRating    = "rating"
+)
 
-	// 获取总数
-	if err := r.db.Model(&models.Product{}).Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
+// productSortOrders 排序方式对应的排序语句，id 保证分页稳定
+var productSortOrders = map[string]string{
+	ProductSortNewest:    "created_at DESC, id DESC",
+	ProductSortPriceAsc:  "price ASC, id ASC",
+	ProductSortPriceDesc: "price DESC, id DESC",
+	ProductSortSales:     "sales DESC, id DESC",
+	ProductSortRating:    "rating DESC, id DESC",
+}
 
-	// 获取分页数据
-	err := r.db.Offset(offset).
-		Limit(pageSize).
-		Order("created_at DESC").
-		Find(&products).Error
+// productKeysetOrders 排序方式对应的游标分页排序键
+var productKeysetOrders = map[string]keysetOrder{
+	ProductSortNewest:    {Name: ProductSortNewest, Column: "created_at", Desc: true},
+	ProductSortPriceAsc:  {Name: ProductSortPriceAsc, Column: "price", Desc: false},
+	ProductSortPriceDesc: {Name: ProductSortPriceDesc, Column: "price", Desc: true},
+	ProductSortSales:     {Name: ProductSortSales, Column: "sales", Desc: true},
+	ProductSortRating:    {Name: ProductSortRating, Column: "rating", Desc: true},
+}
 
-	return products, total, err
+// productSortKey 获取产品在指定排序方式下的排序键
+func productSortKey(sort string, product *models.Product) string {
+	switch sort {
+	case ProductSortPriceAsc, ProductSortPriceDesc:
+		return product.Price.String()
+	case ProductSortSales:
+		return strconv.Itoa(product.Sales)
+	case ProductSortRating:
+		return strconv.FormatFloat(product.Rating, 'f', 1, 64)
+	default:
+		return cursorTime(product.CreatedAt)
+	}
 }
 
-// ListByCategory 按类别查询产品
-func (r *ProductRepository) ListByCategory(category string, page, pageSize int) ([]models.Product, int64, error) {
-	var products []models.Product
-	var total int64
+// ValidProductSort 判断排序方式是否受支持
+func ValidProductSort(sort string) bool {
+	_, ok := productSortOrders[sort]
+	return ok
+}
 
-	offset := (page - 1) * pageSize
+// ProductQuery 产品列表查询条件，各条件之间为 AND 关系，零值表示不限制
+type ProductQuery struct {
+	CategoryIDs []uint            // 类目ID列表，命中任一即可
+	Category    string            // 类目名称，兼容历史的类目文本
+	MinPrice    decimal.Decimal   // 最低价格，基准币种
+	MaxPrice    decimal.Decimal   // 最高价格，基准币种
+	Tags        []string          // 标签，命中任一即可
+	Keyword     string            // 名称或描述关键字
+	Status      string            // 产品状态
+	InStock     bool              // 仅返回有库存的产品
+	Attributes  []AttributeFilter // 规格属性筛选，不同属性之间为 AND 关系
+	Sort        string            // 排序方式，默认最新
+	Page        int
+	PageSize    int
+}
 
-	// 获取总数
-	if err := r.db.Model(&models.Product{}).Where("category = ?", category).Count(&total).Error; err != nil {
-		return nil, 0, err
+// apply 将查询条件应用到查询上
+func (q *ProductQuery) apply(db *gorm.DB) *gorm.DB {
+	if len(q.CategoryIDs) > 0 {
+		db = db.Where("category_id IN ?", q.CategoryIDs)
 	}
+	if q.Category != "" {
+		db = db.Where("category = ?", q.Category)
+	}
+	if q.MinPrice.IsPositive() {
+		db = db.Where("price >= ?", q.MinPrice)
+	}
+	if q.MaxPrice.IsPositive() {
+		db = db.Where("price <= ?", q.MaxPrice)
+	}
+	if len(q.Tags) > 0 {
+		// MySQL JSON 数组匹配任一标签
+		conditions := make([]string, 0, len(q.Tags))
+		args := make([]interface{}, 0, len(q.Tags))
+		for _, tag := range q.Tags {
+			conditions = append(conditions, "JSON_CONTAINS(tags, JSON_QUOTE(?))")
+			args = append(args, tag)
+		}
+		db = db.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	}
+	if q.Keyword != "" {
+		db = db.Where("(name LIKE ? OR description LIKE ?)", "%"+q.Keyword+"%", "%"+q.Keyword+"%")
+	}
+	if q.Status != "" {
+		db = db.Where("status = ?", q.Status)
+	}
+	if q.InStock {
+		db = db.Where("stock > 0")
+	}
+	for _, filter := range q.Attributes {
+		db = db.Where("id IN (?)", filter.subquery(db.Session(&gorm.Session{NewDB: true})))
+	}
+	return db
+}
 
-	// 获取分页数据
-	err := r.db.Where("category = ?", category).
-		Offset(offset).
-		Limit(pageSize).
-		Order("created_at DESC").
-		Find(&products).Error
-
-	return products, total, err
+// withoutAttribute 返回去掉指定属性筛选后的查询条件，用于多选分面统计
+func (q ProductQuery) withoutAttribute(code string) ProductQuery {
+	filters := make([]AttributeFilter, 0, len(q.Attributes))
+	for _, filter := range q.Attributes {
+		if filter.Code != code {
+			filters = append(filters, filter)
+		}
+	}
+	q.Attributes = filters
+	return q
 }
 
-// ListByPriceRange 按价格区间查询产品
-func (r *ProductRepository) ListByPriceRange(minPrice, maxPrice float64, page, pageSize int) ([]models.Product, int64, error) {
+// Query 按组合条件分页查询产品
+func (r *ProductRepository) Query(q ProductQuery) ([]models.Product, int64, error) {
 	var products []models.Product
 	var total int64
 
-	offset := (page - 1) * pageSize
+	offset := (q.Page - 1) * q.PageSize
 
 	// 获取总数
-	if err := r.db.Model(&models.Product{}).
-		Where("price >= ? AND price <= ?", minPrice, maxPrice).
-		Count(&total).Error; err != nil {
+	if err := q.apply(r.db.Model(&models.Product{})).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
+	order, ok := productSortOrders[q.Sort]
+	if !ok {
+		order = productSortOrders[ProductSortNewest]
+	}
+
 	// 获取分页数据
-	err := r.db.Where("price >= ? AND price <= ?", minPrice, maxPrice).
+	err := q.apply(r.db.Model(&models.Product{})).
 		Offset(offset).
-		Limit(pageSize).
-		Order("price ASC").
+		Limit(q.PageSize).
+		Order(order).
 		Find(&products).Error
 
 	return products, total, err
 }
 
-// ListByTags 按标签查询产品
-func (r *ProductRepository) ListByTags(tags []string, page, pageSize int) ([]models.Product, int64, error) {
-	var products []models.Product
-	var total int64
-
-	offset := (page - 1) * pageSize
-
-	// 获取总数
-	if err := r.db.Model(&models.Product{}).
-		Where("tags ?| array[:tags]", sql.Named("tags", tags)). // PostgreSQL语法
-		Count(&total).Error; err != nil {
-		return nil, 0, err
+// QueryByCursor 按组合条件游标分页查询产品，忽略 Page 和 PageSize
+func (r *ProductRepository) QueryByCursor(q ProductQuery, req CursorRequest) ([]models.Product, *CursorPage, error) {
+	sort := q.Sort
+	if _, ok := productKeysetOrders[sort]; !ok {
+		sort = ProductSortNewest
 	}
 
-	// 获取分页数据
-	err := r.db.Where("tags ?| array[:tags]", sql.Named("tags", tags)).
-		Offset(offset).
-		Limit(pageSize).
-		Order("created_at DESC").
-		Find(&products).Error
+	return cursorPaginate(q.apply(r.db.Model(&models.Product{})), productKeysetOrders[sort], req,
+		func(product *models.Product) (string, uint) {
+			return productSortKey(sort, product), product.ID
+		})
+}
 
-	return products, total, err
+// List 获取产品列表(支持分页)
+func (r *ProductRepository) List(page, pageSize int) ([]models.Product, int64, error) {
+	return r.Query(ProductQuery{Page: page, PageSize: pageSize})
+}
+
+// ListByCategoryIDs 按类目ID查询产品，用于包含子孙类目的筛选
+func (r *ProductRepository) ListByCategoryIDs(categoryIDs []uint, page, pageSize int) ([]models.Product, int64, error) {
+	return r.Query(ProductQuery{CategoryIDs: categoryIDs, Page: page, PageSize: pageSize})
 }
 
 // UpdateStock 更新库存
 func (r *ProductRepository) UpdateStock(id uint, quantity int) error {
-	return r.db.Model(&models.Product{}).
+	db := r.db
+	if quantity > 0 {
+		db = db.Unscoped() // 取消、退款回补库存时商品可能已被删除
+	}
+	result := db.Model(&models.Product{}).
 		Where("id = ? AND stock >= ?", id, -quantity). // 确保库存充足
-		UpdateColumn("stock", gorm.Expr("stock + ?", quantity)).Error
+		UpdateColumn("stock", gorm.Expr("stock + ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInsufficientStock
+	}
+	return nil
+}
+
+// SetStock 直接设置库存，仅用于由组件库存推算的套装商品
+func (r *ProductRepository) SetStock(id uint, stock int) error {
+	return r.db.Model(&models.Product{}).
+		Where("id = ?", id).
+		UpdateColumn("stock", stock).Error
+}
+
+// SetType 设置商品类型
+func (r *ProductRepository) SetType(id uint, productType string) error {
+	return r.db.Model(&models.Product{}).
+		Where("id = ?", id).
+		UpdateColumn("type", productType).Error
+}
+
+// GetStock 获取产品当前库存
+func (r *ProductRepository) GetStock(id uint) (int, error) {
+	var stock int
+	err := r.db.Unscoped().Model(&models.Product{}).
+		Select("stock").
+		Where("id = ?", id).
+		Scan(&stock).Error
+	return stock, err
 }
 
 // UpdateSales 更新销量
 func (r *ProductRepository) UpdateSales(id uint, quantity int) error {
-	return r.db.Model(&models.Product{}).
+	return r.db.Unscoped().Model(&models.Product{}).
 		Where("id = ?", id).
 		UpdateColumn("sales", gorm.Expr("sales + ?", quantity)).Error
 }
 
 // Search 搜索产品
 func (r *ProductRepository) Search(keyword string, page, pageSize int) ([]models.Product, int64, error) {
+	return r.Query(ProductQuery{Keyword: keyword, Page: page, PageSize: pageSize})
+}
+
+// GetVersion 获取产品当前版本号
+func (r *ProductRepository) GetVersion(id uint) (uint, error) {
+	var version uint
+	err := r.db.Model(&models.Product{}).
+		Select("version").
+		Where("id = ?", id).
+		Scan(&version).Error
+	return version, err
+}
+
+// ListByIDs 按ID批量获取产品
+func (r *ProductRepository) ListByIDs(ids []uint) ([]models.Product, error) {
 	var products []models.Product
-	var total int64
+	if len(ids) == 0 {
+		return products, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&products).Error
+	return products, err
+}
 
-	offset := (page - 1) * pageSize
+// ListActive 获取所有上架产品
+func (r *ProductRepository) ListActive() ([]models.Product, error) {
+	var products []models.Product
+	err := r.db.Where("status = ?", "active").
+		Order("id ASC").
+		Find(&products).Error
+	return products, err
+}
 
-	query := r.db.Model(&models.Product{}).
-		Where("name LIKE ? OR description LIKE ?",
-			"%"+keyword+"%", "%"+keyword+"%")
+// ListIDsByCategory 获取类目下全部产品的ID
+func (r *ProductRepository) ListIDsByCategory(categoryID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.Product{}).
+		Where("category_id = ?", categoryID).
+		Pluck("id", &ids).Error
+	return ids, err
+}
 
-	// 获取总数
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
+// GetByExternalID 根据外部编码获取产品
+func (r *ProductRepository) GetByExternalID(externalID string) (*models.Product, error) {
+	var product models.Product
+	err := r.db.Where("external_id = ?", externalID).First(&product).Error
+	if err != nil {
+		return nil, err
 	}
+	return &product, nil
+}
 
-	// 获取分页数据
-	err := query.Offset(offset).
-		Limit(pageSize).
-		Order("created_at DESC").
+// ListWithSKUsAfter 按ID升序分批获取产品及其规格项和SKU，用于全量导出
+func (r *ProductRepository) ListWithSKUsAfter(afterID uint, limit int) ([]models.Product, error) {
+	var products []models.Product
+	err := r.db.Preload("Options", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort_order ASC, id ASC")
+	}).Preload("SKUs", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
 		Find(&products).Error
-
-	return products, total, err
+	return products, err
 }
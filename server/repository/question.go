@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"errors"
+
+	"shopify/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrAlreadyVoted 已点赞过该回答
+var ErrAlreadyVoted = errors.New("already upvoted")
+
+type QuestionRepository struct {
+	*BaseRepository
+}
+
+func NewQuestionRepository(db *gorm.DB) *QuestionRepository {
+	return &QuestionRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// publicUser 问答中只展示用户的昵称和头像
+func publicUser(db *gorm.DB) *gorm.DB {
+	return db.Select("id, nickname, avatar")
+}
+
+// CreateQuestion 创建问题
+func (r *QuestionRepository) CreateQuestion(question *models.ProductQuestion) error {
+	return r.db.Create(question).Error
+}
+
+// GetQuestion 获取问题
+func (r *QuestionRepository) GetQuestion(id uint) (*models.ProductQuestion, error) {
+	var question models.ProductQuestion
+	if err := r.db.Preload("User", publicUser).First(&question, id).Error; err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+// ListApprovedQuestions 分页获取商品已审核的问题，回答多的问题排在前面
+func (r *QuestionRepository) ListApprovedQuestions(productID uint, page, pageSize int) ([]models.ProductQuestion, int64, error) {
+	var questions []models.ProductQuestion
+	var total int64
+
+	query := r.db.Model(&models.ProductQuestion{}).
+		Where("product_id = ? AND status = ?", productID, models.QAStatusApproved)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("User", publicUser).
+		Order("answer_count DESC, id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&questions).Error
+	return questions, total, err
+}
+
+// ListQuestionsByStatus 分页获取指定状态的问题，用于审核
+func (r *QuestionRepository) ListQuestionsByStatus(status string, page, pageSize int) ([]models.ProductQuestion, int64, error) {
+	var questions []models.ProductQuestion
+	var total int64
+
+	query := r.db.Model(&models.ProductQuestion{}).Where("status = ?", status)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("User", publicUser).
+		Preload("Product", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, name")
+		}).
+		Order("id ASC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&questions).Error
+	return questions, total, err
+}
+
+// UpdateQuestionStatus 更新问题审核状态
+func (r *QuestionRepository) UpdateQuestionStatus(id uint, status string) error {
+	return r.db.Model(&models.ProductQuestion{}).
+		Where("id = ?", id).
+		Update("status", status).Error
+}
+
+// DeleteQuestion 删除问题及其回答
+func (r *QuestionRepository) DeleteQuestion(id uint) error {
+	if err := r.db.Where("question_id = ?", id).Delete(&models.ProductAnswer{}).Error; err != nil {
+		return err
+	}
+	return r.db.Delete(&models.ProductQuestion{}, id).Error
+}
+
+// RefreshAnswerCount 重新统计问题已审核的回答数量
+func (r *QuestionRepository) RefreshAnswerCount(questionID uint) error {
+	count := r.db.Model(&models.ProductAnswer{}).
+		Select("COUNT(*)").
+		Where("question_id = ? AND status = ? AND deleted_at IS NULL", questionID, models.QAStatusApproved)
+	return r.db.Model(&models.ProductQuestion{}).
+		Where("id = ?", questionID).
+		UpdateColumn("answer_count", count).Error
+}
+
+// CreateAnswer 创建回答
+func (r *QuestionRepository) CreateAnswer(answer *models.ProductAnswer) error {
+	return r.db.Create(answer).Error
+}
+
+// GetAnswer 获取回答
+func (r *QuestionRepository) GetAnswer(id uint) (*models.ProductAnswer, error) {
+	var answer models.ProductAnswer
+	if err := r.db.Preload("User", publicUser).First(&answer, id).Error; err != nil {
+		return nil, err
+	}
+	return &answer, nil
+}
+
+// ListApprovedAnswers 获取多个问题已审核的回答，官方回答优先，其次按点赞数排序
+func (r *QuestionRepository) ListApprovedAnswers(questionIDs []uint) ([]models.ProductAnswer, error) {
+	var answers []models.ProductAnswer
+	if len(questionIDs) == 0 {
+		return answers, nil
+	}
+	err := r.db.Where("question_id IN ? AND status = ?", questionIDs, models.QAStatusApproved).
+		Preload("User", publicUser).
+		Order("is_admin DESC, upvotes DESC, id ASC").
+		Find(&answers).Error
+	return answers, err
+}
+
+// ListAnswersByStatus 分页获取指定状态的回答，用于审核
+func (r *QuestionRepository) ListAnswersByStatus(status string, page, pageSize int) ([]models.ProductAnswer, int64, error) {
+	var answers []models.ProductAnswer
+	var total int64
+
+	query := r.db.Model(&models.ProductAnswer{}).Where("status = ?", status)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("User", publicUser).
+		Preload("Question").
+		Order("id ASC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&answers).Error
+	return answers, total, err
+}
+
+// UpdateAnswerStatus 更新回答审核状态
+func (r *QuestionRepository) UpdateAnswerStatus(id uint, status string) error {
+	return r.db.Model(&models.ProductAnswer{}).
+		Where("id = ?", id).
+		Update("status", status).Error
+}
+
+// DeleteAnswer 删除回答
+func (r *QuestionRepository) DeleteAnswer(id uint) error {
+	return r.db.Delete(&models.ProductAnswer{}, id).Error
+}
+
+// IsVerifiedBuyer 判断用户是否有包含该商品的已完成订单
+func (r *QuestionRepository) IsVerifiedBuyer(userID, productID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.OrderItem{}).
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("orders.user_id = ? AND orders.status = ? AND order_items.product_id = ?",
+			userID, models.OrderStatusCompleted, productID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// AddVote 点赞回答，重复点赞返回 ErrAlreadyVoted
+func (r *QuestionRepository) AddVote(answerID, userID uint) error {
+	var count int64
+	if err := r.db.Model(&models.AnswerVote{}).
+		Where("answer_id = ? AND user_id = ?", answerID, userID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrAlreadyVoted
+	}
+	if err := r.db.Create(&models.AnswerVote{AnswerID: answerID, UserID: userID}).Error; err != nil {
+		return err
+	}
+	return r.db.Model(&models.ProductAnswer{}).
+		Where("id = ?", answerID).
+		UpdateColumn("upvotes", gorm.Expr("upvotes + 1")).Error
+}
+
+// RemoveVote 取消点赞，未点赞时不报错
+func (r *QuestionRepository) RemoveVote(answerID, userID uint) error {
+	result := r.db.Where("answer_id = ? AND user_id = ?", answerID, userID).Delete(&models.AnswerVote{})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+	return r.db.Model(&models.ProductAnswer{}).
+		Where("id = ? AND upvotes > 0", answerID).
+		UpdateColumn("upvotes", gorm.Expr("upvotes - 1")).Error
+}
+
+// ListVotedAnswerIDs 获取用户在指定回答中已点赞的回答ID
+func (r *QuestionRepository) ListVotedAnswerIDs(userID uint, answerIDs []uint) ([]uint, error) {
+	var ids []uint
+	if len(answerIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Model(&models.AnswerVote{}).
+		Where("user_id = ? AND answer_id IN ?", userID, answerIDs).
+		Pluck("answer_id", &ids).Error
+	return ids, err
+}
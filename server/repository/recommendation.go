@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"shopify/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// paidOrderStatuses 视为已成交的订单状态
+var paidOrderStatuses = []string{models.OrderStatusPaid, models.OrderStatusShipped, models.OrderStatusCompleted}
+
+// ProductCoOccurrence 两个商品同时出现的订单数
+type ProductCoOccurrence struct {
+	ProductID    uint `json:"product_id"`
+	AssociatedID uint `json:"associated_id"`
+	Orders       int  `json:"orders"`
+}
+
+// ProductOrderCount 商品出现的订单数
+type ProductOrderCount struct {
+	ProductID uint `json:"product_id"`
+	Orders    int  `json:"orders"`
+}
+
+type RecommendationRepository struct {
+	*BaseRepository
+}
+
+func NewRecommendationRepository(db *gorm.DB) *RecommendationRepository {
+	return &RecommendationRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// paidOrderItems 指定时间以来已支付且未退款订单的订单项
+func (r *RecommendationRepository) paidOrderItems(since time.Time) *gorm.DB {
+	return r.db.Model(&models.OrderItem{}).
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("orders.created_at >= ? AND orders.status IN ? AND orders.deleted_at IS NULL", since, paidOrderStatuses).
+		Where("(orders.payment_status IS NULL OR orders.payment_status <> ?)", models.PaymentStatusRefunded)
+}
+
+// CountCoOccurrences 统计指定时间以来商品两两同时出现的订单数，只返回不少于 minOrders 的组合
+func (r *RecommendationRepository) CountCoOccurrences(since time.Time, minOrders int) ([]ProductCoOccurrence, error) {
+	var rows []ProductCoOccurrence
+	err := r.paidOrderItems(since).
+		Select("order_items.product_id, other.product_id AS associated_id, COUNT(DISTINCT order_items.order_id) AS orders").
+		Joins("JOIN order_items other ON other.order_id = order_items.order_id AND other.product_id <> order_items.product_id").
+		Group("order_items.product_id, other.product_id").
+		Having("COUNT(DISTINCT order_items.order_id) >= ?", minOrders).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// CountProductOrders 统计指定时间以来各商品出现的订单数
+func (r *RecommendationRepository) CountProductOrders(since time.Time) ([]ProductOrderCount, error) {
+	var rows []ProductOrderCount
+	err := r.paidOrderItems(since).
+		Select("order_items.product_id, COUNT(DISTINCT order_items.order_id) AS orders").
+		Group("order_items.product_id").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// ReplaceAssociations 用新的计算结果整体替换共现关系
+func (r *RecommendationRepository) ReplaceAssociations(associations []models.ProductAssociation) error {
+	if err := r.db.Where("1 = 1").Delete(&models.ProductAssociation{}).Error; err != nil {
+		return err
+	}
+	if len(associations) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(associations, 500).Error
+}
+
+// ListAssociations 获取商品的共现关系，按关联度倒序
+func (r *RecommendationRepository) ListAssociations(productID uint, limit int) ([]models.ProductAssociation, error) {
+	var associations []models.ProductAssociation
+	err := r.db.Where("product_id = ?", productID).
+		Order("score DESC, orders DESC, associated_id ASC").
+		Limit(limit).
+		Find(&associations).Error
+	return associations, err
+}
+
+// ListRelations 获取商品的人工关联，置顶按排序值升序
+func (r *RecommendationRepository) ListRelations(productID uint) ([]models.ProductRelation, error) {
+	var relations []models.ProductRelation
+	err := r.db.Where("product_id = ?", productID).
+		Order("sort_order ASC, id ASC").
+		Find(&relations).Error
+	return relations, err
+}
+
+// SaveRelation 保存人工关联，同一对商品只保留一条
+func (r *RecommendationRepository) SaveRelation(relation *models.ProductRelation) error {
+	var existing models.ProductRelation
+	err := r.db.Where("product_id = ? AND related_id = ?", relation.ProductID, relation.RelatedID).First(&existing).Error
+	if err == nil {
+		relation.ID = existing.ID
+		relation.CreatedAt = existing.CreatedAt
+		return r.db.Model(&existing).
+			Select("status", "sort_order", "operator_id", "updated_at").
+			Updates(models.ProductRelation{
+				Status:     relation.Status,
+				SortOrder:  relation.SortOrder,
+				OperatorID: relation.OperatorID,
+				UpdatedAt:  time.Now(),
+			}).Error
+	}
+	if err != gorm.ErrRecordNotFound {
+		return err
+	}
+	return r.db.Create(relation).Error
+}
+
+// DeleteRelation 删除人工关联，返回是否存在
+func (r *RecommendationRepository) DeleteRelation(productID, relatedID uint) (bool, error) {
+	result := r.db.Where("product_id = ? AND related_id = ?", productID, relatedID).Delete(&models.ProductRelation{})
+	return result.RowsAffected > 0, result.Error
+}
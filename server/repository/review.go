@@ -92,6 +92,34 @@ func (r *ReviewRepository) ListByUser(userID uint, page, pageSize int) ([]models
 	return reviews, total, err
 }
 
+// reviewKeysetOrder 评论游标分页的排序键
+var reviewKeysetOrder = keysetOrder{Name: "newest", Column: "created_at", Desc: true}
+
+// reviewSortKey 获取评论的排序键
+func reviewSortKey(review *models.Review) (string, uint) {
+	return cursorTime(review.CreatedAt), review.ID
+}
+
+// ListByProductCursor 游标分页获取商品的评论列表
+func (r *ReviewRepository) ListByProductCursor(productID uint, req CursorRequest) ([]models.Review, *CursorPage, error) {
+	query := r.db.Model(&models.Review{}).
+		Where("product_id = ?", productID).
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, nickname, avatar")
+		})
+
+	return cursorPaginate(query, reviewKeysetOrder, req, reviewSortKey)
+}
+
+// ListByUserCursor 游标分页获取用户的评论列表
+func (r *ReviewRepository) ListByUserCursor(userID uint, req CursorRequest) ([]models.Review, *CursorPage, error) {
+	query := r.db.Model(&models.Review{}).
+		Where("user_id = ?", userID).
+		Preload("Product")
+
+	return cursorPaginate(query, reviewKeysetOrder, req, reviewSortKey)
+}
+
 // Delete 删除评论
 func (r *ReviewRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Review{}, id).Error
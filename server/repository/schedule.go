@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"shopify/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ScheduleRepository struct {
+	*BaseRepository
+}
+
+func NewScheduleRepository(db *gorm.DB) *ScheduleRepository {
+	return &ScheduleRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create 创建定时任务
+func (r *ScheduleRepository) Create(schedule *models.ProductSchedule) error {
+	return r.db.Create(schedule).Error
+}
+
+// GetByID 获取定时任务
+func (r *ScheduleRepository) GetByID(id uint) (*models.ProductSchedule, error) {
+	var schedule models.ProductSchedule
+	err := r.db.First(&schedule, id).Error
+	return &schedule, err
+}
+
+// ListByProduct 获取商品的定时任务，按执行时间倒序
+func (r *ScheduleRepository) ListByProduct(productID uint) ([]models.ProductSchedule, error) {
+	var schedules []models.ProductSchedule
+	err := r.db.Where("product_id = ?", productID).
+		Order("run_at DESC, id DESC").
+		Find(&schedules).Error
+	return schedules, err
+}
+
+// ListDue 获取已到执行时间的待执行任务
+func (r *ScheduleRepository) ListDue(now time.Time, limit int) ([]models.ProductSchedule, error) {
+	var schedules []models.ProductSchedule
+	err := r.db.Where("status = ? AND run_at <= ?", models.ScheduleStatusPending, now).
+		Order("run_at ASC, id ASC").
+		Limit(limit).
+		Find(&schedules).Error
+	return schedules, err
+}
+
+// ListDueReverts 获取已到恢复时间的已执行任务
+func (r *ScheduleRepository) ListDueReverts(now time.Time, limit int) ([]models.ProductSchedule, error) {
+	var schedules []models.ProductSchedule
+	err := r.db.Where("status = ? AND revert_at IS NOT NULL AND revert_at <= ?", models.ScheduleStatusApplied, now).
+		Order("revert_at ASC, id ASC").
+		Limit(limit).
+		Find(&schedules).Error
+	return schedules, err
+}
+
+// Transition 仅当任务处于 from 状态时更新，返回是否更新成功，避免重复执行
+func (r *ScheduleRepository) Transition(id uint, from string, updates map[string]interface{}) (bool, error) {
+	result := r.db.Model(&models.ProductSchedule{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(updates)
+	return result.RowsAffected > 0, result.Error
+}
+
+// CreatePriceHistory 记录价格变动
+func (r *ScheduleRepository) CreatePriceHistory(history *models.PriceHistory) error {
+	return r.db.Create(history).Error
+}
+
+// ListRecentPriceHistory 获取商品指定时间之后的价格变动，按时间倒序
+func (r *ScheduleRepository) ListRecentPriceHistory(productID uint, since time.Time, limit int) ([]models.PriceHistory, error) {
+	var histories []models.PriceHistory
+	err := r.db.Where("product_id = ? AND created_at >= ?", productID, since).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&histories).Error
+	return histories, err
+}
+
+// ListPriceHistory 分页获取商品的全部价格变动
+func (r *ScheduleRepository) ListPriceHistory(productID uint, page, pageSize int) ([]models.PriceHistory, int64, error) {
+	var histories []models.PriceHistory
+	var total int64
+
+	offset := (page - 1) * pageSize
+
+	query := r.db.Model(&models.PriceHistory{}).Where("product_id = ?", productID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Offset(offset).
+		Limit(pageSize).
+		Order("created_at DESC, id DESC").
+		Find(&histories).Error
+
+	return histories, total, err
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"shopify/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SearchRepository struct {
+	*BaseRepository
+}
+
+func NewSearchRepository(db *gorm.DB) *SearchRepository {
+	return &SearchRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// QueryCount 搜索词统计
+type QueryCount struct {
+	Query          string    `json:"query"`            // 搜索词
+	Count          int64     `json:"count"`            // 搜索次数
+	LastSearchedAt time.Time `json:"last_searched_at"` // 最近一次搜索时间
+}
+
+// CreateLog 记录搜索日志
+func (r *SearchRepository) CreateLog(log *models.SearchLog) error {
+	return r.db.Create(log).Error
+}
+
+// ListTopQueries 统计时间窗口内有结果的热门搜索词，prefix 不为空时只统计以其开头的词
+func (r *SearchRepository) ListTopQueries(since time.Time, prefix string, limit int) ([]QueryCount, error) {
+	var counts []QueryCount
+	query := r.db.Model(&models.SearchLog{}).
+		Select("query, COUNT(*) AS count, MAX(created_at) AS last_searched_at").
+		Where("created_at >= ? AND result_count > 0", since)
+	if prefix != "" {
+		query = query.Where("query LIKE ?", escapeLike(prefix)+"%")
+	}
+	err := query.Group("query").
+		Order("count DESC, last_searched_at DESC").
+		Limit(limit).
+		Scan(&counts).Error
+	return counts, err
+}
+
+// ListZeroResultQueries 统计时间窗口内无结果的搜索词(支持分页)
+func (r *SearchRepository) ListZeroResultQueries(since time.Time, page, pageSize int) ([]QueryCount, int64, error) {
+	var counts []QueryCount
+	var total int64
+
+	offset := (page - 1) * pageSize
+
+	query := r.db.Model(&models.SearchLog{}).
+		Where("created_at >= ? AND result_count = 0", since)
+
+	// 获取总数
+	if err := query.Distinct("query").Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 获取分页数据
+	err := r.db.Model(&models.SearchLog{}).
+		Select("query, COUNT(*) AS count, MAX(created_at) AS last_searched_at").
+		Where("created_at >= ? AND result_count = 0", since).
+		Group("query").
+		Order("count DESC, last_searched_at DESC").
+		Offset(offset).
+		Limit(pageSize).
+		Scan(&counts).Error
+
+	return counts, total, err
+}
+
+// ListTerms 获取运营配置的搜索词，status 为空时返回全部
+func (r *SearchRepository) ListTerms(status string) ([]models.SearchTerm, error) {
+	var terms []models.SearchTerm
+	query := r.db.Model(&models.SearchTerm{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("sort_order ASC, id ASC").Find(&terms).Error
+	return terms, err
+}
+
+// GetTermByID 获取搜索词配置
+func (r *SearchRepository) GetTermByID(id uint) (*models.SearchTerm, error) {
+	var term models.SearchTerm
+	if err := r.db.First(&term, id).Error; err != nil {
+		return nil, err
+	}
+	return &term, nil
+}
+
+// SaveTerm 保存搜索词配置，同一个词只保留一条
+func (r *SearchRepository) SaveTerm(term *models.SearchTerm) error {
+	var existing models.SearchTerm
+	err := r.db.Where("term = ?", term.Term).First(&existing).Error
+	if err == nil {
+		term.ID = existing.ID
+		term.CreatedAt = existing.CreatedAt
+		return r.db.Model(&existing).
+			Select("status", "sort_order", "updated_at").
+			Updates(models.SearchTerm{
+				Status:    term.Status,
+				SortOrder: term.SortOrder,
+				UpdatedAt: time.Now(),
+			}).Error
+	}
+	if err != gorm.ErrRecordNotFound {
+		return err
+	}
+	return r.db.Create(term).Error
+}
+
+// DeleteTerm 删除搜索词配置
+func (r *SearchRepository) DeleteTerm(id uint) error {
+	return r.db.Delete(&models.SearchTerm{}, id).Error
+}
+
+// escapeLike 转义 LIKE 查询中的通配符
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_")
+	return replacer.Replace(value)
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"shopify/models"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type SKURepository struct {
+	*BaseRepository
+}
+
+func NewSKURepository(db *gorm.DB) *SKURepository {
+	return &SKURepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// ListOptions 获取产品的规格项
+func (r *SKURepository) ListOptions(productID uint) ([]models.ProductOption, error) {
+	var options []models.ProductOption
+	err := r.db.Where("product_id = ?", productID).
+		Order("sort_order ASC, id ASC").
+		Find(&options).Error
+	return options, err
+}
+
+// ReplaceOptions 替换产品的全部规格项
+func (r *SKURepository) ReplaceOptions(productID uint, options []models.ProductOption) error {
+	if err := r.db.Where("product_id = ?", productID).Delete(&models.ProductOption{}).Error; err != nil {
+		return err
+	}
+	if len(options) == 0 {
+		return nil
+	}
+	for i := range options {
+		options[i].ID = 0
+		options[i].ProductID = productID
+		if options[i].Values == nil {
+			options[i].Values = make([]string, 0)
+		}
+	}
+	return r.db.Create(&options).Error
+}
+
+// Create 创建SKU
+func (r *SKURepository) Create(sku *models.SKU) error {
+	return r.db.Create(sku).Error
+}
+
+// GetByID 获取SKU详情
+func (r *SKURepository) GetByID(id uint) (*models.SKU, error) {
+	var sku models.SKU
+	err := r.db.First(&sku, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sku, nil
+}
+
+// GetByCode 通过SKU编码获取SKU
+func (r *SKURepository) GetByCode(code string) (*models.SKU, error) {
+	var sku models.SKU
+	err := r.db.Where("code = ?", code).First(&sku).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sku, nil
+}
+
+// ListByProduct 获取产品的所有SKU
+func (r *SKURepository) ListByProduct(productID uint) ([]models.SKU, error) {
+	var skus []models.SKU
+	err := r.db.Where("product_id = ?", productID).
+		Order("id ASC").
+		Find(&skus).Error
+	return skus, err
+}
+
+// Update 更新SKU信息，库存需通过库存调整写入流水
+func (r *SKURepository) Update(sku *models.SKU) error {
+	return r.db.Model(&models.SKU{}).
+		Where("id = ?", sku.ID).
+		Select("code", "options", "price", "barcode", "image", "status", "updated_at").
+		Updates(models.SKU{
+			Code:      sku.Code,
+			Options:   sku.Options,
+			Price:     sku.Price,
+			Barcode:   sku.Barcode,
+			Image:     sku.Image,
+			Status:    sku.Status,
+			UpdatedAt: time.Now(),
+		}).Error
+}
+
+// UpdatePrice 更新SKU价格
+func (r *SKURepository) UpdatePrice(id uint, price decimal.Decimal) error {
+	return r.db.Model(&models.SKU{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"price":      price,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// Delete 删除SKU(软删除)
+func (r *SKURepository) Delete(id uint) error {
+	return r.db.Delete(&models.SKU{}, id).Error
+}
+
+// ChangeStock 增减SKU库存
+func (r *SKURepository) ChangeStock(id uint, quantity int) error {
+	db := r.db
+	if quantity > 0 {
+		db = db.Unscoped() // 取消、退款回补库存时SKU可能已被删除
+	}
+	result := db.Model(&models.SKU{}).
+		Where("id = ? AND stock >= ?", id, -quantity). // 确保库存充足
+		UpdateColumn("stock", gorm.Expr("stock + ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInsufficientStock
+	}
+	return nil
+}
+
+// CountByProduct 统计产品的SKU数量
+func (r *SKURepository) CountByProduct(productID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.SKU{}).Where("product_id = ?", productID).Count(&count).Error
+	return count, err
+}
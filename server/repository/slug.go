@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"shopify/models"
+
+	"gorm.io/gorm"
+)
+
+type SlugRepository struct {
+	*BaseRepository
+}
+
+func NewSlugRepository(db *gorm.DB) *SlugRepository {
+	return &SlugRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// GetBySlug 根据 slug 获取记录，包括已被替换的旧 slug
+func (r *SlugRepository) GetBySlug(slug string) (*models.ProductSlug, error) {
+	var productSlug models.ProductSlug
+	err := r.db.Where("slug = ?", slug).First(&productSlug).Error
+	if err != nil {
+		return nil, err
+	}
+	return &productSlug, nil
+}
+
+// Create 记录商品使用的 slug
+func (r *SlugRepository) Create(productSlug *models.ProductSlug) error {
+	return r.db.Create(productSlug).Error
+}
+
+// ListByProduct 获取商品用过的全部 slug
+func (r *SlugRepository) ListByProduct(productID uint) ([]models.ProductSlug, error) {
+	var slugs []models.ProductSlug
+	err := r.db.Where("product_id = ?", productID).
+		Order("id ASC").
+		Find(&slugs).Error
+	return slugs, err
+}
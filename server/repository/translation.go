@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"time"
+
+	"shopify/models"
+
+	"gorm.io/gorm"
+)
+
+type TranslationRepository struct {
+	*BaseRepository
+}
+
+func NewTranslationRepository(db *gorm.DB) *TranslationRepository {
+	return &TranslationRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// ListByEntity 获取对象在各语言下的全部翻译
+func (r *TranslationRepository) ListByEntity(entityType string, entityID uint) ([]models.Translation, error) {
+	var translations []models.Translation
+	err := r.db.Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("locale ASC, field ASC").
+		Find(&translations).Error
+	return translations, err
+}
+
+// ListByEntities 批量获取多个对象的翻译，locale 为空时返回所有语言
+func (r *TranslationRepository) ListByEntities(entityType string, entityIDs []uint, locale string) ([]models.Translation, error) {
+	var translations []models.Translation
+	if len(entityIDs) == 0 {
+		return translations, nil
+	}
+	query := r.db.Where("entity_type = ? AND entity_id IN ?", entityType, entityIDs)
+	if locale != "" {
+		query = query.Where("locale = ?", locale)
+	}
+	err := query.Order("entity_id ASC, locale ASC").Find(&translations).Error
+	return translations, err
+}
+
+// ListByType 获取某类对象的全部翻译，用于重建搜索索引
+func (r *TranslationRepository) ListByType(entityType string) ([]models.Translation, error) {
+	var translations []models.Translation
+	err := r.db.Where("entity_type = ?", entityType).
+		Order("entity_id ASC, locale ASC").
+		Find(&translations).Error
+	return translations, err
+}
+
+// Save 保存翻译，同一对象同一语言的同一字段只保留一条
+func (r *TranslationRepository) Save(translation *models.Translation) error {
+	var existing models.Translation
+	err := r.db.Where("entity_type = ? AND entity_id = ? AND locale = ? AND field = ?",
+		translation.EntityType, translation.EntityID, translation.Locale, translation.Field).
+		First(&existing).Error
+	if err == nil {
+		translation.ID = existing.ID
+		translation.CreatedAt = existing.CreatedAt
+		return r.db.Model(&existing).
+			Select("value", "updated_at").
+			Updates(models.Translation{
+				Value:     translation.Value,
+				UpdatedAt: time.Now(),
+			}).Error
+	}
+	if err != gorm.ErrRecordNotFound {
+		return err
+	}
+	return r.db.Create(translation).Error
+}
+
+// Delete 删除对象在某个语言下的翻译，field 为空时删除该语言的全部字段
+func (r *TranslationRepository) Delete(entityType string, entityID uint, locale, field string) error {
+	query := r.db.Where("entity_type = ? AND entity_id = ? AND locale = ?", entityType, entityID, locale)
+	if field != "" {
+		query = query.Where("field = ?", field)
+	}
+	return query.Delete(&models.Translation{}).Error
+}
@@ -286,4 +286,12 @@ func (r *UserRepository) ResetPassword(email, hashedPassword string) error {
 			"verification_code": "",
 			"code_expiry":      nil,
 		}).Error
-} 
\ No newline at end of file
+} 
+// ListAdminEmails 获取所有管理员的邮箱
+func (r *UserRepository) ListAdminEmails() ([]string, error) {
+	var emails []string
+	err := r.db.Model(&models.User{}).
+		Where("role = ?", "admin").
+		Pluck("email", &emails).Error
+	return emails, err
+}
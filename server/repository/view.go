@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"shopify/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ViewedProduct 用户浏览过的商品及最近浏览时间
+type ViewedProduct struct {
+	ProductID uint      `json:"product_id"`
+	ViewedAt  time.Time `json:"viewed_at"`
+}
+
+// ProductViewStats 商品浏览量统计
+type ProductViewStats struct {
+	ProductID uint   `json:"product_id"`
+	Name      string `json:"name"`
+	Views     int64  `json:"views"`    // 浏览次数
+	Visitors  int64  `json:"visitors"` // 独立访客数，登录用户按用户、匿名访客按会话计
+}
+
+// DailyViews 按天统计的浏览量
+type DailyViews struct {
+	Date     string `json:"date"`
+	Views    int64  `json:"views"`
+	Visitors int64  `json:"visitors"`
+}
+
+// ProductFunnel 商品浏览→加购→下单转化漏斗
+type ProductFunnel struct {
+	ProductID   uint    `json:"product_id"`
+	Name        string  `json:"name"`
+	Viewers     int64   `json:"viewers"`       // 浏览过的登录用户数
+	CartUsers   int64   `json:"cart_users"`    // 浏览后加入购物车的用户数
+	OrderUsers  int64   `json:"order_users"`   // 浏览后下单的用户数
+	ViewToCart  float64 `json:"view_to_cart"`  // 浏览到加购转化率
+	CartToOrder float64 `json:"cart_to_order"` // 加购到下单转化率
+	ViewToOrder float64 `json:"view_to_order"` // 浏览到下单转化率
+}
+
+// visitorKey 独立访客标识，登录用户按用户、匿名访客按会话区分
+const visitorKey = "COALESCE(CONCAT('u', product_views.user_id), CONCAT('s', product_views.session_id))"
+
+type ViewRepository struct {
+	*BaseRepository
+}
+
+func NewViewRepository(db *gorm.DB) *ViewRepository {
+	return &ViewRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// CreateBatch 批量写入浏览记录
+func (r *ViewRepository) CreateBatch(views []models.ProductView) error {
+	if len(views) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(views, 500).Error
+}
+
+// ListRecentlyViewed 获取用户最近浏览的商品，同一商品只保留最近一次
+func (r *ViewRepository) ListRecentlyViewed(userID uint, limit int) ([]ViewedProduct, error) {
+	var viewed []ViewedProduct
+	err := r.db.Model(&models.ProductView{}).
+		Select("product_id, MAX(created_at) AS viewed_at").
+		Where("user_id = ?", userID).
+		Group("product_id").
+		Order("viewed_at DESC").
+		Limit(limit).
+		Scan(&viewed).Error
+	return viewed, err
+}
+
+// CountProductViews 统计商品在时间范围内的浏览次数和独立访客数
+func (r *ViewRepository) CountProductViews(productID uint, since time.Time) (*ProductViewStats, error) {
+	stats := ProductViewStats{ProductID: productID}
+	err := r.db.Model(&models.ProductView{}).
+		Select("COUNT(*) AS views, COUNT(DISTINCT "+visitorKey+") AS visitors").
+		Where("product_id = ? AND created_at >= ?", productID, since).
+		Scan(&stats).Error
+	stats.ProductID = productID
+	return &stats, err
+}
+
+// ListDailyViews 按天统计商品在时间范围内的浏览量
+func (r *ViewRepository) ListDailyViews(productID uint, since time.Time) ([]DailyViews, error) {
+	var daily []DailyViews
+	err := r.db.Model(&models.ProductView{}).
+		Select("DATE_FORMAT(created_at, '%Y-%m-%d') AS date, COUNT(*) AS views, COUNT(DISTINCT "+visitorKey+") AS visitors").
+		Where("product_id = ? AND created_at >= ?", productID, since).
+		Group("date").
+		Order("date ASC").
+		Scan(&daily).Error
+	return daily, err
+}
+
+// ListTopViewed 分页获取时间范围内浏览量最高的商品
+func (r *ViewRepository) ListTopViewed(since time.Time, page, pageSize int) ([]ProductViewStats, int64, error) {
+	var stats []ProductViewStats
+	var total int64
+
+	base := r.db.Model(&models.ProductView{}).Where("product_views.created_at >= ?", since)
+	if err := base.Session(&gorm.Session{}).Distinct("product_id").Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := base.Select("product_views.product_id, products.name, COUNT(*) AS views, COUNT(DISTINCT " + visitorKey + ") AS visitors").
+		Joins("JOIN products ON products.id = product_views.product_id").
+		Group("product_views.product_id, products.name").
+		Order("views DESC, product_views.product_id ASC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Scan(&stats).Error
+	return stats, total, err
+}
+
+// 下单后购物车会被清空，已下单的用户同样计入加购
+const (
+	funnelOrdered = `EXISTS (SELECT 1 FROM order_items oi JOIN orders o ON o.id = oi.order_id
+		WHERE oi.product_id = v.product_id AND o.user_id = v.user_id AND o.created_at >= v.first_viewed_at
+		AND o.status <> ? AND o.deleted_at IS NULL)`
+	funnelCarted = `EXISTS (SELECT 1 FROM cart_items ci
+		WHERE ci.product_id = v.product_id AND ci.user_id = v.user_id AND ci.created_at >= v.first_viewed_at)`
+)
+
+// ListFunnels 统计时间范围内登录用户浏览→加购→下单的转化
+// 只统计首次浏览之后发生的加购和下单，productID 为 0 时返回浏览人数最多的商品
+func (r *ViewRepository) ListFunnels(since time.Time, productID uint, limit int) ([]ProductFunnel, error) {
+	// 每个用户在时间范围内首次浏览商品的时间
+	viewers := r.db.Model(&models.ProductView{}).
+		Select("product_id, user_id, MIN(created_at) AS first_viewed_at").
+		Where("user_id IS NOT NULL AND created_at >= ?", since).
+		Group("product_id, user_id")
+	if productID != 0 {
+		viewers = viewers.Where("product_id = ?", productID)
+	}
+
+	var funnels []ProductFunnel
+	query := r.db.Table("(?) AS v", viewers).
+		Select("v.product_id, products.name, COUNT(*) AS viewers, "+
+			"SUM(CASE WHEN "+funnelCarted+" OR "+funnelOrdered+" THEN 1 ELSE 0 END) AS cart_users, "+
+			"SUM(CASE WHEN "+funnelOrdered+" THEN 1 ELSE 0 END) AS order_users",
+			models.OrderStatusCancelled, models.OrderStatusCancelled).
+		Joins("JOIN products ON products.id = v.product_id").
+		Group("v.product_id, products.name").
+		Order("viewers DESC, v.product_id ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Scan(&funnels).Error
+	return funnels, err
+}
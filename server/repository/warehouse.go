@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"shopify/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WarehouseRepository struct {
+	*BaseRepository
+}
+
+func NewWarehouseRepository(db *gorm.DB) *WarehouseRepository {
+	return &WarehouseRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create 创建仓库
+func (r *WarehouseRepository) Create(warehouse *models.Warehouse) error {
+	if warehouse.Regions == nil {
+		warehouse.Regions = make([]string, 0)
+	}
+	return r.db.Create(warehouse).Error
+}
+
+// GetByID 获取仓库详情
+func (r *WarehouseRepository) GetByID(id uint) (*models.Warehouse, error) {
+	var warehouse models.Warehouse
+	err := r.db.First(&warehouse, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+// Update 更新仓库信息
+func (r *WarehouseRepository) Update(warehouse *models.Warehouse) error {
+	if warehouse.Regions == nil {
+		warehouse.Regions = make([]string, 0)
+	}
+	return r.db.Model(&models.Warehouse{}).
+		Where("id = ?", warehouse.ID).
+		Select("code", "name", "province", "city", "address", "contact", "phone", "regions", "priority", "status", "updated_at").
+		Updates(models.Warehouse{
+			Code:      warehouse.Code,
+			Name:      warehouse.Name,
+			Province:  warehouse.Province,
+			City:      warehouse.City,
+			Address:   warehouse.Address,
+			Contact:   warehouse.Contact,
+			Phone:     warehouse.Phone,
+			Regions:   warehouse.Regions,
+			Priority:  warehouse.Priority,
+			Status:    warehouse.Status,
+			UpdatedAt: time.Now(),
+		}).Error
+}
+
+// Delete 删除仓库(软删除)
+func (r *WarehouseRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Warehouse{}, id).Error
+}
+
+// List 获取仓库列表
+func (r *WarehouseRepository) List() ([]models.Warehouse, error) {
+	var warehouses []models.Warehouse
+	err := r.db.Order("priority ASC, id ASC").Find(&warehouses).Error
+	return warehouses, err
+}
+
+// GetStock 获取仓库中某个产品的库存记录
+func (r *WarehouseRepository) GetStock(warehouseID, productID uint) (*models.WarehouseStock, error) {
+	var stock models.WarehouseStock
+	err := r.db.Where("warehouse_id = ? AND product_id = ?", warehouseID, productID).
+		First(&stock).Error
+	if err != nil {
+		return nil, err
+	}
+	return &stock, nil
+}
+
+// ListStocksByProduct 获取产品在各个有效仓库中的库存
+func (r *WarehouseRepository) ListStocksByProduct(productID uint) ([]models.WarehouseStock, error) {
+	var stocks []models.WarehouseStock
+	err := r.db.Joins("Warehouse").
+		Where("warehouse_stocks.product_id = ? AND Warehouse.status = ?", productID, "active").
+		Order("Warehouse.priority ASC, Warehouse.id ASC").
+		Find(&stocks).Error
+	return stocks, err
+}
+
+// ListStocksByWarehouse 获取仓库中的产品库存(支持分页)
+func (r *WarehouseRepository) ListStocksByWarehouse(warehouseID uint, page, pageSize int) ([]models.WarehouseStock, int64, error) {
+	var stocks []models.WarehouseStock
+	var total int64
+
+	offset := (page - 1) * pageSize
+
+	// 获取总数
+	if err := r.db.Model(&models.WarehouseStock{}).Where("warehouse_id = ?", warehouseID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 获取分页数据
+	err := r.db.Where("warehouse_id = ?", warehouseID).
+		Offset(offset).
+		Limit(pageSize).
+		Order("product_id ASC").
+		Find(&stocks).Error
+
+	return stocks, total, err
+}
+
+// SumStockByProduct 统计产品在所有仓库中的库存总量
+func (r *WarehouseRepository) SumStockByProduct(productID uint) (int, error) {
+	var total int
+	err := r.db.Model(&models.WarehouseStock{}).
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("product_id = ?", productID).
+		Scan(&total).Error
+	return total, err
+}
+
+// ChangeStock 增减仓库库存，库存记录不存在时自动创建
+func (r *WarehouseRepository) ChangeStock(warehouseID, productID uint, quantity int) error {
+	if quantity > 0 {
+		stock := models.WarehouseStock{WarehouseID: warehouseID, ProductID: productID}
+		if err := r.db.Where(&stock).FirstOrCreate(&stock).Error; err != nil {
+			return err
+		}
+	}
+
+	result := r.db.Model(&models.WarehouseStock{}).
+		Where("warehouse_id = ? AND product_id = ? AND quantity >= ?", warehouseID, productID, -quantity). // 确保库存充足
+		UpdateColumn("quantity", gorm.Expr("quantity + ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInsufficientStock
+	}
+	return nil
+}
+
+// CreateAllocation 创建订单发货仓分配记录
+func (r *WarehouseRepository) CreateAllocation(allocation *models.OrderAllocation) error {
+	return r.db.Create(allocation).Error
+}
+
+// ListAllocationsByOrder 获取订单的发货仓分配记录
+func (r *WarehouseRepository) ListAllocationsByOrder(orderID uint) ([]models.OrderAllocation, error) {
+	var allocations []models.OrderAllocation
+	err := r.db.Where("order_id = ?", orderID).
+		Preload("Warehouse").
+		Order("id ASC").
+		Find(&allocations).Error
+	return allocations, err
+}
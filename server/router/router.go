@@ -16,6 +16,14 @@ func RegisterRoutes(r *gin.Engine, sf *service.ServiceFactory, db *gorm.DB) {
 	// 健康检查
 	r.GET("/health", handlers.HealthCheck)
 
+	// 站点地图
+	r.GET("/sitemap.xml", handlers.GetSitemap)
+	r.GET("/sitemap/:page", handlers.GetSitemapPage)
+
+	// 商品源，供 Google Merchant Center 等平台抓取，地址中包含访问令牌
+	r.GET("/feeds/:token/google.xml", handlers.GetGoogleFeed)
+	r.GET("/feeds/:token/products.csv", handlers.GetCSVFeed)
+
 	// API v1 分组
 	v1 := r.Group("/api/v1")
 	{
@@ -31,26 +39,45 @@ func RegisterRoutes(r *gin.Engine, sf *service.ServiceFactory, db *gorm.DB) {
 			// 公开的商品接口
 			products := public.Group("/products")
 			{
-				products.GET("", handlers.ListProducts)                    // 获取商品列表
-				products.GET("/:id", handlers.GetProduct)                  // 获取商品详情
-				products.GET("/category/:category", handlers.ListProducts) // 按类别查询商品
-				products.GET("/search", handlers.ListProducts)             // 搜索商品
+				products.GET("", handlers.ListProducts)                                                     // 获取商品列表，支持组合筛选和排序
+				products.GET("/:id", middleware.OptionalAuthMiddleware(), handlers.GetProduct)              // 获取商品详情，登录用户计入最近浏览
+				products.GET("/category/:category", handlers.ListProducts)                                  // 按类别查询商品
+				products.GET("/search", middleware.OptionalAuthMiddleware(), handlers.SearchProducts)       // 全文搜索商品，登录用户的搜索记录关联用户
+				products.GET("/compare", middleware.OptionalAuthMiddleware(), handlers.CompareProducts)     // 商品对比，未传 ids 时对比登录用户的对比列表
+				products.GET("/slug/:slug", middleware.OptionalAuthMiddleware(), handlers.GetProductBySlug) // 按 slug 获取商品详情，旧 slug 返回 301
 
-				// 添加分类查询路由
-				products.GET("/filter/category", handlers.ListProducts) // 按类别筛选
-				products.GET("/filter/price", handlers.ListProducts)    // 按价格区间筛选
-				products.GET("/filter/tags", handlers.ListProducts)     // 按标签筛选
-				products.GET("/filter/keyword", handlers.ListProducts)  // 按关键词搜索
+				// 历史筛选路由，均为商品列表的别名，查询参数与商品列表一致
+				products.GET("/filter/*filter", handlers.ListProducts)
 
 				// 商品评论
 				products.GET("/:id/reviews", handlers.GetProductReviews) // 获取商品评论列表
+
+				// 商品问答，登录用户返回点赞状态
+				products.GET("/:id/questions", middleware.OptionalAuthMiddleware(), handlers.ListProductQuestions)
+
+				// 相关推荐
+				products.GET("/:id/related", handlers.GetRelatedProducts) // 一起购买和同类目热销
 			}
 
+			// 搜索联想和热搜
+			public.GET("/search/suggest", handlers.SearchSuggest)
+			public.GET("/search/hot", handlers.GetHotSearches)
+
+			// 公开的类目接口
+			public.GET("/categories/tree", handlers.GetCategoryTree)                  // 获取类目树
+			public.GET("/categories/:id/attributes", handlers.ListCategoryAttributes) // 获取类目规格属性
+
 			// 公开的广告接口
 			public.GET("/advertisements", handlers.ListAdvertisements)
 			public.GET("/advertisements/position/:position", handlers.GetActiveAdvertisements)
 			public.GET("/advertisements/:id", handlers.GetAdvertisement)
 
+			// 可选的展示币种
+			public.GET("/currencies", handlers.ListCurrencies)
+
+			// 数字商品签名下载链接，凭签名访问无需登录
+			public.GET("/downloads/:id", handlers.DownloadDigitalFile)
+
 			// 支付相关路由
 			payments := public.Group("/payments")
 			{
@@ -74,16 +101,27 @@ func RegisterRoutes(r *gin.Engine, sf *service.ServiceFactory, db *gorm.DB) {
 				users.DELETE("/addresses/:id", handlers.DeleteUserAddress)
 				users.PUT("/addresses/:id", handlers.UpdateUserAddress)
 				users.PUT("/addresses/:id/default", handlers.SetDefaultAddresses)
+
+				users.GET("/me/recently-viewed", handlers.GetRecentlyViewed) // 最近浏览的商品
+
+				users.GET("/me/compare", handlers.GetCompareList)                      // 对比列表及对比矩阵
+				users.PUT("/me/compare", handlers.ReplaceCompareList)                  // 同步对比列表
+				users.DELETE("/me/compare", handlers.ClearCompareList)                 // 清空对比列表
+				users.POST("/me/compare/:productId", handlers.AddToCompareList)        // 加入对比列表
+				users.DELETE("/me/compare/:productId", handlers.RemoveFromCompareList) // 移出对比列表
 			}
 
 			// 订单相关
 			orders := authorized.Group("/orders")
 			{
 				orders.POST("", handlers.CreateOrder)
+				orders.POST("/preview", handlers.PreviewOrder) // 按结算币种预览订单金额
 				orders.GET("", handlers.ListOrders)
 				orders.GET("/:id", handlers.GetOrder)
 				orders.PUT("/:id/status", handlers.UpdateOrderStatus)
 				orders.GET("/:id/logistics", handlers.GetLogistics)
+				orders.GET("/:id/shipments", handlers.ListShipments)
+				orders.GET("/:id/downloads", handlers.GetOrderDownloads) // 数字商品授权码和下载链接
 			}
 
 			// 购物车相关
@@ -98,6 +136,9 @@ func RegisterRoutes(r *gin.Engine, sf *service.ServiceFactory, db *gorm.DB) {
 				cart.GET("/selected", handlers.GetSelectedCartItems)
 			}
 
+			// 文件上传
+			authorized.POST("/uploads", handlers.UploadMedia)
+
 			// 评论相关
 			reviews := authorized.Group("/reviews")
 			{
@@ -106,6 +147,12 @@ func RegisterRoutes(r *gin.Engine, sf *service.ServiceFactory, db *gorm.DB) {
 				reviews.DELETE("/:id", handlers.DeleteReview) // 删除评论
 			}
 
+			// 商品问答
+			authorized.POST("/products/:id/questions", handlers.AskQuestion)      // 提问，审核后展示
+			authorized.POST("/questions/:id/answers", handlers.AnswerQuestion)    // 管理员或已购买用户回答
+			authorized.POST("/answers/:id/upvote", handlers.UpvoteAnswer)         // 点赞回答
+			authorized.DELETE("/answers/:id/upvote", handlers.RemoveAnswerUpvote) // 取消点赞
+
 			// 管理员路由组
 			admin := authorized.Group("/admin")
 			{
@@ -122,6 +169,106 @@ func RegisterRoutes(r *gin.Engine, sf *service.ServiceFactory, db *gorm.DB) {
 					adminProducts.DELETE("/:id", handlers.DeleteProduct) // 删除商品
 					adminProducts.GET("", handlers.ListProducts)         // 管理员查看所有商品
 					adminProducts.GET("/:id", handlers.GetProduct)       // 管理员查看商品详情
+
+					adminProducts.POST("/:id/stock/adjust", handlers.AdjustProductStock)            // 人工调整库存
+					adminProducts.GET("/:id/stock/movements", handlers.ListStockMovements)          // 查看库存流水
+					adminProducts.GET("/:id/stock/warehouses", handlers.ListProductWarehouseStocks) // 查看分仓库存
+					adminProducts.PUT("/:id/skus", handlers.SaveProductSKUs)                        // 批量编辑规格和SKU
+					adminProducts.PUT("/:id/attributes", handlers.SaveProductAttributes)            // 保存规格属性值
+					adminProducts.GET("/:id/components", handlers.GetBundleComponents)              // 套装组件
+					adminProducts.PUT("/:id/components", handlers.SaveBundleComponents)             // 保存套装组件，普通商品转为套装
+					adminProducts.DELETE("/:id/components", handlers.RemoveBundleComponents)        // 取消套装
+
+					adminProducts.GET("/:id/digital", handlers.GetDigitalProduct)                  // 数字商品文件和授权码库存
+					adminProducts.POST("/:id/digital/files", handlers.UploadDigitalFile)           // 上传文件，普通商品转为数字商品
+					adminProducts.DELETE("/:id/digital/files/:fileId", handlers.DeleteDigitalFile) // 删除文件
+					adminProducts.POST("/:id/digital/keys", handlers.AddLicenseKeys)               // 导入授权码
+					adminProducts.GET("/:id/digital/keys", handlers.ListLicenseKeys)               // 授权码列表
+					adminProducts.DELETE("/:id/digital/keys/:keyId", handlers.DeleteLicenseKey)    // 删除未分配的授权码
+
+					adminProducts.POST("/import", handlers.ImportProducts)   // 批量导入，后台执行
+					adminProducts.GET("/imports", handlers.ListImportJobs)   // 导入任务列表
+					adminProducts.GET("/imports/:id", handlers.GetImportJob) // 导入进度和报告
+					adminProducts.GET("/export", handlers.ExportProducts)    // 全量导出
+
+					adminProducts.POST("/:id/schedules", handlers.CreateProductSchedule)               // 创建定时上下架或改价
+					adminProducts.GET("/:id/schedules", handlers.ListProductSchedules)                 // 定时任务列表
+					adminProducts.DELETE("/:id/schedules/:scheduleId", handlers.CancelProductSchedule) // 取消定时任务
+					adminProducts.GET("/:id/price-history", handlers.ListPriceHistory)                 // 价格变动历史
+
+					adminProducts.GET("/:id/related", handlers.GetProductRelations)                 // 推荐配置和计算结果
+					adminProducts.PUT("/:id/related/:relatedId", handlers.SaveProductRelation)      // 置顶或屏蔽关联商品
+					adminProducts.DELETE("/:id/related/:relatedId", handlers.DeleteProductRelation) // 取消置顶或屏蔽
+
+					adminProducts.GET("/:id/views", handlers.GetProductViewReport) // 浏览量和转化漏斗
+					adminProducts.GET("/:id/slugs", handlers.ListProductSlugs)     // slug 历史
+
+					adminProducts.GET("/:id/translations", handlers.GetProductTranslations)               // 各语言的翻译
+					adminProducts.PUT("/:id/translations/:locale", handlers.SaveProductTranslations)      // 保存某个语言的翻译
+					adminProducts.DELETE("/:id/translations/:locale", handlers.DeleteProductTranslations) // 删除某个语言的翻译
+				}
+
+				// 类目管理
+				categories := admin.Group("/categories")
+				{
+					categories.GET("", handlers.AdminGetCategoryTree)
+					categories.POST("", handlers.CreateCategory)
+					categories.PUT("/reorder", handlers.ReorderCategories) // 同级重排序
+					categories.PUT("/:id", handlers.UpdateCategory)
+					categories.DELETE("/:id", handlers.DeleteCategory)
+					categories.PUT("/:id/move", handlers.MoveCategory) // 移动类目
+
+					categories.GET("/:id/attributes", handlers.ListCategoryAttributes)   // 类目规格属性
+					categories.POST("/:id/attributes", handlers.CreateCategoryAttribute) // 定义规格属性
+
+					categories.GET("/:id/translations", handlers.GetCategoryTranslations)               // 各语言的翻译
+					categories.PUT("/:id/translations/:locale", handlers.SaveCategoryTranslations)      // 保存某个语言的翻译
+					categories.DELETE("/:id/translations/:locale", handlers.DeleteCategoryTranslations) // 删除某个语言的翻译
+				}
+
+				// 规格属性管理
+				admin.PUT("/attributes/:id", handlers.UpdateAttribute)
+				admin.DELETE("/attributes/:id", handlers.DeleteAttribute)
+
+				// 搜索运营
+				admin.GET("/search/terms", handlers.ListSearchTerms)
+				admin.POST("/search/terms", handlers.SaveSearchTerm) // 置顶或屏蔽搜索词
+				admin.DELETE("/search/terms/:id", handlers.DeleteSearchTerm)
+				admin.GET("/search/zero-results", handlers.ListZeroResultSearches) // 无结果搜索报表
+
+				// 文件管理
+				admin.POST("/media/cleanup", handlers.CleanupOrphanMedia) // 清理未被引用的文件
+
+				// 商品推荐
+				admin.POST("/recommendations/recompute", handlers.RecomputeRecommendations) // 立即重新计算一起购买关系
+
+				// 库存管理
+				admin.GET("/inventory/audit", handlers.StockAudit)           // 库存对账报表
+				admin.GET("/inventory/at-risk", handlers.ListAtRiskProducts) // 缺货风险商品
+
+				// 浏览统计
+				admin.GET("/analytics/views", handlers.ListTopViewedProducts)           // 商品浏览排行
+				admin.GET("/analytics/funnel", handlers.GetConversionFunnel)            // 浏览→加购→下单转化漏斗
+				admin.GET("/analytics/bundle-revenue", handlers.GetBundleRevenueReport) // 套装收入按组件分摊
+
+				// 站内通知
+				notifications := admin.Group("/notifications")
+				{
+					notifications.GET("", handlers.ListNotifications)
+					notifications.PUT("/read-all", handlers.MarkAllNotificationsRead)
+					notifications.PUT("/:id/read", handlers.MarkNotificationRead)
+				}
+
+				// 仓库管理
+				warehouses := admin.Group("/warehouses")
+				{
+					warehouses.POST("", handlers.CreateWarehouse)
+					warehouses.GET("", handlers.ListWarehouses)
+					warehouses.POST("/transfer", handlers.TransferWarehouseStock) // 仓库间调拨
+					warehouses.GET("/:id", handlers.GetWarehouse)
+					warehouses.PUT("/:id", handlers.UpdateWarehouse)
+					warehouses.DELETE("/:id", handlers.DeleteWarehouse)
+					warehouses.GET("/:id/stocks", handlers.ListWarehouseStocks) // 查看仓库库存
 				}
 
 				// 订单管理
@@ -132,6 +279,7 @@ func RegisterRoutes(r *gin.Engine, sf *service.ServiceFactory, db *gorm.DB) {
 					adminOrders.PUT("/:id/status", handlers.UpdateOrderStatus)           // 更新订单状态
 					adminOrders.POST("/:id/logistics", handlers.UpdateLogistics)         // 更新物流信息
 					adminOrders.POST("/:id/logistics/trace", handlers.AddLogisticsTrace) // 添加物流跟踪记录
+					adminOrders.GET("/:id/packing-slips", handlers.GetPackingSlips)      // 按发货仓获取装箱单
 				}
 
 				// 商品管理
@@ -144,6 +292,18 @@ func RegisterRoutes(r *gin.Engine, sf *service.ServiceFactory, db *gorm.DB) {
 					advertisements.PUT("/:id", handlers.UpdateAdvertisement)
 					advertisements.DELETE("/:id", handlers.DeleteAdvertisement)
 					advertisements.PUT("/:id/status", handlers.UpdateAdvertisementStatus)
+
+					advertisements.GET("/:id/translations", handlers.GetAdvertisementTranslations)               // 各语言的翻译
+					advertisements.PUT("/:id/translations/:locale", handlers.SaveAdvertisementTranslations)      // 保存某个语言的翻译
+					advertisements.DELETE("/:id/translations/:locale", handlers.DeleteAdvertisementTranslations) // 删除某个语言的翻译
+				}
+
+				// 币种和汇率管理
+				currencies := admin.Group("/currencies")
+				{
+					currencies.GET("", handlers.AdminListCurrencies)
+					currencies.POST("", handlers.CreateCurrency)
+					currencies.PUT("/:code", handlers.UpdateCurrency)
 				}
 
 				// 评论管理
@@ -152,6 +312,14 @@ func RegisterRoutes(r *gin.Engine, sf *service.ServiceFactory, db *gorm.DB) {
 					reviews.GET("/products/:id", handlers.AdminReviewsListOfProduct) // 管理员查看商品评论
 					reviews.DELETE("/reviews/:id", handlers.AdminDeleteReview)       // 管理员删除评论
 				}
+
+				// 商品问答审核
+				admin.GET("/questions", handlers.AdminListQuestions)               // 问题审核队列
+				admin.PUT("/questions/:id/status", handlers.AdminModerateQuestion) // 审核问题
+				admin.DELETE("/questions/:id", handlers.AdminDeleteQuestion)       // 删除问题及其回答
+				admin.GET("/answers", handlers.AdminListAnswers)                   // 回答审核队列
+				admin.PUT("/answers/:id/status", handlers.AdminModerateAnswer)     // 审核回答
+				admin.DELETE("/answers/:id", handlers.AdminDeleteAnswer)           // 删除回答
 			}
 		}
 	}
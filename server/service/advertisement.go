@@ -40,12 +40,21 @@ func (s *AdvertisementService) GetAd(id uint) (*models.Advertisement, error) {
 }
 
 // UpdateAd 更新广告信息
+// ad.Version 为客户端持有的版本号，为 0 时以当前版本为准
 func (s *AdvertisementService) UpdateAd(ad *models.Advertisement) error {
 	// 验证时间
 	if ad.StartTime.After(ad.EndTime) {
 		return errors.New("start time cannot be after end time")
 	}
 
+	existing, err := s.GetAd(ad.ID)
+	if err != nil {
+		return errors.New("advertisement not found")
+	}
+	if ad.Version == 0 {
+		ad.Version = existing.Version
+	}
+
 	return s.repoFactory.GetAdvertisementRepository().Update(ad)
 }
 
@@ -69,8 +78,8 @@ func (s *AdvertisementService) ListActiveAds() ([]models.Advertisement, error) {
 	return s.repoFactory.GetAdvertisementRepository().ListActive()
 }
 
-// UpdateAdStatus 更新广告状态
-func (s *AdvertisementService) UpdateAdStatus(id uint, status string) error {
+// UpdateAdStatus 更新广告状态，version 为 0 时不校验版本号
+func (s *AdvertisementService) UpdateAdStatus(id uint, status string, version uint) error {
 	// 验证状态值
 	validStatuses := map[string]bool{
 		"active":   true,
@@ -80,5 +89,5 @@ func (s *AdvertisementService) UpdateAdStatus(id uint, status string) error {
 		return errors.New("invalid status value")
 	}
 
-	return s.repoFactory.GetAdvertisementRepository().UpdateStatus(id, status)
+	return s.repoFactory.GetAdvertisementRepository().UpdateStatus(id, status, version)
 }
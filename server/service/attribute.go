@@ -0,0 +1,533 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"shopify/models"
+	"shopify/repository"
+)
+
+// AttributeFilter 规格属性筛选条件
+type AttributeFilter = repository.AttributeFilter
+
+// ErrInvalidAttributeFilter 规格属性筛选条件无效
+var ErrInvalidAttributeFilter = errors.New("invalid attribute filter")
+
+// attributeCodePattern 属性编码只允许小写字母、数字和下划线，用于 attr[code] 查询参数
+var attributeCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+type AttributeService struct {
+	*Service
+}
+
+func NewAttributeService(base *Service) *AttributeService {
+	return &AttributeService{Service: base}
+}
+
+// ProductSpec 产品规格参数表中的一行
+type ProductSpec struct {
+	Code    string `json:"code"`    // 属性编码
+	Name    string `json:"name"`    // 属性名称
+	Type    string `json:"type"`    // 属性类型
+	Value   string `json:"value"`   // 属性值
+	Unit    string `json:"unit"`    // 单位
+	Display string `json:"display"` // 带单位的展示文本
+}
+
+// AttributeFacetValue 属性分面中的一个取值
+type AttributeFacetValue struct {
+	Value    string `json:"value"`    // 属性值
+	Count    int64  `json:"count"`    // 产品数量
+	Selected bool   `json:"selected"` // 是否已选中
+}
+
+// AttributeFacet 属性分面统计
+type AttributeFacet struct {
+	Code   string                `json:"code"`   // 属性编码
+	Name   string                `json:"name"`   // 属性名称
+	Type   string                `json:"type"`   // 属性类型
+	Unit   string                `json:"unit"`   // 单位
+	Values []AttributeFacetValue `json:"values"` // 各取值的产品数量
+}
+
+// categoryPathIDs 从类目路径中解析出祖先链上的类目ID，包含自身
+func categoryPathIDs(path string) []uint {
+	ids := make([]uint, 0)
+	for _, part := range strings.Split(strings.Trim(path, "/"), "/") {
+		if id, err := strconv.ParseUint(part, 10, 32); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
+
+// categoryAttributes 获取类目可用的属性定义，包含从祖先类目继承的属性，祖先的属性排在前面
+func categoryAttributes(repoFactory *repository.RepositoryFactory, categoryID uint) ([]models.AttributeDefinition, error) {
+	category, err := repoFactory.GetCategoryRepository().GetByID(categoryID)
+	if err != nil {
+		return nil, errors.New("category not found")
+	}
+	ids := categoryPathIDs(category.Path)
+	definitions, err := repoFactory.GetAttributeRepository().ListDefinitionsByCategories(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	level := make(map[uint]int, len(ids))
+	for i, id := range ids {
+		level[id] = i
+	}
+	sort.SliceStable(definitions, func(i, j int) bool {
+		return level[definitions[i].CategoryID] < level[definitions[j].CategoryID]
+	})
+	return definitions, nil
+}
+
+// parseAttributeNumber 解析数值，允许带上属性单位，如 16GB
+func parseAttributeNumber(definition *models.AttributeDefinition, raw string) (float64, error) {
+	raw = strings.TrimSpace(raw)
+	if unit := strings.ToLower(definition.Unit); unit != "" && strings.HasSuffix(strings.ToLower(raw), unit) {
+		raw = strings.TrimSpace(raw[:len(raw)-len(unit)])
+	}
+	number, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number for attribute %s: %s", definition.Code, raw)
+	}
+	return number, nil
+}
+
+// normalizeAttributeValue 校验并规范化属性值，返回规范化后的值和数值类型的数值
+func normalizeAttributeValue(definition *models.AttributeDefinition, raw string) (string, *float64, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil, fmt.Errorf("empty value for attribute %s", definition.Code)
+	}
+
+	switch definition.Type {
+	case models.AttributeTypeNumber:
+		number, err := parseAttributeNumber(definition, raw)
+		if err != nil {
+			return "", nil, err
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), &number, nil
+	case models.AttributeTypeEnum:
+		for _, allowed := range definition.AllowedValues {
+			if strings.EqualFold(allowed, raw) {
+				return allowed, nil, nil
+			}
+		}
+		return "", nil, fmt.Errorf("value %s is not allowed for attribute %s", raw, definition.Code)
+	case models.AttributeTypeBoolean:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid boolean for attribute %s: %s", definition.Code, raw)
+		}
+		return strconv.FormatBool(value), nil, nil
+	default:
+		if len([]rune(raw)) > 100 {
+			return "", nil, fmt.Errorf("value too long for attribute %s", definition.Code)
+		}
+		return raw, nil, nil
+	}
+}
+
+// specDisplay 生成规格参数的展示文本
+func specDisplay(definition *models.AttributeDefinition, value string) string {
+	switch definition.Type {
+	case models.AttributeTypeBoolean:
+		if value == "true" {
+			return "是"
+		}
+		return "否"
+	case models.AttributeTypeNumber:
+		if definition.Unit != "" {
+			return value + " " + definition.Unit
+		}
+	}
+	return value
+}
+
+// validateAttributeDefinition 校验属性定义
+func validateAttributeDefinition(definition *models.AttributeDefinition) error {
+	definition.Name = strings.TrimSpace(definition.Name)
+	if definition.Name == "" {
+		return errors.New("attribute name is required")
+	}
+
+	switch definition.Type {
+	case models.AttributeTypeEnum:
+		seen := make(map[string]bool, len(definition.AllowedValues))
+		values := make([]string, 0, len(definition.AllowedValues))
+		for _, value := range definition.AllowedValues {
+			value = strings.TrimSpace(value)
+			if value == "" || seen[strings.ToLower(value)] {
+				continue
+			}
+			seen[strings.ToLower(value)] = true
+			values = append(values, value)
+		}
+		if len(values) == 0 {
+			return errors.New("enum attribute requires allowed values")
+		}
+		definition.AllowedValues = values
+	case models.AttributeTypeText, models.AttributeTypeNumber, models.AttributeTypeBoolean:
+		definition.AllowedValues = nil
+	default:
+		return errors.New("invalid attribute type")
+	}
+	return nil
+}
+
+// ListCategoryAttributes 获取类目可用的属性定义，包含继承自祖先类目的属性
+func (s *AttributeService) ListCategoryAttributes(categoryID uint) ([]models.AttributeDefinition, error) {
+	return categoryAttributes(s.repoFactory, categoryID)
+}
+
+// CreateAttribute 为类目创建属性定义
+// 编码在祖先和子孙类目中必须唯一，避免继承后同一产品出现两个同名属性
+func (s *AttributeService) CreateAttribute(definition *models.AttributeDefinition) error {
+	definition.Code = strings.ToLower(strings.TrimSpace(definition.Code))
+	if !attributeCodePattern.MatchString(definition.Code) {
+		return errors.New("invalid attribute code")
+	}
+	if err := validateAttributeDefinition(definition); err != nil {
+		return err
+	}
+
+	categoryRepo := s.repoFactory.GetCategoryRepository()
+	category, err := categoryRepo.GetByID(definition.CategoryID)
+	if err != nil {
+		return errors.New("category not found")
+	}
+	scope, err := categoryRepo.ListDescendantIDs(category.Path)
+	if err != nil {
+		return err
+	}
+	scope = append(scope, categoryPathIDs(category.Path)...)
+
+	attributeRepo := s.repoFactory.GetAttributeRepository()
+	count, err := attributeRepo.CountDefinitionsByCode(scope, definition.Code)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("attribute %s already exists in this category tree", definition.Code)
+	}
+
+	return attributeRepo.CreateDefinition(definition)
+}
+
+// UpdateAttribute 更新属性定义，已被产品使用时不允许修改类型或删除在用的可选值
+func (s *AttributeService) UpdateAttribute(definition *models.AttributeDefinition) error {
+	attributeRepo := s.repoFactory.GetAttributeRepository()
+	existing, err := attributeRepo.GetDefinition(definition.ID)
+	if err != nil {
+		return errors.New("attribute not found")
+	}
+	if err := validateAttributeDefinition(definition); err != nil {
+		return err
+	}
+
+	if definition.Type != existing.Type {
+		used, err := attributeRepo.CountValues(existing.ID, nil)
+		if err != nil {
+			return err
+		}
+		if used > 0 {
+			return errors.New("attribute type cannot be changed while products use it")
+		}
+	} else if definition.Type == models.AttributeTypeEnum {
+		allowed := make(map[string]bool, len(definition.AllowedValues))
+		for _, value := range definition.AllowedValues {
+			allowed[value] = true
+		}
+		removed := make([]string, 0)
+		for _, value := range existing.AllowedValues {
+			if !allowed[value] {
+				removed = append(removed, value)
+			}
+		}
+		if len(removed) > 0 {
+			used, err := attributeRepo.CountValues(existing.ID, removed)
+			if err != nil {
+				return err
+			}
+			if used > 0 {
+				return errors.New("removed allowed values are still used by products")
+			}
+		}
+	}
+
+	definition.CategoryID = existing.CategoryID
+	definition.Code = existing.Code
+	return attributeRepo.UpdateDefinition(definition)
+}
+
+// DeleteAttribute 删除属性定义及产品上的对应属性值
+func (s *AttributeService) DeleteAttribute(id uint) error {
+	attributeRepo := s.repoFactory.GetAttributeRepository()
+	if _, err := attributeRepo.GetDefinition(id); err != nil {
+		return errors.New("attribute not found")
+	}
+	return attributeRepo.DeleteDefinition(id)
+}
+
+// SaveProductAttributes 整体保存产品的属性值，键为属性编码，空值表示不填写
+func (s *AttributeService) SaveProductAttributes(productID uint, values map[string]string) ([]ProductSpec, error) {
+	product, err := s.repoFactory.GetProductRepository().GetByID(productID)
+	if err != nil {
+		return nil, errors.New("product not found")
+	}
+	if product.CategoryID == nil {
+		return nil, errors.New("product has no category")
+	}
+
+	definitions, err := categoryAttributes(s.repoFactory, *product.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	byCode := make(map[string]*models.AttributeDefinition, len(definitions))
+	for i := range definitions {
+		byCode[definitions[i].Code] = &definitions[i]
+	}
+
+	records := make([]models.ProductAttributeValue, 0, len(values))
+	for code, raw := range values {
+		definition, ok := byCode[code]
+		if !ok {
+			return nil, fmt.Errorf("unknown attribute: %s", code)
+		}
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		value, number, err := normalizeAttributeValue(definition, raw)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, models.ProductAttributeValue{
+			ProductID:    productID,
+			AttributeID:  definition.ID,
+			Code:         code,
+			Value:        value,
+			NumericValue: number,
+		})
+	}
+
+	if err := s.repoFactory.GetAttributeRepository().ReplaceProductValues(productID, records); err != nil {
+		return nil, err
+	}
+	return s.productSpecs(product)
+}
+
+// productSpecs 生成产品的规格参数表，按属性定义顺序排列，忽略已不属于当前类目的属性值
+func (s *Service) productSpecs(product *models.Product) ([]ProductSpec, error) {
+	specs := make([]ProductSpec, 0)
+	if product.CategoryID == nil {
+		return specs, nil
+	}
+	definitions, err := categoryAttributes(s.repoFactory, *product.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	values, err := s.repoFactory.GetAttributeRepository().ListValuesByProduct(product.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	byAttribute := make(map[uint]string, len(values))
+	for _, value := range values {
+		byAttribute[value.AttributeID] = value.Value
+	}
+	for i := range definitions {
+		definition := &definitions[i]
+		value, ok := byAttribute[definition.ID]
+		if !ok {
+			continue
+		}
+		specs = append(specs, ProductSpec{
+			Code:    definition.Code,
+			Name:    definition.Name,
+			Type:    definition.Type,
+			Value:   value,
+			Unit:    definition.Unit,
+			Display: specDisplay(definition, value),
+		})
+	}
+	return specs, nil
+}
+
+// pruneProductAttributes 产品更换类目后删除新类目下不存在的属性值
+func pruneProductAttributes(repoFactory *repository.RepositoryFactory, product *models.Product) error {
+	ids := make([]uint, 0)
+	if product.CategoryID != nil {
+		definitions, err := categoryAttributes(repoFactory, *product.CategoryID)
+		if err != nil {
+			return err
+		}
+		for _, definition := range definitions {
+			ids = append(ids, definition.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return repoFactory.GetAttributeRepository().ReplaceProductValues(product.ID, nil)
+	}
+	return repoFactory.GetAttributeRepository().DeleteProductValuesExcept(product.ID, ids)
+}
+
+// normalizeAttributeFilters 按属性定义规范化筛选值
+// 同一属性的多个值为 OR 关系；数值属性支持 min..max 区间，任一端可省略
+func (s *Service) normalizeAttributeFilters(query *ProductQuery) error {
+	if len(query.Attributes) == 0 {
+		return nil
+	}
+
+	codes := make([]string, 0, len(query.Attributes))
+	for _, filter := range query.Attributes {
+		codes = append(codes, filter.Code)
+	}
+	definitions, err := s.repoFactory.GetAttributeRepository().ListFilterableByCodes(codes)
+	if err != nil {
+		return err
+	}
+	byCode := make(map[string]*models.AttributeDefinition, len(definitions))
+	for i := range definitions {
+		if _, ok := byCode[definitions[i].Code]; !ok {
+			byCode[definitions[i].Code] = &definitions[i]
+		}
+	}
+
+	merged := make(map[string]*AttributeFilter)
+	filters := make([]AttributeFilter, 0, len(query.Attributes))
+	order := make([]string, 0, len(query.Attributes))
+	for _, filter := range query.Attributes {
+		definition, ok := byCode[filter.Code]
+		if !ok {
+			return fmt.Errorf("%w: unknown attribute %s", ErrInvalidAttributeFilter, filter.Code)
+		}
+		target, ok := merged[filter.Code]
+		if !ok {
+			target = &AttributeFilter{Code: filter.Code}
+			merged[filter.Code] = target
+			order = append(order, filter.Code)
+		}
+		for _, raw := range filter.Values {
+			if definition.Type == models.AttributeTypeNumber && strings.Contains(raw, "..") {
+				bounds := strings.SplitN(raw, "..", 2)
+				for i, bound := range bounds {
+					if strings.TrimSpace(bound) == "" {
+						continue
+					}
+					number, err := parseAttributeNumber(definition, bound)
+					if err != nil {
+						return fmt.Errorf("%w: %v", ErrInvalidAttributeFilter, err)
+					}
+					if i == 0 {
+						target.Min = &number
+					} else {
+						target.Max = &number
+					}
+				}
+				continue
+			}
+			value, _, err := normalizeAttributeValue(definition, raw)
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidAttributeFilter, err)
+			}
+			target.Values = append(target.Values, value)
+		}
+		if filter.Min != nil {
+			target.Min = filter.Min
+		}
+		if filter.Max != nil {
+			target.Max = filter.Max
+		}
+	}
+
+	for _, code := range order {
+		filter := merged[code]
+		if len(filter.Values) > 0 && (filter.Min != nil || filter.Max != nil) {
+			return fmt.Errorf("%w: attribute %s cannot combine values and range", ErrInvalidAttributeFilter, code)
+		}
+		if len(filter.Values) == 0 && filter.Min == nil && filter.Max == nil {
+			continue
+		}
+		filters = append(filters, *filter)
+	}
+	query.Attributes = filters
+	return nil
+}
+
+// AttributeFacets 统计满足查询条件的产品在各可筛选属性上的取值分布
+func (s *ProductService) AttributeFacets(categoryID uint, query ProductQuery) ([]AttributeFacet, error) {
+	if err := validateProductQuery(&query); err != nil {
+		return nil, err
+	}
+	if err := s.applyCategoryFilter(categoryID, &query); err != nil {
+		return nil, err
+	}
+	if err := s.normalizeAttributeFilters(&query); err != nil {
+		return nil, err
+	}
+
+	counts, err := s.repoFactory.GetAttributeRepository().CountFacets(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(counts) == 0 {
+		return []AttributeFacet{}, nil
+	}
+
+	selected := make(map[string]bool)
+	for _, filter := range query.Attributes {
+		for _, value := range filter.Values {
+			selected[filter.Code+"\x00"+value] = true
+		}
+	}
+
+	codes := make([]string, 0)
+	grouped := make(map[string][]AttributeFacetValue)
+	for _, count := range counts {
+		if _, ok := grouped[count.Code]; !ok {
+			codes = append(codes, count.Code)
+		}
+		grouped[count.Code] = append(grouped[count.Code], AttributeFacetValue{
+			Value:    count.Value,
+			Count:    count.Count,
+			Selected: selected[count.Code+"\x00"+count.Value],
+		})
+	}
+
+	definitions, err := s.repoFactory.GetAttributeRepository().ListFilterableByCodes(codes)
+	if err != nil {
+		return nil, err
+	}
+	facets := make([]AttributeFacet, 0, len(grouped))
+	for _, definition := range definitions {
+		values, ok := grouped[definition.Code]
+		if !ok {
+			continue
+		}
+		// 同一编码在多个类目下定义时只输出一次
+		delete(grouped, definition.Code)
+		if definition.Type == models.AttributeTypeNumber {
+			sort.SliceStable(values, func(i, j int) bool {
+				a, _ := strconv.ParseFloat(values[i].Value, 64)
+				b, _ := strconv.ParseFloat(values[j].Value, 64)
+				return a < b
+			})
+		}
+		facets = append(facets, AttributeFacet{
+			Code:   definition.Code,
+			Name:   definition.Name,
+			Type:   definition.Type,
+			Unit:   definition.Unit,
+			Values: values,
+		})
+	}
+	return facets, nil
+}
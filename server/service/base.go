@@ -1,15 +1,60 @@
 package service
 
 import (
+	"shopify/pkg/search"
+	"shopify/pkg/storage"
 	"shopify/repository"
 )
 
+// ErrVersionConflict 乐观锁版本冲突
+var ErrVersionConflict = repository.ErrVersionConflict
+
+// ErrInvalidCursor 分页游标无效
+var ErrInvalidCursor = repository.ErrInvalidCursor
+
+// CursorRequest 游标分页请求
+type CursorRequest = repository.CursorRequest
+
+// CursorPage 游标分页结果
+type CursorPage = repository.CursorPage
+
 type Service struct {
-	repoFactory *repository.RepositoryFactory
+	repoFactory    *repository.RepositoryFactory
+	searchEngine   search.Engine
+	storage        storage.Storage
+	privateStorage storage.Storage
+	viewRecorder   *ViewRecorder
+	sitemap        *sitemapCache
+	feed           *feedCache
+	currency       *currencyCache
 }
 
 func NewService(repoFactory *repository.RepositoryFactory) *Service {
 	return &Service{
-		repoFactory: repoFactory,
+		repoFactory:  repoFactory,
+		searchEngine: search.NewMemoryIndex(),
+		sitemap:      &sitemapCache{},
+		feed:         &feedCache{},
+		currency:     &currencyCache{},
 	}
-} 
\ No newline at end of file
+}
+
+// SetSearchEngine 替换默认的进程内搜索引擎
+func (s *Service) SetSearchEngine(engine search.Engine) {
+	s.searchEngine = engine
+}
+
+// SetStorage 设置文件存储，未设置时上传接口不可用
+func (s *Service) SetStorage(store storage.Storage) {
+	s.storage = store
+}
+
+// SetPrivateStorage 设置数字商品文件的私有存储，未设置时不能上传和下载数字文件
+func (s *Service) SetPrivateStorage(store storage.Storage) {
+	s.privateStorage = store
+}
+
+// SetViewRecorder 设置浏览记录缓冲，未设置时浏览记录同步写入
+func (s *Service) SetViewRecorder(recorder *ViewRecorder) {
+	s.viewRecorder = recorder
+}
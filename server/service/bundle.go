@@ -0,0 +1,308 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"shopify/models"
+	"shopify/repository"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ErrBundleStock 套装库存由组件库存推算，不能直接调整
+var ErrBundleStock = errors.New("bundle stock is derived from its components, adjust component stock instead")
+
+// ComponentRevenue 组件商品通过套装售出的数量和分摊收入
+type ComponentRevenue = repository.ComponentRevenue
+
+// BundleComponentInput 套装组件参数
+type BundleComponentInput struct {
+	ProductID uint  // 组件商品ID
+	SKUID     *uint // 组件SKU ID，组件商品有规格时必填
+	Quantity  int   // 每套包含的数量
+}
+
+type BundleService struct {
+	*Service
+}
+
+func NewBundleService(base *Service) *BundleService {
+	return &BundleService{Service: base}
+}
+
+// componentStock 组件当前可用库存，组件商品或SKU已删除、下架时为 0
+func componentStock(component *models.BundleComponent) int {
+	if component.Component.ID == 0 || component.Component.Status != "active" {
+		return 0
+	}
+	if component.SKUID != nil {
+		if component.SKU == nil || component.SKU.ID == 0 || component.SKU.Status != "active" {
+			return 0
+		}
+		return component.SKU.Stock
+	}
+	return component.Component.Stock
+}
+
+// bundleStock 根据组件库存推算套装可售数量，取各组件可组成套数的最小值
+func bundleStock(components []models.BundleComponent) int {
+	if len(components) == 0 {
+		return 0
+	}
+	stock := -1
+	for i := range components {
+		if components[i].Quantity <= 0 {
+			return 0
+		}
+		sets := componentStock(&components[i]) / components[i].Quantity
+		if stock < 0 || sets < stock {
+			stock = sets
+		}
+	}
+	if stock < 0 {
+		return 0
+	}
+	return stock
+}
+
+// refreshBundleStock 重新计算套装的库存
+func refreshBundleStock(repoFactory *repository.RepositoryFactory, bundleID uint) error {
+	components, err := repoFactory.GetBundleRepository().ListComponents(bundleID)
+	if err != nil {
+		return err
+	}
+	return repoFactory.GetProductRepository().SetStock(bundleID, bundleStock(components))
+}
+
+// syncBundleStock 组件库存变化后重新计算包含该组件的套装库存，调用方负责开启事务
+func syncBundleStock(repoFactory *repository.RepositoryFactory, productIDs ...uint) error {
+	bundleIDs, err := repoFactory.GetBundleRepository().ListBundleIDsByComponents(productIDs)
+	if err != nil {
+		return err
+	}
+	for _, bundleID := range bundleIDs {
+		if err := refreshBundleStock(repoFactory, bundleID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// allocateBundleRevenue 将套装订单项的收入按组件单独售价占比分摊到各组件
+// 按分取整，尾差按最大余数法分配，保证分摊合计等于订单项金额；组件均无售价时按数量分摊
+func allocateBundleRevenue(total decimal.Decimal, components []models.OrderItemComponent) {
+	if len(components) == 0 {
+		return
+	}
+	weights := make([]decimal.Decimal, len(components))
+	sum := decimal.Zero
+	for i, component := range components {
+		weights[i] = component.UnitPrice.Mul(decimal.NewFromInt(int64(component.Quantity)))
+		sum = sum.Add(weights[i])
+	}
+	if !sum.IsPositive() {
+		sum = decimal.Zero
+		for i, component := range components {
+			weights[i] = decimal.NewFromInt(int64(component.Quantity))
+			sum = sum.Add(weights[i])
+		}
+	}
+
+	cents := total.Shift(2).Round(0).IntPart()
+	remainders := make([]decimal.Decimal, len(components))
+	allocated := int64(0)
+	shares := make([]int64, len(components))
+	for i := range components {
+		exact := total.Shift(2).Mul(weights[i]).Div(sum)
+		shares[i] = exact.Floor().IntPart()
+		remainders[i] = exact.Sub(decimal.NewFromInt(shares[i]))
+		allocated += shares[i]
+	}
+	for left := cents - allocated; left > 0; left-- {
+		best := 0
+		for i := range remainders {
+			if remainders[i].GreaterThan(remainders[best]) {
+				best = i
+			}
+		}
+		shares[best]++
+		remainders[best] = decimal.NewFromInt(-1)
+	}
+	for i := range components {
+		components[i].AllocatedAmount = decimal.New(shares[i], -2)
+	}
+}
+
+// bundleOrderComponents 为套装订单项生成组件明细并分摊收入
+func bundleOrderComponents(components []models.BundleComponent, item *models.OrderItem) []models.OrderItemComponent {
+	result := make([]models.OrderItemComponent, 0, len(components))
+	for _, component := range components {
+		price := component.Component.Price
+		if component.SKU != nil {
+			price = component.SKU.Price
+		}
+		result = append(result, models.OrderItemComponent{
+			OrderID:     item.OrderID,
+			OrderItemID: item.ID,
+			ProductID:   component.ComponentID,
+			Product:     component.Component,
+			SKUID:       component.SKUID,
+			Quantity:    component.Quantity * item.Quantity,
+			UnitPrice:   price,
+		})
+	}
+	allocateBundleRevenue(item.Price.Mul(decimal.NewFromInt(int64(item.Quantity))), result)
+	return result
+}
+
+// GetComponents 获取套装的组件
+func (s *BundleService) GetComponents(bundleID uint) ([]models.BundleComponent, error) {
+	product, err := s.repoFactory.GetProductRepository().GetByID(bundleID)
+	if err != nil {
+		return nil, errors.New("product not found")
+	}
+	if product.Type != models.ProductTypeBundle {
+		return nil, errors.New("product is not a bundle")
+	}
+	return s.repoFactory.GetBundleRepository().ListComponents(bundleID)
+}
+
+// SaveComponents 保存套装组件，普通商品保存组件后转为套装
+// 组件必须是普通商品，有规格的组件需指定SKU；转为套装前商品自身的库存需先调整为零
+func (s *BundleService) SaveComponents(bundleID uint, inputs []BundleComponentInput) (*ProductDetail, error) {
+	if len(inputs) == 0 {
+		return nil, errors.New("at least one component is required")
+	}
+
+	err := s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepoFactory := repository.NewRepositoryFactory(tx)
+		productRepo := txRepoFactory.GetProductRepository()
+		bundleRepo := txRepoFactory.GetBundleRepository()
+
+		bundle, err := productRepo.GetByID(bundleID)
+		if err != nil {
+			return errors.New("product not found")
+		}
+		if bundle.Type != models.ProductTypeBundle {
+			if bundle.Type != "" && bundle.Type != models.ProductTypeStandard {
+				return fmt.Errorf("%s product cannot be a bundle", bundle.Type)
+			}
+			if bundle.Stock != 0 {
+				return errors.New("product stock must be adjusted to zero before converting to a bundle")
+			}
+			count, err := txRepoFactory.GetSKURepository().CountByProduct(bundleID)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return errors.New("product with skus cannot be a bundle")
+			}
+			isComponent, err := bundleRepo.IsComponent(bundleID)
+			if err != nil {
+				return err
+			}
+			if isComponent {
+				return errors.New("product is a component of another bundle")
+			}
+		}
+
+		components := make([]models.BundleComponent, 0, len(inputs))
+		seen := make(map[string]bool)
+		for i, input := range inputs {
+			if input.Quantity <= 0 {
+				return errors.New("component quantity must be positive")
+			}
+			if input.ProductID == bundleID {
+				return errors.New("bundle cannot contain itself")
+			}
+			component, err := productRepo.GetByID(input.ProductID)
+			if err != nil {
+				return fmt.Errorf("component product %d not found", input.ProductID)
+			}
+			if component.Type == models.ProductTypeBundle {
+				return fmt.Errorf("bundle %s cannot be a component", component.Name)
+			}
+			if component.Type != "" && component.Type != models.ProductTypeStandard {
+				return fmt.Errorf("%s product %s cannot be a component", component.Type, component.Name)
+			}
+			sku, err := resolveSKU(txRepoFactory, component, input.SKUID)
+			if err != nil {
+				return err
+			}
+			var skuID *uint
+			if sku != nil {
+				skuID = &sku.ID
+			}
+
+			key := fmt.Sprintf("%d-%d", component.ID, 0)
+			if skuID != nil {
+				key = fmt.Sprintf("%d-%d", component.ID, *skuID)
+			}
+			if seen[key] {
+				return fmt.Errorf("duplicate component: %s", component.Name)
+			}
+			seen[key] = true
+
+			components = append(components, models.BundleComponent{
+				ComponentID: component.ID,
+				SKUID:       skuID,
+				Quantity:    input.Quantity,
+				SortOrder:   i,
+			})
+		}
+
+		if err := bundleRepo.ReplaceComponents(bundleID, components); err != nil {
+			return err
+		}
+		if err := productRepo.SetType(bundleID, models.ProductTypeBundle); err != nil {
+			return err
+		}
+		return refreshBundleStock(txRepoFactory, bundleID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.syncSearchIndex(bundleID)
+	return NewProductService(s.Service).GetProductDetail(bundleID)
+}
+
+// RemoveComponents 删除套装的全部组件，商品恢复为库存为零的普通商品
+func (s *BundleService) RemoveComponents(bundleID uint) error {
+	err := s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepoFactory := repository.NewRepositoryFactory(tx)
+		productRepo := txRepoFactory.GetProductRepository()
+
+		bundle, err := productRepo.GetByID(bundleID)
+		if err != nil {
+			return errors.New("product not found")
+		}
+		if bundle.Type != models.ProductTypeBundle {
+			return errors.New("product is not a bundle")
+		}
+		if err := txRepoFactory.GetBundleRepository().DeleteComponents(bundleID); err != nil {
+			return err
+		}
+		if err := productRepo.SetType(bundleID, models.ProductTypeStandard); err != nil {
+			return err
+		}
+		return productRepo.SetStock(bundleID, 0)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.syncSearchIndex(bundleID)
+	return nil
+}
+
+// ComponentRevenue 统计最近若干天内各组件通过套装售出的数量和分摊收入
+func (s *BundleService) ComponentRevenue(days int) ([]ComponentRevenue, error) {
+	since, err := viewReportSince(days)
+	if err != nil {
+		return nil, err
+	}
+	return s.repoFactory.GetBundleRepository().SumComponentRevenue(since)
+}
@@ -14,24 +14,36 @@ func NewCartService(base *Service) *CartService {
 }
 
 // AddItem 添加商品到购物车
-func (s *CartService) AddItem(userID, productID uint, quantity int) error {
+func (s *CartService) AddItem(userID, productID uint, skuID *uint, quantity int) error {
     // 检查商品是否存在
     product, err := s.repoFactory.GetProductRepository().GetByID(productID)
     if err != nil {
         return errors.New("product not found")
     }
 
+    // 有规格的商品按SKU校验库存
+    sku, err := resolveSKU(s.repoFactory, product, skuID)
+    if err != nil {
+        return err
+    }
+    stock := product.Stock
+    if sku != nil {
+        stock = sku.Stock
+    } else {
+        skuID = nil
+    }
+
     // 检查库存
-    if product.Stock < quantity {
+    if stock < quantity {
         return errors.New("insufficient stock")
     }
 
     // 检查购物车是否已有该商品
-    existingItem, err := s.repoFactory.GetCartRepository().GetCartItem(userID, productID)
+    existingItem, err := s.repoFactory.GetCartRepository().GetCartItem(userID, productID, skuID)
     if err == nil {
         // 更新数量
         newQuantity := existingItem.Quantity + quantity
-        if product.Stock < newQuantity {
+        if stock < newQuantity {
             return errors.New("insufficient stock")
         }
         return s.repoFactory.GetCartRepository().UpdateQuantity(existingItem.ID, newQuantity)
@@ -41,6 +53,7 @@ func (s *CartService) AddItem(userID, productID uint, quantity int) error {
     cartItem := &models.CartItem{
         UserID:    userID,
         ProductID: productID,
+        SKUID:     skuID,
         Quantity:  quantity,
         Selected:  true,
     }
@@ -67,7 +80,16 @@ func (s *CartService) UpdateQuantity(userID, itemID uint, quantity int) error {
         return errors.New("product not found")
     }
 
-    if product.Stock < quantity {
+    stock := product.Stock
+    if item.SKUID != nil {
+        sku, err := s.repoFactory.GetSKURepository().GetByID(*item.SKUID)
+        if err != nil {
+            return errors.New("sku not found")
+        }
+        stock = sku.Stock
+    }
+
+    if stock < quantity {
         return errors.New("insufficient stock")
     }
 
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"shopify/models"
+	"shopify/repository"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type CategoryService struct {
+	*Service
+}
+
+func NewCategoryService(base *Service) *CategoryService {
+	return &CategoryService{Service: base}
+}
+
+// GetTree 获取类目树，activeOnly 为 true 时不返回停用的类目及其子树
+func (s *CategoryService) GetTree(activeOnly bool) ([]*models.Category, error) {
+	categories, err := s.repoFactory.GetCategoryRepository().ListAll(activeOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	// 按层级升序返回，父类目总是先于子类目出现
+	nodes := make(map[uint]*models.Category, len(categories))
+	roots := make([]*models.Category, 0)
+	for i := range categories {
+		node := &categories[i]
+		nodes[node.ID] = node
+		if node.ParentID == nil {
+			roots = append(roots, node)
+			continue
+		}
+		// 父类目被停用时整棵子树不可见
+		if parent, ok := nodes[*node.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+	return roots, nil
+}
+
+// GetCategory 获取类目详情
+func (s *CategoryService) GetCategory(id uint) (*models.Category, error) {
+	return s.repoFactory.GetCategoryRepository().GetByID(id)
+}
+
+// checkSiblingName 校验同级类目名称不重复
+func checkSiblingName(categoryRepo *repository.CategoryRepository, parentID *uint, name string, selfID uint) error {
+	existing, err := categoryRepo.GetByName(parentID, name)
+	if err == nil && existing.ID != selfID {
+		return fmt.Errorf("category %s already exists under the same parent", name)
+	}
+	return nil
+}
+
+// CreateCategory 创建类目
+func (s *CategoryService) CreateCategory(category *models.Category) error {
+	category.Name = strings.TrimSpace(category.Name)
+	category.GoogleCategory = strings.TrimSpace(category.GoogleCategory)
+	if category.Name == "" {
+		return errors.New("category name is required")
+	}
+
+	err := s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		categoryRepo := repository.NewRepositoryFactory(tx).GetCategoryRepository()
+
+		parentPath := ""
+		if category.ParentID != nil {
+			parent, err := categoryRepo.GetByID(*category.ParentID)
+			if err != nil {
+				return errors.New("parent category not found")
+			}
+			parentPath = parent.Path
+			category.Level = parent.Level + 1
+		}
+		if err := checkSiblingName(categoryRepo, category.ParentID, category.Name, 0); err != nil {
+			return err
+		}
+
+		return categoryRepo.Create(category, parentPath)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidateSitemap()
+	s.invalidateFeed()
+	return nil
+}
+
+// UpdateCategory 更新类目名称、图标、排序和启用状态
+func (s *CategoryService) UpdateCategory(category *models.Category) error {
+	category.Name = strings.TrimSpace(category.Name)
+	category.GoogleCategory = strings.TrimSpace(category.GoogleCategory)
+	if category.Name == "" {
+		return errors.New("category name is required")
+	}
+
+	err := s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		categoryRepo := repository.NewRepositoryFactory(tx).GetCategoryRepository()
+
+		existing, err := categoryRepo.GetByID(category.ID)
+		if err != nil {
+			return errors.New("category not found")
+		}
+		if err := checkSiblingName(categoryRepo, existing.ParentID, category.Name, existing.ID); err != nil {
+			return err
+		}
+		if err := categoryRepo.Update(category); err != nil {
+			return err
+		}
+		if existing.Name != category.Name {
+			return categoryRepo.SyncProductCategoryName(category.ID, category.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidateSitemap()
+	s.invalidateFeed()
+	return nil
+}
+
+// DeleteCategory 删除类目，存在子类目或关联产品时不允许删除
+func (s *CategoryService) DeleteCategory(id uint) error {
+	categoryRepo := s.repoFactory.GetCategoryRepository()
+	if _, err := categoryRepo.GetByID(id); err != nil {
+		return errors.New("category not found")
+	}
+
+	children, err := categoryRepo.CountChildren(id)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return errors.New("category has subcategories, move or delete them first")
+	}
+
+	products, err := categoryRepo.CountProducts([]uint{id})
+	if err != nil {
+		return err
+	}
+	if products > 0 {
+		return errors.New("category still has products")
+	}
+
+	if err := s.repoFactory.GetAttributeRepository().DeleteDefinitionsByCategory(id); err != nil {
+		return err
+	}
+	if err := categoryRepo.Delete(id); err != nil {
+		return err
+	}
+
+	s.invalidateSitemap()
+	s.invalidateFeed()
+	return nil
+}
+
+// MoveCategory 将类目移动到新的父类目下，parentID 为空表示移为顶级类目
+func (s *CategoryService) MoveCategory(id uint, parentID *uint, sortOrder int) error {
+	err := s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		categoryRepo := repository.NewRepositoryFactory(tx).GetCategoryRepository()
+
+		category, err := categoryRepo.GetByID(id)
+		if err != nil {
+			return errors.New("category not found")
+		}
+
+		newPath := fmt.Sprintf("/%d/", id)
+		newLevel := 0
+		if parentID != nil {
+			parent, err := categoryRepo.GetByID(*parentID)
+			if err != nil {
+				return errors.New("parent category not found")
+			}
+			// 不能移动到自身或自身的子孙类目下
+			if strings.HasPrefix(parent.Path, category.Path) {
+				return errors.New("cannot move a category under itself or its descendants")
+			}
+			newPath = fmt.Sprintf("%s%d/", parent.Path, id)
+			newLevel = parent.Level + 1
+		}
+		if err := checkSiblingName(categoryRepo, parentID, category.Name, id); err != nil {
+			return err
+		}
+
+		if newPath != category.Path {
+			if err := categoryRepo.Move(id, parentID, category.Path, newPath, newLevel-category.Level); err != nil {
+				return err
+			}
+		}
+		return categoryRepo.UpdateSortOrder(id, sortOrder)
+	})
+	if err != nil {
+		return err
+	}
+
+	// 上级类目变化会影响商品源中继承的 Google 商品类目和类目路径
+	s.invalidateFeed()
+	return nil
+}
+
+// ReorderCategories 按给定顺序重排同级类目
+func (s *CategoryService) ReorderCategories(parentID *uint, ids []uint) error {
+	return s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		categoryRepo := repository.NewRepositoryFactory(tx).GetCategoryRepository()
+
+		siblings, err := categoryRepo.ListChildren(parentID)
+		if err != nil {
+			return err
+		}
+		siblingIDs := make(map[uint]bool, len(siblings))
+		for _, sibling := range siblings {
+			siblingIDs[sibling.ID] = true
+		}
+
+		for i, id := range ids {
+			if !siblingIDs[id] {
+				return fmt.Errorf("category %d is not a child of the given parent", id)
+			}
+			if err := categoryRepo.UpdateSortOrder(id, i); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DescendantIDs 获取类目自身及所有子孙类目的ID
+func (s *CategoryService) DescendantIDs(id uint) ([]uint, error) {
+	category, err := s.repoFactory.GetCategoryRepository().GetByID(id)
+	if err != nil {
+		return nil, errors.New("category not found")
+	}
+	return s.repoFactory.GetCategoryRepository().ListDescendantIDs(category.Path)
+}
+
+// resolveCategory 校验产品关联的类目并同步类目名称
+func resolveCategory(repoFactory *repository.RepositoryFactory, product *models.Product) error {
+	if product.CategoryID == nil {
+		return nil
+	}
+	category, err := repoFactory.GetCategoryRepository().GetByID(*product.CategoryID)
+	if err != nil {
+		return errors.New("category not found")
+	}
+	if !category.Active {
+		return errors.New("category is inactive")
+	}
+	product.Category = category.Name
+	return nil
+}
+
+// MigrateLegacyCategories 将产品上的历史类目文本迁移为顶级类目并关联
+func (s *CategoryService) MigrateLegacyCategories() error {
+	names, err := s.repoFactory.GetCategoryRepository().ListLegacyCategoryNames()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		err := s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+			categoryRepo := repository.NewRepositoryFactory(tx).GetCategoryRepository()
+
+			category, err := categoryRepo.GetByName(nil, name)
+			if err != nil {
+				category = &models.Category{Name: name, Active: true}
+				if err := categoryRepo.Create(category, ""); err != nil {
+					return err
+				}
+			}
+			return categoryRepo.LinkLegacyProducts(name, category.ID)
+		})
+		if err != nil {
+			return err
+		}
+		log.Printf("迁移历史类目: %s", name)
+	}
+	return nil
+}
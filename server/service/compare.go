@@ -0,0 +1,395 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"shopify/config"
+	"shopify/models"
+	"shopify/repository"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// MaxCompareProducts 同时对比的商品数量上限
+const MaxCompareProducts = 4
+
+// ErrCompareListFull 对比列表已满
+var ErrCompareListFull = fmt.Errorf("at most %d products can be compared", MaxCompareProducts)
+
+// 对比矩阵的分组
+const (
+	CompareGroupBasic = "basic" // 价格、评分、销量等基础信息
+	CompareGroupSpec  = "spec"  // 类目规格属性
+)
+
+// 库存状态
+const (
+	StockStatusInStock    = "in_stock"
+	StockStatusLowStock   = "low_stock"
+	StockStatusOutOfStock = "out_of_stock"
+)
+
+// CompareValue 对比矩阵中的一个单元格
+type CompareValue struct {
+	Value   string `json:"value"`   // 归一化后的值，用于判断差异，缺失时为空
+	Display string `json:"display"` // 展示文本，缺失时为 "-"
+	Best    bool   `json:"best"`    // 是否为该行最优值，如最低价、最高评分
+}
+
+// CompareRow 对比矩阵的一行，Values 与商品顺序一致
+type CompareRow struct {
+	Key       string         `json:"key"`       // 行标识，规格属性为属性编码
+	Name      string         `json:"name"`      // 行名称
+	Group     string         `json:"group"`     // 分组：basic/spec
+	Different bool           `json:"different"` // 各商品的值是否不同
+	Values    []CompareValue `json:"values"`    // 各商品的值
+}
+
+// ProductComparison 商品对比结果
+type ProductComparison struct {
+	Products []models.Product `json:"products"` // 对比的商品，即矩阵的列
+	Rows     []CompareRow     `json:"rows"`     // 对比项，即矩阵的行
+}
+
+type CompareService struct {
+	*Service
+}
+
+func NewCompareService(base *Service) *CompareService {
+	return &CompareService{Service: base}
+}
+
+// stockStatus 将库存数量转换为库存状态，商品未设置阈值时使用默认低库存阈值
+func stockStatus(product *models.Product) string {
+	if product.Stock <= 0 {
+		return StockStatusOutOfStock
+	}
+	threshold := product.LowStockThreshold
+	if threshold <= 0 {
+		threshold = config.GlobalConfig.Inventory.LowStockThreshold
+	}
+	if product.Stock <= threshold {
+		return StockStatusLowStock
+	}
+	return StockStatusInStock
+}
+
+// productPriceRange 商品的价格区间，有规格时取启用SKU的最低价和最高价
+func productPriceRange(product *models.Product) (decimal.Decimal, decimal.Decimal) {
+	low, high := product.Price, product.Price
+	first := true
+	for _, sku := range product.SKUs {
+		if sku.Status != "active" {
+			continue
+		}
+		if first || sku.Price.LessThan(low) {
+			low = sku.Price
+		}
+		if first || sku.Price.GreaterThan(high) {
+			high = sku.Price
+		}
+		first = false
+	}
+	return low, high
+}
+
+// uniqueCompareIDs 去重并校验对比商品数量
+func uniqueCompareIDs(ids []uint) ([]uint, error) {
+	unique := make([]uint, 0, len(ids))
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	if len(unique) > MaxCompareProducts {
+		return nil, ErrCompareListFull
+	}
+	return unique, nil
+}
+
+// loadCompareProducts 按顺序加载对比的商品，strict 为 false 时跳过已删除或下架的商品
+func (s *CompareService) loadCompareProducts(ids []uint, strict bool) ([]models.Product, error) {
+	products := make([]models.Product, 0, len(ids))
+	for _, id := range ids {
+		product, err := s.repoFactory.GetProductRepository().GetWithSKUs(id)
+		if err == nil && product.Status != "active" {
+			err = gorm.ErrRecordNotFound
+		}
+		if err == gorm.ErrRecordNotFound {
+			if strict {
+				return nil, fmt.Errorf("product %d not found", id)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, *product)
+	}
+	return products, nil
+}
+
+// Compare 对比指定的商品，返回按行归一化的对比矩阵，价格按展示币种换算
+func (s *CompareService) Compare(ids []uint, currency models.Currency) (*ProductComparison, error) {
+	ids, err := uniqueCompareIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, errors.New("at least one product is required")
+	}
+	products, err := s.loadCompareProducts(ids, true)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildComparison(products, currency)
+}
+
+// CompareSaved 对比用户对比列表中的商品，已删除或下架的商品不参与对比
+func (s *CompareService) CompareSaved(userID uint, currency models.Currency) (*ProductComparison, error) {
+	ids, err := s.repoFactory.GetCompareRepository().ListProductIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	products, err := s.loadCompareProducts(ids, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildComparison(products, currency)
+}
+
+// buildComparison 生成对比矩阵
+func (s *CompareService) buildComparison(products []models.Product, currency models.Currency) (*ProductComparison, error) {
+	rows := make([]CompareRow, 0)
+	cells := func() []CompareValue { return make([]CompareValue, len(products)) }
+
+	// 价格：有规格的商品展示价格区间，按最低价比较
+	price := CompareRow{Key: "price", Name: "价格", Group: CompareGroupBasic, Values: cells()}
+	prices := make([]*decimal.Decimal, len(products))
+	for i := range products {
+		low, high := productPriceRange(&products[i])
+		low, high = convertAmount(low, currency), convertAmount(high, currency)
+		display := currency.Symbol + low.StringFixed(int32(currency.Decimals))
+		if !high.Equal(low) {
+			display += " - " + currency.Symbol + high.StringFixed(int32(currency.Decimals))
+		}
+		price.Values[i] = CompareValue{Value: low.String() + "-" + high.String(), Display: display}
+		prices[i] = &low
+	}
+	markBest(&price, prices, false)
+	rows = append(rows, price)
+
+	rating := CompareRow{Key: "rating", Name: "评分", Group: CompareGroupBasic, Values: cells()}
+	ratings := make([]*decimal.Decimal, len(products))
+	for i := range products {
+		value := decimal.NewFromFloat(products[i].Rating).Round(1)
+		rating.Values[i] = CompareValue{Value: value.StringFixed(1), Display: value.StringFixed(1)}
+		ratings[i] = &value
+	}
+	markBest(&rating, ratings, true)
+	rows = append(rows, rating)
+
+	sales := CompareRow{Key: "sales", Name: "销量", Group: CompareGroupBasic, Values: cells()}
+	salesScores := make([]*decimal.Decimal, len(products))
+	for i := range products {
+		value := decimal.NewFromInt(int64(products[i].Sales))
+		sales.Values[i] = CompareValue{Value: value.String(), Display: value.String()}
+		salesScores[i] = &value
+	}
+	markBest(&sales, salesScores, true)
+	rows = append(rows, sales)
+
+	stock := CompareRow{Key: "stock_status", Name: "库存状态", Group: CompareGroupBasic, Values: cells()}
+	for i := range products {
+		status := stockStatus(&products[i])
+		stock.Values[i] = CompareValue{Value: status, Display: status}
+	}
+	rows = append(rows, stock)
+
+	category := CompareRow{Key: "category", Name: "类目", Group: CompareGroupBasic, Values: cells()}
+	for i := range products {
+		category.Values[i] = compareText(products[i].Category, products[i].Category)
+	}
+	rows = append(rows, category)
+
+	// 标签按忽略大小写排序后比较，与顺序无关
+	tags := CompareRow{Key: "tags", Name: "标签", Group: CompareGroupBasic, Values: cells()}
+	for i := range products {
+		sorted := append([]string(nil), products[i].Tags...)
+		sort.Slice(sorted, func(a, b int) bool { return strings.ToLower(sorted[a]) < strings.ToLower(sorted[b]) })
+		tags.Values[i] = compareText(strings.ToLower(strings.Join(sorted, ",")), strings.Join(sorted, ", "))
+	}
+	rows = append(rows, tags)
+
+	// 规格属性取各商品属性的并集，按第一次出现的顺序排列，缺少该属性的商品显示 "-"
+	specRows := make(map[string]*CompareRow)
+	specOrder := make([]string, 0)
+	for i := range products {
+		specs, err := s.productSpecs(&products[i])
+		if err != nil {
+			return nil, err
+		}
+		for _, spec := range specs {
+			row, ok := specRows[spec.Code]
+			if !ok {
+				row = &CompareRow{Key: spec.Code, Name: spec.Name, Group: CompareGroupSpec, Values: cells()}
+				for j := range row.Values {
+					row.Values[j] = compareText("", "")
+				}
+				specRows[spec.Code] = row
+				specOrder = append(specOrder, spec.Code)
+			}
+			row.Values[i] = compareText(strings.ToLower(strings.TrimSpace(spec.Value)), spec.Display)
+		}
+	}
+	for _, code := range specOrder {
+		rows = append(rows, *specRows[code])
+	}
+
+	for i := range rows {
+		rows[i].Different = rowDiffers(rows[i].Values)
+	}
+
+	return &ProductComparison{Products: products, Rows: rows}, nil
+}
+
+// compareText 生成文本单元格，值为空时显示 "-"
+func compareText(value, display string) CompareValue {
+	if value == "" {
+		return CompareValue{Display: "-"}
+	}
+	return CompareValue{Value: value, Display: display}
+}
+
+// rowDiffers 判断一行中各商品的值是否不同
+func rowDiffers(values []CompareValue) bool {
+	for i := 1; i < len(values); i++ {
+		if values[i].Value != values[0].Value {
+			return true
+		}
+	}
+	return false
+}
+
+// markBest 在各商品取值不同时标记最优值，higher 为 true 表示越大越好，并列时都标记
+func markBest(row *CompareRow, scores []*decimal.Decimal, higher bool) {
+	var best *decimal.Decimal
+	distinct := false
+	for _, score := range scores {
+		if score == nil {
+			continue
+		}
+		if best == nil {
+			best = score
+			continue
+		}
+		if !score.Equal(*best) {
+			distinct = true
+		}
+		if (higher && score.GreaterThan(*best)) || (!higher && score.LessThan(*best)) {
+			best = score
+		}
+	}
+	if best == nil || !distinct {
+		return
+	}
+	for i, score := range scores {
+		if score != nil && score.Equal(*best) {
+			row.Values[i].Best = true
+		}
+	}
+}
+
+// ParseCompareIDs 解析以逗号分隔的商品ID
+func ParseCompareIDs(value string) ([]uint, error) {
+	ids := make([]uint, 0)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid product id: %s", part)
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
+
+// ListCompareIDs 获取用户对比列表中的商品ID
+func (s *CompareService) ListCompareIDs(userID uint) ([]uint, error) {
+	return s.repoFactory.GetCompareRepository().ListProductIDs(userID)
+}
+
+// AddToCompare 将商品加入对比列表，最多保存 MaxCompareProducts 个
+func (s *CompareService) AddToCompare(userID, productID uint) ([]uint, error) {
+	product, err := s.repoFactory.GetProductRepository().GetByID(productID)
+	if err != nil || product.Status != "active" {
+		return nil, errors.New("product not found")
+	}
+
+	var ids []uint
+	err = s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		compareRepo := repository.NewRepositoryFactory(tx).GetCompareRepository()
+		current, err := compareRepo.ListProductIDs(userID)
+		if err != nil {
+			return err
+		}
+		for _, id := range current {
+			if id == productID {
+				ids = current
+				return nil
+			}
+		}
+		if len(current) >= MaxCompareProducts {
+			return ErrCompareListFull
+		}
+		if err := compareRepo.Add(userID, productID); err != nil {
+			return err
+		}
+		ids = append(current, productID)
+		return nil
+	})
+	return ids, err
+}
+
+// RemoveFromCompare 从对比列表中移除商品
+func (s *CompareService) RemoveFromCompare(userID, productID uint) ([]uint, error) {
+	compareRepo := s.repoFactory.GetCompareRepository()
+	if err := compareRepo.Remove(userID, productID); err != nil {
+		return nil, err
+	}
+	return compareRepo.ListProductIDs(userID)
+}
+
+// ReplaceCompareList 用新的商品列表替换对比列表，用于同步其他设备上的对比列表
+func (s *CompareService) ReplaceCompareList(userID uint, productIDs []uint) ([]uint, error) {
+	ids, err := uniqueCompareIDs(productIDs)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.loadCompareProducts(ids, true); err != nil {
+		return nil, err
+	}
+	err = s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		return repository.NewRepositoryFactory(tx).GetCompareRepository().Replace(userID, ids)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ClearCompareList 清空对比列表
+func (s *CompareService) ClearCompareList(userID uint) error {
+	return s.repoFactory.GetCompareRepository().Clear(userID)
+}
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"shopify/config"
+	"shopify/models"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrUnsupportedCurrency 币种不存在或未启用
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	// ErrCurrencyNotFound 币种不存在
+	ErrCurrencyNotFound = errors.New("currency not found")
+	// ErrCurrencyExists 币种代码已存在
+	ErrCurrencyExists = errors.New("currency already exists")
+)
+
+// currencyCacheTTL 币种缓存有效期，管理员修改汇率后立即失效
+const currencyCacheTTL = time.Minute
+
+// currencyCodePattern ISO 4217 币种代码
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// currencyCache 缓存全部币种，避免每次换算价格都查询数据库
+type currencyCache struct {
+	mu       sync.RWMutex
+	loadedAt time.Time
+	byCode   map[string]models.Currency
+}
+
+// BaseCurrency 基准币种，商品价格和订单金额均以该币种保存
+func BaseCurrency() string {
+	return strings.ToUpper(config.GlobalConfig.Currency.Base)
+}
+
+// DefaultCurrency 请求未指定币种时的展示币种
+func DefaultCurrency() string {
+	if code := strings.ToUpper(config.GlobalConfig.Currency.Default); code != "" {
+		return code
+	}
+	return BaseCurrency()
+}
+
+// baseCurrency 基准币种的默认定义，数据库中没有记录时使用
+func baseCurrency() models.Currency {
+	return models.Currency{
+		Code:     BaseCurrency(),
+		Rate:     decimal.NewFromInt(1),
+		Decimals: 2,
+		Rounding: models.RoundingHalfUp,
+		Active:   true,
+	}
+}
+
+// validRounding 判断舍入方式是否受支持
+func validRounding(rounding string) bool {
+	switch rounding {
+	case models.RoundingHalfUp, models.RoundingHalfEven, models.RoundingCeil, models.RoundingFloor:
+		return true
+	}
+	return false
+}
+
+// roundAmount 按币种的小数位数和舍入方式舍入金额
+func roundAmount(amount decimal.Decimal, currency models.Currency) decimal.Decimal {
+	places := int32(currency.Decimals)
+	switch currency.Rounding {
+	case models.RoundingHalfEven:
+		return amount.RoundBank(places)
+	case models.RoundingCeil:
+		return amount.RoundCeil(places)
+	case models.RoundingFloor:
+		return amount.RoundFloor(places)
+	default:
+		return amount.Round(places)
+	}
+}
+
+// convertAmount 将基准币种金额换算为目标币种并舍入
+func convertAmount(amount decimal.Decimal, currency models.Currency) decimal.Decimal {
+	return roundAmount(amount.Mul(currency.Rate), currency)
+}
+
+// toBaseAmount 将目标币种金额换算回基准币种，用于价格筛选，不做舍入
+func toBaseAmount(amount decimal.Decimal, currency models.Currency) decimal.Decimal {
+	if amount.IsZero() || currency.Rate.IsZero() {
+		return amount
+	}
+	return amount.DivRound(currency.Rate, 8)
+}
+
+// newMoney 将基准币种金额换算为展示金额
+func newMoney(amount decimal.Decimal, currency models.Currency) *models.Money {
+	return &models.Money{
+		Amount:   convertAmount(amount, currency),
+		Currency: currency.Code,
+		Symbol:   currency.Symbol,
+	}
+}
+
+// currencies 获取全部币种，缓存过期时重新加载，加载失败时继续使用旧缓存
+func (s *Service) currencies() map[string]models.Currency {
+	s.currency.mu.RLock()
+	if s.currency.byCode != nil && time.Since(s.currency.loadedAt) < currencyCacheTTL {
+		byCode := s.currency.byCode
+		s.currency.mu.RUnlock()
+		return byCode
+	}
+	s.currency.mu.RUnlock()
+
+	s.currency.mu.Lock()
+	defer s.currency.mu.Unlock()
+	if s.currency.byCode != nil && time.Since(s.currency.loadedAt) < currencyCacheTTL {
+		return s.currency.byCode
+	}
+
+	list, err := s.repoFactory.GetCurrencyRepository().List(false)
+	if err != nil {
+		log.Printf("加载币种失败: %v", err)
+		if s.currency.byCode != nil {
+			return s.currency.byCode
+		}
+		list = nil
+	}
+	byCode := make(map[string]models.Currency, len(list)+1)
+	for _, currency := range list {
+		byCode[currency.Code] = currency
+	}
+	// 基准币种的汇率固定为 1
+	base, ok := byCode[BaseCurrency()]
+	if !ok {
+		base = baseCurrency()
+	}
+	base.Rate = decimal.NewFromInt(1)
+	base.Active = true
+	byCode[base.Code] = base
+
+	s.currency.byCode = byCode
+	s.currency.loadedAt = time.Now()
+	return byCode
+}
+
+// invalidateCurrencies 币种变化后清空缓存
+func (s *Service) invalidateCurrencies() {
+	s.currency.mu.Lock()
+	s.currency.byCode = nil
+	s.currency.mu.Unlock()
+}
+
+// lookupCurrency 获取启用的币种，code 为空时使用默认展示币种
+func (s *Service) lookupCurrency(code string) (models.Currency, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		code = DefaultCurrency()
+	}
+	currency, ok := s.currencies()[code]
+	if !ok || !currency.Active {
+		return models.Currency{}, ErrUnsupportedCurrency
+	}
+	return currency, nil
+}
+
+// displayCurrency 获取展示币种，不可用时依次回退到默认展示币种和基准币种
+func (s *Service) displayCurrency(code string) models.Currency {
+	if currency, err := s.lookupCurrency(code); err == nil {
+		return currency
+	}
+	if currency, err := s.lookupCurrency(""); err == nil {
+		return currency
+	}
+	return s.currencies()[BaseCurrency()]
+}
+
+// CurrencyRequest 创建或更新币种的参数
+type CurrencyRequest struct {
+	Code     string
+	Name     string
+	Symbol   string
+	Rate     decimal.Decimal
+	Decimals int
+	Rounding string
+	Active   bool
+}
+
+type CurrencyService struct {
+	*Service
+}
+
+func NewCurrencyService(base *Service) *CurrencyService {
+	return &CurrencyService{Service: base}
+}
+
+// EnsureBaseCurrency 确保基准币种存在且汇率为 1
+func (s *CurrencyService) EnsureBaseCurrency() error {
+	repo := s.repoFactory.GetCurrencyRepository()
+	currency, err := repo.GetByCode(BaseCurrency())
+	if err == gorm.ErrRecordNotFound {
+		base := baseCurrency()
+		return repo.Create(&base)
+	}
+	if err != nil {
+		return err
+	}
+	if currency.Rate.Equal(decimal.NewFromInt(1)) && currency.Active {
+		return nil
+	}
+	currency.Rate = decimal.NewFromInt(1)
+	currency.Active = true
+	return repo.Update(currency)
+}
+
+// DisplayCurrency 获取请求的展示币种，不可用时回退到默认币种
+func (s *CurrencyService) DisplayCurrency(code string) models.Currency {
+	return s.displayCurrency(code)
+}
+
+// ListCurrencies 获取币种列表，activeOnly 为 true 时只返回前台可选的币种
+func (s *CurrencyService) ListCurrencies(activeOnly bool) ([]models.Currency, error) {
+	return s.repoFactory.GetCurrencyRepository().List(activeOnly)
+}
+
+// validateCurrency 校验币种参数
+func validateCurrency(req *CurrencyRequest) error {
+	if !req.Rate.IsPositive() {
+		return errors.New("rate must be positive")
+	}
+	if req.Decimals < 0 || req.Decimals > 4 {
+		return errors.New("decimals must be between 0 and 4")
+	}
+	if req.Rounding == "" {
+		req.Rounding = models.RoundingHalfUp
+	}
+	if !validRounding(req.Rounding) {
+		return errors.New("invalid rounding")
+	}
+	return nil
+}
+
+// CreateCurrency 创建币种
+func (s *CurrencyService) CreateCurrency(req CurrencyRequest) (*models.Currency, error) {
+	req.Code = strings.ToUpper(strings.TrimSpace(req.Code))
+	if !currencyCodePattern.MatchString(req.Code) {
+		return nil, errors.New("invalid currency code")
+	}
+	if req.Code == BaseCurrency() {
+		req.Rate = decimal.NewFromInt(1)
+		req.Active = true
+	}
+	if err := validateCurrency(&req); err != nil {
+		return nil, err
+	}
+
+	repo := s.repoFactory.GetCurrencyRepository()
+	if _, err := repo.GetByCode(req.Code); err == nil {
+		return nil, ErrCurrencyExists
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	currency := &models.Currency{
+		Code:     req.Code,
+		Name:     strings.TrimSpace(req.Name),
+		Symbol:   strings.TrimSpace(req.Symbol),
+		Rate:     req.Rate,
+		Decimals: req.Decimals,
+		Rounding: req.Rounding,
+		Active:   req.Active,
+	}
+	if err := repo.Create(currency); err != nil {
+		return nil, err
+	}
+	s.invalidateCurrencies()
+	return currency, nil
+}
+
+// UpdateCurrency 更新币种的汇率和舍入规则，基准币种的汇率固定为 1 且不能停用
+func (s *CurrencyService) UpdateCurrency(code string, req CurrencyRequest) (*models.Currency, error) {
+	repo := s.repoFactory.GetCurrencyRepository()
+	currency, err := repo.GetByCode(strings.ToUpper(strings.TrimSpace(code)))
+	if err == gorm.ErrRecordNotFound {
+		return nil, ErrCurrencyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if currency.Code == BaseCurrency() {
+		if !req.Rate.Equal(decimal.NewFromInt(1)) || !req.Active {
+			return nil, errors.New("base currency rate is fixed and it cannot be deactivated")
+		}
+	} else if currency.Code == DefaultCurrency() && !req.Active {
+		return nil, errors.New("default currency cannot be deactivated")
+	}
+	if err := validateCurrency(&req); err != nil {
+		return nil, err
+	}
+
+	currency.Name = strings.TrimSpace(req.Name)
+	currency.Symbol = strings.TrimSpace(req.Symbol)
+	currency.Rate = req.Rate
+	currency.Decimals = req.Decimals
+	currency.Rounding = req.Rounding
+	currency.Active = req.Active
+	if err := repo.Update(currency); err != nil {
+		return nil, err
+	}
+	s.invalidateCurrencies()
+	return currency, nil
+}
+
+// ToBaseAmount 将展示币种金额换算为基准币种，用于价格筛选
+func (s *CurrencyService) ToBaseAmount(amount decimal.Decimal, currency models.Currency) decimal.Decimal {
+	return toBaseAmount(amount, currency)
+}
+
+// ApplyProductPrices 为商品及其 SKU 填充展示价格
+func (s *CurrencyService) ApplyProductPrices(currency models.Currency, products ...*models.Product) {
+	for _, product := range products {
+		if product == nil {
+			continue
+		}
+		product.DisplayPrice = newMoney(product.Price, currency)
+		for i := range product.SKUs {
+			product.SKUs[i].DisplayPrice = newMoney(product.SKUs[i].Price, currency)
+		}
+	}
+}
+
+// ApplyProductListPrices 为商品列表填充展示价格
+func (s *CurrencyService) ApplyProductListPrices(currency models.Currency, products []models.Product) {
+	for i := range products {
+		s.ApplyProductPrices(currency, &products[i])
+	}
+}
+
+// ApplyCartPrices 为购物车项填充展示价格，小计按换算后的单价乘以数量计算，与订单预览一致
+func (s *CurrencyService) ApplyCartPrices(currency models.Currency, items []models.CartItem) {
+	for i := range items {
+		item := &items[i]
+		s.ApplyProductPrices(currency, &item.Product)
+		price := item.Product.Price
+		if item.SKU != nil {
+			item.SKU.DisplayPrice = newMoney(item.SKU.Price, currency)
+			price = item.SKU.Price
+		}
+		item.DisplaySubtotal = &models.Money{
+			Amount:   convertAmount(price, currency).Mul(decimal.NewFromInt(int64(item.Quantity))),
+			Currency: currency.Code,
+			Symbol:   currency.Symbol,
+		}
+	}
+}
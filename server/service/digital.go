@@ -0,0 +1,671 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"shopify/config"
+	"shopify/models"
+	"shopify/pkg/storage"
+	"shopify/pkg/utils/email"
+	"shopify/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrDigitalStock 数字商品库存由授权码池推算，不能直接调整
+	ErrDigitalStock = errors.New("digital product stock is derived from its license keys")
+	// ErrInvalidDownloadLink 下载链接签名错误或已过期
+	ErrInvalidDownloadLink = errors.New("invalid or expired download link")
+	// ErrDownloadLimitReached 下载次数已用完
+	ErrDownloadLimitReached = errors.New("download limit reached")
+	// ErrDownloadUnavailable 订单已取消或退款，不能下载
+	ErrDownloadUnavailable = errors.New("download is no longer available")
+)
+
+// maxLicenseKeyLength 授权码最大长度，与字段长度一致
+const maxLicenseKeyLength = 255
+
+var (
+	downloadSecretOnce sync.Once
+	downloadSecret     []byte
+)
+
+// downloadSigningSecret 下载链接签名密钥，未配置时在进程内随机生成
+func downloadSigningSecret() []byte {
+	downloadSecretOnce.Do(func() {
+		if secret := config.GlobalConfig.Digital.SigningSecret; secret != "" {
+			downloadSecret = []byte(secret)
+			return
+		}
+		downloadSecret = make([]byte, 32)
+		if _, err := rand.Read(downloadSecret); err != nil {
+			log.Fatalf("生成下载签名密钥失败: %v", err)
+		}
+		log.Printf("未配置 digital.signing_secret，已随机生成，重启后已发出的下载链接将失效")
+	})
+	return downloadSecret
+}
+
+// signDownload 计算下载链接签名
+func signDownload(grantID uint, expires int64) string {
+	mac := hmac.New(sha256.New, downloadSigningSecret())
+	fmt.Fprintf(mac, "%d:%d", grantID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// downloadURL 生成带过期时间和签名的下载地址
+func downloadURL(grantID uint, expiresAt time.Time) string {
+	expires := expiresAt.Unix()
+	return fmt.Sprintf("%s/api/v1/downloads/%d?expires=%d&signature=%s",
+		strings.TrimRight(config.GlobalConfig.Digital.APIURL, "/"), grantID, expires, signDownload(grantID, expires))
+}
+
+// downloadLinkTTL 下载链接有效期
+func downloadLinkTTL() time.Duration {
+	minutes := config.GlobalConfig.Digital.LinkTTLMinutes
+	if minutes <= 0 {
+		minutes = 60
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// downloadLimit 每个订单每个文件的下载次数上限
+func downloadLimit() int {
+	if limit := config.GlobalConfig.Digital.DownloadLimit; limit > 0 {
+		return limit
+	}
+	return 5
+}
+
+// NewPrivateStorage 创建数字文件存储
+// 本地存储时使用独立的目录，不经过静态资源路由；S3 存储时使用独立的私有存储桶，对象以 private 权限上传
+func NewPrivateStorage(storageCfg config.StorageConfig, digitalCfg config.DigitalConfig) (storage.Storage, error) {
+	switch storageCfg.Driver {
+	case "", "local":
+		return storage.NewLocalStorage(storage.LocalConfig{Root: digitalCfg.LocalRoot})
+	case "s3":
+		if digitalCfg.S3Bucket == "" || digitalCfg.S3Bucket == storageCfg.S3.Bucket {
+			return nil, errors.New("digital.s3_bucket must be set to a private bucket separate from storage.s3.bucket")
+		}
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint:     storageCfg.S3.Endpoint,
+			Region:       storageCfg.S3.Region,
+			Bucket:       digitalCfg.S3Bucket,
+			AccessKey:    storageCfg.S3.AccessKey,
+			SecretKey:    storageCfg.S3.SecretKey,
+			UsePathStyle: storageCfg.S3.UsePathStyle,
+			ACL:          "private",
+		})
+	default:
+		return nil, fmt.Errorf("unsupported storage driver: %s", storageCfg.Driver)
+	}
+}
+
+// orderDownloadable 订单取消或退款后不能再下载，下载授权只在支付成功后创建
+func orderDownloadable(order *models.Order) bool {
+	return order.Status != models.OrderStatusCancelled &&
+		order.PaymentStatus != models.PaymentStatusRefunded
+}
+
+// signOrderDownloads 为订单中仍可下载的文件生成签名链接
+func signOrderDownloads(order *models.Order) {
+	if !orderDownloadable(order) {
+		return
+	}
+	expiresAt := time.Now().Add(downloadLinkTTL())
+	for i := range order.OrderItems {
+		signDownloads(order.OrderItems[i].Downloads, expiresAt)
+	}
+}
+
+// signDownloads 为未用完次数的下载授权生成签名链接
+func signDownloads(grants []models.DownloadGrant, expiresAt time.Time) {
+	for i := range grants {
+		if grants[i].DownloadCount >= grants[i].DownloadLimit {
+			continue
+		}
+		grants[i].URL = downloadURL(grants[i].ID, expiresAt)
+		grants[i].URLExpiresAt = &expiresAt
+	}
+}
+
+// digitalStock 推算数字商品库存：有授权码池时为可分配数量，仅有文件时不限库存，两者都没有时为零
+func digitalStock(repoFactory *repository.RepositoryFactory, productID uint) (int, error) {
+	digitalRepo := repoFactory.GetDigitalRepository()
+	total, available, err := digitalRepo.CountLicenseKeys(productID)
+	if err != nil {
+		return 0, err
+	}
+	if total > 0 {
+		return int(available), nil
+	}
+	assets, err := digitalRepo.ListAssets(productID)
+	if err != nil {
+		return 0, err
+	}
+	if len(assets) > 0 {
+		return models.DigitalUnlimitedStock, nil
+	}
+	return 0, nil
+}
+
+// refreshDigitalStock 重新计算数字商品的库存
+func refreshDigitalStock(repoFactory *repository.RepositoryFactory, productID uint) error {
+	stock, err := digitalStock(repoFactory, productID)
+	if err != nil {
+		return err
+	}
+	return repoFactory.GetProductRepository().SetStock(productID, stock)
+}
+
+// reserveDigitalItem 下单时为数字商品订单项预留授权码，调用方负责开启事务
+func reserveDigitalItem(repoFactory *repository.RepositoryFactory, product *models.Product, item *models.OrderItem) error {
+	total, _, err := repoFactory.GetDigitalRepository().CountLicenseKeys(product.ID)
+	if err != nil {
+		return err
+	}
+	if total == 0 {
+		return nil
+	}
+	err = repoFactory.GetDigitalRepository().ReserveKeys(product.ID, item.OrderID, item.ID, item.Quantity)
+	if err == repository.ErrInsufficientStock {
+		return fmt.Errorf("insufficient stock for product: %s", product.Name)
+	}
+	if err != nil {
+		return err
+	}
+	return refreshDigitalStock(repoFactory, product.ID)
+}
+
+// orderItemType 订单项的商品类型，以下单时的快照为准，历史订单没有快照时取商品当前类型
+func orderItemType(item *models.OrderItem) string {
+	if item.ProductType != "" {
+		return item.ProductType
+	}
+	return item.Product.Type
+}
+
+// releaseDigitalItem 订单取消或退款时处理数字商品订单项：未发放的授权码放回池中，已发放的授权码作废
+func releaseDigitalItem(repoFactory *repository.RepositoryFactory, item *models.OrderItem) error {
+	digitalRepo := repoFactory.GetDigitalRepository()
+	if err := digitalRepo.ReleaseKeys(item.ID); err != nil {
+		return err
+	}
+	if err := digitalRepo.RevokeKeys(item.ID); err != nil {
+		return err
+	}
+	return refreshDigitalStock(repoFactory, item.ProductID)
+}
+
+// fulfilDigitalItems 支付成功后发放订单中的数字商品，已发放的订单项跳过，可重复调用
+// 订单只包含数字商品时直接完成订单，返回本次新发放的订单项
+func fulfilDigitalItems(repoFactory *repository.RepositoryFactory, orderID uint) ([]uint, error) {
+	order, err := repoFactory.GetOrderRepository().GetByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !orderDownloadable(order) {
+		return nil, nil
+	}
+
+	now := time.Now()
+	digitalRepo := repoFactory.GetDigitalRepository()
+	delivered := make([]uint, 0)
+	allDigital := len(order.OrderItems) > 0
+	for _, item := range order.OrderItems {
+		if orderItemType(&item) != models.ProductTypeDigital {
+			allDigital = false
+			continue
+		}
+		if item.DeliveredAt != nil {
+			continue
+		}
+
+		if err := digitalRepo.DeliverKeys(item.ID, now); err != nil {
+			return nil, err
+		}
+		assets, err := digitalRepo.ListAssets(item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		grants := make([]models.DownloadGrant, 0, len(assets))
+		for _, asset := range assets {
+			grants = append(grants, models.DownloadGrant{
+				OrderID:       order.ID,
+				OrderItemID:   item.ID,
+				UserID:        order.UserID,
+				AssetID:       asset.ID,
+				DownloadLimit: downloadLimit(),
+			})
+		}
+		if err := digitalRepo.CreateGrants(grants); err != nil {
+			return nil, err
+		}
+		if err := repoFactory.GetOrderRepository().MarkItemDelivered(item.ID, now); err != nil {
+			return nil, err
+		}
+		delivered = append(delivered, item.ID)
+	}
+
+	// 纯数字商品订单无需发货
+	if allDigital && order.Status != models.OrderStatusCompleted {
+		if err := repoFactory.GetOrderRepository().UpdateStatus(order.ID, models.OrderStatusCompleted); err != nil {
+			return nil, err
+		}
+	}
+	return delivered, nil
+}
+
+// deliverDigitalGoods 发放订单中的数字商品并邮件通知用户，邮件发送失败只记录日志
+func (s *Service) deliverDigitalGoods(orderID uint) error {
+	var delivered []uint
+	err := s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		delivered, err = fulfilDigitalItems(repository.NewRepositoryFactory(tx), orderID)
+		return err
+	})
+	if err != nil || len(delivered) == 0 {
+		return err
+	}
+
+	order, err := s.repoFactory.GetOrderRepository().GetByID(orderID)
+	if err != nil {
+		return err
+	}
+	if order.User.Email == "" {
+		return nil
+	}
+	signOrderDownloads(order)
+	subject, body := digitalDeliveryEmail(order, delivered)
+	if err := email.NewEmailSender().Send([]string{order.User.Email}, subject, body); err != nil {
+		// 邮件发送失败不影响发放，用户可在订单详情中查看
+		log.Printf("failed to send digital delivery email for order %s: %v", order.OrderNumber, err)
+	}
+	return nil
+}
+
+// digitalDeliveryEmail 生成数字商品发放邮件
+func digitalDeliveryEmail(order *models.Order, itemIDs []uint) (string, string) {
+	included := make(map[uint]bool, len(itemIDs))
+	for _, id := range itemIDs {
+		included[id] = true
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Thank you for your order %s. Your digital items are ready.\n", order.OrderNumber)
+	for _, item := range order.OrderItems {
+		if !included[item.ID] {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s\n", item.Product.Name)
+		for _, key := range item.LicenseKeys {
+			fmt.Fprintf(&b, "  License key: %s\n", key.Key)
+		}
+		for _, grant := range item.Downloads {
+			if grant.URL == "" {
+				continue
+			}
+			fmt.Fprintf(&b, "  Download %s: %s\n", grant.Asset.Name, grant.URL)
+		}
+	}
+	fmt.Fprintf(&b, "\nDownload links expire after %d minutes and each file can be downloaded %d times. "+
+		"You can get new links from your order detail page at any time.\n",
+		int(downloadLinkTTL().Minutes()), downloadLimit())
+	return fmt.Sprintf("Your digital items for order %s", order.OrderNumber), b.String()
+}
+
+// DigitalProduct 数字商品的文件和授权码库存
+type DigitalProduct struct {
+	ProductID     uint                  `json:"product_id"`
+	Assets        []models.DigitalAsset `json:"assets"`
+	TotalKeys     int64                 `json:"total_keys"`
+	AvailableKeys int64                 `json:"available_keys"`
+	Stock         int                   `json:"stock"`
+}
+
+// OrderDigitalContent 订单中已发放的授权码和下载链接
+type OrderDigitalContent struct {
+	LicenseKeys []models.LicenseKey    `json:"license_keys"`
+	Downloads   []models.DownloadGrant `json:"downloads"`
+}
+
+// DownloadFile 下载的文件内容，调用方负责关闭 Body
+type DownloadFile struct {
+	Name        string
+	ContentType string
+	Size        int64
+	Body        io.ReadCloser
+}
+
+type DigitalService struct {
+	*Service
+}
+
+func NewDigitalService(base *Service) *DigitalService {
+	return &DigitalService{Service: base}
+}
+
+// MaxFileSize 数字文件的大小上限(字节)
+func (s *DigitalService) MaxFileSize() int64 {
+	return int64(config.GlobalConfig.Digital.MaxFileMB) << 20
+}
+
+// ensureDigital 确认商品为数字商品，普通商品转为数字商品
+// 转换前商品库存需为零且没有规格，也不能是套装的组件
+func ensureDigital(repoFactory *repository.RepositoryFactory, productID uint) error {
+	product, err := repoFactory.GetProductRepository().GetByID(productID)
+	if err != nil {
+		return errors.New("product not found")
+	}
+	if product.Type == models.ProductTypeDigital {
+		return nil
+	}
+	if product.Type != "" && product.Type != models.ProductTypeStandard {
+		return fmt.Errorf("%s product cannot be digital", product.Type)
+	}
+	if product.Stock != 0 {
+		return errors.New("product stock must be adjusted to zero before converting to a digital product")
+	}
+	count, err := repoFactory.GetSKURepository().CountByProduct(productID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("product with skus cannot be digital")
+	}
+	isComponent, err := repoFactory.GetBundleRepository().IsComponent(productID)
+	if err != nil {
+		return err
+	}
+	if isComponent {
+		return errors.New("product is a component of a bundle")
+	}
+	return repoFactory.GetProductRepository().SetType(productID, models.ProductTypeDigital)
+}
+
+// GetDigitalProduct 获取数字商品的文件和授权码数量
+func (s *DigitalService) GetDigitalProduct(productID uint) (*DigitalProduct, error) {
+	product, err := s.repoFactory.GetProductRepository().GetByID(productID)
+	if err != nil {
+		return nil, errors.New("product not found")
+	}
+	if product.Type != models.ProductTypeDigital {
+		return nil, errors.New("product is not digital")
+	}
+	digitalRepo := s.repoFactory.GetDigitalRepository()
+	assets, err := digitalRepo.ListAssets(productID)
+	if err != nil {
+		return nil, err
+	}
+	total, available, err := digitalRepo.CountLicenseKeys(productID)
+	if err != nil {
+		return nil, err
+	}
+	return &DigitalProduct{
+		ProductID:     productID,
+		Assets:        assets,
+		TotalKeys:     total,
+		AvailableKeys: available,
+		Stock:         product.Stock,
+	}, nil
+}
+
+// UploadAsset 上传数字文件，文件以随机路径保存在私有存储中
+func (s *DigitalService) UploadAsset(productID uint, filename string, data []byte) (*models.DigitalAsset, error) {
+	if s.privateStorage == nil {
+		return nil, errors.New("storage not configured")
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty file")
+	}
+	if int64(len(data)) > s.MaxFileSize() {
+		return nil, errors.New("file too large")
+	}
+	name := path.Base(strings.ReplaceAll(strings.TrimSpace(filename), "\\", "/"))
+	if name == "" || name == "." || name == "/" {
+		return nil, errors.New("file name is required")
+	}
+
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	asset := &models.DigitalAsset{
+		ProductID:   productID,
+		Name:        name,
+		StorageKey:  path.Join("digital", strconv.FormatUint(uint64(productID), 10), hex.EncodeToString(random)),
+		ContentType: http.DetectContentType(data),
+		Size:        int64(len(data)),
+		Checksum:    hex.EncodeToString(sum[:]),
+	}
+
+	err := s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepoFactory := repository.NewRepositoryFactory(tx)
+		if err := ensureDigital(txRepoFactory, productID); err != nil {
+			return err
+		}
+		if err := txRepoFactory.GetDigitalRepository().CreateAsset(asset); err != nil {
+			return err
+		}
+		if err := s.privateStorage.Put(asset.StorageKey, data, asset.ContentType); err != nil {
+			return err
+		}
+		return refreshDigitalStock(txRepoFactory, productID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.syncSearchIndex(productID)
+	return asset, nil
+}
+
+// DeleteAsset 删除数字文件，已发放的下载授权仍可下载，文件内容保留
+func (s *DigitalService) DeleteAsset(productID, assetID uint) error {
+	err := s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepoFactory := repository.NewRepositoryFactory(tx)
+		asset, err := txRepoFactory.GetDigitalRepository().GetAsset(assetID)
+		if err != nil || asset.ProductID != productID {
+			return errors.New("file not found")
+		}
+		if err := txRepoFactory.GetDigitalRepository().DeleteAsset(assetID); err != nil {
+			return err
+		}
+		return refreshDigitalStock(txRepoFactory, productID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.syncSearchIndex(productID)
+	return nil
+}
+
+// AddLicenseKeys 导入授权码，忽略空行和已存在的授权码，返回实际导入的数量
+func (s *DigitalService) AddLicenseKeys(productID uint, keys []string) (int, error) {
+	unique := make([]string, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if key == "" || seen[key] {
+			continue
+		}
+		if len(key) > maxLicenseKeyLength {
+			return 0, fmt.Errorf("license key exceeds %d characters", maxLicenseKeyLength)
+		}
+		seen[key] = true
+		unique = append(unique, key)
+	}
+	if len(unique) == 0 {
+		return 0, errors.New("at least one license key is required")
+	}
+
+	added := 0
+	err := s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepoFactory := repository.NewRepositoryFactory(tx)
+		if err := ensureDigital(txRepoFactory, productID); err != nil {
+			return err
+		}
+		digitalRepo := txRepoFactory.GetDigitalRepository()
+		existing, err := digitalRepo.ListExistingKeys(productID, unique)
+		if err != nil {
+			return err
+		}
+		exists := make(map[string]bool, len(existing))
+		for _, key := range existing {
+			exists[key] = true
+		}
+
+		records := make([]models.LicenseKey, 0, len(unique))
+		for _, key := range unique {
+			if exists[key] {
+				continue
+			}
+			records = append(records, models.LicenseKey{
+				ProductID: productID,
+				Key:       key,
+				Status:    models.LicenseKeyAvailable,
+			})
+		}
+		if err := digitalRepo.CreateLicenseKeys(records); err != nil {
+			return err
+		}
+		added = len(records)
+		return refreshDigitalStock(txRepoFactory, productID)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.syncSearchIndex(productID)
+	return added, nil
+}
+
+// ListLicenseKeys 分页获取商品的授权码
+func (s *DigitalService) ListLicenseKeys(productID uint, status string, page, pageSize int) ([]models.LicenseKey, int64, error) {
+	switch status {
+	case "", models.LicenseKeyAvailable, models.LicenseKeyReserved, models.LicenseKeyDelivered, models.LicenseKeyRevoked:
+	default:
+		return nil, 0, errors.New("invalid status")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return s.repoFactory.GetDigitalRepository().ListLicenseKeys(productID, status, page, pageSize)
+}
+
+// DeleteLicenseKey 删除未分配的授权码
+func (s *DigitalService) DeleteLicenseKey(productID, keyID uint) error {
+	err := s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepoFactory := repository.NewRepositoryFactory(tx)
+		digitalRepo := txRepoFactory.GetDigitalRepository()
+		key, err := digitalRepo.GetLicenseKey(keyID)
+		if err != nil || key.ProductID != productID {
+			return errors.New("license key not found")
+		}
+		deleted, err := digitalRepo.DeleteAvailableKey(keyID)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return errors.New("only available license keys can be deleted")
+		}
+		return refreshDigitalStock(txRepoFactory, productID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.syncSearchIndex(productID)
+	return nil
+}
+
+// GetOrderContent 获取订单中已发放的授权码和新的下载链接
+func (s *DigitalService) GetOrderContent(orderID, userID uint) (*OrderDigitalContent, error) {
+	order, err := s.repoFactory.GetOrderRepository().GetByID(orderID)
+	if err != nil {
+		return nil, errors.New("order not found")
+	}
+	if userID != 0 && order.UserID != userID {
+		return nil, errors.New("permission denied")
+	}
+
+	content := &OrderDigitalContent{
+		LicenseKeys: make([]models.LicenseKey, 0),
+		Downloads:   make([]models.DownloadGrant, 0),
+	}
+	if !orderDownloadable(order) {
+		return content, nil
+	}
+	signOrderDownloads(order)
+	for _, item := range order.OrderItems {
+		content.LicenseKeys = append(content.LicenseKeys, item.LicenseKeys...)
+		content.Downloads = append(content.Downloads, item.Downloads...)
+	}
+	return content, nil
+}
+
+// Download 校验签名下载链接并扣减下载次数，返回文件内容
+func (s *DigitalService) Download(grantID uint, expires, signature string) (*DownloadFile, error) {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return nil, ErrInvalidDownloadLink
+	}
+	if !hmac.Equal([]byte(signature), []byte(signDownload(grantID, expiresAt))) {
+		return nil, ErrInvalidDownloadLink
+	}
+	if s.privateStorage == nil {
+		return nil, errors.New("storage not configured")
+	}
+
+	digitalRepo := s.repoFactory.GetDigitalRepository()
+	grant, err := digitalRepo.GetGrant(grantID)
+	if err != nil {
+		return nil, ErrInvalidDownloadLink
+	}
+	order, err := s.repoFactory.GetOrderRepository().GetByID(grant.OrderID)
+	if err != nil {
+		return nil, ErrInvalidDownloadLink
+	}
+	if !orderDownloadable(order) {
+		return nil, ErrDownloadUnavailable
+	}
+
+	// 先打开文件再计数，存储读取失败不消耗下载次数
+	body, err := s.privateStorage.Get(grant.Asset.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := digitalRepo.IncrementDownload(grantID, time.Now())
+	if err != nil || !ok {
+		body.Close()
+		if err == nil {
+			err = ErrDownloadLimitReached
+		}
+		return nil, err
+	}
+	return &DownloadFile{
+		Name:        grant.Asset.Name,
+		ContentType: grant.Asset.ContentType,
+		Size:        grant.Asset.Size,
+		Body:        body,
+	}, nil
+}
@@ -37,3 +37,86 @@ func (f *ServiceFactory) GetPaymentService() *PaymentService {
 	return NewPaymentService(f.base)
 }
 
+func (f *ServiceFactory) GetInventoryService() *InventoryService {
+	return NewInventoryService(f.base)
+}
+
+func (f *ServiceFactory) GetWarehouseService() *WarehouseService {
+	return NewWarehouseService(f.base)
+}
+
+func (f *ServiceFactory) GetNotificationService() *NotificationService {
+	return NewNotificationService(f.base)
+}
+
+func (f *ServiceFactory) GetStockAlertService() *StockAlertService {
+	return NewStockAlertService(f.base)
+}
+
+func (f *ServiceFactory) GetSKUService() *SKUService {
+	return NewSKUService(f.base)
+}
+
+func (f *ServiceFactory) GetCategoryService() *CategoryService {
+	return NewCategoryService(f.base)
+}
+
+func (f *ServiceFactory) GetSearchService() *SearchService {
+	return NewSearchService(f.base)
+}
+
+func (f *ServiceFactory) GetMediaService() *MediaService {
+	return NewMediaService(f.base)
+}
+
+func (f *ServiceFactory) GetAttributeService() *AttributeService {
+	return NewAttributeService(f.base)
+}
+
+func (f *ServiceFactory) GetImportService() *ImportService {
+	return NewImportService(f.base)
+}
+
+func (f *ServiceFactory) GetScheduleService() *ScheduleService {
+	return NewScheduleService(f.base)
+}
+
+func (f *ServiceFactory) GetRecommendationService() *RecommendationService {
+	return NewRecommendationService(f.base)
+}
+
+func (f *ServiceFactory) GetViewService() *ViewService {
+	return NewViewService(f.base)
+}
+
+func (f *ServiceFactory) GetSitemapService() *SitemapService {
+	return NewSitemapService(f.base)
+}
+
+func (f *ServiceFactory) GetFeedService() *FeedService {
+	return NewFeedService(f.base)
+}
+
+func (f *ServiceFactory) GetTranslationService() *TranslationService {
+	return NewTranslationService(f.base)
+}
+
+func (f *ServiceFactory) GetCurrencyService() *CurrencyService {
+	return NewCurrencyService(f.base)
+}
+
+func (f *ServiceFactory) GetBundleService() *BundleService {
+	return NewBundleService(f.base)
+}
+
+func (f *ServiceFactory) GetDigitalService() *DigitalService {
+	return NewDigitalService(f.base)
+}
+
+func (f *ServiceFactory) GetQuestionService() *QuestionService {
+	return NewQuestionService(f.base)
+}
+
+func (f *ServiceFactory) GetCompareService() *CompareService {
+	return NewCompareService(f.base)
+}
@@ -0,0 +1,546 @@
+package service
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"shopify/config"
+	"shopify/models"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// 商品源格式
+const (
+	FeedFormatGoogle = "google" // Google Merchant Center RSS 2.0
+	FeedFormatCSV    = "csv"    // 通用 CSV，列名与 Google 商品属性一致
+)
+
+const (
+	// feedRebuildInterval 距上次完整重建超过该时间后重新完整生成，兜底没有经过变更通知的修改
+	feedRebuildInterval = time.Hour
+	// feedBatchSize 完整重建时每批读取的产品数
+	feedBatchSize = 500
+	// maxFeedAdditionalImages Google 商品源最多支持的附加图片数
+	maxFeedAdditionalImages = 10
+)
+
+// googleNamespace Google 商品属性的命名空间
+const googleNamespace = "http://base.google.com/ns/1.0"
+
+// ErrFeedNotFound 商品源未启用、令牌错误或格式不支持
+var ErrFeedNotFound = errors.New("feed not found")
+
+// csvFeedHeader 通用 CSV 商品源的表头
+var csvFeedHeader = []string{
+	"id", "item_group_id", "title", "description", "link", "image_link", "additional_image_link",
+	"price", "availability", "condition", "brand", "gtin", "mpn", "google_product_category", "product_type",
+}
+
+// FeedItem 商品源中的一个条目，有 SKU 的商品每个 SKU 一个条目
+type FeedItem struct {
+	ID               string          // 条目ID，SKU 条目为 产品ID_SKU ID
+	ItemGroupID      string          // 同一商品的不同 SKU 使用产品ID作为分组
+	Title            string          // 标题，SKU 条目附带规格值
+	Description      string          // 描述
+	Link             string          // 商品页地址
+	ImageLink        string          // 主图
+	AdditionalImages []string        // 附加图片
+	Price            decimal.Decimal // 价格
+	Availability     string          // 库存状态：in_stock/out_of_stock
+	GTIN             string          // 商品条码，仅在 SKU 条形码为有效 GTIN 时填写
+	MPN              string          // 制造商编号，使用 SKU 编码
+	GoogleCategory   string          // Google 商品类目
+	ProductType      string          // 本站类目路径，如 服装 > 男装
+}
+
+// feedCache 按产品缓存的商品源条目，商品变化时只重新生成对应产品的条目
+type feedCache struct {
+	mu             sync.Mutex
+	builtAt        time.Time                // 上次完整重建的时间，零值表示需要完整重建
+	lastMovementID uint                     // 已处理的最大库存流水ID，库存变动通过流水发现
+	categories     map[uint]models.Category // 完整重建时加载的类目，类目变化会触发完整重建
+	items          map[uint][]FeedItem      // 按产品ID保存的条目
+	dirty          map[uint]bool            // 等待重新生成条目的产品
+	rendered       map[string][]byte        // 按格式缓存的输出，条目变化后清空
+}
+
+func (c *feedCache) markDirty(productID uint) {
+	c.mu.Lock()
+	if c.dirty == nil {
+		c.dirty = make(map[uint]bool)
+	}
+	c.dirty[productID] = true
+	c.mu.Unlock()
+}
+
+func (c *feedCache) invalidate() {
+	c.mu.Lock()
+	c.builtAt = time.Time{}
+	c.mu.Unlock()
+}
+
+// markFeedDirty 商品变化后标记需要重新生成该商品的商品源条目
+func (s *Service) markFeedDirty(productID uint) {
+	s.feed.markDirty(productID)
+}
+
+// invalidateFeed 类目等影响全部商品的变化后标记商品源需要完整重建
+func (s *Service) invalidateFeed() {
+	s.feed.invalidate()
+}
+
+type FeedService struct {
+	*Service
+}
+
+func NewFeedService(base *Service) *FeedService {
+	return &FeedService{Service: base}
+}
+
+// Feed 校验令牌并返回指定格式的商品源
+func (s *FeedService) Feed(token, format string) ([]byte, error) {
+	expected := config.GlobalConfig.Feed.Token
+	if expected == "" || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+		return nil, ErrFeedNotFound
+	}
+	if format != FeedFormatGoogle && format != FeedFormatCSV {
+		return nil, ErrFeedNotFound
+	}
+
+	c := s.feed
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := s.refreshFeed(); err != nil {
+		return nil, err
+	}
+	if data, ok := c.rendered[format]; ok {
+		return data, nil
+	}
+
+	items := c.sortedItems()
+	var data []byte
+	var err error
+	if format == FeedFormatGoogle {
+		data, err = encodeGoogleFeed(items)
+	} else {
+		data, err = encodeCSVFeed(items)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.rendered[format] = data
+	return data, nil
+}
+
+// refreshFeed 更新缓存的条目，调用方需持有锁
+// 超过重建间隔或类目变化时完整重建，否则只重新生成有变化或库存变动的商品
+func (s *FeedService) refreshFeed() error {
+	c := s.feed
+	if c.builtAt.IsZero() || time.Since(c.builtAt) > feedRebuildInterval {
+		return s.rebuildFeed()
+	}
+
+	inventoryRepo := s.repoFactory.GetInventoryRepository()
+	latest, err := inventoryRepo.LatestMovementID()
+	if err != nil {
+		return err
+	}
+	if latest > c.lastMovementID {
+		ids, err := inventoryRepo.ListProductIDsBetween(c.lastMovementID, latest)
+		if err != nil {
+			return err
+		}
+		// 套装库存随组件变化，没有自己的流水
+		bundleIDs, err := s.repoFactory.GetBundleRepository().ListBundleIDsByComponents(ids)
+		if err != nil {
+			return err
+		}
+		for _, id := range append(ids, bundleIDs...) {
+			c.dirty[id] = true
+		}
+		c.lastMovementID = latest
+	}
+	if len(c.dirty) == 0 {
+		return nil
+	}
+
+	productRepo := s.repoFactory.GetProductRepository()
+	for id := range c.dirty {
+		product, err := productRepo.GetWithSKUs(id)
+		if err == gorm.ErrRecordNotFound {
+			delete(c.items, id)
+		} else if err != nil {
+			return err
+		} else {
+			c.setItems(product)
+		}
+		delete(c.dirty, id)
+	}
+	c.rendered = make(map[string][]byte)
+	return nil
+}
+
+// rebuildFeed 重新生成全部上架商品的条目，调用方需持有锁
+func (s *FeedService) rebuildFeed() error {
+	c := s.feed
+	startedAt := time.Now()
+	c.builtAt = time.Time{} // 重建失败时下次访问继续完整重建
+
+	// 先记录流水位置，重建期间发生的库存变动会在下次访问时重新生成
+	latest, err := s.repoFactory.GetInventoryRepository().LatestMovementID()
+	if err != nil {
+		return err
+	}
+
+	categories, err := s.repoFactory.GetCategoryRepository().ListAll(false)
+	if err != nil {
+		return err
+	}
+	c.categories = make(map[uint]models.Category, len(categories))
+	for _, category := range categories {
+		c.categories[category.ID] = category
+	}
+
+	c.items = make(map[uint][]FeedItem)
+	c.dirty = make(map[uint]bool)
+	productRepo := s.repoFactory.GetProductRepository()
+	var afterID uint
+	for {
+		products, err := productRepo.ListWithSKUsAfter(afterID, feedBatchSize)
+		if err != nil {
+			return err
+		}
+		for i := range products {
+			c.setItems(&products[i])
+		}
+		if len(products) < feedBatchSize {
+			break
+		}
+		afterID = products[len(products)-1].ID
+	}
+
+	c.lastMovementID = latest
+	c.builtAt = startedAt
+	c.rendered = make(map[string][]byte)
+	return nil
+}
+
+// setItems 生成商品的条目，未上架的商品从商品源中移除
+func (c *feedCache) setItems(product *models.Product) {
+	if product.Status != "active" {
+		delete(c.items, product.ID)
+		return
+	}
+
+	cfg := config.GlobalConfig.SEO
+	siteURL := strings.TrimRight(cfg.SiteURL, "/")
+	images := make([]string, 0, len(product.Images))
+	for _, image := range product.Images {
+		if image != "" {
+			images = append(images, absoluteURL(siteURL, image))
+		}
+	}
+
+	base := FeedItem{
+		ID:          strconv.FormatUint(uint64(product.ID), 10),
+		Title:       truncateRunes(product.Name, 150),
+		Description: truncateRunes(product.Description, 5000),
+		Link:        productPageURL(product),
+		Price:       product.Price,
+	}
+	if base.Description == "" {
+		base.Description = base.Title
+	}
+	if len(images) > 0 {
+		base.ImageLink = images[0]
+		base.AdditionalImages = limitStrings(images[1:], maxFeedAdditionalImages)
+	}
+	if product.CategoryID != nil {
+		base.GoogleCategory = c.googleCategory(*product.CategoryID)
+		base.ProductType = c.categoryPath(*product.CategoryID)
+	}
+
+	var items []FeedItem
+	for _, sku := range product.SKUs {
+		if sku.Status != "active" {
+			continue
+		}
+		item := base
+		item.ID = fmt.Sprintf("%d_%d", product.ID, sku.ID)
+		item.ItemGroupID = base.ID
+		item.Title = truncateRunes(skuTitle(product, &sku), 150)
+		item.Price = sku.Price
+		item.Availability = feedAvailability(sku.Stock)
+		item.MPN = sku.Code
+		if validGTIN(sku.Barcode) {
+			item.GTIN = sku.Barcode
+		}
+		if sku.Image != "" {
+			item.ImageLink = absoluteURL(siteURL, sku.Image)
+			item.AdditionalImages = limitStrings(images, maxFeedAdditionalImages)
+		}
+		items = append(items, item)
+	}
+
+	// 没有启用的 SKU 时按商品输出一个条目
+	if len(items) == 0 {
+		base.Availability = feedAvailability(product.Stock)
+		items = append(items, base)
+	}
+	c.items[product.ID] = items
+}
+
+// googleCategory 获取类目对应的 Google 商品类目，未设置时沿用最近的上级类目
+func (c *feedCache) googleCategory(categoryID uint) string {
+	for id := categoryID; ; {
+		category, ok := c.categories[id]
+		if !ok {
+			return ""
+		}
+		if category.GoogleCategory != "" {
+			return category.GoogleCategory
+		}
+		if category.ParentID == nil {
+			return ""
+		}
+		id = *category.ParentID
+	}
+}
+
+// categoryPath 根据类目的祖先路径生成 服装 > 男装 形式的类目路径
+func (c *feedCache) categoryPath(categoryID uint) string {
+	category, ok := c.categories[categoryID]
+	if !ok {
+		return ""
+	}
+	var names []string
+	for _, part := range strings.Split(strings.Trim(category.Path, "/"), "/") {
+		id, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			continue
+		}
+		if ancestor, ok := c.categories[uint(id)]; ok {
+			names = append(names, ancestor.Name)
+		}
+	}
+	if len(names) == 0 {
+		return category.Name
+	}
+	return strings.Join(names, " > ")
+}
+
+// sortedItems 按产品ID顺序返回全部条目
+func (c *feedCache) sortedItems() []FeedItem {
+	ids := make([]uint, 0, len(c.items))
+	for id := range c.items {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var items []FeedItem
+	for _, id := range ids {
+		items = append(items, c.items[id]...)
+	}
+	return items
+}
+
+// skuTitle 商品名称加上按规格项顺序排列的规格值，如 T恤 (红色 / M)
+func skuTitle(product *models.Product, sku *models.SKU) string {
+	var values []string
+	for _, option := range product.Options {
+		if value := sku.Options[option.Name]; value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return product.Name
+	}
+	return fmt.Sprintf("%s (%s)", product.Name, strings.Join(values, " / "))
+}
+
+// feedAvailability 将库存数量转换为商品源的库存状态
+func feedAvailability(stock int) string {
+	if stock > 0 {
+		return "in_stock"
+	}
+	return "out_of_stock"
+}
+
+// validGTIN 判断条形码是否为校验位正确的 GTIN-8/12/13/14
+func validGTIN(code string) bool {
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+
+	sum := 0
+	for i := len(code) - 1; i >= 0; i-- {
+		if code[i] < '0' || code[i] > '9' {
+			return false
+		}
+		digit := int(code[i] - '0')
+		// 从校验位往前数，偶数位权重为 3
+		if (len(code)-1-i)%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
+
+// absoluteURL 将站内相对地址转换为绝对地址
+func absoluteURL(siteURL, value string) string {
+	if strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") {
+		return value
+	}
+	if strings.HasPrefix(value, "//") {
+		return "https:" + value
+	}
+	return siteURL + "/" + strings.TrimLeft(value, "/")
+}
+
+// limitStrings 截取前 limit 个元素
+func limitStrings(values []string, limit int) []string {
+	if len(values) > limit {
+		return values[:limit]
+	}
+	return values
+}
+
+// feedPrice 商品源使用 "99.00 CNY" 格式的价格，商品价格以基准币种保存
+func feedPrice(price decimal.Decimal) string {
+	return price.StringFixed(2) + " " + BaseCurrency()
+}
+
+// identifierExists 既没有 GTIN 也没有品牌加制造商编号时需要声明商品没有唯一标识
+func identifierExists(item *FeedItem, brand string) string {
+	if item.GTIN == "" && (brand == "" || item.MPN == "") {
+		return "no"
+	}
+	return ""
+}
+
+type googleFeed struct {
+	XMLName xml.Name      `xml:"rss"`
+	Version string        `xml:"version,attr"`
+	XmlnsG  string        `xml:"xmlns:g,attr"`
+	Channel googleChannel `xml:"channel"`
+}
+
+type googleChannel struct {
+	Title       string       `xml:"title"`
+	Link        string       `xml:"link"`
+	Description string       `xml:"description"`
+	Items       []googleItem `xml:"item"`
+}
+
+type googleItem struct {
+	ID                    string   `xml:"g:id"`
+	ItemGroupID           string   `xml:"g:item_group_id,omitempty"`
+	Title                 string   `xml:"g:title"`
+	Description           string   `xml:"g:description"`
+	Link                  string   `xml:"g:link"`
+	ImageLink             string   `xml:"g:image_link,omitempty"`
+	AdditionalImageLinks  []string `xml:"g:additional_image_link"`
+	Price                 string   `xml:"g:price"`
+	Availability          string   `xml:"g:availability"`
+	Condition             string   `xml:"g:condition"`
+	Brand                 string   `xml:"g:brand,omitempty"`
+	GTIN                  string   `xml:"g:gtin,omitempty"`
+	MPN                   string   `xml:"g:mpn,omitempty"`
+	IdentifierExists      string   `xml:"g:identifier_exists,omitempty"`
+	GoogleProductCategory string   `xml:"g:google_product_category,omitempty"`
+	ProductType           string   `xml:"g:product_type,omitempty"`
+}
+
+// encodeGoogleFeed 生成 Google Merchant Center 使用的 RSS 2.0 商品源
+func encodeGoogleFeed(items []FeedItem) ([]byte, error) {
+	cfg := config.GlobalConfig.Feed
+	feed := googleFeed{
+		Version: "2.0",
+		XmlnsG:  googleNamespace,
+		Channel: googleChannel{
+			Title:       cfg.Title,
+			Link:        strings.TrimRight(config.GlobalConfig.SEO.SiteURL, "/") + "/",
+			Description: cfg.Title,
+			Items:       make([]googleItem, 0, len(items)),
+		},
+	}
+	for i := range items {
+		item := &items[i]
+		feed.Channel.Items = append(feed.Channel.Items, googleItem{
+			ID:                    item.ID,
+			ItemGroupID:           item.ItemGroupID,
+			Title:                 item.Title,
+			Description:           item.Description,
+			Link:                  item.Link,
+			ImageLink:             item.ImageLink,
+			AdditionalImageLinks:  item.AdditionalImages,
+			Price:                 feedPrice(item.Price),
+			Availability:          item.Availability,
+			Condition:             "new",
+			Brand:                 cfg.Brand,
+			GTIN:                  item.GTIN,
+			MPN:                   item.MPN,
+			IdentifierExists:      identifierExists(item, cfg.Brand),
+			GoogleProductCategory: item.GoogleCategory,
+			ProductType:           item.ProductType,
+		})
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(feed); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeCSVFeed 生成通用 CSV 商品源，多张附加图片以逗号分隔
+func encodeCSVFeed(items []FeedItem) ([]byte, error) {
+	brand := config.GlobalConfig.Feed.Brand
+	rows := make([][]string, 0, len(items)+1)
+	rows = append(rows, csvFeedHeader)
+	for i := range items {
+		item := &items[i]
+		rows = append(rows, []string{
+			item.ID,
+			item.ItemGroupID,
+			item.Title,
+			item.Description,
+			item.Link,
+			item.ImageLink,
+			strings.Join(item.AdditionalImages, ","),
+			feedPrice(item.Price),
+			item.Availability,
+			"new",
+			brand,
+			item.GTIN,
+			item.MPN,
+			item.GoogleCategory,
+			item.ProductType,
+		})
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
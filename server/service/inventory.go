@@ -0,0 +1,251 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"shopify/models"
+	"shopify/repository"
+
+	"gorm.io/gorm"
+)
+
+type InventoryService struct {
+	*Service
+}
+
+func NewInventoryService(base *Service) *InventoryService {
+	return &InventoryService{Service: base}
+}
+
+// changeStock 变更库存并写入流水，调用方负责开启事务
+func changeStock(repoFactory *repository.RepositoryFactory, movement *models.InventoryMovement) error {
+	if movement.Delta == 0 {
+		return errors.New("stock delta cannot be zero")
+	}
+
+	productRepo := repoFactory.GetProductRepository()
+	if err := productRepo.UpdateStock(movement.ProductID, movement.Delta); err != nil {
+		return err
+	}
+
+	// 指定仓库时同步变更仓库库存
+	if movement.WarehouseID != 0 {
+		if err := repoFactory.GetWarehouseRepository().ChangeStock(movement.WarehouseID, movement.ProductID, movement.Delta); err != nil {
+			return err
+		}
+	}
+
+	// 指定SKU时同步变更SKU库存
+	if movement.SKUID != 0 {
+		if err := repoFactory.GetSKURepository().ChangeStock(movement.SKUID, movement.Delta); err != nil {
+			return err
+		}
+	}
+
+	// 记录变动后的库存数量
+	quantity, err := productRepo.GetStock(movement.ProductID)
+	if err != nil {
+		return err
+	}
+	movement.Quantity = quantity
+
+	if err := repoFactory.GetInventoryRepository().CreateMovement(movement); err != nil {
+		return err
+	}
+
+	// 组件库存变化后同步套装库存
+	return syncBundleStock(repoFactory, movement.ProductID)
+}
+
+// restockOrder 将订单中的商品回补库存，已分配仓库的部分回补到原仓库，套装回补到各组件，数字商品释放或作废授权码
+func restockOrder(repoFactory *repository.RepositoryFactory, order *models.Order, reason string) error {
+	for _, item := range order.OrderItems {
+		if orderItemType(&item) == models.ProductTypeDigital {
+			if err := releaseDigitalItem(repoFactory, &item); err != nil {
+				return err
+			}
+		} else if len(item.Components) > 0 {
+			for _, component := range item.Components {
+				if err := restockLine(repoFactory, order, item.ID, component.ProductID, component.SKUID, component.Quantity, reason); err != nil {
+					return err
+				}
+			}
+		} else if err := restockLine(repoFactory, order, item.ID, item.ProductID, item.SKUID, item.Quantity, reason); err != nil {
+			return err
+		}
+		if err := repoFactory.GetProductRepository().UpdateSales(item.ProductID, -item.Quantity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restockLine 回补订单项中某个商品的库存
+func restockLine(repoFactory *repository.RepositoryFactory, order *models.Order, itemID, productID uint, sku *uint, quantity int, reason string) error {
+	var skuID uint
+	if sku != nil {
+		skuID = *sku
+	}
+	remaining := quantity
+	for _, allocation := range order.Allocations {
+		if allocation.OrderItemID != itemID || allocation.ProductID != productID {
+			continue
+		}
+		movement := &models.InventoryMovement{
+			ProductID:   productID,
+			WarehouseID: allocation.WarehouseID,
+			SKUID:       skuID,
+			Delta:       allocation.Quantity,
+			Reason:      reason,
+			ReferenceID: order.ID,
+		}
+		if err := changeStock(repoFactory, movement); err != nil {
+			return err
+		}
+		remaining -= allocation.Quantity
+	}
+
+	if remaining > 0 {
+		movement := &models.InventoryMovement{
+			ProductID:   productID,
+			SKUID:       skuID,
+			Delta:       remaining,
+			Reason:      reason,
+			ReferenceID: order.ID,
+		}
+		if err := changeStock(repoFactory, movement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AdjustStock 人工调整库存，必须填写调整原因
+// warehouseID 不为 0 时同时调整该仓库的库存，有规格的产品必须指定 skuID
+func (s *InventoryService) AdjustStock(productID, warehouseID, skuID uint, delta int, remark string, operatorID uint) (*models.InventoryMovement, error) {
+	if remark == "" {
+		return nil, errors.New("adjustment reason is required")
+	}
+	return s.adjustStock(productID, warehouseID, skuID, delta, models.InventoryReasonAdjustment, remark, operatorID)
+}
+
+// adjustStock 按指定原因调整库存并记入流水，如批量导入
+func (s *InventoryService) adjustStock(productID, warehouseID, skuID uint, delta int, reason, remark string, operatorID uint) (*models.InventoryMovement, error) {
+	if delta == 0 {
+		return nil, errors.New("stock delta cannot be zero")
+	}
+
+	product, err := s.repoFactory.GetProductRepository().GetByID(productID)
+	if err != nil {
+		return nil, errors.New("product not found")
+	}
+	if product.Type == models.ProductTypeBundle {
+		return nil, ErrBundleStock
+	}
+	if product.Type == models.ProductTypeDigital {
+		return nil, ErrDigitalStock
+	}
+	if _, err := resolveSKU(s.repoFactory, product, &skuID); err != nil {
+		return nil, err
+	}
+	if warehouseID != 0 && skuID != 0 {
+		return nil, ErrSKUWarehouseStock
+	}
+	if warehouseID != 0 {
+		if _, err := s.repoFactory.GetWarehouseRepository().GetByID(warehouseID); err != nil {
+			return nil, errors.New("warehouse not found")
+		}
+	}
+
+	movement := &models.InventoryMovement{
+		ProductID:   productID,
+		WarehouseID: warehouseID,
+		SKUID:       skuID,
+		Delta:       delta,
+		Reason:      reason,
+		Remark:      remark,
+		OperatorID:  operatorID,
+	}
+
+	err = s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		return changeStock(repository.NewRepositoryFactory(tx), movement)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return movement, nil
+}
+
+// ListMovements 获取产品的库存流水
+func (s *InventoryService) ListMovements(productID uint, page, pageSize int) ([]models.InventoryMovement, int64, error) {
+	return s.repoFactory.GetInventoryRepository().ListByProduct(productID, page, pageSize)
+}
+
+// BackfillOpeningStock 为启用流水前已有库存的产品补记期初流水，使对账从当前库存开始
+// 有规格的产品按SKU分别记录，SKU库存合计与产品库存不一致的部分记为产品级期初库存
+func (s *InventoryService) BackfillOpeningStock() error {
+	const batchSize = 100
+	total := 0
+	for {
+		products, err := s.repoFactory.GetInventoryRepository().ListProductsWithoutMovements(batchSize)
+		if err != nil {
+			return err
+		}
+		if len(products) == 0 {
+			break
+		}
+		for i := range products {
+			product := &products[i]
+			err := s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+				return recordOpeningStock(repository.NewRepositoryFactory(tx), product)
+			})
+			if err != nil {
+				return fmt.Errorf("record opening stock for product %d: %v", product.ID, err)
+			}
+		}
+		total += len(products)
+	}
+	if total > 0 {
+		log.Printf("已为 %d 个产品补记期初库存流水", total)
+	}
+	return nil
+}
+
+// recordOpeningStock 写入产品的期初库存流水，不改变当前库存，调用方负责开启事务
+func recordOpeningStock(repoFactory *repository.RepositoryFactory, product *models.Product) error {
+	skus, err := repoFactory.GetSKURepository().ListByProduct(product.ID)
+	if err != nil {
+		return err
+	}
+
+	var movements []models.InventoryMovement
+	remaining := product.Stock
+	for _, sku := range skus {
+		if sku.Stock == 0 {
+			continue
+		}
+		movements = append(movements, models.InventoryMovement{SKUID: sku.ID, Delta: sku.Stock})
+		remaining -= sku.Stock
+	}
+	if remaining != 0 {
+		movements = append(movements, models.InventoryMovement{Delta: remaining})
+	}
+
+	for i := range movements {
+		movements[i].ProductID = product.ID
+		movements[i].Quantity = product.Stock
+		movements[i].Reason = models.InventoryReasonOpening
+		movements[i].Remark = "期初库存"
+		if err := repoFactory.GetInventoryRepository().CreateMovement(&movements[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StockAudit 根据流水重建库存并标记差异
+func (s *InventoryService) StockAudit(onlyDrift bool) ([]repository.StockDrift, error) {
+	return s.repoFactory.GetInventoryRepository().ListStockDrift(onlyDrift)
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"shopify/config"
+	"shopify/models"
+	"shopify/pkg/storage"
+)
+
+// 允许上传的图片类型及对应扩展名
+var allowedMediaTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// 各用途是否仅允许管理员上传
+var mediaPurposes = map[string]bool{
+	models.MediaPurposeProduct:  true,
+	models.MediaPurposeAd:       true,
+	models.MediaPurposeCategory: true,
+	models.MediaPurposeReview:   false,
+	models.MediaPurposeAvatar:   false,
+}
+
+// ValidMediaPurpose 判断上传用途是否合法，并返回是否仅限管理员
+func ValidMediaPurpose(purpose string) (valid bool, adminOnly bool) {
+	adminOnly, valid = mediaPurposes[purpose]
+	return valid, adminOnly
+}
+
+// NewStorage 根据配置创建文件存储
+func NewStorage(cfg config.StorageConfig) (storage.Storage, error) {
+	switch cfg.Driver {
+	case "", "local":
+		return storage.NewLocalStorage(storage.LocalConfig{
+			Root:    cfg.LocalRoot,
+			BaseURL: cfg.BaseURL,
+		})
+	case "s3":
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint:     cfg.S3.Endpoint,
+			Region:       cfg.S3.Region,
+			Bucket:       cfg.S3.Bucket,
+			AccessKey:    cfg.S3.AccessKey,
+			SecretKey:    cfg.S3.SecretKey,
+			PublicURL:    cfg.S3.PublicURL,
+			UsePathStyle: cfg.S3.UsePathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported storage driver: %s", cfg.Driver)
+	}
+}
+
+type MediaService struct {
+	*Service
+}
+
+func NewMediaService(base *Service) *MediaService {
+	return &MediaService{Service: base}
+}
+
+// MaxUploadSize 单个上传文件的大小上限(字节)
+func (s *MediaService) MaxUploadSize() int64 {
+	return int64(config.GlobalConfig.Storage.MaxUploadMB) << 20
+}
+
+// Upload 保存上传的图片并生成缩略图
+// 相同内容的文件只存储一份，重复上传直接返回已有记录
+func (s *MediaService) Upload(data []byte, purpose string, uploaderID uint) (*models.MediaFile, error) {
+	if s.storage == nil {
+		return nil, errors.New("storage not configured")
+	}
+	if valid, _ := ValidMediaPurpose(purpose); !valid {
+		return nil, errors.New("invalid purpose")
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty file")
+	}
+	if int64(len(data)) > s.MaxUploadSize() {
+		return nil, errors.New("file too large")
+	}
+
+	// 以文件内容判断类型，不信任客户端声明的 Content-Type 和扩展名
+	mimeType := http.DetectContentType(data)
+	ext, ok := allowedMediaTypes[mimeType]
+	if !ok {
+		return nil, errors.New("unsupported file type")
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	mediaRepo := s.repoFactory.GetMediaRepository()
+	if existing, err := mediaRepo.GetByHash(hash); err == nil {
+		return existing, nil
+	}
+
+	// 按哈希前缀分目录，避免单个目录下文件过多
+	dir := path.Join("media", hash[:2], hash[2:4])
+	key := path.Join(dir, hash+ext)
+	if err := s.storage.Put(key, data, mimeType); err != nil {
+		return nil, err
+	}
+
+	file := &models.MediaFile{
+		Hash:       hash,
+		Key:        key,
+		URL:        s.storage.URL(key),
+		MimeType:   mimeType,
+		Size:       int64(len(data)),
+		Thumbnails: make(map[string]string),
+		Purpose:    purpose,
+		UploaderID: uploaderID,
+	}
+
+	// WebP 等标准库无法解码的格式只保存原图
+	if img, info, err := storage.DecodeImage(data); err == nil {
+		file.Width, file.Height = info.Width, info.Height
+		for _, size := range config.GlobalConfig.Storage.ThumbnailSizes {
+			thumb := storage.Thumbnail(img, size)
+			if thumb == nil {
+				continue
+			}
+			thumbData, contentType, thumbExt, err := storage.EncodeImage(thumb, info.Format)
+			if err != nil {
+				return nil, err
+			}
+			thumbKey := path.Join(dir, hash+"_"+strconv.Itoa(size)+thumbExt)
+			if err := s.storage.Put(thumbKey, thumbData, contentType); err != nil {
+				return nil, err
+			}
+			file.Thumbnails[strconv.Itoa(size)] = s.storage.URL(thumbKey)
+		}
+	}
+
+	if err := mediaRepo.Create(file); err != nil {
+		// 并发上传同一文件时唯一索引冲突，返回先写入的记录
+		if existing, getErr := mediaRepo.GetByHash(hash); getErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+// fetchClient 下载远程图片使用的客户端
+var fetchClient = &http.Client{Timeout: 15 * time.Second}
+
+// Fetch 下载远程图片并按上传流程保存，用于批量导入时转存供应商图片
+func (s *MediaService) Fetch(url, purpose string, uploaderID uint) (*models.MediaFile, error) {
+	resp, err := fetchClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, s.MaxUploadSize()+1))
+	if err != nil {
+		return nil, err
+	}
+	return s.Upload(data, purpose, uploaderID)
+}
+
+// CleanupOrphans 清理超过保留期且未被任何业务数据引用的文件，返回清理数量
+func (s *MediaService) CleanupOrphans() (int, error) {
+	if s.storage == nil {
+		return 0, nil
+	}
+	mediaRepo := s.repoFactory.GetMediaRepository()
+
+	graceHours := config.GlobalConfig.Storage.OrphanGraceHours
+	files, err := mediaRepo.ListCreatedBefore(time.Now().Add(-time.Duration(graceHours) * time.Hour))
+	if err != nil {
+		return 0, err
+	}
+	if len(files) == 0 {
+		return 0, nil
+	}
+
+	referenced, err := mediaRepo.ListReferencedURLs()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, file := range files {
+		if isMediaReferenced(&file, referenced) {
+			continue
+		}
+		keys := []string{file.Key}
+		for size := range file.Thumbnails {
+			keys = append(keys, thumbnailKey(file.Key, size, file.Thumbnails[size]))
+		}
+		failed := false
+		for _, key := range keys {
+			if err := s.storage.Delete(key); err != nil {
+				log.Printf("删除孤儿文件 %s 失败: %v", key, err)
+				failed = true
+			}
+		}
+		if failed {
+			continue
+		}
+		if err := mediaRepo.Delete(file.ID); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// RunOrphanCleanup 供定时任务调用的孤儿文件清理
+func (s *MediaService) RunOrphanCleanup() error {
+	removed, err := s.CleanupOrphans()
+	if removed > 0 {
+		log.Printf("已清理 %d 个未被引用的文件", removed)
+	}
+	return err
+}
+
+// isMediaReferenced 原图或任一缩略图被引用即视为在用
+func isMediaReferenced(file *models.MediaFile, referenced map[string]bool) bool {
+	if referenced[file.URL] {
+		return true
+	}
+	for _, url := range file.Thumbnails {
+		if referenced[url] {
+			return true
+		}
+	}
+	return false
+}
+
+// thumbnailKey 根据原图路径和缩略图地址还原缩略图的存储路径
+func thumbnailKey(key, size, url string) string {
+	dir, name := path.Split(key)
+	base := name[:len(name)-len(path.Ext(name))]
+	return dir + base + "_" + size + path.Ext(url)
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"shopify/models"
+)
+
+type NotificationService struct {
+	*Service
+}
+
+func NewNotificationService(base *Service) *NotificationService {
+	return &NotificationService{Service: base}
+}
+
+// Notify 创建管理员站内通知，同一对象存在未读通知时不重复创建
+// 返回是否创建了新通知
+func (s *NotificationService) Notify(notificationType, title, content string, referenceID uint) (bool, error) {
+	repo := s.repoFactory.GetNotificationRepository()
+
+	exists, err := repo.HasUnread(notificationType, referenceID)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	notification := &models.AdminNotification{
+		Type:        notificationType,
+		Title:       title,
+		Content:     content,
+		ReferenceID: referenceID,
+	}
+	if err := repo.Create(notification); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ListNotifications 获取管理员通知列表
+func (s *NotificationService) ListNotifications(unreadOnly bool, page, pageSize int) ([]models.AdminNotification, int64, error) {
+	return s.repoFactory.GetNotificationRepository().List(unreadOnly, page, pageSize)
+}
+
+// MarkRead 标记通知为已读
+func (s *NotificationService) MarkRead(id uint) error {
+	return s.repoFactory.GetNotificationRepository().MarkRead(id)
+}
+
+// MarkAllRead 标记所有通知为已读
+func (s *NotificationService) MarkAllRead() error {
+	return s.repoFactory.GetNotificationRepository().MarkAllRead()
+}
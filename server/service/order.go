@@ -19,11 +19,127 @@ func NewOrderService(base *Service) *OrderService {
 	return &OrderService{Service: base}
 }
 
-// CreateOrder 创建订单
-func (s *OrderService) CreateOrder(userID uint, items []models.OrderItem, addressID uint) (*models.Order, error) {
+// OrderPreviewItem 订单预览中的商品行
+type OrderPreviewItem struct {
+	ProductID    uint            `json:"product_id"`    // 产品ID
+	SKUID        *uint           `json:"sku_id"`        // SKU ID，无规格商品为空
+	Name         string          `json:"name"`          // 产品名称
+	Quantity     int             `json:"quantity"`      // 数量
+	Price        decimal.Decimal `json:"price"`         // 基准币种单价
+	DisplayPrice *models.Money   `json:"display_price"` // 结算币种单价
+	Subtotal     *models.Money   `json:"subtotal"`      // 结算币种小计
+}
+
+// OrderPreview 订单预览，金额与实际下单一致
+type OrderPreview struct {
+	Items            []OrderPreviewItem `json:"items"`             // 商品行
+	TotalAmount      decimal.Decimal    `json:"total_amount"`      // 基准币种总金额
+	BaseCurrency     string             `json:"base_currency"`     // 基准币种
+	Currency         string             `json:"currency"`          // 结算币种
+	Symbol           string             `json:"symbol"`            // 结算币种符号
+	ExchangeRate     decimal.Decimal    `json:"exchange_rate"`     // 结算币种相对基准币种的汇率
+	SettlementAmount decimal.Decimal    `json:"settlement_amount"` // 结算币种总金额
+}
+
+// priceOrderItems 校验商品和库存并按当前价格为订单项定价，返回涉及的商品和基准币种总金额
+func priceOrderItems(repoFactory *repository.RepositoryFactory, items []models.OrderItem) (map[uint]*models.Product, decimal.Decimal, error) {
+	totalAmount := decimal.NewFromFloat(0)
+	products := make(map[uint]*models.Product)
+
+	for i := range items {
+		if items[i].Quantity <= 0 {
+			return nil, totalAmount, errors.New("quantity must be positive")
+		}
+		product, err := repoFactory.GetProductRepository().GetByID(items[i].ProductID)
+		if err != nil {
+			return nil, totalAmount, err
+		}
+		products[product.ID] = product
+
+		// 有规格的商品按SKU计价和校验库存
+		sku, err := resolveSKU(repoFactory, product, items[i].SKUID)
+		if err != nil {
+			return nil, totalAmount, err
+		}
+		stock, price := product.Stock, product.Price
+		if sku != nil {
+			stock, price = sku.Stock, sku.Price
+		} else {
+			items[i].SKUID = nil
+		}
+
+		if stock < items[i].Quantity {
+			return nil, totalAmount, fmt.Errorf("insufficient stock for product: %s", product.Name)
+		}
+
+		items[i].Price = price
+		itemTotal := price.Mul(decimal.NewFromInt(int64(items[i].Quantity)))
+		totalAmount = totalAmount.Add(itemTotal)
+	}
+	return products, totalAmount, nil
+}
+
+// settlementAmount 按结算币种计算订单金额，逐行换算单价后乘以数量，与购物车展示一致
+func settlementAmount(items []models.OrderItem, currency models.Currency) decimal.Decimal {
+	amount := decimal.NewFromFloat(0)
+	for _, item := range items {
+		amount = amount.Add(convertAmount(item.Price, currency).Mul(decimal.NewFromInt(int64(item.Quantity))))
+	}
+	return amount
+}
+
+// PreviewOrder 按结算币种预览订单金额，不扣减库存
+func (s *OrderService) PreviewOrder(items []models.OrderItem, currencyCode string) (*OrderPreview, error) {
+	if len(items) == 0 {
+		return nil, errors.New("order items are required")
+	}
+	currency, err := s.lookupCurrency(currencyCode)
+	if err != nil {
+		return nil, err
+	}
+	products, totalAmount, err := priceOrderItems(s.repoFactory, items)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &OrderPreview{
+		Items:            make([]OrderPreviewItem, 0, len(items)),
+		TotalAmount:      totalAmount,
+		BaseCurrency:     BaseCurrency(),
+		Currency:         currency.Code,
+		Symbol:           currency.Symbol,
+		ExchangeRate:     currency.Rate,
+		SettlementAmount: settlementAmount(items, currency),
+	}
+	for _, item := range items {
+		unit := newMoney(item.Price, currency)
+		preview.Items = append(preview.Items, OrderPreviewItem{
+			ProductID:    item.ProductID,
+			SKUID:        item.SKUID,
+			Name:         products[item.ProductID].Name,
+			Quantity:     item.Quantity,
+			Price:        item.Price,
+			DisplayPrice: unit,
+			Subtotal: &models.Money{
+				Amount:   unit.Amount.Mul(decimal.NewFromInt(int64(item.Quantity))),
+				Currency: currency.Code,
+				Symbol:   currency.Symbol,
+			},
+		})
+	}
+	return preview, nil
+}
+
+// CreateOrder 创建订单，currencyCode 为结算币种，为空时使用默认币种，订单保存下单时的汇率快照
+func (s *OrderService) CreateOrder(userID uint, items []models.OrderItem, addressID uint, currencyCode string) (*models.Order, error) {
 	var result *models.Order
 
-	err := s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+	currency, err := s.lookupCurrency(currencyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
 		txRepoFactory := repository.NewRepositoryFactory(tx)
 		
 		// 验证地址是否存在且属于该用户
@@ -32,62 +148,125 @@ func (s *OrderService) CreateOrder(userID uint, items []models.OrderItem, addres
 			return errors.New("invalid address")
 		}
 
-		totalAmount := decimal.NewFromFloat(0)
-		
 		// 验证商品并计算总金额
-		for i := range items {
-			product, err := txRepoFactory.GetProductRepository().GetByID(items[i].ProductID)
-			if err != nil {
-				return err
-			}
-
-			if product.Stock < items[i].Quantity {
-				return fmt.Errorf("insufficient stock for product: %s", product.Name)
-			}
-
-			// 更新库存和销量
-			if err := txRepoFactory.GetProductRepository().UpdateStock(product.ID, -items[i].Quantity); err != nil {
-				return err
-			}
-			if err := txRepoFactory.GetProductRepository().UpdateSales(product.ID, items[i].Quantity); err != nil {
-				return err
-			}
-
-			items[i].Price = product.Price
-			itemTotal := product.Price.Mul(decimal.NewFromInt(int64(items[i].Quantity)))
-			totalAmount = totalAmount.Add(itemTotal)
+		products, totalAmount, err := priceOrderItems(txRepoFactory, items)
+		if err != nil {
+			return err
 		}
 
 		// 创建订单
 		order := &models.Order{
-			UserID:        userID,
-			OrderNumber:   generateOrderNumber(),
-			TotalAmount:   totalAmount,
-			Status:        "pending",
-			AddressID:     addressID,
-			PaymentStatus: "unpaid",
+			UserID:           userID,
+			OrderNumber:      generateOrderNumber(),
+			TotalAmount:      totalAmount,
+			Currency:         currency.Code,
+			ExchangeRate:     currency.Rate,
+			SettlementAmount: settlementAmount(items, currency),
+			Status:           "pending",
+			AddressID:        addressID,
+			PaymentStatus:    "unpaid",
 		}
 
 		if err := txRepoFactory.GetOrderRepository().Create(order); err != nil {
 			return err
 		}
 
-		// 创建订单项
+		// 创建订单项并分配发货仓
+		warehouseIDs := make([]uint, 0)
+		seenWarehouses := make(map[uint]bool)
+		hasUnassigned := false
+		hasPhysical := false
 		for _, item := range items {
 			item.OrderID = order.ID
+			item.ProductType = products[item.ProductID].Type
 			if err := txRepoFactory.GetOrderRepository().CreateOrderItem(&item); err != nil {
 				return err
 			}
+
+			// 数字商品只预留授权码，支付成功后自动发放，不占用仓库库存
+			if item.ProductType == models.ProductTypeDigital {
+				if err := reserveDigitalItem(txRepoFactory, products[item.ProductID], &item); err != nil {
+					return err
+				}
+			} else {
+				hasPhysical = true
+
+				// 套装按组件扣减库存，同时记录组件明细和收入分摊
+				lines := []orderStockLine{{product: products[item.ProductID], skuID: item.SKUID, quantity: item.Quantity}}
+				if products[item.ProductID].Type == models.ProductTypeBundle {
+					components, err := txRepoFactory.GetBundleRepository().ListComponents(item.ProductID)
+					if err != nil {
+						return err
+					}
+					if len(components) == 0 {
+						return fmt.Errorf("bundle %s has no components", products[item.ProductID].Name)
+					}
+					if err := txRepoFactory.GetBundleRepository().CreateOrderItemComponents(bundleOrderComponents(components, &item)); err != nil {
+						return err
+					}
+					lines = lines[:0]
+					for i := range components {
+						lines = append(lines, orderStockLine{
+							product:  &components[i].Component,
+							skuID:    components[i].SKUID,
+							quantity: components[i].Quantity * item.Quantity,
+						})
+					}
+				}
+
+				for _, line := range lines {
+					allocations, unassigned, err := deductOrderStock(txRepoFactory, address.Province, &item, line)
+					if err != nil {
+						return err
+					}
+					for _, allocation := range allocations {
+						if !seenWarehouses[allocation.WarehouseID] {
+							seenWarehouses[allocation.WarehouseID] = true
+							warehouseIDs = append(warehouseIDs, allocation.WarehouseID)
+						}
+					}
+					if unassigned {
+						hasUnassigned = true
+					}
+				}
+			}
+
+			if err := txRepoFactory.GetProductRepository().UpdateSales(item.ProductID, item.Quantity); err != nil {
+				return err
+			}
+
+			// 扣减库存后再次确认商品未被修改，避免按过期价格下单
+			version, err := txRepoFactory.GetProductRepository().GetVersion(item.ProductID)
+			if err != nil {
+				return err
+			}
+			if version != products[item.ProductID].Version {
+				return fmt.Errorf("product %s has been updated, please retry: %w",
+					products[item.ProductID].Name, repository.ErrVersionConflict)
+			}
 		}
 
-		// 创建物流信息
-		logistics := &models.Logistics{
-			OrderID:     order.ID,
-			Status:      "pending",  // 初始状态为待处���
-			ShippingFee: decimal.NewFromFloat(0),  // 初始运费为0
+		// 每个发货仓创建一条物流信息，纯数字商品订单不创建物流信息
+		logisticsList := make([]*models.Logistics, 0, len(warehouseIDs)+1)
+		for i := range warehouseIDs {
+			logisticsList = append(logisticsList, &models.Logistics{
+				OrderID:     order.ID,
+				WarehouseID: &warehouseIDs[i],
+				Status:      "pending",
+				ShippingFee: decimal.NewFromFloat(0),
+			})
 		}
-		if err := txRepoFactory.GetOrderRepository().CreateLogistics(logistics); err != nil {
-			return err
+		if hasUnassigned || (hasPhysical && len(logisticsList) == 0) {
+			logisticsList = append(logisticsList, &models.Logistics{
+				OrderID:     order.ID,
+				Status:      "pending",  // 初始状态为待处理
+				ShippingFee: decimal.NewFromFloat(0),  // 初始运费为0
+			})
+		}
+		for _, logistics := range logisticsList {
+			if err := txRepoFactory.GetOrderRepository().CreateLogistics(logistics); err != nil {
+				return err
+			}
 		}
 
 		// 清空购物车
@@ -106,6 +285,65 @@ func (s *OrderService) CreateOrder(userID uint, items []models.OrderItem, addres
 	return result, nil
 }
 
+// orderStockLine 订单项需要扣减库存的商品，套装订单项对应各组件
+type orderStockLine struct {
+	product  *models.Product
+	skuID    *uint
+	quantity int
+}
+
+// deductOrderStock 为订单项分配发货仓并扣减库存，返回分配结果以及是否有未分仓扣减的部分
+func deductOrderStock(repoFactory *repository.RepositoryFactory, province string, item *models.OrderItem, line orderStockLine) ([]models.OrderAllocation, bool, error) {
+	var skuID uint
+	if line.skuID != nil {
+		skuID = *line.skuID
+	}
+
+	// 仓库库存按产品记录，有规格的商品全部从未分仓库存扣减，SKU库存由 changeStock 校验
+	var allocations []models.OrderAllocation
+	unassigned := line.quantity
+	if skuID == 0 {
+		var err error
+		allocations, unassigned, err = allocateItem(repoFactory, province, line.product, line.quantity)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	// 按分配结果扣减库存并记录流水
+	for i := range allocations {
+		allocations[i].OrderID = item.OrderID
+		allocations[i].OrderItemID = item.ID
+		movement := &models.InventoryMovement{
+			ProductID:   line.product.ID,
+			WarehouseID: allocations[i].WarehouseID,
+			SKUID:       skuID,
+			Delta:       -allocations[i].Quantity,
+			Reason:      models.InventoryReasonOrder,
+			ReferenceID: item.OrderID,
+		}
+		if err := changeStock(repoFactory, movement); err != nil {
+			return nil, false, err
+		}
+		if err := repoFactory.GetWarehouseRepository().CreateAllocation(&allocations[i]); err != nil {
+			return nil, false, err
+		}
+	}
+	if unassigned > 0 {
+		movement := &models.InventoryMovement{
+			ProductID:   line.product.ID,
+			SKUID:       skuID,
+			Delta:       -unassigned,
+			Reason:      models.InventoryReasonOrder,
+			ReferenceID: item.OrderID,
+		}
+		if err := changeStock(repoFactory, movement); err != nil {
+			return nil, false, err
+		}
+	}
+	return allocations, unassigned > 0, nil
+}
+
 // GetOrder 获取订单详情
 func (s *OrderService) GetOrder(id uint, userID uint) (*models.Order, error) {
 	order, err := s.repoFactory.GetOrderRepository().GetByID(id)
@@ -117,7 +355,9 @@ func (s *OrderService) GetOrder(id uint, userID uint) (*models.Order, error) {
 	if userID != 0 && order.UserID != userID {
 		return nil, errors.New("permission denied")
 	}
-	
+
+	// 为已发放的数字文件生成新的签名下载链接
+	signOrderDownloads(order)
 	return order, nil
 }
 
@@ -125,13 +365,18 @@ func (s *OrderService) GetOrderByID(orderID uint) (*models.Order, error) {
 	return s.repoFactory.GetOrderRepository().GetByID(orderID)
 }
 
+// ListUserOrdersByCursor 游标分页获取用户订单列表
+func (s *OrderService) ListUserOrdersByCursor(userID uint, req CursorRequest) ([]models.Order, *CursorPage, error) {
+	return s.repoFactory.GetOrderRepository().ListByUserIDCursor(userID, req)
+}
+
 // ListUserOrders 获取用户订单列表
 func (s *OrderService) ListUserOrders(userID uint, page, pageSize int) ([]models.Order, int64, error) {
 	return s.repoFactory.GetOrderRepository().ListByUserID(userID, page, pageSize)
 }
 
-// UpdateOrderStatus 更新订单状态
-func (s *OrderService) UpdateOrderStatus(orderID uint, status string) error {
+// UpdateOrderStatus 更新订单状态，version 为 0 时不校验版本号
+func (s *OrderService) UpdateOrderStatus(orderID uint, status string, version uint) error {
 	// 验证状态值
 	validStatuses := map[string]bool{
 		"pending":   true,
@@ -144,17 +389,61 @@ func (s *OrderService) UpdateOrderStatus(orderID uint, status string) error {
 		return errors.New("invalid status value")
 	}
 
-	return s.repoFactory.GetOrderRepository().UpdateStatus(orderID, status)
+	return s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepoFactory := repository.NewRepositoryFactory(tx)
+
+		order, err := txRepoFactory.GetOrderRepository().GetByID(orderID)
+		if err != nil {
+			return errors.New("order not found")
+		}
+		if version != 0 && order.Version != version {
+			return repository.ErrVersionConflict
+		}
+
+		// 未发货的订单取消时回补库存，已退款的订单在退款时已回补
+		if status == models.OrderStatusCancelled &&
+			(order.Status == models.OrderStatusPending || order.Status == models.OrderStatusPaid) &&
+			order.PaymentStatus != models.PaymentStatusRefunded {
+			if err := restockOrder(txRepoFactory, order, models.InventoryReasonCancel); err != nil {
+				return err
+			}
+		}
+
+		return txRepoFactory.GetOrderRepository().UpdateStatusWithVersion(orderID, status, order.Version)
+	})
 }
 
-// UpdatePaymentStatus 更新支付状态
+// UpdatePaymentStatus 更新支付状态，支付成功时自动发放订单中的数字商品
 func (s *OrderService) UpdatePaymentStatus(orderID uint, status string) error {
 	var paymentTime *time.Time
 	if status == "paid" {
 		now := time.Now()
 		paymentTime = &now
 	}
-	return s.repoFactory.GetOrderRepository().UpdatePaymentStatus(orderID, status, paymentTime)
+
+	err := s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepoFactory := repository.NewRepositoryFactory(tx)
+
+		order, err := txRepoFactory.GetOrderRepository().GetByID(orderID)
+		if err != nil {
+			return errors.New("order not found")
+		}
+
+		// 退款时回补库存，已取消的订单在取消时已回补
+		if status == models.PaymentStatusRefunded &&
+			order.PaymentStatus != models.PaymentStatusRefunded &&
+			order.Status != models.OrderStatusCancelled {
+			if err := restockOrder(txRepoFactory, order, models.InventoryReasonRefund); err != nil {
+				return err
+			}
+		}
+
+		return txRepoFactory.GetOrderRepository().UpdatePaymentStatus(orderID, status, paymentTime)
+	})
+	if err != nil || status != models.PaymentStatusPaid {
+		return err
+	}
+	return s.deliverDigitalGoods(orderID)
 }
 
 // CreateLogistics 创建物流信息
@@ -194,7 +483,12 @@ func (s *OrderService) UpdateLogistics(logistics *models.Logistics) error {
 
 		// 先创建或更新物流信息
 		var existingLogistics *models.Logistics
-		existingLogistics, err := txRepoFactory.GetOrderRepository().GetLogistics(logistics.OrderID)
+		var err error
+		if logistics.WarehouseID != nil {
+			existingLogistics, err = txRepoFactory.GetOrderRepository().GetLogisticsByWarehouse(logistics.OrderID, logistics.WarehouseID)
+		} else {
+			existingLogistics, err = txRepoFactory.GetOrderRepository().GetLogistics(logistics.OrderID)
+		}
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				// 如果不存在，则创建新的物流信息
@@ -260,11 +554,21 @@ func (s *OrderService) GetLogistics(orderID uint) (*models.Logistics, error) {
 	return s.repoFactory.GetOrderRepository().GetLogistics(orderID)
 }
 
+// ListLogistics 获取订单按发货仓拆分的所有物流信息
+func (s *OrderService) ListLogistics(orderID uint) ([]models.Logistics, error) {
+	return s.repoFactory.GetOrderRepository().ListLogistics(orderID)
+}
+
 // AddLogisticsTrace 添加物流跟踪记录
 func (s *OrderService) AddLogisticsTrace(trace *models.LogisticsTrace) error {
 	return s.repoFactory.GetOrderRepository().AddLogisticsTrace(trace)
 }
 
+// ListOrdersByStatusCursor 管理员按状态游标分页查询订单
+func (s *OrderService) ListOrdersByStatusCursor(status string, req CursorRequest) ([]models.Order, *CursorPage, error) {
+	return s.repoFactory.GetOrderRepository().ListOrdersByStatusCursor(status, req)
+}
+
 // ListOrdersByStatus 管理员按状态查询订单
 func (s *OrderService) ListOrdersByStatus(status string, page, pageSize int) ([]models.Order, int64, error) {
 	return s.repoFactory.GetOrderRepository().ListOrdersByStatus(status, page, pageSize)
@@ -29,6 +29,7 @@ func (s *PaymentService) CreatePayment(orderID uint, method string) (*models.Pay
 	}
 
 	// 创建支付记录
+	// 订单的结算币种和汇率只用于展示和对账，支付始终按基准币种金额发起
 	paymentRecord := &models.Payment{
 		OrderID:       orderID,
 		PaymentMethod: method,
@@ -130,6 +131,10 @@ func (s *PaymentService) HandleCallback(method string, data map[string]string) e
 		if err := s.repoFactory.GetOrderRepository().UpdateStatus(payment.OrderID, "paid"); err != nil {
 			return err
 		}
+		// 数字商品支付成功后自动发放
+		if err := s.deliverDigitalGoods(payment.OrderID); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -2,7 +2,11 @@ package service
 
 import (
 	"errors"
+	"log"
 	"shopify/models"
+	"shopify/repository"
+
+	"gorm.io/gorm"
 )
 
 type ProductService struct {
@@ -13,15 +17,76 @@ func NewProductService(base *Service) *ProductService {
 	return &ProductService{Service: base}
 }
 
+// ProductQuery 产品列表查询条件
+type ProductQuery = repository.ProductQuery
+
+// ProductSortNewest 默认排序方式
+const ProductSortNewest = repository.ProductSortNewest
+
+// ValidProductSort 判断排序方式是否受支持
+var ValidProductSort = repository.ValidProductSort
+
+// ProductDetail 产品详情，包含规格组合矩阵
+type ProductDetail struct {
+	*models.Product
+	Variants     []VariantCombination     `json:"variants,omitempty"` // 规格组合及可售状态
+	Specs        []ProductSpec            `json:"specs"`              // 规格参数表
+	PriceHistory []models.PriceHistory    `json:"price_history"`      // 近期价格变动，用于价格保护
+	Questions    []models.ProductQuestion `json:"questions"`          // 热门问答
+}
+
 func (s *ProductService) GetProduct(id uint) (*models.Product, error) {
 	return s.repoFactory.GetProductRepository().GetByID(id)
 }
 
+// GetProductDetail 获取产品详情及规格组合矩阵
+func (s *ProductService) GetProductDetail(id uint) (*ProductDetail, error) {
+	product, err := s.repoFactory.GetProductRepository().GetWithSKUs(id)
+	if err != nil {
+		return nil, err
+	}
+
+	specs, err := s.productSpecs(product)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.recentPriceHistory(product.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.Type == models.ProductTypeBundle {
+		product.Components, err = s.repoFactory.GetBundleRepository().ListComponents(product.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	questions, err := s.topProductQuestions(product.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProductDetail{
+		Product:      product,
+		Variants:     buildVariantMatrix(product.Options, product.SKUs),
+		Specs:        specs,
+		PriceHistory: history,
+		Questions:    questions,
+	}, nil
+}
+
 func (s *ProductService) ListProducts(page, pageSize int) ([]models.Product, int64, error) {
 	return s.repoFactory.GetProductRepository().List(page, pageSize)
 }
 
 func (s *ProductService) CreateProduct(product *models.Product) error {
+	return s.createProduct(product, models.InventoryReasonAdjustment, "初始库存", 0)
+}
+
+// createProduct 创建产品，初始库存按 reason 和 remark 记入流水
+func (s *ProductService) createProduct(product *models.Product, reason, remark string, operatorID uint) error {
 	if product.Name == "" {
 		return errors.New("product name is required")
 	}
@@ -43,19 +108,162 @@ func (s *ProductService) CreateProduct(product *models.Product) error {
 		product.Tags = make([]string, 0)
 	}
 
-	return s.repoFactory.GetProductRepository().Create(product)
+	// slug 在产品创建后单独写入，未指定时根据名称生成
+	requestedSlug := product.Slug
+	product.Slug = ""
+
+	err := s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepoFactory := repository.NewRepositoryFactory(tx)
+
+		if err := resolveCategory(txRepoFactory, product); err != nil {
+			return err
+		}
+		if err := txRepoFactory.GetProductRepository().Create(product); err != nil {
+			return err
+		}
+		if err := assignSlug(txRepoFactory, product, requestedSlug); err != nil {
+			return err
+		}
+
+		// 初始库存记入流水，保证对账从零开始
+		if product.Stock > 0 {
+			movement := &models.InventoryMovement{
+				ProductID:  product.ID,
+				Delta:      product.Stock,
+				Quantity:   product.Stock,
+				Reason:     reason,
+				Remark:     remark,
+				OperatorID: operatorID,
+			}
+			if err := txRepoFactory.GetInventoryRepository().CreateMovement(movement); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.syncSearchIndex(product.ID)
+	return nil
 }
 
-func (s *ProductService) UpdateProduct(product *models.Product) error {
-	if _, err := s.GetProduct(product.ID); err != nil {
+// UpdateProduct 更新产品，只修改 fields 中列出的字段，字段名与请求的 JSON 字段一致
+// product.Version 为客户端持有的版本号，为 0 时以当前版本为准；product.Slug 不为空时修改 slug
+func (s *ProductService) UpdateProduct(product *models.Product, fields []string, operatorID uint) error {
+	existing, err := s.GetProduct(product.ID)
+	if err != nil {
 		return errors.New("product not found")
 	}
 
-	if product.Status != "" && product.Status != "active" && product.Status != "inactive" {
-		return errors.New("invalid status value")
+	updated := *existing
+	if product.Version != 0 {
+		updated.Version = product.Version
 	}
 
-	return s.repoFactory.GetProductRepository().Update(product)
+	columns := make([]string, 0, len(fields)+1)
+	changeCategory := false
+	for _, field := range fields {
+		switch field {
+		case "name":
+			if product.Name == "" {
+				return errors.New("product name is required")
+			}
+			updated.Name = product.Name
+		case "description":
+			updated.Description = product.Description
+		case "price":
+			// 价格为 0 视为未修改
+			if product.Price.IsZero() {
+				continue
+			}
+			if product.Price.IsNegative() {
+				return errors.New("product price cannot be negative")
+			}
+			updated.Price = product.Price
+		case "low_stock_threshold":
+			updated.LowStockThreshold = product.LowStockThreshold
+		case "status":
+			if product.Status == "" {
+				continue
+			}
+			if product.Status != "active" && product.Status != "inactive" {
+				return errors.New("invalid status value")
+			}
+			updated.Status = product.Status
+		case "category_id":
+			// 类目ID为空或 0 时清除类目
+			updated.CategoryID = product.CategoryID
+			if updated.CategoryID != nil && *updated.CategoryID == 0 {
+				updated.CategoryID = nil
+			}
+			updated.Category = ""
+			changeCategory = true
+			columns = append(columns, "category")
+		case "images":
+			updated.Images = product.Images
+		case "tags":
+			updated.Tags = product.Tags
+		case "external_id":
+			updated.ExternalID = product.ExternalID
+		default:
+			continue
+		}
+		columns = append(columns, field)
+	}
+
+	changeSlug := product.Slug != "" && product.Slug != existing.Slug
+
+	err = s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepoFactory := repository.NewRepositoryFactory(tx)
+
+		// 修改 slug 前先校验，避免产品已更新而 slug 冲突
+		if changeSlug {
+			if err := checkSlugAvailable(txRepoFactory, updated.ID, product.Slug); err != nil {
+				return err
+			}
+		}
+		if changeCategory {
+			if err := resolveCategory(txRepoFactory, &updated); err != nil {
+				return err
+			}
+		}
+
+		if err := txRepoFactory.GetProductRepository().Update(&updated, columns...); err != nil {
+			return err
+		}
+		if changeSlug {
+			if err := assignSlug(txRepoFactory, &updated, product.Slug); err != nil {
+				return err
+			}
+		}
+
+		if err := recordPriceChange(txRepoFactory, updated.ID, 0, existing.Price, updated.Price, models.PriceSourceManual, nil, operatorID); err != nil {
+			return err
+		}
+
+		// 更换类目后原类目的规格属性不再适用
+		if !sameCategory(existing.CategoryID, updated.CategoryID) {
+			if err := pruneProductAttributes(txRepoFactory, &updated); err != nil {
+				return err
+			}
+		}
+
+		// 组件上下架会影响套装的可售数量
+		if updated.Status != existing.Status {
+			return syncBundleStock(txRepoFactory, updated.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	updated.Version++
+	*product = updated
+	s.syncSearchIndex(product.ID)
+	return nil
 }
 
 func (s *ProductService) DeleteProduct(id uint) error {
@@ -63,25 +271,75 @@ func (s *ProductService) DeleteProduct(id uint) error {
 		return errors.New("product not found")
 	}
 
-	return s.repoFactory.GetProductRepository().Delete(id)
+	if err := s.repoFactory.GetProductRepository().Delete(id); err != nil {
+		return err
+	}
+
+	// 组件删除后所属套装不可售
+	if err := syncBundleStock(s.repoFactory, id); err != nil {
+		log.Printf("同步产品 %d 所属套装的库存失败: %v", id, err)
+	}
+
+	s.syncSearchIndex(id)
+	return nil
+}
+
+// applyCategoryFilter 将类目筛选展开为类目自身及所有子孙类目
+func (s *ProductService) applyCategoryFilter(categoryID uint, query *ProductQuery) error {
+	if categoryID == 0 {
+		return nil
+	}
+	category, err := s.repoFactory.GetCategoryRepository().GetByID(categoryID)
+	if err != nil {
+		return errors.New("category not found")
+	}
+	ids, err := s.repoFactory.GetCategoryRepository().ListDescendantIDs(category.Path)
+	if err != nil {
+		return err
+	}
+	query.CategoryIDs = ids
+	return nil
 }
 
-func (s *ProductService) ListProductsByCategory(category string, page, pageSize int) ([]models.Product, int64, error) {
-	return s.repoFactory.GetProductRepository().ListByCategory(category, page, pageSize)
+// validateProductQuery 校验查询条件
+func validateProductQuery(query *ProductQuery) error {
+	if query.MinPrice.IsPositive() && query.MaxPrice.IsPositive() && query.MinPrice.GreaterThan(query.MaxPrice) {
+		return errors.New("invalid price range")
+	}
+	if query.Sort != "" && !repository.ValidProductSort(query.Sort) {
+		return errors.New("invalid sort value")
+	}
+	return nil
 }
 
-func (s *ProductService) ListProductsByPriceRange(minPrice, maxPrice float64, page, pageSize int) ([]models.Product, int64, error) {
-	if minPrice > maxPrice {
-		return nil, 0, errors.New("invalid price range")
+// QueryProducts 按组合条件查询产品，categoryID 不为 0 时包含所有子孙类目下的产品
+func (s *ProductService) QueryProducts(categoryID uint, query ProductQuery) ([]models.Product, int64, error) {
+	if err := validateProductQuery(&query); err != nil {
+		return nil, 0, err
 	}
-	return s.repoFactory.GetProductRepository().ListByPriceRange(minPrice, maxPrice, page, pageSize)
+	if err := s.applyCategoryFilter(categoryID, &query); err != nil {
+		return nil, 0, err
+	}
+	if err := s.normalizeAttributeFilters(&query); err != nil {
+		return nil, 0, err
+	}
+
+	return s.repoFactory.GetProductRepository().Query(query)
 }
 
-func (s *ProductService) ListProductsByTags(tags []string, page, pageSize int) ([]models.Product, int64, error) {
-	if len(tags) == 0 {
-		return nil, 0, errors.New("tags cannot be empty")
+// QueryProductsByCursor 按组合条件游标分页查询产品
+func (s *ProductService) QueryProductsByCursor(categoryID uint, query ProductQuery, req CursorRequest) ([]models.Product, *CursorPage, error) {
+	if err := validateProductQuery(&query); err != nil {
+		return nil, nil, err
 	}
-	return s.repoFactory.GetProductRepository().ListByTags(tags, page, pageSize)
+	if err := s.applyCategoryFilter(categoryID, &query); err != nil {
+		return nil, nil, err
+	}
+	if err := s.normalizeAttributeFilters(&query); err != nil {
+		return nil, nil, err
+	}
+
+	return s.repoFactory.GetProductRepository().QueryByCursor(query, req)
 }
 
 func (s *ProductService) SearchProducts(keyword string, page, pageSize int) ([]models.Product, int64, error) {
@@ -89,4 +347,12 @@ func (s *ProductService) SearchProducts(keyword string, page, pageSize int) ([]m
 		return nil, 0, errors.New("search keyword cannot be empty")
 	}
 	return s.repoFactory.GetProductRepository().Search(keyword, page, pageSize)
-} 
\ No newline at end of file
+}
+
+// sameCategory 判断两个类目ID是否相同
+func sameCategory(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
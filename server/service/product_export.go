@@ -0,0 +1,123 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"shopify/models"
+	"shopify/pkg/spreadsheet"
+)
+
+// exportBatchSize 导出时每批读取的商品数
+const exportBatchSize = 200
+
+// ExportCatalogue 按标准格式导出全部商品，导出的文件修改后可直接重新导入
+// 有SKU的商品每个SKU一行，商品字段只写在第一行
+func (s *ImportService) ExportCatalogue(format string) ([]byte, error) {
+	categoryPaths, err := s.categoryPathNames()
+	if err != nil {
+		return nil, err
+	}
+
+	productRepo := s.repoFactory.GetProductRepository()
+	products := make([]models.Product, 0)
+	var afterID uint
+	for {
+		batch, err := productRepo.ListWithSKUsAfter(afterID, exportBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, batch...)
+		if len(batch) < exportBatchSize {
+			break
+		}
+		afterID = batch[len(batch)-1].ID
+	}
+
+	// 规格列数按规格项最多的商品确定
+	optionCount := 0
+	for _, product := range products {
+		if len(product.Options) > optionCount {
+			optionCount = len(product.Options)
+		}
+	}
+	header := append([]string(nil), standardColumns...)
+	for i := 1; i <= optionCount; i++ {
+		header = append(header, fmt.Sprintf("option%d_name", i), fmt.Sprintf("option%d_value", i))
+	}
+	position := make(map[string]int, len(header))
+	for i, column := range header {
+		position[column] = i
+	}
+
+	rows := [][]string{header}
+	for _, product := range products {
+		first := make([]string, len(header))
+		first[position[columnID]] = strconv.FormatUint(uint64(product.ID), 10)
+		if product.ExternalID != nil {
+			first[position[columnExternalID]] = *product.ExternalID
+		}
+		first[position[columnName]] = product.Name
+		first[position[columnDescription]] = product.Description
+		if product.CategoryID != nil {
+			first[position[columnCategory]] = categoryPaths[*product.CategoryID]
+		}
+		first[position[columnStatus]] = product.Status
+		first[position[columnTags]] = strings.Join(product.Tags, listSeparator)
+		first[position[columnImages]] = strings.Join(product.Images, listSeparator)
+		first[position[columnLowStock]] = strconv.Itoa(product.LowStockThreshold)
+
+		if len(product.SKUs) == 0 {
+			first[position[columnPrice]] = product.Price.StringFixed(2)
+			first[position[columnStock]] = strconv.Itoa(product.Stock)
+			rows = append(rows, first)
+			continue
+		}
+
+		for i, sku := range product.SKUs {
+			row := first
+			if i > 0 {
+				// 后续行只保留分组用的编码
+				row = make([]string, len(header))
+				row[position[columnID]] = first[position[columnID]]
+				row[position[columnExternalID]] = first[position[columnExternalID]]
+			}
+			row[position[columnSKU]] = sku.Code
+			row[position[columnPrice]] = sku.Price.StringFixed(2)
+			row[position[columnStock]] = strconv.Itoa(sku.Stock)
+			row[position[columnBarcode]] = sku.Barcode
+			row[position[columnSKUImage]] = sku.Image
+			row[position[columnSKUStatus]] = sku.Status
+			for j, option := range product.Options {
+				row[position[fmt.Sprintf("option%d_name", j+1)]] = option.Name
+				row[position[fmt.Sprintf("option%d_value", j+1)]] = sku.Options[option.Name]
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	return spreadsheet.Write(format, rows)
+}
+
+// categoryPathNames 生成所有类目的名称路径，如 数码 > 手机
+func (s *Service) categoryPathNames() (map[uint]string, error) {
+	categories, err := s.repoFactory.GetCategoryRepository().ListAll(false)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uint]string, len(categories))
+	for _, category := range categories {
+		names[category.ID] = category.Name
+	}
+
+	paths := make(map[uint]string, len(categories))
+	for _, category := range categories {
+		parts := make([]string, 0, category.Level+1)
+		for _, id := range categoryPathIDs(category.Path) {
+			parts = append(parts, names[id])
+		}
+		paths[category.ID] = strings.Join(parts, " "+categorySeparator+" ")
+	}
+	return paths, nil
+}
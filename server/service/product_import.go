@@ -0,0 +1,885 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"shopify/models"
+	"shopify/pkg/spreadsheet"
+
+	"github.com/shopspring/decimal"
+)
+
+// 标准导入导出格式的列名
+const (
+	columnID          = "id"
+	columnExternalID  = "external_id"
+	columnName        = "name"
+	columnDescription = "description"
+	columnCategory    = "category"
+	columnStatus      = "status"
+	columnTags        = "tags"
+	columnImages      = "images"
+	columnLowStock    = "low_stock_threshold"
+	columnSKU         = "sku"
+	columnPrice       = "price"
+	columnStock       = "stock"
+	columnBarcode     = "barcode"
+	columnSKUImage    = "sku_image"
+	columnSKUStatus   = "sku_status"
+)
+
+// standardColumns 标准格式的固定列，规格列 optionN_name/optionN_value 追加在后面
+var standardColumns = []string{
+	columnID, columnExternalID, columnName, columnDescription, columnCategory, columnStatus,
+	columnTags, columnImages, columnLowStock, columnSKU, columnPrice, columnStock,
+	columnBarcode, columnSKUImage, columnSKUStatus,
+}
+
+// optionColumnPattern 规格列，如 option1_name、option1_value
+var optionColumnPattern = regexp.MustCompile(`^option(\d+)_(name|value)$`)
+
+const (
+	// listSeparator 标签、图片等多值列的分隔符
+	listSeparator = "|"
+	// categorySeparator 类目路径分隔符，如 数码 > 手机
+	categorySeparator = ">"
+	// maxImportErrors 单个任务最多记录的错误数
+	maxImportErrors = 1000
+	// maxImportRows 单个文件最多的数据行数
+	maxImportRows = 20000
+	// importStockRemark 导入写入库存时的流水备注
+	importStockRemark = "批量导入"
+)
+
+// ImportOptions 导入选项
+type ImportOptions struct {
+	DryRun           bool // 仅校验并输出报告，不写入数据
+	CreateCategories bool // 类目不存在时自动创建
+	FetchImages      bool // 下载远程图片到文件存储，否则按原地址保存
+}
+
+// importOption 一个规格项及取值
+type importOption struct {
+	Name  string
+	Value string
+}
+
+// importVariant 导入文件中的一个SKU
+type importVariant struct {
+	Row     int
+	SKU     string
+	Options []importOption
+	Price   *decimal.Decimal
+	Stock   *int
+	Barcode string
+	Image   string
+	Status  string
+}
+
+// importProduct 按商品聚合后的导入数据，空值表示不修改
+type importProduct struct {
+	Row               int // 商品第一行的行号
+	ID                uint
+	ExternalID        string
+	Name              string
+	Description       string
+	Category          string
+	Status            string
+	Tags              []string
+	Images            []string
+	LowStockThreshold *int
+	Price             *decimal.Decimal
+	Stock             *int
+	Variants          []importVariant
+	Errors            []models.ImportError // 解析阶段的错误，存在时整个商品跳过
+}
+
+func (p *importProduct) addError(row int, column, message string) {
+	p.Errors = append(p.Errors, models.ImportError{Row: row, Column: column, Message: message})
+}
+
+// importParser 将表格行解析为商品，返回商品列表和不影响导入的提示
+type importParser func(rows [][]string) ([]*importProduct, []models.ImportError, error)
+
+// importParsers 按来源格式注册的解析器
+var importParsers = map[string]importParser{
+	models.ImportSourceStandard: parseStandardRows,
+	models.ImportSourceShopify:  parseShopifyRows,
+}
+
+// ValidImportSource 判断导入来源格式是否受支持
+func ValidImportSource(source string) bool {
+	_, ok := importParsers[source]
+	return ok
+}
+
+// sheetRow 按列名读取一行数据
+type sheetRow struct {
+	index  map[string]int
+	values []string
+}
+
+func (r sheetRow) get(column string) string {
+	i, ok := r.index[column]
+	if !ok || i >= len(r.values) {
+		return ""
+	}
+	return strings.TrimSpace(r.values[i])
+}
+
+func (r sheetRow) blank() bool {
+	for _, value := range r.values {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// headerIndex 建立列名到列号的映射，列名忽略大小写和首尾空格
+func headerIndex(header []string) map[string]int {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, exists := index[name]; name != "" && !exists {
+			index[name] = i
+		}
+	}
+	return index
+}
+
+// splitList 拆分多值列
+func splitList(value, separator string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, separator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseImportPrice 解析价格，必须为正数
+func parseImportPrice(value string) (*decimal.Decimal, error) {
+	price, err := decimal.NewFromString(value)
+	if err != nil || !price.IsPositive() {
+		return nil, errors.New("price must be a positive number")
+	}
+	price = price.Round(2)
+	return &price, nil
+}
+
+// parseImportInt 解析非负整数
+func parseImportInt(value, column string) (*int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 0 {
+		return nil, fmt.Errorf("%s must be a non-negative integer", column)
+	}
+	return &number, nil
+}
+
+// parseStandardRows 解析标准格式
+// 同一 id 或 external_id 的多行合并为一个商品的多个SKU，商品字段取第一个非空值；
+// 两者都为空的行各自作为一个商品
+func parseStandardRows(rows [][]string) ([]*importProduct, []models.ImportError, error) {
+	if len(rows) == 0 {
+		return nil, nil, errors.New("file is empty")
+	}
+	index := headerIndex(rows[0])
+	warnings := make([]models.ImportError, 0)
+
+	known := make(map[string]bool, len(standardColumns))
+	for _, column := range standardColumns {
+		known[column] = true
+	}
+	optionNumbers := make(map[int]bool)
+	for name := range index {
+		if known[name] {
+			continue
+		}
+		if match := optionColumnPattern.FindStringSubmatch(name); match != nil {
+			number, _ := strconv.Atoi(match[1])
+			optionNumbers[number] = true
+			continue
+		}
+		warnings = append(warnings, models.ImportError{Row: 1, Column: name, Message: "unknown column ignored"})
+	}
+	if _, ok := index[columnName]; !ok {
+		if _, ok := index[columnExternalID]; !ok {
+			if _, ok := index[columnID]; !ok {
+				if _, ok := index[columnSKU]; !ok {
+					return nil, nil, errors.New("header must contain one of id, external_id, sku or name")
+				}
+			}
+		}
+	}
+	numbers := make([]int, 0, len(optionNumbers))
+	for number := range optionNumbers {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+
+	products := make([]*importProduct, 0)
+	groups := make(map[string]*importProduct)
+	for i := 1; i < len(rows); i++ {
+		rowNumber := i + 1
+		row := sheetRow{index: index, values: rows[i]}
+		if row.blank() {
+			continue
+		}
+
+		key := fmt.Sprintf("row:%d", rowNumber)
+		if id := row.get(columnID); id != "" {
+			key = "id:" + id
+		} else if externalID := row.get(columnExternalID); externalID != "" {
+			key = "ext:" + externalID
+		}
+		product, exists := groups[key]
+		if !exists {
+			product = &importProduct{Row: rowNumber}
+			groups[key] = product
+			products = append(products, product)
+		}
+
+		if value := row.get(columnID); value != "" && product.ID == 0 {
+			id, err := strconv.ParseUint(value, 10, 32)
+			if err != nil || id == 0 {
+				product.addError(rowNumber, columnID, "id must be a positive integer")
+			} else {
+				product.ID = uint(id)
+			}
+		}
+		fillString(&product.ExternalID, row.get(columnExternalID))
+		fillString(&product.Name, row.get(columnName))
+		fillString(&product.Description, row.get(columnDescription))
+		fillString(&product.Category, row.get(columnCategory))
+		fillString(&product.Status, strings.ToLower(row.get(columnStatus)))
+		if value := row.get(columnTags); value != "" && product.Tags == nil {
+			product.Tags = splitList(value, listSeparator)
+		}
+		if value := row.get(columnImages); value != "" && product.Images == nil {
+			product.Images = splitList(value, listSeparator)
+		}
+		if value := row.get(columnLowStock); value != "" && product.LowStockThreshold == nil {
+			threshold, err := parseImportInt(value, columnLowStock)
+			if err != nil {
+				product.addError(rowNumber, columnLowStock, err.Error())
+			}
+			product.LowStockThreshold = threshold
+		}
+
+		// 规格列
+		options := make([]importOption, 0, len(numbers))
+		for _, number := range numbers {
+			nameColumn := fmt.Sprintf("option%d_name", number)
+			valueColumn := fmt.Sprintf("option%d_value", number)
+			name, value := row.get(nameColumn), row.get(valueColumn)
+			if name == "" && value == "" {
+				continue
+			}
+			if name == "" {
+				product.addError(rowNumber, nameColumn, "option name is required when value is set")
+				continue
+			}
+			if value == "" {
+				product.addError(rowNumber, valueColumn, "option value is required when name is set")
+				continue
+			}
+			options = append(options, importOption{Name: name, Value: value})
+		}
+
+		var price *decimal.Decimal
+		if value := row.get(columnPrice); value != "" {
+			parsed, err := parseImportPrice(value)
+			if err != nil {
+				product.addError(rowNumber, columnPrice, err.Error())
+			}
+			price = parsed
+		}
+		var stock *int
+		if value := row.get(columnStock); value != "" {
+			parsed, err := parseImportInt(value, columnStock)
+			if err != nil {
+				product.addError(rowNumber, columnStock, err.Error())
+			}
+			stock = parsed
+		}
+
+		sku := row.get(columnSKU)
+		if sku == "" && len(options) == 0 {
+			// 无规格商品
+			if exists && len(product.Variants) > 0 {
+				product.addError(rowNumber, columnSKU, "sku is required for additional rows of a product")
+				continue
+			}
+			if price != nil {
+				product.Price = price
+			}
+			if stock != nil {
+				product.Stock = stock
+			}
+			continue
+		}
+		if sku == "" {
+			product.addError(rowNumber, columnSKU, "sku is required when options are set")
+			continue
+		}
+		product.Variants = append(product.Variants, importVariant{
+			Row:     rowNumber,
+			SKU:     sku,
+			Options: options,
+			Price:   price,
+			Stock:   stock,
+			Barcode: row.get(columnBarcode),
+			Image:   row.get(columnSKUImage),
+			Status:  strings.ToLower(row.get(columnSKUStatus)),
+		})
+	}
+
+	if len(products) == 0 {
+		return nil, nil, errors.New("file has no data rows")
+	}
+	return products, warnings, nil
+}
+
+// fillString 目标为空时使用新值
+func fillString(target *string, value string) {
+	if *target == "" {
+		*target = value
+	}
+}
+
+type ImportService struct {
+	*Service
+}
+
+func NewImportService(base *Service) *ImportService {
+	return &ImportService{Service: base}
+}
+
+// StartImport 解析上传的文件并创建后台导入任务，返回的任务可用于轮询进度
+func (s *ImportService) StartImport(source, fileName string, data []byte, options ImportOptions, operatorID uint) (*models.ImportJob, error) {
+	parser, ok := importParsers[source]
+	if !ok {
+		return nil, errors.New("unsupported import source")
+	}
+	format, err := spreadsheet.DetectFormat(fileName)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := spreadsheet.Read(format, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > maxImportRows+1 {
+		return nil, fmt.Errorf("file has too many rows, at most %d are allowed", maxImportRows)
+	}
+	products, warnings, err := parser(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &models.ImportJob{
+		Source:     source,
+		Format:     format,
+		FileName:   fileName,
+		DryRun:     options.DryRun,
+		Status:     models.ImportStatusPending,
+		Total:      len(products),
+		Errors:     make([]models.ImportError, 0),
+		Warnings:   warnings,
+		OperatorID: operatorID,
+	}
+	if err := s.repoFactory.GetImportRepository().Create(job); err != nil {
+		return nil, err
+	}
+
+	go s.runImport(job, products, options)
+	return job, nil
+}
+
+// GetImportJob 获取导入任务的进度和报告
+func (s *ImportService) GetImportJob(id uint) (*models.ImportJob, error) {
+	return s.repoFactory.GetImportRepository().GetByID(id)
+}
+
+// ListImportJobs 获取导入任务列表
+func (s *ImportService) ListImportJobs(page, pageSize int) ([]models.ImportJob, int64, error) {
+	return s.repoFactory.GetImportRepository().List(page, pageSize)
+}
+
+// FailInterruptedJobs 服务重启后将中断的任务标记为失败
+func (s *ImportService) FailInterruptedJobs() error {
+	count, err := s.repoFactory.GetImportRepository().FailUnfinished("interrupted by server restart")
+	if count > 0 {
+		log.Printf("已将 %d 个中断的导入任务标记为失败", count)
+	}
+	return err
+}
+
+// runImport 逐个商品执行导入并记录进度，每个商品独立生效，失败的商品不影响其他商品
+func (s *ImportService) runImport(job *models.ImportJob, products []*importProduct, options ImportOptions) {
+	importRepo := s.repoFactory.GetImportRepository()
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("导入任务 %d 异常终止: %v", job.ID, r)
+			job.Status = models.ImportStatusFailed
+			job.Message = fmt.Sprintf("internal error: %v", r)
+			now := time.Now()
+			job.FinishedAt = &now
+			if err := importRepo.Save(job); err != nil {
+				log.Printf("保存导入任务 %d 失败: %v", job.ID, err)
+			}
+		}
+	}()
+
+	now := time.Now()
+	job.Status = models.ImportStatusRunning
+	job.StartedAt = &now
+	if err := importRepo.Save(job); err != nil {
+		log.Printf("保存导入任务 %d 失败: %v", job.ID, err)
+	}
+
+	run := &importRun{
+		ImportService: s,
+		options:       options,
+		operatorID:    job.OperatorID,
+		skuOwners:     make(map[string]int),
+		categories:    make(map[string]*models.Category),
+	}
+	lastSaved := time.Now()
+	for i, product := range products {
+		created, errs := run.importProduct(i, product)
+		if len(errs) > 0 {
+			job.Failed++
+			for _, e := range errs {
+				if len(job.Errors) >= maxImportErrors {
+					break
+				}
+				job.Errors = append(job.Errors, e)
+			}
+		} else if created {
+			job.Created++
+		} else {
+			job.Updated++
+		}
+		job.Processed++
+
+		// 控制进度写入频率
+		if time.Since(lastSaved) >= time.Second {
+			if err := importRepo.Save(job); err != nil {
+				log.Printf("保存导入任务 %d 进度失败: %v", job.ID, err)
+			}
+			lastSaved = time.Now()
+		}
+	}
+	job.Warnings = append(job.Warnings, run.warnings...)
+
+	finished := time.Now()
+	job.Status = models.ImportStatusCompleted
+	job.FinishedAt = &finished
+	if err := importRepo.Save(job); err != nil {
+		log.Printf("保存导入任务 %d 失败: %v", job.ID, err)
+	}
+}
+
+// importRun 单个导入任务的执行上下文
+type importRun struct {
+	*ImportService
+	options    ImportOptions
+	operatorID uint
+	skuOwners  map[string]int              // 文件中SKU编码所属商品的序号，用于发现重复
+	categories map[string]*models.Category // 已解析的类目路径
+	warnings   []models.ImportError
+}
+
+// importProduct 校验并导入一个商品，返回是否为新建及错误列表
+func (r *importRun) importProduct(position int, p *importProduct) (bool, []models.ImportError) {
+	errs := append([]models.ImportError(nil), p.Errors...)
+	fail := func(row int, column, message string) {
+		errs = append(errs, models.ImportError{Row: row, Column: column, Message: message})
+	}
+
+	productRepo := r.repoFactory.GetProductRepository()
+	skuRepo := r.repoFactory.GetSKURepository()
+
+	// 匹配已有商品：id > external_id > SKU编码
+	var existing *models.Product
+	if p.ID != 0 {
+		product, err := productRepo.GetByID(p.ID)
+		if err != nil {
+			fail(p.Row, columnID, "product not found")
+			return false, errs
+		}
+		existing = product
+	}
+	if p.ExternalID != "" {
+		if len(p.ExternalID) > 64 {
+			fail(p.Row, columnExternalID, "external_id is too long")
+		} else if product, err := productRepo.GetByExternalID(p.ExternalID); err == nil {
+			if existing != nil && existing.ID != product.ID {
+				fail(p.Row, columnExternalID, fmt.Sprintf("external_id already belongs to product %d", product.ID))
+			} else {
+				existing = product
+			}
+		}
+	}
+
+	existingSKUs := make(map[string]*models.SKU)
+	for _, variant := range p.Variants {
+		if owner, ok := r.skuOwners[variant.SKU]; ok && owner != position {
+			fail(variant.Row, columnSKU, "duplicate sku in file: "+variant.SKU)
+			continue
+		}
+		r.skuOwners[variant.SKU] = position
+
+		sku, err := skuRepo.GetByCode(variant.SKU)
+		if err != nil {
+			continue
+		}
+		if existing == nil && p.ID == 0 && p.ExternalID == "" {
+			if product, err := productRepo.GetByID(sku.ProductID); err == nil {
+				existing = product
+			}
+		}
+		if existing == nil || sku.ProductID != existing.ID {
+			fail(variant.Row, columnSKU, fmt.Sprintf("sku %s already belongs to product %d", variant.SKU, sku.ProductID))
+			continue
+		}
+		existingSKUs[variant.SKU] = sku
+	}
+
+	// 商品字段校验
+	if existing == nil && p.Name == "" {
+		fail(p.Row, columnName, "name is required for new products")
+	}
+	if len([]rune(p.Name)) > 100 {
+		fail(p.Row, columnName, "name is too long")
+	}
+	if p.Status != "" && p.Status != "active" && p.Status != "inactive" {
+		fail(p.Row, columnStatus, "status must be active or inactive")
+	}
+	if existing == nil && len(p.Variants) == 0 && p.Price == nil {
+		fail(p.Row, columnPrice, "price is required for new products")
+	}
+	for _, image := range p.Images {
+		if !validImageURL(image) {
+			fail(p.Row, columnImages, "invalid image url: "+image)
+		}
+	}
+
+	var category *models.Category
+	if p.Category != "" {
+		resolved, err := r.resolveCategory(p.Category)
+		if err != nil {
+			fail(p.Row, columnCategory, err.Error())
+		}
+		category = resolved
+	}
+
+	// SKU校验
+	var hasSKUs bool
+	if existing != nil {
+		count, err := skuRepo.CountByProduct(existing.ID)
+		if err != nil {
+			fail(p.Row, "", err.Error())
+			return false, errs
+		}
+		hasSKUs = count > 0
+	}
+	options, skus, variantErrs := buildImportSKUs(p.Variants, existingSKUs)
+	errs = append(errs, variantErrs...)
+	if len(p.Variants) > 0 && existing != nil && !hasSKUs && existing.Stock > 0 {
+		fail(p.Row, columnSKU, "product stock must be adjusted to zero before adding skus")
+	}
+	if len(p.Variants) == 0 && hasSKUs && p.Stock != nil {
+		fail(p.Row, columnStock, "product has skus, stock must be set per sku")
+	}
+
+	if len(errs) > 0 || r.options.DryRun {
+		return existing == nil, errs
+	}
+
+	// 写入
+	images := p.Images
+	if r.options.FetchImages {
+		images = r.fetchImages(p.Row, columnImages, images)
+		for i := range skus {
+			if skus[i].Image != "" {
+				skus[i].Image = r.fetchImages(p.Variants[i].Row, columnSKUImage, []string{skus[i].Image})[0]
+			}
+		}
+	}
+
+	product := &models.Product{}
+	if existing != nil {
+		product = existing
+	}
+	if p.ExternalID != "" {
+		externalID := p.ExternalID
+		product.ExternalID = &externalID
+	}
+	if p.Name != "" {
+		product.Name = p.Name
+	}
+	if p.Description != "" {
+		product.Description = p.Description
+	}
+	if p.Status != "" {
+		product.Status = p.Status
+	}
+	if p.Tags != nil {
+		product.Tags = p.Tags
+	}
+	if images != nil {
+		product.Images = images
+	}
+	if p.LowStockThreshold != nil {
+		product.LowStockThreshold = *p.LowStockThreshold
+	}
+	if p.Price != nil {
+		product.Price = *p.Price
+	}
+	if category != nil {
+		product.CategoryID = &category.ID
+	}
+
+	productService := NewProductService(r.Service)
+	if existing == nil {
+		if len(skus) > 0 {
+			// 展示价格稍后由SKU最低价覆盖
+			product.Price = skus[0].Price
+		} else if p.Stock != nil {
+			product.Stock = *p.Stock
+		}
+		if err := productService.createProduct(product, models.InventoryReasonImport, importStockRemark, r.operatorID); err != nil {
+			fail(p.Row, "", err.Error())
+			return true, errs
+		}
+	} else {
+		// 未导入的字段已使用原值，除类目外整体写入
+		fields := []string{"name", "description", "price", "low_stock_threshold", "status", "images", "tags", "external_id"}
+		if category != nil {
+			fields = append(fields, "category_id")
+		}
+		if err := productService.UpdateProduct(product, fields, r.operatorID); err != nil {
+			fail(p.Row, "", err.Error())
+			return false, errs
+		}
+	}
+
+	inventoryService := NewInventoryService(r.Service)
+	if len(skus) > 0 {
+		if _, err := NewSKUService(r.Service).saveSKUs(product.ID, options, skus, models.InventoryReasonImport, importStockRemark, r.operatorID); err != nil {
+			fail(p.Row, columnSKU, err.Error())
+			return existing == nil, errs
+		}
+		// 已有SKU的库存按差额调整并记入流水
+		for _, variant := range p.Variants {
+			sku, ok := existingSKUs[variant.SKU]
+			if !ok || variant.Stock == nil || *variant.Stock == sku.Stock {
+				continue
+			}
+			if _, err := inventoryService.adjustStock(product.ID, 0, sku.ID, *variant.Stock-sku.Stock, models.InventoryReasonImport, importStockRemark, r.operatorID); err != nil {
+				fail(variant.Row, columnStock, err.Error())
+			}
+		}
+	} else if existing != nil && p.Stock != nil && *p.Stock != existing.Stock {
+		if _, err := inventoryService.adjustStock(product.ID, 0, 0, *p.Stock-existing.Stock, models.InventoryReasonImport, importStockRemark, r.operatorID); err != nil {
+			fail(p.Row, columnStock, err.Error())
+		}
+	}
+
+	return existing == nil, errs
+}
+
+// buildImportSKUs 根据导入的SKU行生成规格项和SKU列表，所有SKU必须使用相同的规格项
+func buildImportSKUs(variants []importVariant, existing map[string]*models.SKU) ([]models.ProductOption, []models.SKU, []models.ImportError) {
+	errs := make([]models.ImportError, 0)
+	if len(variants) == 0 {
+		return nil, nil, errs
+	}
+
+	names := make([]string, 0, len(variants[0].Options))
+	for _, option := range variants[0].Options {
+		names = append(names, option.Name)
+	}
+	values := make(map[string][]string, len(names))
+	seenValues := make(map[string]bool)
+
+	skus := make([]models.SKU, 0, len(variants))
+	rows := make([]int, 0, len(variants))
+	for _, variant := range variants {
+		if len(variant.Options) != len(names) {
+			errs = append(errs, models.ImportError{Row: variant.Row, Column: "option1_name", Message: "all skus of a product must use the same options"})
+			continue
+		}
+		optionValues := make(map[string]string, len(names))
+		consistent := true
+		for i, option := range variant.Options {
+			if option.Name != names[i] {
+				consistent = false
+				break
+			}
+			optionValues[option.Name] = option.Value
+			if key := option.Name + "\x00" + option.Value; !seenValues[key] {
+				seenValues[key] = true
+				values[option.Name] = append(values[option.Name], option.Value)
+			}
+		}
+		if !consistent {
+			errs = append(errs, models.ImportError{Row: variant.Row, Column: "option1_name", Message: "all skus of a product must use the same options in the same order"})
+			continue
+		}
+		if variant.Status != "" && variant.Status != "active" && variant.Status != "inactive" {
+			errs = append(errs, models.ImportError{Row: variant.Row, Column: columnSKUStatus, Message: "sku_status must be active or inactive"})
+			continue
+		}
+		if variant.Image != "" && !validImageURL(variant.Image) {
+			errs = append(errs, models.ImportError{Row: variant.Row, Column: columnSKUImage, Message: "invalid image url: " + variant.Image})
+			continue
+		}
+
+		sku := models.SKU{
+			Code:    variant.SKU,
+			Options: optionValues,
+			Barcode: variant.Barcode,
+			Image:   variant.Image,
+			Status:  variant.Status,
+		}
+		if current, ok := existing[variant.SKU]; ok {
+			// 已有SKU未填写的字段保持不变
+			sku.ID = current.ID
+			sku.Price = current.Price
+			sku.Stock = current.Stock
+			if sku.Barcode == "" {
+				sku.Barcode = current.Barcode
+			}
+			if sku.Image == "" {
+				sku.Image = current.Image
+			}
+			if sku.Status == "" {
+				sku.Status = current.Status
+			}
+		} else if variant.Price == nil {
+			errs = append(errs, models.ImportError{Row: variant.Row, Column: columnPrice, Message: "price is required for new skus"})
+			continue
+		} else if variant.Stock != nil {
+			sku.Stock = *variant.Stock
+		}
+		if variant.Price != nil {
+			sku.Price = *variant.Price
+		}
+		skus = append(skus, sku)
+		rows = append(rows, variant.Row)
+	}
+
+	options := make([]models.ProductOption, 0, len(names))
+	for i, name := range names {
+		options = append(options, models.ProductOption{Name: name, Values: values[name], SortOrder: i})
+	}
+
+	// 与保存SKU时相同的校验，保证试运行的报告与实际导入一致
+	if err := validateOptions(options); err != nil {
+		errs = append(errs, models.ImportError{Row: variants[0].Row, Column: "option1_name", Message: err.Error()})
+		return options, skus, errs
+	}
+	seenKeys := make(map[string]bool, len(skus))
+	for i := range skus {
+		if err := validateSKU(options, &skus[i]); err != nil {
+			errs = append(errs, models.ImportError{Row: rows[i], Column: columnSKU, Message: err.Error()})
+			continue
+		}
+		key := skuKey(options, skus[i].Options)
+		if seenKeys[key] {
+			errs = append(errs, models.ImportError{Row: rows[i], Column: "option1_value", Message: "duplicate option combination: " + key})
+		}
+		seenKeys[key] = true
+	}
+	return options, skus, errs
+}
+
+// validImageURL 图片地址必须是 http(s) 地址或站内路径
+func validImageURL(url string) bool {
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "/")
+}
+
+// resolveCategory 解析类目，支持类目ID或以 > 分隔的类目名称路径
+// 开启自动创建时逐级创建缺失的类目，试运行时只做提示
+func (r *importRun) resolveCategory(value string) (*models.Category, error) {
+	if category, ok := r.categories[value]; ok {
+		return category, nil
+	}
+	categoryRepo := r.repoFactory.GetCategoryRepository()
+
+	if id, err := strconv.ParseUint(value, 10, 32); err == nil {
+		category, err := categoryRepo.GetByID(uint(id))
+		if err != nil {
+			return nil, fmt.Errorf("category %s not found", value)
+		}
+		r.categories[value] = category
+		return category, nil
+	}
+
+	names := splitList(value, categorySeparator)
+	if len(names) == 0 {
+		return nil, errors.New("invalid category path")
+	}
+	var parent *models.Category
+	for i, name := range names {
+		var parentID *uint
+		if parent != nil {
+			parentID = &parent.ID
+		}
+		category, err := categoryRepo.GetByName(parentID, name)
+		if err == nil {
+			parent = category
+			continue
+		}
+		missing := strings.Join(names[:i+1], " "+categorySeparator+" ")
+		if !r.options.CreateCategories {
+			return nil, fmt.Errorf("category %s not found", missing)
+		}
+		if r.options.DryRun {
+			// 试运行不创建类目，后续商品复用同一结果
+			r.warnings = append(r.warnings, models.ImportError{Column: columnCategory, Message: "category will be created: " + missing})
+			r.categories[value] = nil
+			return nil, nil
+		}
+		category = &models.Category{ParentID: parentID, Name: name}
+		if err := NewCategoryService(r.Service).CreateCategory(category); err != nil {
+			return nil, err
+		}
+		parent = category
+	}
+	r.categories[value] = parent
+	return parent, nil
+}
+
+// fetchImages 下载远程图片到文件存储，失败时保留原地址并记录提示
+func (r *importRun) fetchImages(row int, column string, urls []string) []string {
+	media := NewMediaService(r.Service)
+	result := make([]string, 0, len(urls))
+	for _, url := range urls {
+		if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			result = append(result, url)
+			continue
+		}
+		file, err := media.Fetch(url, models.MediaPurposeProduct, r.operatorID)
+		if err != nil {
+			r.warnings = append(r.warnings, models.ImportError{Row: row, Column: column, Message: fmt.Sprintf("image %s kept as url: %v", url, err)})
+			result = append(result, url)
+			continue
+		}
+		result = append(result, file.URL)
+	}
+	return result
+}
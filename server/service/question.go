@@ -0,0 +1,334 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"shopify/models"
+	"shopify/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNotVerifiedBuyer 只有管理员和已完成订单的购买者可以回答
+	ErrNotVerifiedBuyer = errors.New("only verified buyers can answer questions")
+	// ErrAlreadyVoted 已点赞过该回答
+	ErrAlreadyVoted = repository.ErrAlreadyVoted
+)
+
+const (
+	maxQuestionLength = 500  // 问题最大字数
+	maxAnswerLength   = 2000 // 回答最大字数
+	topQuestions      = 3    // 商品详情中展示的问题数
+	topAnswers        = 2    // 商品详情中每个问题展示的回答数
+)
+
+type QuestionService struct {
+	*Service
+}
+
+func NewQuestionService(base *Service) *QuestionService {
+	return &QuestionService{Service: base}
+}
+
+// validQAStatus 判断审核状态是否合法
+func validQAStatus(status string) bool {
+	switch status {
+	case models.QAStatusPending, models.QAStatusApproved, models.QAStatusRejected:
+		return true
+	}
+	return false
+}
+
+// qaContent 校验问答内容长度
+func qaContent(content string, maxLength int) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", errors.New("content is required")
+	}
+	if utf8.RuneCountInString(content) > maxLength {
+		return "", fmt.Errorf("content exceeds %d characters", maxLength)
+	}
+	return content, nil
+}
+
+// notifyQAPending 提醒管理员审核新的问答，通知失败只记录日志
+func (s *Service) notifyQAPending(productID uint, content string) {
+	_, err := NewNotificationService(s).Notify(models.NotificationTypeQAPending,
+		fmt.Sprintf("商品 #%d 有新的问答待审核", productID), content, productID)
+	if err != nil {
+		log.Printf("failed to create q&a moderation notification: %v", err)
+	}
+}
+
+// attachAnswers 为问题填充已审核的回答，limit 大于 0 时每个问题只保留前 limit 个回答
+// userID 不为 0 时标记该用户已点赞的回答
+func (s *Service) attachAnswers(questions []models.ProductQuestion, userID uint, limit int) error {
+	questionIDs := make([]uint, 0, len(questions))
+	for _, question := range questions {
+		questionIDs = append(questionIDs, question.ID)
+	}
+	questionRepo := s.repoFactory.GetQuestionRepository()
+	answers, err := questionRepo.ListApprovedAnswers(questionIDs)
+	if err != nil {
+		return err
+	}
+
+	voted := make(map[uint]bool)
+	if userID != 0 {
+		answerIDs := make([]uint, 0, len(answers))
+		for _, answer := range answers {
+			answerIDs = append(answerIDs, answer.ID)
+		}
+		ids, err := questionRepo.ListVotedAnswerIDs(userID, answerIDs)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			voted[id] = true
+		}
+	}
+
+	byQuestion := make(map[uint][]models.ProductAnswer, len(questions))
+	for _, answer := range answers {
+		if limit > 0 && len(byQuestion[answer.QuestionID]) >= limit {
+			continue
+		}
+		answer.Upvoted = voted[answer.ID]
+		byQuestion[answer.QuestionID] = append(byQuestion[answer.QuestionID], answer)
+	}
+	for i := range questions {
+		questions[i].Answers = byQuestion[questions[i].ID]
+		if questions[i].Answers == nil {
+			questions[i].Answers = make([]models.ProductAnswer, 0)
+		}
+	}
+	return nil
+}
+
+// topProductQuestions 商品详情中展示的热门问答
+func (s *Service) topProductQuestions(productID uint) ([]models.ProductQuestion, error) {
+	questions, _, err := s.repoFactory.GetQuestionRepository().ListApprovedQuestions(productID, 1, topQuestions)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachAnswers(questions, 0, topAnswers); err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
+
+// AskQuestion 提交商品问题，审核通过后在前台展示
+func (s *QuestionService) AskQuestion(productID, userID uint, content string) (*models.ProductQuestion, error) {
+	content, err := qaContent(content, maxQuestionLength)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repoFactory.GetProductRepository().GetByID(productID); err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	question := &models.ProductQuestion{
+		ProductID: productID,
+		UserID:    userID,
+		Content:   content,
+		Status:    models.QAStatusPending,
+	}
+	if err := s.repoFactory.GetQuestionRepository().CreateQuestion(question); err != nil {
+		return nil, err
+	}
+	s.notifyQAPending(productID, content)
+	return question, nil
+}
+
+// ListProductQuestions 分页获取商品已审核的问答，userID 不为 0 时标记该用户已点赞的回答
+func (s *QuestionService) ListProductQuestions(productID, userID uint, page, pageSize int) ([]models.ProductQuestion, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 50 {
+		pageSize = 10
+	}
+	questions, total, err := s.repoFactory.GetQuestionRepository().ListApprovedQuestions(productID, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.attachAnswers(questions, userID, 0); err != nil {
+		return nil, 0, err
+	}
+	return questions, total, nil
+}
+
+// AnswerQuestion 回答已审核的问题
+// 管理员的回答作为官方回答直接展示，已购买用户的回答需审核
+func (s *QuestionService) AnswerQuestion(questionID, userID uint, isAdmin bool, content string) (*models.ProductAnswer, error) {
+	content, err := qaContent(content, maxAnswerLength)
+	if err != nil {
+		return nil, err
+	}
+
+	questionRepo := s.repoFactory.GetQuestionRepository()
+	question, err := questionRepo.GetQuestion(questionID)
+	if err != nil || question.Status != models.QAStatusApproved {
+		return nil, errors.New("question not found")
+	}
+
+	verified, err := questionRepo.IsVerifiedBuyer(userID, question.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin && !verified {
+		return nil, ErrNotVerifiedBuyer
+	}
+
+	answer := &models.ProductAnswer{
+		QuestionID:      questionID,
+		UserID:          userID,
+		Content:         content,
+		IsAdmin:         isAdmin,
+		IsVerifiedBuyer: verified,
+		Status:          models.QAStatusPending,
+	}
+	if isAdmin {
+		answer.Status = models.QAStatusApproved
+	}
+
+	err = s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		txQuestionRepo := repository.NewRepositoryFactory(tx).GetQuestionRepository()
+		if err := txQuestionRepo.CreateAnswer(answer); err != nil {
+			return err
+		}
+		return txQuestionRepo.RefreshAnswerCount(questionID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin {
+		s.notifyQAPending(question.ProductID, content)
+	}
+	return answer, nil
+}
+
+// UpvoteAnswer 点赞已审核的回答，不能给自己的回答点赞
+func (s *QuestionService) UpvoteAnswer(answerID, userID uint) (*models.ProductAnswer, error) {
+	answer, err := s.repoFactory.GetQuestionRepository().GetAnswer(answerID)
+	if err != nil || answer.Status != models.QAStatusApproved {
+		return nil, errors.New("answer not found")
+	}
+	if answer.UserID == userID {
+		return nil, errors.New("cannot upvote your own answer")
+	}
+
+	err = s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		return repository.NewRepositoryFactory(tx).GetQuestionRepository().AddVote(answerID, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	answer.Upvotes++
+	answer.Upvoted = true
+	return answer, nil
+}
+
+// RemoveUpvote 取消点赞
+func (s *QuestionService) RemoveUpvote(answerID, userID uint) error {
+	if _, err := s.repoFactory.GetQuestionRepository().GetAnswer(answerID); err != nil {
+		return errors.New("answer not found")
+	}
+	return s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		return repository.NewRepositoryFactory(tx).GetQuestionRepository().RemoveVote(answerID, userID)
+	})
+}
+
+// ListQuestionQueue 获取问题审核队列，status 为空时返回待审核的问题
+func (s *QuestionService) ListQuestionQueue(status string, page, pageSize int) ([]models.ProductQuestion, int64, error) {
+	if status == "" {
+		status = models.QAStatusPending
+	}
+	if !validQAStatus(status) {
+		return nil, 0, errors.New("invalid status")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return s.repoFactory.GetQuestionRepository().ListQuestionsByStatus(status, page, pageSize)
+}
+
+// ListAnswerQueue 获取回答审核队列，status 为空时返回待审核的回答
+func (s *QuestionService) ListAnswerQueue(status string, page, pageSize int) ([]models.ProductAnswer, int64, error) {
+	if status == "" {
+		status = models.QAStatusPending
+	}
+	if !validQAStatus(status) {
+		return nil, 0, errors.New("invalid status")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return s.repoFactory.GetQuestionRepository().ListAnswersByStatus(status, page, pageSize)
+}
+
+// ModerateQuestion 审核问题
+func (s *QuestionService) ModerateQuestion(questionID uint, status string) error {
+	if !validQAStatus(status) {
+		return errors.New("invalid status")
+	}
+	questionRepo := s.repoFactory.GetQuestionRepository()
+	if _, err := questionRepo.GetQuestion(questionID); err != nil {
+		return errors.New("question not found")
+	}
+	return questionRepo.UpdateQuestionStatus(questionID, status)
+}
+
+// ModerateAnswer 审核回答并更新问题的回答数量
+func (s *QuestionService) ModerateAnswer(answerID uint, status string) error {
+	if !validQAStatus(status) {
+		return errors.New("invalid status")
+	}
+	answer, err := s.repoFactory.GetQuestionRepository().GetAnswer(answerID)
+	if err != nil {
+		return errors.New("answer not found")
+	}
+	return s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		questionRepo := repository.NewRepositoryFactory(tx).GetQuestionRepository()
+		if err := questionRepo.UpdateAnswerStatus(answerID, status); err != nil {
+			return err
+		}
+		return questionRepo.RefreshAnswerCount(answer.QuestionID)
+	})
+}
+
+// DeleteQuestion 删除问题及其全部回答
+func (s *QuestionService) DeleteQuestion(questionID uint) error {
+	if _, err := s.repoFactory.GetQuestionRepository().GetQuestion(questionID); err != nil {
+		return errors.New("question not found")
+	}
+	return s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		return repository.NewRepositoryFactory(tx).GetQuestionRepository().DeleteQuestion(questionID)
+	})
+}
+
+// DeleteAnswer 删除回答并更新问题的回答数量
+func (s *QuestionService) DeleteAnswer(answerID uint) error {
+	answer, err := s.repoFactory.GetQuestionRepository().GetAnswer(answerID)
+	if err != nil {
+		return errors.New("answer not found")
+	}
+	return s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		questionRepo := repository.NewRepositoryFactory(tx).GetQuestionRepository()
+		if err := questionRepo.DeleteAnswer(answerID); err != nil {
+			return err
+		}
+		return questionRepo.RefreshAnswerCount(answer.QuestionID)
+	})
+}
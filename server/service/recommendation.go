@@ -0,0 +1,284 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"shopify/config"
+	"shopify/models"
+	"shopify/repository"
+
+	"gorm.io/gorm"
+)
+
+// 相关推荐来源常量
+const (
+	RelatedSourcePinned         = "pinned"          // 运营置顶
+	RelatedSourceBoughtTogether = "bought_together" // 经常一起购买
+	RelatedSourceBestSeller     = "best_seller"     // 同类目热销
+)
+
+// maxRelatedLimit 相关推荐单次返回数量上限
+const maxRelatedLimit = 20
+
+// RelatedProduct 相关推荐商品
+type RelatedProduct struct {
+	models.Product
+	Source string `json:"source"` // 推荐来源：置顶/一起购买/同类目热销
+}
+
+// ProductRelations 商品的推荐配置，包含人工关联和计算出的共现关系
+type ProductRelations struct {
+	Relations    []models.ProductRelation    `json:"relations"`    // 人工置顶和屏蔽
+	Associations []models.ProductAssociation `json:"associations"` // 根据订单计算的一起购买关系
+}
+
+type RecommendationService struct {
+	*Service
+}
+
+func NewRecommendationService(base *Service) *RecommendationService {
+	return &RecommendationService{Service: base}
+}
+
+// RecomputeAssociations 根据统计窗口内已支付订单重新计算商品共现关系
+// 关联度为共同订单数除以两者各自订单数的几何平均，每个商品只保留关联度最高的若干个
+func (s *RecommendationService) RecomputeAssociations() error {
+	cfg := config.GlobalConfig.Recommendation
+	since := time.Now().AddDate(0, 0, -cfg.WindowDays)
+	recommendationRepo := s.repoFactory.GetRecommendationRepository()
+
+	minOrders := cfg.MinOrders
+	if minOrders < 1 {
+		minOrders = 1
+	}
+	pairs, err := recommendationRepo.CountCoOccurrences(since, minOrders)
+	if err != nil {
+		return err
+	}
+	counts, err := recommendationRepo.CountProductOrders(since)
+	if err != nil {
+		return err
+	}
+
+	orders := make(map[uint]int, len(counts))
+	for _, count := range counts {
+		orders[count.ProductID] = count.Orders
+	}
+
+	byProduct := make(map[uint][]models.ProductAssociation)
+	for _, pair := range pairs {
+		denominator := math.Sqrt(float64(orders[pair.ProductID]) * float64(orders[pair.AssociatedID]))
+		if denominator == 0 {
+			continue
+		}
+		byProduct[pair.ProductID] = append(byProduct[pair.ProductID], models.ProductAssociation{
+			ProductID:    pair.ProductID,
+			AssociatedID: pair.AssociatedID,
+			Orders:       pair.Orders,
+			Score:        math.Round(float64(pair.Orders)/denominator*10000) / 10000,
+		})
+	}
+
+	associations := make([]models.ProductAssociation, 0, len(pairs))
+	for _, list := range byProduct {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Score != list[j].Score {
+				return list[i].Score > list[j].Score
+			}
+			if list[i].Orders != list[j].Orders {
+				return list[i].Orders > list[j].Orders
+			}
+			return list[i].AssociatedID < list[j].AssociatedID
+		})
+		if cfg.MaxPerProduct > 0 && len(list) > cfg.MaxPerProduct {
+			list = list[:cfg.MaxPerProduct]
+		}
+		associations = append(associations, list...)
+	}
+
+	err = s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		return repository.NewRepositoryFactory(tx).GetRecommendationRepository().ReplaceAssociations(associations)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("商品关联计算完成，共 %d 个商品 %d 条关联", len(byProduct), len(associations))
+	return nil
+}
+
+// RelatedProducts 获取商品的相关推荐
+// 依次取运营置顶、经常一起购买、同类目热销，跳过屏蔽、下架和无库存的商品
+func (s *RecommendationService) RelatedProducts(productID uint, limit int) ([]RelatedProduct, error) {
+	if limit <= 0 {
+		limit = config.GlobalConfig.Recommendation.Limit
+	}
+	if limit > maxRelatedLimit {
+		limit = maxRelatedLimit
+	}
+
+	productRepo := s.repoFactory.GetProductRepository()
+	recommendationRepo := s.repoFactory.GetRecommendationRepository()
+
+	product, err := productRepo.GetByID(productID)
+	if err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	relations, err := recommendationRepo.ListRelations(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 已选中或屏蔽的商品不再重复推荐
+	skip := map[uint]bool{productID: true}
+	var pinnedIDs []uint
+	for _, relation := range relations {
+		if relation.Status == models.ProductRelationPinned {
+			pinnedIDs = append(pinnedIDs, relation.RelatedID)
+		} else {
+			skip[relation.RelatedID] = true
+		}
+	}
+
+	result := make([]RelatedProduct, 0, limit)
+	appendProducts := func(ids []uint, source string, requireStock bool) error {
+		if len(result) >= limit || len(ids) == 0 {
+			return nil
+		}
+		products, err := productRepo.ListByIDs(ids)
+		if err != nil {
+			return err
+		}
+		byID := make(map[uint]models.Product, len(products))
+		for _, p := range products {
+			byID[p.ID] = p
+		}
+		// 按传入顺序输出，保持置顶排序和关联度排序
+		for _, id := range ids {
+			p, ok := byID[id]
+			if !ok || skip[id] || p.Status != "active" || (requireStock && p.Stock <= 0) {
+				continue
+			}
+			skip[id] = true
+			result = append(result, RelatedProduct{Product: p, Source: source})
+			if len(result) >= limit {
+				break
+			}
+		}
+		return nil
+	}
+
+	// 运营置顶的商品即使暂时缺货也展示
+	if err := appendProducts(pinnedIDs, RelatedSourcePinned, false); err != nil {
+		return nil, err
+	}
+
+	associations, err := recommendationRepo.ListAssociations(productID, config.GlobalConfig.Recommendation.MaxPerProduct)
+	if err != nil {
+		return nil, err
+	}
+	associatedIDs := make([]uint, 0, len(associations))
+	for _, association := range associations {
+		associatedIDs = append(associatedIDs, association.AssociatedID)
+	}
+	if err := appendProducts(associatedIDs, RelatedSourceBoughtTogether, true); err != nil {
+		return nil, err
+	}
+
+	if len(result) < limit && (product.CategoryID != nil || product.Category != "") {
+		query := ProductQuery{
+			Status:   "active",
+			InStock:  true,
+			Sort:     repository.ProductSortSales,
+			Page:     1,
+			PageSize: limit + len(skip),
+		}
+		if product.CategoryID != nil {
+			query.CategoryIDs = []uint{*product.CategoryID}
+		} else {
+			query.Category = product.Category
+		}
+		bestSellers, _, err := productRepo.Query(query)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]uint, 0, len(bestSellers))
+		for _, p := range bestSellers {
+			ids = append(ids, p.ID)
+		}
+		if err := appendProducts(ids, RelatedSourceBestSeller, true); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// GetProductRelations 获取商品的人工关联和计算出的共现关系
+func (s *RecommendationService) GetProductRelations(productID uint) (*ProductRelations, error) {
+	if _, err := s.repoFactory.GetProductRepository().GetByID(productID); err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	recommendationRepo := s.repoFactory.GetRecommendationRepository()
+	relations, err := recommendationRepo.ListRelations(productID)
+	if err != nil {
+		return nil, err
+	}
+	associations, err := recommendationRepo.ListAssociations(productID, config.GlobalConfig.Recommendation.MaxPerProduct)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProductRelations{
+		Relations:    relations,
+		Associations: associations,
+	}, nil
+}
+
+// SaveRelation 置顶或屏蔽商品的关联商品，同一对商品重复提交时更新其状态
+func (s *RecommendationService) SaveRelation(productID, relatedID uint, status string, sortOrder int, operatorID uint) (*models.ProductRelation, error) {
+	if status != models.ProductRelationPinned && status != models.ProductRelationBlocked {
+		return nil, errors.New("invalid relation status")
+	}
+	if productID == relatedID {
+		return nil, errors.New("product cannot be related to itself")
+	}
+
+	productRepo := s.repoFactory.GetProductRepository()
+	if _, err := productRepo.GetByID(productID); err != nil {
+		return nil, errors.New("product not found")
+	}
+	if _, err := productRepo.GetByID(relatedID); err != nil {
+		return nil, errors.New("related product not found")
+	}
+
+	relation := &models.ProductRelation{
+		ProductID:  productID,
+		RelatedID:  relatedID,
+		Status:     status,
+		SortOrder:  sortOrder,
+		OperatorID: operatorID,
+	}
+	if err := s.repoFactory.GetRecommendationRepository().SaveRelation(relation); err != nil {
+		return nil, err
+	}
+	return relation, nil
+}
+
+// DeleteRelation 取消关联商品的置顶或屏蔽
+func (s *RecommendationService) DeleteRelation(productID, relatedID uint) error {
+	deleted, err := s.repoFactory.GetRecommendationRepository().DeleteRelation(productID, relatedID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("relation not found")
+	}
+	return nil
+}
@@ -69,6 +69,16 @@ func (s *ReviewService) GetProductReviews(productID uint, page, pageSize int) ([
 	return s.repoFactory.GetReviewRepository().ListByProduct(productID, page, pageSize)
 }
 
+// GetProductReviewsByCursor 游标分页获取商品评论列表
+func (s *ReviewService) GetProductReviewsByCursor(productID uint, req CursorRequest) ([]models.Review, *CursorPage, error) {
+	return s.repoFactory.GetReviewRepository().ListByProductCursor(productID, req)
+}
+
+// GetUserReviewsByCursor 游标分页获取用户评论列表
+func (s *ReviewService) GetUserReviewsByCursor(userID uint, req CursorRequest) ([]models.Review, *CursorPage, error) {
+	return s.repoFactory.GetReviewRepository().ListByUserCursor(userID, req)
+}
+
 // GetUserReviews 获取用户评论列表
 func (s *ReviewService) GetUserReviews(userID uint, page, pageSize int) ([]models.Review, int64, error) {
 	return s.repoFactory.GetReviewRepository().ListByUser(userID, page, pageSize)
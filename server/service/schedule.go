@@ -0,0 +1,349 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"shopify/models"
+	"shopify/repository"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	// priceHistoryWindow 商品详情中返回的价格历史时间范围，覆盖常见的价格保护期
+	priceHistoryWindow = 90 * 24 * time.Hour
+	// priceHistoryLimit 商品详情中最多返回的价格历史条数
+	priceHistoryLimit = 100
+	// scheduleBatchSize 每轮最多处理的定时任务数
+	scheduleBatchSize = 100
+)
+
+// errScheduleChanged 定时任务在执行过程中被取消或已被其他实例处理
+var errScheduleChanged = errors.New("schedule status changed")
+
+type ScheduleService struct {
+	*Service
+}
+
+func NewScheduleService(base *Service) *ScheduleService {
+	return &ScheduleService{Service: base}
+}
+
+// recordPriceChange 记录价格变动，价格未变化时不记录
+func recordPriceChange(repoFactory *repository.RepositoryFactory, productID, skuID uint, oldPrice, newPrice decimal.Decimal, source string, scheduleID *uint, operatorID uint) error {
+	if oldPrice.Equal(newPrice) {
+		return nil
+	}
+	return repoFactory.GetScheduleRepository().CreatePriceHistory(&models.PriceHistory{
+		ProductID:  productID,
+		SKUID:      skuID,
+		OldPrice:   oldPrice,
+		NewPrice:   newPrice,
+		Source:     source,
+		ScheduleID: scheduleID,
+		OperatorID: operatorID,
+	})
+}
+
+// syncProductMinPrice SKU价格变化后将商品展示价格更新为在售SKU的最低价
+func syncProductMinPrice(repoFactory *repository.RepositoryFactory, product *models.Product, source string, scheduleID *uint, operatorID uint) error {
+	skus, err := repoFactory.GetSKURepository().ListByProduct(product.ID)
+	if err != nil {
+		return err
+	}
+	var minPrice *decimal.Decimal
+	for _, sku := range skus {
+		if sku.Status != "active" {
+			continue
+		}
+		if minPrice == nil || sku.Price.LessThan(*minPrice) {
+			price := sku.Price
+			minPrice = &price
+		}
+	}
+	if minPrice == nil || minPrice.Equal(product.Price) {
+		return nil
+	}
+	if err := repoFactory.GetProductRepository().UpdatePrice(product.ID, *minPrice); err != nil {
+		return err
+	}
+	return recordPriceChange(repoFactory, product.ID, 0, product.Price, *minPrice, source, scheduleID, operatorID)
+}
+
+// CreateSchedule 创建定时上下架或改价任务
+// 有SKU的商品改价必须指定SKU，商品展示价格随SKU最低价变化
+func (s *ScheduleService) CreateSchedule(schedule *models.ProductSchedule) error {
+	product, err := s.repoFactory.GetProductRepository().GetByID(schedule.ProductID)
+	if err != nil {
+		return errors.New("product not found")
+	}
+
+	now := time.Now()
+	if !schedule.RunAt.After(now) {
+		return errors.New("run_at must be in the future")
+	}
+	if schedule.RevertAt != nil && !schedule.RevertAt.After(schedule.RunAt) {
+		return errors.New("revert_at must be after run_at")
+	}
+
+	switch schedule.Action {
+	case models.ScheduleActionPublish, models.ScheduleActionUnpublish:
+		schedule.Price = nil
+		schedule.SKUID = 0
+	case models.ScheduleActionPrice:
+		if schedule.Price == nil || !schedule.Price.IsPositive() {
+			return errors.New("price must be positive")
+		}
+		price := schedule.Price.Round(2)
+		schedule.Price = &price
+
+		count, err := s.repoFactory.GetSKURepository().CountByProduct(product.ID)
+		if err != nil {
+			return err
+		}
+		if count > 0 && schedule.SKUID == 0 {
+			return errors.New("product has skus, sku_id is required")
+		}
+		if schedule.SKUID != 0 {
+			sku, err := s.repoFactory.GetSKURepository().GetByID(schedule.SKUID)
+			if err != nil || sku.ProductID != product.ID {
+				return errors.New("sku not found")
+			}
+		}
+	default:
+		return errors.New("invalid action")
+	}
+
+	schedule.Status = models.ScheduleStatusPending
+	return s.repoFactory.GetScheduleRepository().Create(schedule)
+}
+
+// ListSchedules 获取商品的定时任务
+func (s *ScheduleService) ListSchedules(productID uint) ([]models.ProductSchedule, error) {
+	return s.repoFactory.GetScheduleRepository().ListByProduct(productID)
+}
+
+// CancelSchedule 取消尚未执行的定时任务
+func (s *ScheduleService) CancelSchedule(productID, id uint) error {
+	scheduleRepo := s.repoFactory.GetScheduleRepository()
+	schedule, err := scheduleRepo.GetByID(id)
+	if err != nil || schedule.ProductID != productID {
+		return errors.New("schedule not found")
+	}
+	ok, err := scheduleRepo.Transition(id, models.ScheduleStatusPending, map[string]interface{}{
+		"status": models.ScheduleStatusCancelled,
+	})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("only pending schedules can be cancelled")
+	}
+	return nil
+}
+
+// ListPriceHistory 分页获取商品的价格变动历史
+func (s *ScheduleService) ListPriceHistory(productID uint, page, pageSize int) ([]models.PriceHistory, int64, error) {
+	return s.repoFactory.GetScheduleRepository().ListPriceHistory(productID, page, pageSize)
+}
+
+// recentPriceHistory 获取商品详情展示的近期价格历史
+func (s *Service) recentPriceHistory(productID uint) ([]models.PriceHistory, error) {
+	return s.repoFactory.GetScheduleRepository().ListRecentPriceHistory(productID, time.Now().Add(-priceHistoryWindow), priceHistoryLimit)
+}
+
+// RunDueSchedules 执行到期的定时任务和到期的恢复，供定时任务调用
+func (s *ScheduleService) RunDueSchedules() error {
+	now := time.Now()
+	scheduleRepo := s.repoFactory.GetScheduleRepository()
+
+	due, err := scheduleRepo.ListDue(now, scheduleBatchSize)
+	if err != nil {
+		return err
+	}
+	for i := range due {
+		s.runSchedule(&due[i], false)
+	}
+
+	reverts, err := scheduleRepo.ListDueReverts(now, scheduleBatchSize)
+	if err != nil {
+		return err
+	}
+	for i := range reverts {
+		s.runSchedule(&reverts[i], true)
+	}
+	return nil
+}
+
+// runSchedule 执行或恢复单个定时任务，失败时记录原因
+func (s *ScheduleService) runSchedule(schedule *models.ProductSchedule, revert bool) {
+	from := models.ScheduleStatusPending
+	if revert {
+		from = models.ScheduleStatusApplied
+	}
+
+	err := s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepoFactory := repository.NewRepositoryFactory(tx)
+		if revert {
+			return revertSchedule(txRepoFactory, schedule)
+		}
+		return applySchedule(txRepoFactory, schedule)
+	})
+	if errors.Is(err, errScheduleChanged) {
+		return
+	}
+	if err != nil {
+		log.Printf("商品定时任务 %d 执行失败: %v", schedule.ID, err)
+		if _, updateErr := s.repoFactory.GetScheduleRepository().Transition(schedule.ID, from, map[string]interface{}{
+			"status":  models.ScheduleStatusFailed,
+			"message": truncateRunes(err.Error(), 255),
+		}); updateErr != nil {
+			log.Printf("更新商品定时任务 %d 状态失败: %v", schedule.ID, updateErr)
+		}
+		return
+	}
+
+	s.syncSearchIndex(schedule.ProductID)
+}
+
+// applySchedule 执行定时任务并记录执行前的值
+func applySchedule(repoFactory *repository.RepositoryFactory, schedule *models.ProductSchedule) error {
+	productRepo := repoFactory.GetProductRepository()
+	product, err := productRepo.GetByID(schedule.ProductID)
+	if err != nil {
+		return errors.New("product not found")
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":     models.ScheduleStatusApplied,
+		"applied_at": now,
+	}
+
+	switch schedule.Action {
+	case models.ScheduleActionPublish, models.ScheduleActionUnpublish:
+		status := "active"
+		if schedule.Action == models.ScheduleActionUnpublish {
+			status = "inactive"
+		}
+		updates["previous_status"] = product.Status
+		if err := productRepo.UpdateStatus(product.ID, status); err != nil {
+			return err
+		}
+		// 组件上下架会影响套装的可售数量
+		if err := syncBundleStock(repoFactory, product.ID); err != nil {
+			return err
+		}
+	case models.ScheduleActionPrice:
+		previous, err := setSchedulePrice(repoFactory, product, schedule, *schedule.Price, models.PriceSourceSchedule)
+		if err != nil {
+			return err
+		}
+		updates["previous_price"] = previous
+	}
+
+	ok, err := repoFactory.GetScheduleRepository().Transition(schedule.ID, models.ScheduleStatusPending, updates)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errScheduleChanged
+	}
+	return nil
+}
+
+// revertSchedule 将定时任务修改的值恢复为执行前的值
+// 执行后被人工修改过的不再恢复，避免覆盖人工调整
+func revertSchedule(repoFactory *repository.RepositoryFactory, schedule *models.ProductSchedule) error {
+	productRepo := repoFactory.GetProductRepository()
+	product, err := productRepo.GetByID(schedule.ProductID)
+	if err != nil {
+		return errors.New("product not found")
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":      models.ScheduleStatusReverted,
+		"reverted_at": now,
+	}
+
+	switch schedule.Action {
+	case models.ScheduleActionPublish, models.ScheduleActionUnpublish:
+		expected := "active"
+		if schedule.Action == models.ScheduleActionUnpublish {
+			expected = "inactive"
+		}
+		if product.Status != expected || schedule.PreviousStatus == "" {
+			updates["status"] = models.ScheduleStatusSkipped
+			updates["message"] = "status changed after schedule, revert skipped"
+			break
+		}
+		if err := productRepo.UpdateStatus(product.ID, schedule.PreviousStatus); err != nil {
+			return err
+		}
+		if err := syncBundleStock(repoFactory, product.ID); err != nil {
+			return err
+		}
+	case models.ScheduleActionPrice:
+		current, err := currentSchedulePrice(repoFactory, product, schedule)
+		if err != nil {
+			return err
+		}
+		if schedule.PreviousPrice == nil || !current.Equal(*schedule.Price) {
+			updates["status"] = models.ScheduleStatusSkipped
+			updates["message"] = "price changed after schedule, revert skipped"
+			break
+		}
+		if _, err := setSchedulePrice(repoFactory, product, schedule, *schedule.PreviousPrice, models.PriceSourceRevert); err != nil {
+			return err
+		}
+	}
+
+	ok, err := repoFactory.GetScheduleRepository().Transition(schedule.ID, models.ScheduleStatusApplied, updates)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errScheduleChanged
+	}
+	return nil
+}
+
+// currentSchedulePrice 获取定时改价对象的当前价格
+func currentSchedulePrice(repoFactory *repository.RepositoryFactory, product *models.Product, schedule *models.ProductSchedule) (decimal.Decimal, error) {
+	if schedule.SKUID == 0 {
+		return product.Price, nil
+	}
+	sku, err := repoFactory.GetSKURepository().GetByID(schedule.SKUID)
+	if err != nil || sku.ProductID != product.ID {
+		return decimal.Zero, errors.New("sku not found")
+	}
+	return sku.Price, nil
+}
+
+// setSchedulePrice 修改商品或SKU价格并记录价格历史，返回修改前的价格
+func setSchedulePrice(repoFactory *repository.RepositoryFactory, product *models.Product, schedule *models.ProductSchedule, price decimal.Decimal, source string) (decimal.Decimal, error) {
+	previous, err := currentSchedulePrice(repoFactory, product, schedule)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	scheduleID := schedule.ID
+
+	if schedule.SKUID == 0 {
+		if err := repoFactory.GetProductRepository().UpdatePrice(product.ID, price); err != nil {
+			return decimal.Zero, err
+		}
+		return previous, recordPriceChange(repoFactory, product.ID, 0, previous, price, source, &scheduleID, schedule.OperatorID)
+	}
+
+	if err := repoFactory.GetSKURepository().UpdatePrice(schedule.SKUID, price); err != nil {
+		return decimal.Zero, err
+	}
+	if err := recordPriceChange(repoFactory, product.ID, schedule.SKUID, previous, price, source, &scheduleID, schedule.OperatorID); err != nil {
+		return decimal.Zero, err
+	}
+	return previous, syncProductMinPrice(repoFactory, product, source, &scheduleID, schedule.OperatorID)
+}
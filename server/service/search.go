@@ -0,0 +1,360 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"shopify/config"
+	"shopify/models"
+	"shopify/pkg/search"
+	"shopify/repository"
+	"strings"
+	"time"
+)
+
+type SearchService struct {
+	*Service
+}
+
+func NewSearchService(base *Service) *SearchService {
+	return &SearchService{Service: base}
+}
+
+// SearchQuery 搜索条件
+type SearchQuery = search.Query
+
+// SearchResult 搜索结果
+type SearchResult struct {
+	Items       []models.Product `json:"items"`       // 当前页产品，按相关度排序
+	Total       int              `json:"total"`       // 命中总数
+	Page        int              `json:"page"`        // 页码
+	PageSize    int              `json:"page_size"`   // 每页数量
+	Facets      search.Facets    `json:"facets"`      // 分面统计
+	Suggestions []string         `json:"suggestions"` // 纠错建议
+}
+
+// productDocument 将产品及其多语言文本转换为索引文档
+func productDocument(product *models.Product, translations []search.LocalizedText) search.Document {
+	doc := search.Document{
+		ID:           product.ID,
+		Name:         product.Name,
+		Description:  product.Description,
+		CategoryName: product.Category,
+		Tags:         product.Tags,
+		Price:        product.Price.InexactFloat64(),
+		Sales:        product.Sales,
+		Status:       product.Status,
+		Translations: translations,
+	}
+	if product.CategoryID != nil {
+		doc.CategoryID = *product.CategoryID
+	}
+	return doc
+}
+
+// syncSearchIndex 将产品的最新状态同步到搜索索引，产品不存在时从索引删除
+// 索引同步失败不影响业务操作，定时重建会兜底
+func (s *Service) syncSearchIndex(productID uint) {
+	product, err := s.repoFactory.GetProductRepository().GetByID(productID)
+	if err != nil {
+		err = s.searchEngine.Delete(productID)
+	} else {
+		err = s.searchEngine.Index(productDocument(product, s.productTranslations(product)))
+	}
+	if err != nil {
+		log.Printf("同步搜索索引失败, product_id=%d: %v", productID, err)
+	}
+
+	// 商品变化都会经过这里，同时让站点地图失效并重新生成该商品的商品源条目
+	s.invalidateSitemap()
+	s.markFeedDirty(productID)
+}
+
+// RebuildIndex 使用全部上架产品重建搜索索引
+func (s *SearchService) RebuildIndex() error {
+	products, err := s.repoFactory.GetProductRepository().ListActive()
+	if err != nil {
+		return err
+	}
+
+	translationRepo := s.repoFactory.GetTranslationRepository()
+	productTranslations, err := translationRepo.ListByType(models.TranslationEntityProduct)
+	if err != nil {
+		return err
+	}
+	categoryTranslations, err := translationRepo.ListByType(models.TranslationEntityCategory)
+	if err != nil {
+		return err
+	}
+	ptrs := make([]*models.Product, len(products))
+	for i := range products {
+		ptrs[i] = &products[i]
+	}
+	texts := localizedTexts(ptrs, productTranslations, categoryTranslations)
+
+	docs := make([]search.Document, 0, len(products))
+	for i := range products {
+		docs = append(docs, productDocument(&products[i], texts[products[i].ID]))
+	}
+	return s.searchEngine.Rebuild(docs)
+}
+
+// productTranslations 加载单个商品索引用的多语言文本，失败时只打印日志，按默认语言建立索引
+func (s *Service) productTranslations(product *models.Product) []search.LocalizedText {
+	translationRepo := s.repoFactory.GetTranslationRepository()
+	productTranslations, err := translationRepo.ListByEntity(models.TranslationEntityProduct, product.ID)
+	if err != nil {
+		log.Printf("加载商品翻译失败, product_id=%d: %v", product.ID, err)
+		return nil
+	}
+	var categoryTranslations []models.Translation
+	if product.CategoryID != nil {
+		categoryTranslations, err = translationRepo.ListByEntity(models.TranslationEntityCategory, *product.CategoryID)
+		if err != nil {
+			log.Printf("加载类目翻译失败, category_id=%d: %v", *product.CategoryID, err)
+			return nil
+		}
+	}
+	return localizedTexts([]*models.Product{product}, productTranslations, categoryTranslations)[product.ID]
+}
+
+// HotTerm 热搜词
+type HotTerm struct {
+	Term   string `json:"term"`   // 搜索词
+	Count  int64  `json:"count"`  // 窗口内搜索次数，置顶词为0
+	Pinned bool   `json:"pinned"` // 是否为运营置顶
+}
+
+// maxQueryLength 搜索词最大长度，与日志字段长度一致
+const maxQueryLength = 100
+
+// normalizeQuery 规范化搜索词：去除首尾空白、合并连续空白并转为小写
+func normalizeQuery(text string) string {
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+	if runes := []rune(text); len(runes) > maxQueryLength {
+		text = string(runes[:maxQueryLength])
+	}
+	return text
+}
+
+// isBlockedTerm 判断搜索词是否包含被屏蔽的词
+func isBlockedTerm(text string, blocked []models.SearchTerm) bool {
+	for _, term := range blocked {
+		if strings.Contains(text, term.Term) {
+			return true
+		}
+	}
+	return false
+}
+
+// logSearch 记录搜索日志，失败时只打印日志
+func (s *SearchService) logSearch(text string, resultCount int, userID *uint) {
+	entry := &models.SearchLog{
+		Query:       text,
+		ResultCount: resultCount,
+		UserID:      userID,
+	}
+	if err := s.repoFactory.GetSearchRepository().CreateLog(entry); err != nil {
+		log.Printf("记录搜索日志失败: %v", err)
+	}
+}
+
+// Search 搜索产品，categoryID 不为 0 时包含所有子孙类目下的产品
+// 仅在第一页记录搜索日志，避免翻页重复计数
+func (s *SearchService) Search(categoryID uint, query SearchQuery, userID *uint) (*SearchResult, error) {
+	query.Text = normalizeQuery(query.Text)
+
+	if categoryID > 0 {
+		category, err := s.repoFactory.GetCategoryRepository().GetByID(categoryID)
+		if err != nil {
+			return nil, err
+		}
+		ids, err := s.repoFactory.GetCategoryRepository().ListDescendantIDs(category.Path)
+		if err != nil {
+			return nil, err
+		}
+		query.CategoryIDs = ids
+	}
+
+	result, err := s.searchEngine.Search(query)
+	if err != nil {
+		return nil, err
+	}
+	if query.Text != "" && query.Page <= 1 {
+		s.logSearch(query.Text, result.Total, userID)
+	}
+
+	// 按搜索结果的顺序返回产品
+	ids := make([]uint, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		ids = append(ids, hit.ID)
+	}
+	products, err := s.repoFactory.GetProductRepository().ListByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Product, len(products))
+	for _, product := range products {
+		byID[product.ID] = product
+	}
+	items := make([]models.Product, 0, len(ids))
+	for _, id := range ids {
+		if product, ok := byID[id]; ok {
+			items = append(items, product)
+		}
+	}
+
+	return &SearchResult{
+		Items:       items,
+		Total:       result.Total,
+		Page:        query.Page,
+		PageSize:    query.PageSize,
+		Facets:      result.Facets,
+		Suggestions: result.Suggestions,
+	}, nil
+}
+
+// Suggest 返回搜索联想词，依次为置顶词、热门搜索词和商品名称
+func (s *SearchService) Suggest(prefix string) ([]string, error) {
+	prefix = normalizeQuery(prefix)
+	limit := config.GlobalConfig.Search.SuggestLimit
+	if prefix == "" {
+		return []string{}, nil
+	}
+
+	searchRepo := s.repoFactory.GetSearchRepository()
+	blocked, err := searchRepo.ListTerms(models.SearchTermBlocked)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]string, 0, limit)
+	seen := make(map[string]bool)
+	add := func(text string) {
+		key := normalizeQuery(text)
+		if len(suggestions) >= limit || seen[key] || isBlockedTerm(key, blocked) {
+			return
+		}
+		seen[key] = true
+		suggestions = append(suggestions, text)
+	}
+
+	pinned, err := searchRepo.ListTerms(models.SearchTermPinned)
+	if err != nil {
+		return nil, err
+	}
+	for _, term := range pinned {
+		if strings.HasPrefix(term.Term, prefix) {
+			add(term.Term)
+		}
+	}
+
+	since := time.Now().AddDate(0, 0, -config.GlobalConfig.Search.SuggestWindowDays)
+	popular, err := searchRepo.ListTopQueries(since, prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, query := range popular {
+		add(query.Query)
+	}
+
+	names, err := s.searchEngine.Complete(prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		add(name)
+	}
+
+	return suggestions, nil
+}
+
+// HotTerms 返回滑动窗口内的热搜词，置顶词排在最前，屏蔽词不返回
+func (s *SearchService) HotTerms() ([]HotTerm, error) {
+	limit := config.GlobalConfig.Search.HotLimit
+	searchRepo := s.repoFactory.GetSearchRepository()
+
+	terms, err := searchRepo.ListTerms("")
+	if err != nil {
+		return nil, err
+	}
+	var pinned, blocked []models.SearchTerm
+	for _, term := range terms {
+		if term.Status == models.SearchTermPinned {
+			pinned = append(pinned, term)
+		} else {
+			blocked = append(blocked, term)
+		}
+	}
+
+	hot := make([]HotTerm, 0, limit)
+	seen := make(map[string]bool)
+	for _, term := range pinned {
+		if len(hot) >= limit {
+			break
+		}
+		seen[term.Term] = true
+		hot = append(hot, HotTerm{Term: term.Term, Pinned: true})
+	}
+
+	// 多取一些，补足被屏蔽或与置顶重复的词
+	since := time.Now().Add(-time.Duration(config.GlobalConfig.Search.HotWindowHours) * time.Hour)
+	queries, err := searchRepo.ListTopQueries(since, "", limit+len(terms))
+	if err != nil {
+		return nil, err
+	}
+	for _, query := range queries {
+		if len(hot) >= limit {
+			break
+		}
+		if seen[query.Query] || isBlockedTerm(query.Query, blocked) {
+			continue
+		}
+		seen[query.Query] = true
+		hot = append(hot, HotTerm{Term: query.Query, Count: query.Count})
+	}
+
+	return hot, nil
+}
+
+// ListZeroResultQueries 获取最近若干天内无结果的搜索词，按搜索次数排序
+func (s *SearchService) ListZeroResultQueries(days, page, pageSize int) ([]repository.QueryCount, int64, error) {
+	if days <= 0 {
+		days = 7
+	}
+	since := time.Now().AddDate(0, 0, -days)
+	return s.repoFactory.GetSearchRepository().ListZeroResultQueries(since, page, pageSize)
+}
+
+// ListTerms 获取运营配置的搜索词
+func (s *SearchService) ListTerms(status string) ([]models.SearchTerm, error) {
+	return s.repoFactory.GetSearchRepository().ListTerms(status)
+}
+
+// SaveTerm 置顶或屏蔽搜索词
+func (s *SearchService) SaveTerm(text, status string, sortOrder int) (*models.SearchTerm, error) {
+	text = normalizeQuery(text)
+	if text == "" {
+		return nil, errors.New("term is required")
+	}
+	if status != models.SearchTermPinned && status != models.SearchTermBlocked {
+		return nil, errors.New("invalid term status")
+	}
+
+	term := &models.SearchTerm{
+		Term:      text,
+		Status:    status,
+		SortOrder: sortOrder,
+	}
+	if err := s.repoFactory.GetSearchRepository().SaveTerm(term); err != nil {
+		return nil, err
+	}
+	return term, nil
+}
+
+// DeleteTerm 取消搜索词的置顶或屏蔽
+func (s *SearchService) DeleteTerm(id uint) error {
+	if _, err := s.repoFactory.GetSearchRepository().GetTermByID(id); err != nil {
+		return errors.New("search term not found")
+	}
+	return s.repoFactory.GetSearchRepository().DeleteTerm(id)
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"shopify/models"
+)
+
+// Shopify 商品导出 CSV 的列名，统一转为小写后匹配
+const (
+	shopifyHandle          = "handle"
+	shopifyTitle           = "title"
+	shopifyBody            = "body (html)"
+	shopifyType            = "type"
+	shopifyProductCategory = "product category"
+	shopifyTags            = "tags"
+	shopifyPublished       = "published"
+	shopifyStatus          = "status"
+	shopifyVariantSKU      = "variant sku"
+	shopifyVariantQty      = "variant inventory qty"
+	shopifyVariantPrice    = "variant price"
+	shopifyVariantBarcode  = "variant barcode"
+	shopifyVariantImage    = "variant image"
+	shopifyImageSrc        = "image src"
+	shopifyImagePosition   = "image position"
+)
+
+// shopifyDefaultOption Shopify 无规格商品的占位规格
+const (
+	shopifyDefaultOptionName  = "Title"
+	shopifyDefaultOptionValue = "Default Title"
+)
+
+// shopifyMappedColumns 会被导入的列，其余有数据的列在报告中提示未映射
+var shopifyMappedColumns = map[string]bool{
+	shopifyHandle: true, shopifyTitle: true, shopifyBody: true, shopifyType: true,
+	shopifyProductCategory: true, shopifyTags: true, shopifyPublished: true, shopifyStatus: true,
+	shopifyVariantSKU: true, shopifyVariantQty: true, shopifyVariantPrice: true,
+	shopifyVariantBarcode: true, shopifyVariantImage: true, shopifyImageSrc: true, shopifyImagePosition: true,
+	"option1 name": true, "option1 value": true, "option2 name": true, "option2 value": true,
+	"option3 name": true, "option3 value": true,
+}
+
+// shopifyImage 带排序位置的商品图片
+type shopifyImage struct {
+	URL      string
+	Position int
+}
+
+// parseShopifyRows 解析 Shopify 商品导出 CSV
+// 同一 Handle 的多行合并为一个商品，Handle 作为外部编码；图片按 Image Position 排序并按原地址保存
+func parseShopifyRows(rows [][]string) ([]*importProduct, []models.ImportError, error) {
+	if len(rows) == 0 {
+		return nil, nil, errors.New("file is empty")
+	}
+	index := headerIndex(rows[0])
+	if _, ok := index[shopifyHandle]; !ok {
+		return nil, nil, errors.New("header must contain Handle, is this a Shopify product export?")
+	}
+	warnings := make([]models.ImportError, 0)
+
+	products := make([]*importProduct, 0)
+	groups := make(map[string]*importProduct)
+	optionNames := make(map[*importProduct][]string)
+	images := make(map[*importProduct][]shopifyImage)
+	unmapped := make(map[string]bool)
+
+	for i := 1; i < len(rows); i++ {
+		rowNumber := i + 1
+		row := sheetRow{index: index, values: rows[i]}
+		if row.blank() {
+			continue
+		}
+		for name, column := range index {
+			if !shopifyMappedColumns[name] && column < len(row.values) && strings.TrimSpace(row.values[column]) != "" {
+				unmapped[name] = true
+			}
+		}
+
+		handle := row.get(shopifyHandle)
+		if handle == "" {
+			warnings = append(warnings, models.ImportError{Row: rowNumber, Column: shopifyHandle, Message: "row without handle ignored"})
+			continue
+		}
+		product, exists := groups[handle]
+		if !exists {
+			product = &importProduct{Row: rowNumber, ExternalID: handle}
+			groups[handle] = product
+			products = append(products, product)
+		}
+
+		// 商品字段只出现在第一行
+		fillString(&product.Name, row.get(shopifyTitle))
+		fillString(&product.Description, row.get(shopifyBody))
+		if product.Category == "" {
+			if category := row.get(shopifyProductCategory); category != "" {
+				product.Category = category
+			} else {
+				product.Category = row.get(shopifyType)
+			}
+		}
+		if value := row.get(shopifyTags); value != "" && product.Tags == nil {
+			product.Tags = splitList(value, ",")
+		}
+		if product.Status == "" {
+			product.Status = shopifyProductStatus(row.get(shopifyStatus), row.get(shopifyPublished))
+		}
+
+		if src := row.get(shopifyImageSrc); src != "" {
+			position, err := strconv.Atoi(row.get(shopifyImagePosition))
+			if err != nil {
+				position = len(images[product]) + 1
+			}
+			images[product] = append(images[product], shopifyImage{URL: src, Position: position})
+		}
+
+		// 规格名称只出现在第一行，后续行只有规格值
+		if _, ok := optionNames[product]; !ok {
+			names := make([]string, 0, 3)
+			for n := 1; n <= 3; n++ {
+				if name := row.get(fmt.Sprintf("option%d name", n)); name != "" {
+					names = append(names, name)
+				}
+			}
+			optionNames[product] = names
+		}
+
+		sku := row.get(shopifyVariantSKU)
+		priceValue := row.get(shopifyVariantPrice)
+		option1 := row.get("option1 value")
+		if sku == "" && priceValue == "" && option1 == "" {
+			// 只有图片的行
+			continue
+		}
+
+		variant := importVariant{
+			Row:     rowNumber,
+			SKU:     sku,
+			Barcode: strings.TrimPrefix(row.get(shopifyVariantBarcode), "'"),
+			Image:   row.get(shopifyVariantImage),
+		}
+		if priceValue != "" {
+			price, err := parseImportPrice(priceValue)
+			if err != nil {
+				product.addError(rowNumber, "Variant Price", err.Error())
+			}
+			variant.Price = price
+		}
+		if value := row.get(shopifyVariantQty); value != "" {
+			qty, err := strconv.Atoi(value)
+			if err != nil {
+				product.addError(rowNumber, "Variant Inventory Qty", "must be an integer")
+			} else {
+				if qty < 0 {
+					// Shopify 允许超卖产生负库存
+					warnings = append(warnings, models.ImportError{Row: rowNumber, Column: "Variant Inventory Qty", Message: "negative inventory imported as 0"})
+					qty = 0
+				}
+				variant.Stock = &qty
+			}
+		}
+
+		names := optionNames[product]
+		defaultOnly := len(names) == 1 && names[0] == shopifyDefaultOptionName && option1 == shopifyDefaultOptionValue
+		if !defaultOnly {
+			for n, name := range names {
+				value := row.get(fmt.Sprintf("option%d value", n+1))
+				if value == "" {
+					product.addError(rowNumber, fmt.Sprintf("Option%d Value", n+1), "option value is required")
+					continue
+				}
+				variant.Options = append(variant.Options, importOption{Name: name, Value: value})
+			}
+		}
+
+		if defaultOnly && sku == "" {
+			// 无规格且无SKU编码，按普通商品导入
+			product.Price = variant.Price
+			product.Stock = variant.Stock
+			continue
+		}
+		if variant.SKU == "" {
+			// Shopify 的 SKU 可为空，按 Handle 和规格值生成稳定的编码，便于重复导入时匹配
+			values := make([]string, 0, len(variant.Options))
+			for _, option := range variant.Options {
+				values = append(values, option.Value)
+			}
+			variant.SKU = shopifySKUCode(handle, values)
+			warnings = append(warnings, models.ImportError{Row: rowNumber, Column: "Variant SKU", Message: "empty sku, generated " + variant.SKU})
+		}
+		product.Variants = append(product.Variants, variant)
+	}
+
+	for product, list := range images {
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].Position < list[j].Position
+		})
+		urls := make([]string, 0, len(list))
+		seen := make(map[string]bool, len(list))
+		for _, image := range list {
+			if !seen[image.URL] {
+				seen[image.URL] = true
+				urls = append(urls, image.URL)
+			}
+		}
+		product.Images = urls
+	}
+
+	columns := make([]string, 0, len(unmapped))
+	for name := range unmapped {
+		columns = append(columns, name)
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		return index[columns[i]] < index[columns[j]]
+	})
+	for _, name := range columns {
+		warnings = append(warnings, models.ImportError{Row: 1, Column: rows[0][index[name]], Message: "column not mapped"})
+	}
+
+	if len(products) == 0 {
+		return nil, nil, errors.New("file has no data rows")
+	}
+	return products, warnings, nil
+}
+
+// shopifyProductStatus 将 Shopify 的状态映射为上架/下架，草稿和归档视为下架
+func shopifyProductStatus(status, published string) string {
+	switch strings.ToLower(status) {
+	case "active":
+		return "active"
+	case "draft", "archived":
+		return "inactive"
+	}
+	switch strings.ToLower(published) {
+	case "true":
+		return "active"
+	case "false":
+		return "inactive"
+	}
+	return ""
+}
+
+// shopifySKUCode 生成SKU编码，超长时截断
+func shopifySKUCode(handle string, values []string) string {
+	code := handle
+	if len(values) > 0 {
+		code += "-" + strings.Join(values, "-")
+	}
+	code = strings.ReplaceAll(code, " ", "-")
+	if runes := []rune(code); len(code) > 64 {
+		// 按字符截断，避免切断多字节字符
+		for len(string(runes)) > 64 {
+			runes = runes[:len(runes)-1]
+		}
+		code = string(runes)
+	}
+	return code
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"shopify/config"
+	"shopify/models"
+)
+
+// maxSitemapURLs 单个站点地图文件的地址数上限，超过后拆分并生成站点地图索引
+const maxSitemapURLs = 50000
+
+// sitemapNamespace 站点地图协议的命名空间
+const sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// ErrSitemapNotFound 请求的站点地图分页不存在
+var ErrSitemapNotFound = errors.New("sitemap not found")
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	Xmlns    string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapURL `xml:"sitemap"`
+}
+
+// sitemapCache 生成好的站点地图，商品或类目变化后失效，下次访问时重新生成
+type sitemapCache struct {
+	mu    sync.Mutex
+	valid bool
+	pages [][]byte // 第 0 页为 sitemap.xml，拆分时为索引，其余为各分页
+}
+
+func (c *sitemapCache) invalidate() {
+	c.mu.Lock()
+	c.valid = false
+	c.pages = nil
+	c.mu.Unlock()
+}
+
+// invalidateSitemap 商品或类目变化后标记站点地图需要重新生成
+func (s *Service) invalidateSitemap() {
+	s.sitemap.invalidate()
+}
+
+type SitemapService struct {
+	*Service
+}
+
+func NewSitemapService(base *Service) *SitemapService {
+	return &SitemapService{Service: base}
+}
+
+// Sitemap 获取站点地图，page 为 0 时返回 sitemap.xml，拆分后的分页从 1 开始
+func (s *SitemapService) Sitemap(page int) ([]byte, error) {
+	s.sitemap.mu.Lock()
+	defer s.sitemap.mu.Unlock()
+
+	if !s.sitemap.valid {
+		pages, err := s.buildSitemap()
+		if err != nil {
+			return nil, err
+		}
+		s.sitemap.pages = pages
+		s.sitemap.valid = true
+	}
+
+	if page < 0 || page >= len(s.sitemap.pages) {
+		return nil, ErrSitemapNotFound
+	}
+	return s.sitemap.pages[page], nil
+}
+
+// buildSitemap 生成包含首页、启用类目和上架商品的站点地图
+func (s *SitemapService) buildSitemap() ([][]byte, error) {
+	cfg := config.GlobalConfig.SEO
+	siteURL := strings.TrimRight(cfg.SiteURL, "/")
+
+	urls := []sitemapURL{{Loc: siteURL + "/"}}
+
+	categories, err := s.repoFactory.GetCategoryRepository().ListAll(true)
+	if err != nil {
+		return nil, err
+	}
+	for _, category := range categories {
+		path := strings.ReplaceAll(cfg.CategoryPath, "{id}", strconv.FormatUint(uint64(category.ID), 10))
+		urls = append(urls, sitemapURL{Loc: siteURL + path, LastMod: sitemapTime(category.UpdatedAt)})
+	}
+
+	products, err := s.repoFactory.GetProductRepository().ListActive()
+	if err != nil {
+		return nil, err
+	}
+	for i := range products {
+		urls = append(urls, sitemapURL{Loc: productPageURL(&products[i]), LastMod: sitemapTime(products[i].UpdatedAt)})
+	}
+
+	if len(urls) <= maxSitemapURLs {
+		page, err := encodeSitemap(sitemapURLSet{Xmlns: sitemapNamespace, URLs: urls})
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{page}, nil
+	}
+
+	// 超过上限时拆分，sitemap.xml 作为索引指向各分页
+	now := sitemapTime(time.Now())
+	pages := [][]byte{nil}
+	index := sitemapIndex{Xmlns: sitemapNamespace}
+	for start := 0; start < len(urls); start += maxSitemapURLs {
+		end := start + maxSitemapURLs
+		if end > len(urls) {
+			end = len(urls)
+		}
+		page, err := encodeSitemap(sitemapURLSet{Xmlns: sitemapNamespace, URLs: urls[start:end]})
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, page)
+		index.Sitemaps = append(index.Sitemaps, sitemapURL{
+			Loc:     fmt.Sprintf("%s/sitemap/%d.xml", siteURL, len(pages)-1),
+			LastMod: now,
+		})
+	}
+
+	indexPage, err := encodeSitemap(index)
+	if err != nil {
+		return nil, err
+	}
+	pages[0] = indexPage
+	return pages, nil
+}
+
+// productPageURL 商品前台页面的绝对地址，尚未生成 slug 时使用产品ID
+func productPageURL(product *models.Product) string {
+	cfg := config.GlobalConfig.SEO
+	id := strconv.FormatUint(uint64(product.ID), 10)
+	slug := product.Slug
+	if slug == "" {
+		slug = id
+	}
+	path := strings.NewReplacer("{slug}", slug, "{id}", id).Replace(cfg.ProductPath)
+	return strings.TrimRight(cfg.SiteURL, "/") + path
+}
+
+// encodeSitemap 将站点地图编码为带声明的 XML
+func encodeSitemap(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// sitemapTime 站点地图使用 W3C 日期时间格式
+func sitemapTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"shopify/models"
+	"shopify/repository"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type SKUService struct {
+	*Service
+}
+
+func NewSKUService(base *Service) *SKUService {
+	return &SKUService{Service: base}
+}
+
+// VariantCombination 规格组合及其可售状态
+type VariantCombination struct {
+	Options   map[string]string `json:"options"`   // 规格值组合
+	SKUID     uint              `json:"sku_id"`    // 对应的SKU ID，不存在时为0
+	Price     decimal.Decimal   `json:"price"`     // SKU价格
+	Stock     int               `json:"stock"`     // SKU库存
+	Available bool              `json:"available"` // 是否可购买
+}
+
+// skuKey 根据规格项顺序生成规格组合的唯一键
+func skuKey(options []models.ProductOption, values map[string]string) string {
+	parts := make([]string, 0, len(options))
+	for _, option := range options {
+		parts = append(parts, option.Name+"="+values[option.Name])
+	}
+	return strings.Join(parts, ";")
+}
+
+// buildVariantMatrix 枚举所有规格组合并标记可售状态
+func buildVariantMatrix(options []models.ProductOption, skus []models.SKU) []VariantCombination {
+	if len(options) == 0 {
+		return nil
+	}
+
+	skuByKey := make(map[string]models.SKU, len(skus))
+	for _, sku := range skus {
+		skuByKey[skuKey(options, sku.Options)] = sku
+	}
+
+	// 笛卡尔积枚举全部组合
+	combos := []map[string]string{{}}
+	for _, option := range options {
+		next := make([]map[string]string, 0, len(combos)*len(option.Values))
+		for _, combo := range combos {
+			for _, value := range option.Values {
+				item := make(map[string]string, len(combo)+1)
+				for k, v := range combo {
+					item[k] = v
+				}
+				item[option.Name] = value
+				next = append(next, item)
+			}
+		}
+		combos = next
+	}
+
+	matrix := make([]VariantCombination, 0, len(combos))
+	for _, combo := range combos {
+		variant := VariantCombination{Options: combo}
+		if sku, ok := skuByKey[skuKey(options, combo)]; ok {
+			variant.SKUID = sku.ID
+			variant.Price = sku.Price
+			variant.Stock = sku.Stock
+			variant.Available = sku.Status == "active" && sku.Stock > 0
+		}
+		matrix = append(matrix, variant)
+	}
+	return matrix
+}
+
+// validateOptions 校验规格项定义
+func validateOptions(options []models.ProductOption) error {
+	names := make(map[string]bool)
+	for _, option := range options {
+		if option.Name == "" {
+			return errors.New("option name is required")
+		}
+		if names[option.Name] {
+			return fmt.Errorf("duplicate option name: %s", option.Name)
+		}
+		names[option.Name] = true
+		if len(option.Values) == 0 {
+			return fmt.Errorf("option %s must have at least one value", option.Name)
+		}
+		values := make(map[string]bool)
+		for _, value := range option.Values {
+			if value == "" || values[value] {
+				return fmt.Errorf("option %s has empty or duplicate values", option.Name)
+			}
+			values[value] = true
+		}
+	}
+	return nil
+}
+
+// validateSKU 校验SKU的规格值是否与规格项定义一致
+func validateSKU(options []models.ProductOption, sku *models.SKU) error {
+	if sku.Code == "" {
+		return errors.New("sku code is required")
+	}
+	if !sku.Price.IsPositive() {
+		return fmt.Errorf("sku %s price must be positive", sku.Code)
+	}
+	if len(sku.Options) != len(options) {
+		return fmt.Errorf("sku %s must specify every option", sku.Code)
+	}
+	for _, option := range options {
+		value, ok := sku.Options[option.Name]
+		if !ok {
+			return fmt.Errorf("sku %s is missing option %s", sku.Code, option.Name)
+		}
+		valid := false
+		for _, allowed := range option.Values {
+			if allowed == value {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("sku %s has invalid value %q for option %s", sku.Code, value, option.Name)
+		}
+	}
+	if sku.Status == "" {
+		sku.Status = "active"
+	}
+	if sku.Status != "active" && sku.Status != "inactive" {
+		return fmt.Errorf("sku %s has invalid status", sku.Code)
+	}
+	return nil
+}
+
+// SaveSKUs 批量保存产品的规格项和SKU
+// 带ID的SKU会被更新，不带ID的SKU会被创建，未出现在列表中的SKU会被删除。
+// 已有SKU的库存不在此处修改，新建SKU的初始库存记入库存流水。
+func (s *SKUService) SaveSKUs(productID uint, options []models.ProductOption, skus []models.SKU, operatorID uint) (*ProductDetail, error) {
+	return s.saveSKUs(productID, options, skus, models.InventoryReasonAdjustment, "SKU初始库存", operatorID)
+}
+
+// saveSKUs 批量保存规格项和SKU，新建SKU的初始库存按 reason 和 remark 记入流水
+func (s *SKUService) saveSKUs(productID uint, options []models.ProductOption, skus []models.SKU, reason, remark string, operatorID uint) (*ProductDetail, error) {
+	if err := validateOptions(options); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(options, func(i, j int) bool {
+		return options[i].SortOrder < options[j].SortOrder
+	})
+
+	seenKeys := make(map[string]bool)
+	seenCodes := make(map[string]bool)
+	for i := range skus {
+		if err := validateSKU(options, &skus[i]); err != nil {
+			return nil, err
+		}
+		key := skuKey(options, skus[i].Options)
+		if seenKeys[key] {
+			return nil, fmt.Errorf("duplicate option combination: %s", key)
+		}
+		seenKeys[key] = true
+		if seenCodes[skus[i].Code] {
+			return nil, fmt.Errorf("duplicate sku code: %s", skus[i].Code)
+		}
+		seenCodes[skus[i].Code] = true
+	}
+	if len(options) > 0 && len(skus) == 0 {
+		return nil, errors.New("at least one sku is required when options are defined")
+	}
+
+	err := s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepoFactory := repository.NewRepositoryFactory(tx)
+		skuRepo := txRepoFactory.GetSKURepository()
+
+		product, err := txRepoFactory.GetProductRepository().GetByID(productID)
+		if err != nil {
+			return errors.New("product not found")
+		}
+		if product.Type == models.ProductTypeBundle && len(skus) > 0 {
+			return errors.New("bundle cannot have skus")
+		}
+		if product.Type == models.ProductTypeDigital && len(skus) > 0 {
+			return errors.New("digital product cannot have skus")
+		}
+
+		existing, err := skuRepo.ListByProduct(productID)
+		if err != nil {
+			return err
+		}
+
+		// 产品级库存无法拆分到SKU，需先清零
+		if len(existing) == 0 && len(skus) > 0 && product.Stock > 0 {
+			return errors.New("product stock must be adjusted to zero before adding skus")
+		}
+
+		existingByID := make(map[uint]models.SKU, len(existing))
+		for _, sku := range existing {
+			existingByID[sku.ID] = sku
+		}
+
+		// 删除未出现在列表中的SKU，仍有库存的不允许删除
+		kept := make(map[uint]bool)
+		for _, sku := range skus {
+			if sku.ID != 0 {
+				kept[sku.ID] = true
+			}
+		}
+		for _, sku := range existing {
+			if kept[sku.ID] {
+				continue
+			}
+			if sku.Stock > 0 {
+				return fmt.Errorf("sku %s still has stock, adjust it to zero before removing", sku.Code)
+			}
+			// 释放编码，允许新SKU复用
+			sku.Code = fmt.Sprintf("%s#deleted-%d", sku.Code, sku.ID)
+			if err := skuRepo.Update(&sku); err != nil {
+				return err
+			}
+			if err := skuRepo.Delete(sku.ID); err != nil {
+				return err
+			}
+		}
+
+		for i := range skus {
+			sku := &skus[i]
+			sku.ProductID = productID
+
+			if sku.ID != 0 {
+				old, ok := existingByID[sku.ID]
+				if !ok {
+					return fmt.Errorf("sku %d does not belong to this product", sku.ID)
+				}
+				if err := skuRepo.Update(sku); err != nil {
+					return err
+				}
+				if err := recordPriceChange(txRepoFactory, productID, sku.ID, old.Price, sku.Price, models.PriceSourceManual, nil, operatorID); err != nil {
+					return err
+				}
+				continue
+			}
+
+			// 新建SKU，初始库存通过流水写入
+			initialStock := sku.Stock
+			if initialStock < 0 {
+				return fmt.Errorf("sku %s stock cannot be negative", sku.Code)
+			}
+			sku.Stock = 0
+			if err := skuRepo.Create(sku); err != nil {
+				return err
+			}
+			if initialStock > 0 {
+				movement := &models.InventoryMovement{
+					ProductID:  productID,
+					SKUID:      sku.ID,
+					Delta:      initialStock,
+					Reason:     reason,
+					Remark:     remark,
+					OperatorID: operatorID,
+				}
+				if err := changeStock(txRepoFactory, movement); err != nil {
+					return err
+				}
+				sku.Stock = initialStock
+			}
+		}
+
+		if err := skuRepo.ReplaceOptions(productID, options); err != nil {
+			return err
+		}
+
+		// 产品展示价格取在售SKU的最低价
+		var minPrice *decimal.Decimal
+		for _, sku := range skus {
+			if sku.Status != "active" {
+				continue
+			}
+			if minPrice == nil || sku.Price.LessThan(*minPrice) {
+				price := sku.Price
+				minPrice = &price
+			}
+		}
+		if minPrice != nil && !minPrice.Equal(product.Price) {
+			if err := txRepoFactory.GetProductRepository().UpdatePrice(productID, *minPrice); err != nil {
+				return err
+			}
+			if err := recordPriceChange(txRepoFactory, productID, 0, product.Price, *minPrice, models.PriceSourceManual, nil, operatorID); err != nil {
+				return err
+			}
+		}
+
+		// SKU上下架、删除都会影响以其为组件的套装的可售数量
+		return syncBundleStock(txRepoFactory, productID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.syncSearchIndex(productID)
+	return NewProductService(s.Service).GetProductDetail(productID)
+}
+
+// resolveSKU 校验订单或购物车中的SKU选择
+// 有SKU的产品必须选择SKU，返回nil表示无规格产品
+func resolveSKU(repoFactory *repository.RepositoryFactory, product *models.Product, skuID *uint) (*models.SKU, error) {
+	if skuID == nil || *skuID == 0 {
+		count, err := repoFactory.GetSKURepository().CountByProduct(product.ID)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, fmt.Errorf("please select a specification for product: %s", product.Name)
+		}
+		return nil, nil
+	}
+
+	sku, err := repoFactory.GetSKURepository().GetByID(*skuID)
+	if err != nil || sku.ProductID != product.ID {
+		return nil, errors.New("sku not found")
+	}
+	if sku.Status != "active" {
+		return nil, fmt.Errorf("sku %s is not available", sku.Code)
+	}
+	return sku, nil
+}
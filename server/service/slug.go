@@ -0,0 +1,144 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"shopify/models"
+	"shopify/pkg/utils/slug"
+	"shopify/repository"
+
+	"gorm.io/gorm"
+)
+
+// maxSlugSuffix 自动生成 slug 时尝试的最大去重序号，超过后使用产品ID
+const maxSlugSuffix = 100
+
+// ErrSlugTaken slug 已被其他商品使用
+var ErrSlugTaken = errors.New("slug already in use")
+
+// checkSlugAvailable 校验 slug 格式并确认未被其他商品使用，商品自己用过的旧 slug 可以重新启用
+func checkSlugAvailable(repoFactory *repository.RepositoryFactory, productID uint, value string) error {
+	if !slug.Valid(value) {
+		return errors.New("slug may only contain lowercase letters, digits and single hyphens")
+	}
+	existing, err := repoFactory.GetSlugRepository().GetBySlug(value)
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ProductID != productID {
+		return ErrSlugTaken
+	}
+	return nil
+}
+
+// generateSlug 根据商品名称生成未被其他商品使用的 slug，重复时追加序号
+func generateSlug(repoFactory *repository.RepositoryFactory, product *models.Product) (string, error) {
+	fallback := fmt.Sprintf("product-%d", product.ID)
+	base := slug.Generate(product.Name)
+	if base == "" {
+		base = fallback
+	}
+
+	candidate := base
+	for i := 2; i <= maxSlugSuffix; i++ {
+		err := checkSlugAvailable(repoFactory, product.ID, candidate)
+		if err == nil {
+			return candidate, nil
+		}
+		if err != ErrSlugTaken {
+			return "", err
+		}
+		candidate = slug.WithSuffix(base, strconv.Itoa(i))
+	}
+	return fallback, nil
+}
+
+// assignSlug 设置商品当前的 slug，requested 为空时根据名称生成
+// 旧 slug 保留在 ProductSlug 中，用于跳转到新 slug
+func assignSlug(repoFactory *repository.RepositoryFactory, product *models.Product, requested string) error {
+	value := requested
+	if value == "" {
+		generated, err := generateSlug(repoFactory, product)
+		if err != nil {
+			return err
+		}
+		value = generated
+	} else if err := checkSlugAvailable(repoFactory, product.ID, value); err != nil {
+		return err
+	}
+
+	slugRepo := repoFactory.GetSlugRepository()
+	if _, err := slugRepo.GetBySlug(value); err == gorm.ErrRecordNotFound {
+		if err := slugRepo.Create(&models.ProductSlug{ProductID: product.ID, Slug: value}); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if err := repoFactory.GetProductRepository().UpdateSlug(product.ID, value); err != nil {
+		return err
+	}
+	product.Slug = value
+	return nil
+}
+
+// GetProductBySlug 根据 slug 获取产品详情
+// 使用旧 slug 访问时返回当前 slug，由调用方跳转
+func (s *ProductService) GetProductBySlug(value string) (*ProductDetail, string, error) {
+	productSlug, err := s.repoFactory.GetSlugRepository().GetBySlug(value)
+	if err != nil {
+		return nil, "", errors.New("product not found")
+	}
+
+	detail, err := s.GetProductDetail(productSlug.ProductID)
+	if err != nil {
+		return nil, "", errors.New("product not found")
+	}
+	if detail.Slug != value {
+		return detail, detail.Slug, nil
+	}
+	return detail, "", nil
+}
+
+// ListProductSlugs 获取产品用过的全部 slug
+func (s *ProductService) ListProductSlugs(productID uint) ([]models.ProductSlug, error) {
+	return s.repoFactory.GetSlugRepository().ListByProduct(productID)
+}
+
+// BackfillSlugs 为尚未生成 slug 的历史产品生成 slug
+func (s *ProductService) BackfillSlugs() error {
+	const batchSize = 100
+	total := 0
+	for {
+		products, err := s.repoFactory.GetProductRepository().ListWithoutSlug(batchSize)
+		if err != nil {
+			return err
+		}
+		if len(products) == 0 {
+			break
+		}
+		for i := range products {
+			product := &products[i]
+			err := s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+				return assignSlug(repository.NewRepositoryFactory(tx), product, "")
+			})
+			if err != nil {
+				return fmt.Errorf("generate slug for product %d: %v", product.ID, err)
+			}
+		}
+		total += len(products)
+	}
+	if total > 0 {
+		log.Printf("已为 %d 个产品生成 slug", total)
+		s.invalidateSitemap()
+		s.invalidateFeed()
+	}
+	return nil
+}
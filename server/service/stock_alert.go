@@ -0,0 +1,169 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"shopify/config"
+	"shopify/models"
+	"shopify/pkg/utils/email"
+)
+
+type StockAlertService struct {
+	*Service
+}
+
+func NewStockAlertService(base *Service) *StockAlertService {
+	return &StockAlertService{Service: base}
+}
+
+// StockRisk 缺货风险商品
+type StockRisk struct {
+	ProductID     uint     `json:"product_id"`     // 产品ID
+	Name          string   `json:"name"`           // 产品名称
+	Stock         int      `json:"stock"`          // 当前库存
+	Threshold     int      `json:"threshold"`      // 低库存阈值
+	SoldInWindow  int      `json:"sold_in_window"` // 统计周期内销量
+	DailyVelocity float64  `json:"daily_velocity"` // 日均销量
+	DaysOfCover   *float64 `json:"days_of_cover"`  // 预计可售天数，无销量时为空
+	SuggestedQty  int      `json:"suggested_qty"`  // 建议补货数量
+	Reasons       []string `json:"reasons"`        // 预警原因：below_threshold/low_cover
+}
+
+// ListAtRiskProducts 根据库存阈值和近期销售速度计算存在缺货风险的商品
+func (s *StockAlertService) ListAtRiskProducts() ([]StockRisk, error) {
+	cfg := config.GlobalConfig.Inventory
+	windowDays := cfg.SalesWindowDays
+	if windowDays <= 0 {
+		windowDays = 30
+	}
+
+	products, err := s.repoFactory.GetProductRepository().ListActive()
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().AddDate(0, 0, -windowDays)
+	sales, err := s.repoFactory.GetOrderRepository().SumSalesSince(since)
+	if err != nil {
+		return nil, err
+	}
+	sold := make(map[uint]int, len(sales))
+	for _, item := range sales {
+		sold[item.ProductID] = item.Quantity
+	}
+
+	risks := make([]StockRisk, 0)
+	for _, product := range products {
+		threshold := product.LowStockThreshold
+		if threshold <= 0 {
+			threshold = cfg.LowStockThreshold
+		}
+
+		risk := StockRisk{
+			ProductID:     product.ID,
+			Name:          product.Name,
+			Stock:         product.Stock,
+			Threshold:     threshold,
+			SoldInWindow:  sold[product.ID],
+			DailyVelocity: float64(sold[product.ID]) / float64(windowDays),
+		}
+
+		if product.Stock <= threshold {
+			risk.Reasons = append(risk.Reasons, "below_threshold")
+		}
+		if risk.DailyVelocity > 0 {
+			cover := math.Round(float64(product.Stock)/risk.DailyVelocity*10) / 10
+			risk.DaysOfCover = &cover
+			if cover < float64(cfg.CoverDays) {
+				risk.Reasons = append(risk.Reasons, "low_cover")
+			}
+		}
+		if len(risk.Reasons) == 0 {
+			continue
+		}
+
+		// 建议补货到目标可售天数，且不低于阈值
+		target := int(math.Ceil(risk.DailyVelocity * float64(cfg.TargetCoverDays)))
+		if target < threshold {
+			target = threshold
+		}
+		if target > product.Stock {
+			risk.SuggestedQty = target - product.Stock
+		}
+
+		risks = append(risks, risk)
+	}
+
+	// 可售天数少的排在前面，无销量的按库存排序
+	sort.SliceStable(risks, func(i, j int) bool {
+		ci, cj := risks[i].DaysOfCover, risks[j].DaysOfCover
+		if ci != nil && cj != nil {
+			return *ci < *cj
+		}
+		if ci != nil || cj != nil {
+			return ci != nil
+		}
+		return risks[i].Stock < risks[j].Stock
+	})
+
+	return risks, nil
+}
+
+// RunLowStockAlert 每日低库存预警任务：生成站内通知并邮件通知管理员
+func (s *StockAlertService) RunLowStockAlert() error {
+	risks, err := s.ListAtRiskProducts()
+	if err != nil {
+		return err
+	}
+
+	notifier := NewNotificationService(s.Service)
+	var lines []string
+	for _, risk := range risks {
+		content := formatStockRisk(risk)
+		created, err := notifier.Notify(models.NotificationTypeLowStock,
+			fmt.Sprintf("低库存预警：%s", risk.Name), content, risk.ProductID)
+		if err != nil {
+			return err
+		}
+		// 已有未读预警的商品不重复发邮件
+		if created {
+			lines = append(lines, content)
+		}
+	}
+
+	if len(lines) == 0 {
+		return nil
+	}
+
+	emails, err := s.repoFactory.GetUserRepository().ListAdminEmails()
+	if err != nil {
+		return err
+	}
+	if len(emails) == 0 {
+		return nil
+	}
+
+	subject := fmt.Sprintf("Low stock alert: %d products at risk", len(lines))
+	body := "The following products are at risk of running out:\n\n" + strings.Join(lines, "\n")
+	if err := email.NewEmailSender().Send(emails, subject, body); err != nil {
+		// 邮件发送失败不影响站内通知
+		log.Printf("failed to send low stock alert email: %v", err)
+	}
+
+	return nil
+}
+
+// formatStockRisk 格式化预警内容
+func formatStockRisk(risk StockRisk) string {
+	cover := "n/a"
+	if risk.DaysOfCover != nil {
+		cover = fmt.Sprintf("%.1f", *risk.DaysOfCover)
+	}
+	return fmt.Sprintf("#%d %s: stock %d (threshold %d), days of cover %s, suggested replenishment %d",
+		risk.ProductID, risk.Name, risk.Stock, risk.Threshold, cover, risk.SuggestedQty)
+}
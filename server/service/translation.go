@@ -0,0 +1,402 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"shopify/config"
+	"shopify/models"
+	"shopify/pkg/search"
+	"shopify/pkg/utils/locale"
+	"shopify/repository"
+
+	"gorm.io/gorm"
+)
+
+// translatableFields 各类对象可翻译的字段及长度上限，与对象本身的字段长度一致，0 表示不限制
+var translatableFields = map[string]map[string]int{
+	models.TranslationEntityProduct: {
+		models.TranslationFieldName:        100,
+		models.TranslationFieldDescription: 0,
+	},
+	models.TranslationEntityCategory: {
+		models.TranslationFieldName: 50,
+	},
+	models.TranslationEntityAdvertisement: {
+		models.TranslationFieldTitle: 100,
+	},
+}
+
+// ErrUnsupportedLocale 语言不在支持的语言列表中
+var ErrUnsupportedLocale = errors.New("unsupported locale")
+
+// ErrTranslationTargetNotFound 要翻译的商品、类目或广告不存在
+var ErrTranslationTargetNotFound = errors.New("translation target not found")
+
+// DefaultLocale 默认语言
+func DefaultLocale() string {
+	return config.GlobalConfig.I18n.DefaultLocale
+}
+
+// ResolveLocale 根据 lang 参数和 Accept-Language 请求头确定内容语言，都不可用时使用默认语言
+func ResolveLocale(lang, acceptLanguage string) string {
+	cfg := config.GlobalConfig.I18n
+	return locale.Resolve(lang, acceptLanguage, cfg.Locales, cfg.DefaultLocale)
+}
+
+// EntityTranslations 对象在各语言下的翻译
+type EntityTranslations struct {
+	DefaultLocale string                       `json:"default_locale"` // 默认语言，内容保存在对象本身
+	Locales       []string                     `json:"locales"`        // 可翻译的语言，不含默认语言
+	Fields        []string                     `json:"fields"`         // 可翻译的字段
+	Translations  map[string]map[string]string `json:"translations"`   // 语言 -> 字段 -> 翻译内容
+}
+
+type TranslationService struct {
+	*Service
+}
+
+func NewTranslationService(base *Service) *TranslationService {
+	return &TranslationService{Service: base}
+}
+
+// checkTranslationEntity 确认要翻译的对象存在
+func (s *TranslationService) checkTranslationEntity(entityType string, entityID uint) error {
+	var err error
+	switch entityType {
+	case models.TranslationEntityProduct:
+		_, err = s.repoFactory.GetProductRepository().GetByID(entityID)
+	case models.TranslationEntityCategory:
+		_, err = s.repoFactory.GetCategoryRepository().GetByID(entityID)
+	case models.TranslationEntityAdvertisement:
+		_, err = s.repoFactory.GetAdvertisementRepository().GetByID(entityID)
+	default:
+		return fmt.Errorf("unsupported translation entity: %s", entityType)
+	}
+	if err == gorm.ErrRecordNotFound {
+		return ErrTranslationTargetNotFound
+	}
+	return err
+}
+
+// translationLocale 校验并规范化要编辑的语言，默认语言的内容直接在对象上修改
+func translationLocale(value string) (string, error) {
+	matched := locale.Match(value, config.GlobalConfig.I18n.Locales)
+	if matched == "" || !strings.EqualFold(matched, value) {
+		return "", ErrUnsupportedLocale
+	}
+	if matched == DefaultLocale() {
+		return "", errors.New("default locale content is edited on the object itself")
+	}
+	return matched, nil
+}
+
+// GetTranslations 获取对象在各语言下的翻译
+func (s *TranslationService) GetTranslations(entityType string, entityID uint) (*EntityTranslations, error) {
+	if err := s.checkTranslationEntity(entityType, entityID); err != nil {
+		return nil, err
+	}
+
+	translations, err := s.repoFactory.GetTranslationRepository().ListByEntity(entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &EntityTranslations{
+		DefaultLocale: DefaultLocale(),
+		Locales:       make([]string, 0),
+		Fields:        make([]string, 0, len(translatableFields[entityType])),
+		Translations:  make(map[string]map[string]string),
+	}
+	for _, l := range config.GlobalConfig.I18n.Locales {
+		if l != result.DefaultLocale {
+			result.Locales = append(result.Locales, l)
+		}
+	}
+	for field := range translatableFields[entityType] {
+		result.Fields = append(result.Fields, field)
+	}
+	sort.Strings(result.Fields)
+
+	for _, t := range translations {
+		if result.Translations[t.Locale] == nil {
+			result.Translations[t.Locale] = make(map[string]string)
+		}
+		result.Translations[t.Locale][t.Field] = t.Value
+	}
+	return result, nil
+}
+
+// SaveTranslations 保存对象在某个语言下的翻译，内容为空的字段删除翻译，未提交的字段保持不变
+func (s *TranslationService) SaveTranslations(entityType string, entityID uint, localeValue string, fields map[string]string) (*EntityTranslations, error) {
+	loc, err := translationLocale(localeValue)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTranslationEntity(entityType, entityID); err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("at least one field is required")
+	}
+
+	allowed := translatableFields[entityType]
+	for field, value := range fields {
+		limit, ok := allowed[field]
+		if !ok {
+			return nil, fmt.Errorf("field %s cannot be translated", field)
+		}
+		if limit > 0 && utf8.RuneCountInString(strings.TrimSpace(value)) > limit {
+			return nil, fmt.Errorf("%s must be at most %d characters", field, limit)
+		}
+	}
+
+	err = s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		translationRepo := repository.NewRepositoryFactory(tx).GetTranslationRepository()
+		for field, value := range fields {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				if err := translationRepo.Delete(entityType, entityID, loc, field); err != nil {
+					return err
+				}
+				continue
+			}
+			err := translationRepo.Save(&models.Translation{
+				EntityType: entityType,
+				EntityID:   entityID,
+				Locale:     loc,
+				Field:      field,
+				Value:      value,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.syncTranslatedIndex(entityType, entityID)
+	return s.GetTranslations(entityType, entityID)
+}
+
+// DeleteTranslations 删除对象在某个语言下的全部翻译
+func (s *TranslationService) DeleteTranslations(entityType string, entityID uint, localeValue string) error {
+	loc, err := translationLocale(localeValue)
+	if err != nil {
+		return err
+	}
+	if err := s.checkTranslationEntity(entityType, entityID); err != nil {
+		return err
+	}
+	if err := s.repoFactory.GetTranslationRepository().Delete(entityType, entityID, loc, ""); err != nil {
+		return err
+	}
+
+	s.syncTranslatedIndex(entityType, entityID)
+	return nil
+}
+
+// syncTranslatedIndex 翻译变化后更新搜索索引，类目翻译影响类目下全部商品
+func (s *TranslationService) syncTranslatedIndex(entityType string, entityID uint) {
+	switch entityType {
+	case models.TranslationEntityProduct:
+		s.syncSearchIndex(entityID)
+	case models.TranslationEntityCategory:
+		ids, err := s.repoFactory.GetProductRepository().ListIDsByCategory(entityID)
+		if err != nil {
+			log.Printf("获取类目商品失败, category_id=%d: %v", entityID, err)
+			return
+		}
+		for _, id := range ids {
+			s.syncSearchIndex(id)
+		}
+	}
+}
+
+// translationsFor 批量获取对象在某个语言下的翻译，默认语言返回 nil
+func (s *Service) translationsFor(entityType string, ids []uint, loc string) (map[uint]map[string]string, error) {
+	if loc == "" || loc == DefaultLocale() || len(ids) == 0 {
+		return nil, nil
+	}
+	translations, err := s.repoFactory.GetTranslationRepository().ListByEntities(entityType, ids, loc)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uint]map[string]string)
+	for _, t := range translations {
+		if result[t.EntityID] == nil {
+			result[t.EntityID] = make(map[string]string)
+		}
+		result[t.EntityID][t.Field] = t.Value
+	}
+	return result, nil
+}
+
+// LocalizeProducts 将商品名称、描述和类目名称替换为指定语言，未翻译的字段保留默认语言
+// 翻译加载失败时只打印日志，返回默认语言的内容
+func (s *TranslationService) LocalizeProducts(loc string, products ...*models.Product) {
+	if loc == DefaultLocale() || len(products) == 0 {
+		return
+	}
+
+	ids := make([]uint, 0, len(products))
+	var categoryIDs []uint
+	for _, product := range products {
+		ids = append(ids, product.ID)
+		if product.CategoryID != nil {
+			categoryIDs = append(categoryIDs, *product.CategoryID)
+		}
+	}
+
+	translations, err := s.translationsFor(models.TranslationEntityProduct, ids, loc)
+	if err != nil {
+		log.Printf("加载商品翻译失败, locale=%s: %v", loc, err)
+		return
+	}
+	categoryTranslations, err := s.translationsFor(models.TranslationEntityCategory, categoryIDs, loc)
+	if err != nil {
+		log.Printf("加载类目翻译失败, locale=%s: %v", loc, err)
+		return
+	}
+
+	for _, product := range products {
+		if fields, ok := translations[product.ID]; ok {
+			if name := fields[models.TranslationFieldName]; name != "" {
+				product.Name = name
+			}
+			if description := fields[models.TranslationFieldDescription]; description != "" {
+				product.Description = description
+			}
+		}
+		if product.CategoryID != nil {
+			if name := categoryTranslations[*product.CategoryID][models.TranslationFieldName]; name != "" {
+				product.Category = name
+			}
+		}
+	}
+}
+
+// LocalizeProductList 将商品列表替换为指定语言
+func (s *TranslationService) LocalizeProductList(loc string, products []models.Product) {
+	ptrs := make([]*models.Product, len(products))
+	for i := range products {
+		ptrs[i] = &products[i]
+	}
+	s.LocalizeProducts(loc, ptrs...)
+}
+
+// LocalizeCategories 将类目树中的类目名称替换为指定语言
+func (s *TranslationService) LocalizeCategories(loc string, tree []*models.Category) {
+	if loc == DefaultLocale() || len(tree) == 0 {
+		return
+	}
+
+	var all []*models.Category
+	var collect func(nodes []*models.Category)
+	collect = func(nodes []*models.Category) {
+		for _, node := range nodes {
+			all = append(all, node)
+			collect(node.Children)
+		}
+	}
+	collect(tree)
+
+	ids := make([]uint, len(all))
+	for i, category := range all {
+		ids[i] = category.ID
+	}
+	translations, err := s.translationsFor(models.TranslationEntityCategory, ids, loc)
+	if err != nil {
+		log.Printf("加载类目翻译失败, locale=%s: %v", loc, err)
+		return
+	}
+	for _, category := range all {
+		if name := translations[category.ID][models.TranslationFieldName]; name != "" {
+			category.Name = name
+		}
+	}
+}
+
+// LocalizeAdvertisements 将广告标题替换为指定语言
+func (s *TranslationService) LocalizeAdvertisements(loc string, ads ...*models.Advertisement) {
+	if loc == DefaultLocale() || len(ads) == 0 {
+		return
+	}
+
+	ids := make([]uint, len(ads))
+	for i, ad := range ads {
+		ids[i] = ad.ID
+	}
+	translations, err := s.translationsFor(models.TranslationEntityAdvertisement, ids, loc)
+	if err != nil {
+		log.Printf("加载广告翻译失败, locale=%s: %v", loc, err)
+		return
+	}
+	for _, ad := range ads {
+		if title := translations[ad.ID][models.TranslationFieldTitle]; title != "" {
+			ad.Title = title
+		}
+	}
+}
+
+// localizedTexts 将商品及其类目的翻译整理为搜索文档的多语言文本
+func localizedTexts(products []*models.Product, productTranslations, categoryTranslations []models.Translation) map[uint][]search.LocalizedText {
+	byProduct := make(map[uint]map[string]*search.LocalizedText)
+	text := func(productID uint, loc string) *search.LocalizedText {
+		if byProduct[productID] == nil {
+			byProduct[productID] = make(map[string]*search.LocalizedText)
+		}
+		t, ok := byProduct[productID][loc]
+		if !ok {
+			t = &search.LocalizedText{Locale: loc}
+			byProduct[productID][loc] = t
+		}
+		return t
+	}
+
+	for _, t := range productTranslations {
+		switch t.Field {
+		case models.TranslationFieldName:
+			text(t.EntityID, t.Locale).Name = t.Value
+		case models.TranslationFieldDescription:
+			text(t.EntityID, t.Locale).Description = t.Value
+		}
+	}
+
+	categoryNames := make(map[uint]map[string]string)
+	for _, t := range categoryTranslations {
+		if t.Field != models.TranslationFieldName {
+			continue
+		}
+		if categoryNames[t.EntityID] == nil {
+			categoryNames[t.EntityID] = make(map[string]string)
+		}
+		categoryNames[t.EntityID][t.Locale] = t.Value
+	}
+	for _, product := range products {
+		if product.CategoryID == nil {
+			continue
+		}
+		for loc, name := range categoryNames[*product.CategoryID] {
+			text(product.ID, loc).CategoryName = name
+		}
+	}
+
+	result := make(map[uint][]search.LocalizedText, len(byProduct))
+	for productID, locales := range byProduct {
+		texts := make([]search.LocalizedText, 0, len(locales))
+		for _, t := range locales {
+			texts = append(texts, *t)
+		}
+		sort.Slice(texts, func(i, j int) bool { return texts[i].Locale < texts[j].Locale })
+		result[productID] = texts
+	}
+	return result
+}
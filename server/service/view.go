@@ -0,0 +1,356 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"shopify/config"
+	"shopify/models"
+	"shopify/repository"
+)
+
+// maxViewReportDays 浏览统计最多回溯的天数
+const maxViewReportDays = 365
+
+// ProductViewStats 商品浏览量统计
+type ProductViewStats = repository.ProductViewStats
+
+// ProductFunnel 商品浏览→加购→下单转化漏斗
+type ProductFunnel = repository.ProductFunnel
+
+// ProductViewReport 单个商品的浏览量报表
+type ProductViewReport struct {
+	*ProductViewStats
+	Daily  []repository.DailyViews `json:"daily"`  // 按天统计的浏览量
+	Funnel *ProductFunnel          `json:"funnel"` // 登录用户的转化漏斗
+}
+
+// FunnelReport 转化漏斗报表
+type FunnelReport struct {
+	Total    ProductFunnel   `json:"total"`    // 所有商品合计
+	Products []ProductFunnel `json:"products"` // 各商品的转化，按浏览人数倒序
+}
+
+// RecentlyViewedProduct 最近浏览的商品
+type RecentlyViewedProduct struct {
+	models.Product
+	ViewedAt time.Time `json:"viewed_at"` // 最近浏览时间
+}
+
+// ViewRecorder 商品浏览记录缓冲，按数量或时间间隔批量写入数据库
+type ViewRecorder struct {
+	repoFactory   *repository.RepositoryFactory
+	batchSize     int
+	maxBuffer     int
+	flushInterval time.Duration
+
+	mu      sync.Mutex
+	pending []models.ProductView
+	flushCh chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewViewRecorder 创建浏览记录缓冲，需调用 Start 启动后台写入
+func NewViewRecorder(repoFactory *repository.RepositoryFactory, cfg config.TrackingConfig) *ViewRecorder {
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = 200
+	}
+	maxBuffer := cfg.MaxBuffer
+	if maxBuffer < batchSize {
+		maxBuffer = batchSize * 10
+	}
+	flushInterval := time.Duration(cfg.FlushSeconds) * time.Second
+	if flushInterval <= 0 {
+		flushInterval = 5 * time.Second
+	}
+
+	return &ViewRecorder{
+		repoFactory:   repoFactory,
+		batchSize:     batchSize,
+		maxBuffer:     maxBuffer,
+		flushInterval: flushInterval,
+		flushCh:       make(chan struct{}, 1),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Record 将浏览记录加入缓冲，缓冲已满时丢弃，不影响商品详情的响应
+func (r *ViewRecorder) Record(view models.ProductView) {
+	if view.CreatedAt.IsZero() {
+		view.CreatedAt = time.Now()
+	}
+
+	r.mu.Lock()
+	if len(r.pending) >= r.maxBuffer {
+		r.mu.Unlock()
+		return
+	}
+	r.pending = append(r.pending, view)
+	full := len(r.pending) >= r.batchSize
+	r.mu.Unlock()
+
+	if full {
+		select {
+		case r.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Pending 获取用户尚未写入数据库的浏览记录
+func (r *ViewRecorder) Pending(userID uint) []models.ProductView {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var views []models.ProductView
+	for _, view := range r.pending {
+		if view.UserID != nil && *view.UserID == userID {
+			views = append(views, view)
+		}
+	}
+	return views
+}
+
+// Start 启动后台写入
+func (r *ViewRecorder) Start() {
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(r.flushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.stop:
+				r.Flush()
+				return
+			case <-ticker.C:
+				r.Flush()
+			case <-r.flushCh:
+				r.Flush()
+			}
+		}
+	}()
+}
+
+// Stop 停止后台写入并写入剩余的浏览记录
+func (r *ViewRecorder) Stop() {
+	close(r.stop)
+	<-r.done
+}
+
+// Flush 将缓冲中的浏览记录写入数据库，写入失败时放回缓冲等待下次重试
+func (r *ViewRecorder) Flush() {
+	r.mu.Lock()
+	views := r.pending
+	r.pending = nil
+	r.mu.Unlock()
+
+	if len(views) == 0 {
+		return
+	}
+
+	if err := r.repoFactory.GetViewRepository().CreateBatch(views); err != nil {
+		log.Printf("写入 %d 条浏览记录失败: %v", len(views), err)
+		r.mu.Lock()
+		// 失败的记录排在新记录之前，超出上限的部分丢弃
+		r.pending = append(views, r.pending...)
+		if len(r.pending) > r.maxBuffer {
+			r.pending = r.pending[:r.maxBuffer]
+		}
+		r.mu.Unlock()
+	}
+}
+
+type ViewService struct {
+	*Service
+}
+
+func NewViewService(base *Service) *ViewService {
+	return &ViewService{Service: base}
+}
+
+// RecordView 记录商品浏览，未设置缓冲时直接写入
+func (s *ViewService) RecordView(view models.ProductView) {
+	view.Referrer = truncateRunes(view.Referrer, 255)
+	view.SessionID = truncateRunes(view.SessionID, 64)
+
+	if s.viewRecorder != nil {
+		s.viewRecorder.Record(view)
+		return
+	}
+	if err := s.repoFactory.GetViewRepository().CreateBatch([]models.ProductView{view}); err != nil {
+		log.Printf("写入浏览记录失败: %v", err)
+	}
+}
+
+// RecentlyViewed 获取用户最近浏览的商品，同一商品只返回一次，已删除的商品不返回
+func (s *ViewService) RecentlyViewed(userID uint, limit int) ([]RecentlyViewedProduct, error) {
+	if limit <= 0 || limit > config.GlobalConfig.Tracking.RecentLimit {
+		limit = config.GlobalConfig.Tracking.RecentLimit
+	}
+
+	viewed, err := s.repoFactory.GetViewRepository().ListRecentlyViewed(userID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// 合并尚未写入数据库的浏览记录
+	viewedAt := make(map[uint]time.Time, len(viewed))
+	for _, v := range viewed {
+		viewedAt[v.ProductID] = v.ViewedAt
+	}
+	if s.viewRecorder != nil {
+		for _, view := range s.viewRecorder.Pending(userID) {
+			if view.CreatedAt.After(viewedAt[view.ProductID]) {
+				viewedAt[view.ProductID] = view.CreatedAt
+			}
+		}
+	}
+
+	ids := make([]uint, 0, len(viewedAt))
+	for id := range viewedAt {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if !viewedAt[ids[i]].Equal(viewedAt[ids[j]]) {
+			return viewedAt[ids[i]].After(viewedAt[ids[j]])
+		}
+		return ids[i] > ids[j]
+	})
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	products, err := s.repoFactory.GetProductRepository().ListByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Product, len(products))
+	for _, product := range products {
+		byID[product.ID] = product
+	}
+
+	result := make([]RecentlyViewedProduct, 0, len(ids))
+	for _, id := range ids {
+		product, ok := byID[id]
+		if !ok {
+			continue
+		}
+		result = append(result, RecentlyViewedProduct{Product: product, ViewedAt: viewedAt[id]})
+	}
+	return result, nil
+}
+
+// viewReportSince 将统计天数转换为起始时间
+func viewReportSince(days int) (time.Time, error) {
+	if days <= 0 || days > maxViewReportDays {
+		return time.Time{}, errors.New("days must be between 1 and 365")
+	}
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return today.AddDate(0, 0, 1-days), nil
+}
+
+// ProductViewReport 获取单个商品的浏览量、按天趋势和转化漏斗
+func (s *ViewService) ProductViewReport(productID uint, days int) (*ProductViewReport, error) {
+	since, err := viewReportSince(days)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repoFactory.GetProductRepository().GetByID(productID); err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	viewRepo := s.repoFactory.GetViewRepository()
+	stats, err := viewRepo.CountProductViews(productID, since)
+	if err != nil {
+		return nil, err
+	}
+	daily, err := viewRepo.ListDailyViews(productID, since)
+	if err != nil {
+		return nil, err
+	}
+	funnels, err := viewRepo.ListFunnels(since, productID, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	funnel := &ProductFunnel{ProductID: productID}
+	if len(funnels) > 0 {
+		funnel = &funnels[0]
+	}
+	fillFunnelRates(funnel)
+
+	return &ProductViewReport{
+		ProductViewStats: stats,
+		Daily:            daily,
+		Funnel:           funnel,
+	}, nil
+}
+
+// ListTopViewed 分页获取浏览量最高的商品
+func (s *ViewService) ListTopViewed(days, page, pageSize int) ([]ProductViewStats, int64, error) {
+	since, err := viewReportSince(days)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.repoFactory.GetViewRepository().ListTopViewed(since, page, pageSize)
+}
+
+// FunnelReport 获取浏览人数最多的商品的转化漏斗及合计
+func (s *ViewService) FunnelReport(days, limit int) (*FunnelReport, error) {
+	since, err := viewReportSince(days)
+	if err != nil {
+		return nil, err
+	}
+
+	// 合计需要统计全部商品，只截取前 limit 个返回明细
+	funnels, err := s.repoFactory.GetViewRepository().ListFunnels(since, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &FunnelReport{Products: make([]ProductFunnel, 0, limit)}
+	for i := range funnels {
+		report.Total.Viewers += funnels[i].Viewers
+		report.Total.CartUsers += funnels[i].CartUsers
+		report.Total.OrderUsers += funnels[i].OrderUsers
+		if i < limit {
+			fillFunnelRates(&funnels[i])
+			report.Products = append(report.Products, funnels[i])
+		}
+	}
+	fillFunnelRates(&report.Total)
+	return report, nil
+}
+
+// fillFunnelRates 计算漏斗各阶段的转化率
+func fillFunnelRates(funnel *ProductFunnel) {
+	funnel.ViewToCart = conversionRate(funnel.CartUsers, funnel.Viewers)
+	funnel.CartToOrder = conversionRate(funnel.OrderUsers, funnel.CartUsers)
+	funnel.ViewToOrder = conversionRate(funnel.OrderUsers, funnel.Viewers)
+}
+
+// conversionRate 计算转化率，保留四位小数
+func conversionRate(converted, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(converted)/float64(total)*10000) / 10000
+}
+
+// truncateRunes 按字符截断字符串以适应字段长度
+func truncateRunes(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) > limit {
+		return string(runes[:limit])
+	}
+	return value
+}
@@ -0,0 +1,371 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"shopify/models"
+	"shopify/repository"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// ErrSKUWarehouseStock 仓库库存按产品记录，有规格的产品只使用未分仓库存
+var ErrSKUWarehouseStock = errors.New("warehouse stock is tracked per product, products with skus cannot be stocked in warehouses")
+
+type WarehouseService struct {
+	*Service
+}
+
+func NewWarehouseService(base *Service) *WarehouseService {
+	return &WarehouseService{Service: base}
+}
+
+// PackingSlip 装箱单，每个发货仓一张
+type PackingSlip struct {
+	OrderNumber string            `json:"order_number"` // 订单编号
+	Warehouse   *models.Warehouse `json:"warehouse"`    // 发货仓库，为空表示未分配仓库
+	Logistics   *models.Logistics `json:"logistics"`    // 对应的物流信息
+	Address     models.Address    `json:"address"`      // 收货地址
+	Items       []PackingSlipItem `json:"items"`        // 装箱商品
+}
+
+// PackingSlipItem 装箱单商品行
+type PackingSlipItem struct {
+	ProductID uint   `json:"product_id"` // 产品ID
+	Name      string `json:"name"`       // 产品名称
+	Quantity  int    `json:"quantity"`   // 数量
+}
+
+// warehouseServes 判断仓库是否优先配送该省份
+func warehouseServes(warehouse *models.Warehouse, province string) bool {
+	if province == "" {
+		return false
+	}
+	if warehouse.Province == province {
+		return true
+	}
+	for _, region := range warehouse.Regions {
+		if region == province {
+			return true
+		}
+	}
+	return false
+}
+
+// allocateItem 为订单项选择发货仓
+// 优先选择配送该省份的仓库，其次按优先级；单仓库存足够时整单发货，否则拆分到多个仓库。
+// 返回各仓分配结果以及未能从仓库分配、需从未分仓库存扣减的数量。仓库库存按产品记录，仅用于无规格的商品。
+func allocateItem(repoFactory *repository.RepositoryFactory, province string, product *models.Product, quantity int) ([]models.OrderAllocation, int, error) {
+	warehouseRepo := repoFactory.GetWarehouseRepository()
+	stocks, err := warehouseRepo.ListStocksByProduct(product.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 未分仓库存需扣除所有仓库（含停用仓库）中的库存，停用仓库的库存不能当作未分仓库存发货
+	warehouseTotal, err := warehouseRepo.SumStockByProduct(product.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 仅从启用的仓库中选择发货仓
+	available := make([]models.WarehouseStock, 0, len(stocks))
+	for _, stock := range stocks {
+		if stock.Quantity > 0 {
+			available = append(available, stock)
+		}
+	}
+
+	sort.SliceStable(available, func(i, j int) bool {
+		si := warehouseServes(&available[i].Warehouse, province)
+		sj := warehouseServes(&available[j].Warehouse, province)
+		if si != sj {
+			return si
+		}
+		return available[i].Warehouse.Priority < available[j].Warehouse.Priority
+	})
+
+	var allocations []models.OrderAllocation
+
+	// 优先整单从单个仓库发货
+	for _, stock := range available {
+		if stock.Quantity >= quantity {
+			allocations = append(allocations, models.OrderAllocation{
+				WarehouseID: stock.WarehouseID,
+				ProductID:   product.ID,
+				Quantity:    quantity,
+			})
+			return allocations, 0, nil
+		}
+	}
+
+	// 单仓不足时按顺序拆分
+	remaining := quantity
+	for _, stock := range available {
+		if remaining == 0 {
+			break
+		}
+		take := stock.Quantity
+		if take > remaining {
+			take = remaining
+		}
+		allocations = append(allocations, models.OrderAllocation{
+			WarehouseID: stock.WarehouseID,
+			ProductID:   product.ID,
+			Quantity:    take,
+		})
+		remaining -= take
+	}
+
+	// 剩余部分从未分仓的库存中扣减
+	if remaining > 0 && product.Stock-warehouseTotal < remaining {
+		return nil, 0, fmt.Errorf("insufficient stock for product: %s", product.Name)
+	}
+
+	return allocations, remaining, nil
+}
+
+// CreateWarehouse 创建仓库
+func (s *WarehouseService) CreateWarehouse(warehouse *models.Warehouse) error {
+	if warehouse.Code == "" || warehouse.Name == "" {
+		return errors.New("warehouse code and name are required")
+	}
+	if warehouse.Status == "" {
+		warehouse.Status = "active"
+	}
+	if warehouse.Status != "active" && warehouse.Status != "inactive" {
+		return errors.New("invalid status value")
+	}
+	return s.repoFactory.GetWarehouseRepository().Create(warehouse)
+}
+
+// GetWarehouse 获取仓库详情
+func (s *WarehouseService) GetWarehouse(id uint) (*models.Warehouse, error) {
+	return s.repoFactory.GetWarehouseRepository().GetByID(id)
+}
+
+// UpdateWarehouse 更新仓库信息
+func (s *WarehouseService) UpdateWarehouse(warehouse *models.Warehouse) error {
+	if _, err := s.GetWarehouse(warehouse.ID); err != nil {
+		return errors.New("warehouse not found")
+	}
+	if warehouse.Status != "active" && warehouse.Status != "inactive" {
+		return errors.New("invalid status value")
+	}
+	return s.repoFactory.GetWarehouseRepository().Update(warehouse)
+}
+
+// DeleteWarehouse 删除仓库，仓库中仍有库存时不允许删除
+func (s *WarehouseService) DeleteWarehouse(id uint) error {
+	if _, err := s.GetWarehouse(id); err != nil {
+		return errors.New("warehouse not found")
+	}
+
+	stocks, _, err := s.repoFactory.GetWarehouseRepository().ListStocksByWarehouse(id, 1, 1000)
+	if err != nil {
+		return err
+	}
+	for _, stock := range stocks {
+		if stock.Quantity > 0 {
+			return errors.New("warehouse still has stock, transfer it first")
+		}
+	}
+
+	return s.repoFactory.GetWarehouseRepository().Delete(id)
+}
+
+// ListWarehouses 获取仓库列表
+func (s *WarehouseService) ListWarehouses() ([]models.Warehouse, error) {
+	return s.repoFactory.GetWarehouseRepository().List()
+}
+
+// ListWarehouseStocks 获取仓库中的产品库存
+func (s *WarehouseService) ListWarehouseStocks(warehouseID uint, page, pageSize int) ([]models.WarehouseStock, int64, error) {
+	return s.repoFactory.GetWarehouseRepository().ListStocksByWarehouse(warehouseID, page, pageSize)
+}
+
+// ListProductStocks 获取产品在各仓库的库存
+func (s *WarehouseService) ListProductStocks(productID uint) ([]models.WarehouseStock, error) {
+	return s.repoFactory.GetWarehouseRepository().ListStocksByProduct(productID)
+}
+
+// TransferStock 仓库间调拨库存
+// fromID 为 0 表示从未分仓库存调入，toID 为 0 表示调回未分仓库存；调拨不改变产品总库存
+func (s *WarehouseService) TransferStock(productID, fromID, toID uint, quantity int, remark string, operatorID uint) error {
+	if quantity <= 0 {
+		return errors.New("transfer quantity must be positive")
+	}
+	if fromID == toID {
+		return errors.New("source and target warehouse cannot be the same")
+	}
+
+	return s.repoFactory.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepoFactory := repository.NewRepositoryFactory(tx)
+		warehouseRepo := txRepoFactory.GetWarehouseRepository()
+
+		product, err := txRepoFactory.GetProductRepository().GetByID(productID)
+		if err != nil {
+			return errors.New("product not found")
+		}
+		if product.Type == models.ProductTypeBundle {
+			return ErrBundleStock
+		}
+		if product.Type == models.ProductTypeDigital {
+			return ErrDigitalStock
+		}
+		skuCount, err := txRepoFactory.GetSKURepository().CountByProduct(productID)
+		if err != nil {
+			return err
+		}
+		if skuCount > 0 {
+			return ErrSKUWarehouseStock
+		}
+
+		for _, id := range []uint{fromID, toID} {
+			if id == 0 {
+				continue
+			}
+			if _, err := warehouseRepo.GetByID(id); err != nil {
+				return errors.New("warehouse not found")
+			}
+		}
+
+		// 从未分仓库存调入时检查可调数量
+		if fromID == 0 {
+			warehouseTotal, err := warehouseRepo.SumStockByProduct(productID)
+			if err != nil {
+				return err
+			}
+			if product.Stock-warehouseTotal < quantity {
+				return errors.New("insufficient unassigned stock")
+			}
+		} else if err := warehouseRepo.ChangeStock(fromID, productID, -quantity); err != nil {
+			return err
+		}
+
+		if toID != 0 {
+			if err := warehouseRepo.ChangeStock(toID, productID, quantity); err != nil {
+				return err
+			}
+		}
+
+		// 调出和调入各记一条流水，合计为零
+		movements := []models.InventoryMovement{
+			{WarehouseID: fromID, Delta: -quantity},
+			{WarehouseID: toID, Delta: quantity},
+		}
+		for i := range movements {
+			movements[i].ProductID = productID
+			movements[i].Quantity = product.Stock
+			movements[i].Reason = models.InventoryReasonTransfer
+			movements[i].Remark = remark
+			movements[i].OperatorID = operatorID
+			if err := txRepoFactory.GetInventoryRepository().CreateMovement(&movements[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetPackingSlips 按发货仓生成订单装箱单
+func (s *WarehouseService) GetPackingSlips(orderID uint) ([]PackingSlip, error) {
+	order, err := s.repoFactory.GetOrderRepository().GetByID(orderID)
+	if err != nil {
+		return nil, errors.New("order not found")
+	}
+
+	logistics, err := s.repoFactory.GetOrderRepository().ListLogistics(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 统计每个订单项中各商品已分配到仓库的数量，套装按组件拣货
+	type lineKey struct{ itemID, productID uint }
+	allocated := make(map[lineKey]int)
+	slips := make([]PackingSlip, 0)
+	slipIndex := make(map[uint]int)
+
+	names := make(map[lineKey]string)
+	var lines []PackingSlipItem
+	var lineKeys []lineKey
+	for _, item := range order.OrderItems {
+		if orderItemType(&item) == models.ProductTypeDigital {
+			continue // 数字商品自动发放，无需拣货
+		}
+		if len(item.Components) == 0 {
+			key := lineKey{item.ID, item.ProductID}
+			names[key] = item.Product.Name
+			lines = append(lines, PackingSlipItem{ProductID: item.ProductID, Name: item.Product.Name, Quantity: item.Quantity})
+			lineKeys = append(lineKeys, key)
+			continue
+		}
+		for _, component := range item.Components {
+			key := lineKey{item.ID, component.ProductID}
+			name := fmt.Sprintf("%s (%s)", component.Product.Name, item.Product.Name)
+			names[key] = name
+			lines = append(lines, PackingSlipItem{ProductID: component.ProductID, Name: name, Quantity: component.Quantity})
+			lineKeys = append(lineKeys, key)
+		}
+	}
+
+	for _, allocation := range order.Allocations {
+		key := lineKey{allocation.OrderItemID, allocation.ProductID}
+		allocated[key] += allocation.Quantity
+
+		idx, ok := slipIndex[allocation.WarehouseID]
+		if !ok {
+			warehouse := allocation.Warehouse
+			slips = append(slips, PackingSlip{
+				OrderNumber: order.OrderNumber,
+				Warehouse:   &warehouse,
+				Address:     order.Address,
+			})
+			idx = len(slips) - 1
+			slipIndex[allocation.WarehouseID] = idx
+		}
+		slips[idx].Items = append(slips[idx].Items, PackingSlipItem{
+			ProductID: allocation.ProductID,
+			Name:      names[key],
+			Quantity:  allocation.Quantity,
+		})
+	}
+
+	// 未分配仓库的部分单独生成一张装箱单
+	var unassigned []PackingSlipItem
+	for i, line := range lines {
+		if remaining := line.Quantity - allocated[lineKeys[i]]; remaining > 0 {
+			line.Quantity = remaining
+			unassigned = append(unassigned, line)
+		}
+	}
+	if len(unassigned) > 0 {
+		slips = append(slips, PackingSlip{
+			OrderNumber: order.OrderNumber,
+			Address:     order.Address,
+			Items:       unassigned,
+		})
+	}
+
+	// 关联物流信息
+	for i := range slips {
+		for j := range logistics {
+			var warehouseID uint
+			if slips[i].Warehouse != nil {
+				warehouseID = slips[i].Warehouse.ID
+			}
+			var logisticsWarehouseID uint
+			if logistics[j].WarehouseID != nil {
+				logisticsWarehouseID = *logistics[j].WarehouseID
+			}
+			if warehouseID == logisticsWarehouseID {
+				slips[i].Logistics = &logistics[j]
+				break
+			}
+		}
+	}
+
+	return slips, nil
+}